@@ -0,0 +1,65 @@
+package alipay
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+	"errors"
+)
+
+/**
+ * @author: Sam
+ * @since: 1.0.0
+ * @date: 2026/10/20 04:40
+ * @desc: AES加解密，支付宝的内容加密使用AES/CBC/PKCS5Padding，IV为16个0
+ */
+
+var ErrAESKey = errors.New("xpay: aes key must be base64 encoded 16, 24 or 32 bytes")
+var ErrAESPadding = errors.New("xpay: aes padding error")
+
+// ParseAESKey 解析开放平台配置的base64编码的AES密钥
+func ParseAESKey(encryptKey string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(encryptKey)
+	if err != nil {
+		return nil, ErrAESKey
+	}
+	switch len(key) {
+	case 16, 24, 32:
+		return key, nil
+	}
+	return nil, ErrAESKey
+}
+
+// AESEncrypt 加密并返回base64编码的密文
+func AESEncrypt(plaintext, key []byte) (string, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", err
+	}
+	padding := aes.BlockSize - len(plaintext)%aes.BlockSize
+	buff := append(append([]byte(nil), plaintext...), bytes.Repeat([]byte{byte(padding)}, padding)...)
+	cipher.NewCBCEncrypter(block, make([]byte, aes.BlockSize)).CryptBlocks(buff, buff)
+	return base64.StdEncoding.EncodeToString(buff), nil
+}
+
+// AESDecrypt 解密base64编码的密文
+func AESDecrypt(ciphertext string, key []byte) ([]byte, error) {
+	buff, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(buff) == 0 || len(buff)%aes.BlockSize != 0 {
+		return nil, ErrAESPadding
+	}
+	cipher.NewCBCDecrypter(block, make([]byte, aes.BlockSize)).CryptBlocks(buff, buff)
+	padding := int(buff[len(buff)-1])
+	if padding == 0 || padding > aes.BlockSize || !bytes.Equal(buff[len(buff)-padding:], bytes.Repeat([]byte{byte(padding)}, padding)) {
+		return nil, ErrAESPadding
+	}
+	return buff[:len(buff)-padding], nil
+}
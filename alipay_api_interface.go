@@ -72,6 +72,7 @@ type CommonReqParam struct {
 	Version          string `json:"version" url:"version"`                                   // 必选 3 调用的接口版本，固定为：1.0
 	NotifyUrl        string `json:"notify_url,omitempty" url:"notify_url,omitempty"`         // 可选	256	 支付宝服务器主动通知商户服务器里指定的页面http/https路径。
 	AppAuthToken     string `json:"app_auth_token,omitempty" url:"app_auth_token,omitempty"` // 可选	40 详见应用授权概述
+	AuthToken        string `json:"auth_token,omitempty" url:"auth_token,omitempty"`         // 可选	40 用户授权令牌，调用用户授权类接口时传递
 	BizContent       string `json:"biz_content" url:"biz_content"`                           // 必选	无长度限制 请求参数的集合，最大长度不限，除公共参数外所有请求参数都必须放在这个参数中传递，具体参照各产品快速接入文档
 	AppCertSn        string `json:"app_cert_sn" url:"app_cert_sn,omitempty"`                 // 可选	具体参照各产品快速接入文档
 	AlipayRootCertSn string `json:"alipay_root_cert_sn" url:"alipay_root_cert_sn,omitempty"` // 可选	具体参照各产品快速接入文档
@@ -109,6 +110,24 @@ func WithReturnUrl(returnUrl string) func(*CommonReqParam) {
 	}
 }
 
+func WithAppAuthToken(appAuthToken string) func(*CommonReqParam) {
+	return func(param *CommonReqParam) {
+		if len(appAuthToken) == 0 {
+			return
+		}
+		param.AppAuthToken = appAuthToken
+	}
+}
+
+func WithAuthToken(authToken string) func(*CommonReqParam) {
+	return func(param *CommonReqParam) {
+		if len(authToken) == 0 {
+			return
+		}
+		param.AuthToken = authToken
+	}
+}
+
 func WithAppCertSn(appCertSn string) func(*CommonReqParam) {
 	return func(param *CommonReqParam) {
 		if len(appCertSn) == 0 {
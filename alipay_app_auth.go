@@ -0,0 +1,266 @@
+package alipay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+/**
+ * @author: Sam
+ * @since: 1.0.0
+ * @date: 2026/10/20 03:10
+ * @desc: 第三方应用授权管理
+ *
+ * 服务商引导商户访问 AuthorizeURL 授权，授权完成后支付宝带 app_auth_code 跳转到 redirect_uri，
+ * HandleCallback 使用授权码换取 app_auth_token 和 app_refresh_token 并按商户的 user_id 写入 AppAuthStore。
+ * 代商户调用接口时通过 Context 将令牌放入ctx，Client 发送请求时作为公共参数 app_auth_token 传递，
+ * 令牌在过期前 RefreshBefore 内使用时先刷新，也可以定时调用 RefreshExpiring 刷新即将过期的令牌。
+ */
+
+const (
+	// AppToAppAuthURL 第三方应用授权页面
+	AppToAppAuthURL = "https://openauth.alipay.com/oauth2/appToAppAuth.htm"
+	// SandboxAppToAppAuthURL 沙箱环境的第三方应用授权页面
+	SandboxAppToAppAuthURL = "https://openauth-sandbox.dl.alipaydev.com/oauth2/appToAppAuth.htm"
+)
+
+var ErrAppAuthNotExist = errors.New("xpay: app auth not exist")
+var ErrAppAuthCodeEmpty = errors.New("xpay: app_auth_code is empty")
+
+// AppAuth 商户的应用授权令牌
+type AppAuth struct {
+	UserId          string    `json:"user_id"`           // 授权商户的user_id
+	AuthAppId       string    `json:"auth_app_id"`       // 授权商户的appid
+	AppAuthToken    string    `json:"app_auth_token"`    // 应用授权令牌
+	AppRefreshToken string    `json:"app_refresh_token"` // 刷新令牌
+	ExpiresAt       time.Time `json:"expires_at"`        // 应用授权令牌的失效时间，为零值时长期有效
+	ReExpiresAt     time.Time `json:"re_expires_at"`     // 刷新令牌的失效时间
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// AppAuthStore 应用授权令牌的存储
+type AppAuthStore interface {
+	// AppAuth 按商户的user_id查询，不存在时返回nil
+	AppAuth(ctx context.Context, userId string) (*AppAuth, error)
+	// Expiring 失效时间早于before的全部令牌
+	Expiring(ctx context.Context, before time.Time) ([]*AppAuth, error)
+	// Save 新增或者按 UserId 更新令牌
+	Save(ctx context.Context, auth *AppAuth) error
+	// Delete 删除商户的令牌
+	Delete(ctx context.Context, userId string) error
+}
+
+type appAuthTokenKey struct{}
+
+// ContextWithAppAuthToken 返回携带应用授权令牌的ctx，Client 使用该ctx发送的请求会带上 app_auth_token
+func ContextWithAppAuthToken(ctx context.Context, appAuthToken string) context.Context {
+	return context.WithValue(ctx, appAuthTokenKey{}, appAuthToken)
+}
+
+// AppAuthTokenFromContext 获取ctx中的应用授权令牌
+func AppAuthTokenFromContext(ctx context.Context) (string, bool) {
+	appAuthToken, ok := ctx.Value(appAuthTokenKey{}).(string)
+	return appAuthToken, ok && len(appAuthToken) > 0
+}
+
+// AppAuthManager 第三方应用授权管理
+type AppAuthManager struct {
+	client *Client
+	store  AppAuthStore
+	mu     sync.Mutex
+
+	AppId       string // 服务商的第三方应用appid
+	RedirectUri string // 授权完成后的回调地址，需要与应用中配置的授权回调地址一致
+	// AuthorizeBaseURL 授权页面地址，默认按客户端是否为生产环境选择 AppToAppAuthURL 或者 SandboxAppToAppAuthURL
+	AuthorizeBaseURL string
+	// RefreshBefore 令牌失效前多久开始刷新，默认7天
+	RefreshBefore time.Duration
+	Now           func() time.Time
+}
+
+func NewAppAuthManager(client *Client, store AppAuthStore, appId, redirectUri string) *AppAuthManager {
+	manager := &AppAuthManager{
+		client:           client,
+		store:            store,
+		AppId:            appId,
+		RedirectUri:      redirectUri,
+		AuthorizeBaseURL: SandboxAppToAppAuthURL,
+		RefreshBefore:    7 * 24 * time.Hour,
+		Now:              time.Now,
+	}
+	if client.isProd {
+		manager.AuthorizeBaseURL = AppToAppAuthURL
+	}
+	return manager
+}
+
+// AuthorizeURL 商户授权页面的地址，state原样带回回调地址，用于防止CSRF
+func (r *AppAuthManager) AuthorizeURL(state string) (*url.URL, error) {
+	authorizeURL, err := url.Parse(r.AuthorizeBaseURL)
+	if err != nil {
+		return nil, err
+	}
+	query := authorizeURL.Query()
+	query.Set("app_id", r.AppId)
+	query.Set("redirect_uri", r.RedirectUri)
+	if len(state) > 0 {
+		query.Set("state", state)
+	}
+	authorizeURL.RawQuery = query.Encode()
+	return authorizeURL, nil
+}
+
+// HandleCallback 处理授权回调，使用 app_auth_code 换取令牌并保存。state需要调用方从 request 中取出自行校验
+func (r *AppAuthManager) HandleCallback(ctx context.Context, request *http.Request) (*AppAuth, error) {
+	code := request.URL.Query().Get("app_auth_code")
+	if len(code) == 0 {
+		return nil, ErrAppAuthCodeEmpty
+	}
+	return r.Exchange(ctx, code)
+}
+
+// Exchange 使用授权码换取令牌并保存
+func (r *AppAuthManager) Exchange(ctx context.Context, appAuthCode string) (*AppAuth, error) {
+	return r.requestToken(ctx, OpenAuthTokenAppReq{GrantType: GrantAuthorizationCode, Code: appAuthCode})
+}
+
+// Token 商户当前有效的令牌，即将失效时先刷新
+func (r *AppAuthManager) Token(ctx context.Context, userId string) (*AppAuth, error) {
+	auth, err := r.load(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if !r.expiring(auth) {
+		return auth, nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// 等待锁期间其他调用可能已经刷新
+	if auth, err = r.load(ctx, userId); err != nil || !r.expiring(auth) {
+		return auth, err
+	}
+	return r.requestToken(ctx, OpenAuthTokenAppReq{GrantType: GrantRefreshToken, RefreshToken: auth.AppRefreshToken})
+}
+
+// Refresh 立即刷新商户的令牌
+func (r *AppAuthManager) Refresh(ctx context.Context, userId string) (*AppAuth, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	auth, err := r.load(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	return r.requestToken(ctx, OpenAuthTokenAppReq{GrantType: GrantRefreshToken, RefreshToken: auth.AppRefreshToken})
+}
+
+// RefreshExpiring 刷新 RefreshBefore 内即将失效的全部令牌，返回刷新成功的令牌，遇到错误时继续刷新其他商户并返回第一个错误
+func (r *AppAuthManager) RefreshExpiring(ctx context.Context) ([]*AppAuth, error) {
+	auths, err := r.store.Expiring(ctx, r.Now().Add(r.RefreshBefore))
+	if err != nil {
+		return nil, err
+	}
+	var refreshed []*AppAuth
+	var firstErr error
+	for _, auth := range auths {
+		if err = ctx.Err(); err != nil {
+			return refreshed, err
+		}
+		if auth, err = r.Refresh(ctx, auth.UserId); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		refreshed = append(refreshed, auth)
+	}
+	return refreshed, firstErr
+}
+
+// Context 返回携带商户令牌的ctx，用于代商户调用接口
+func (r *AppAuthManager) Context(ctx context.Context, userId string) (context.Context, error) {
+	auth, err := r.Token(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	return ContextWithAppAuthToken(ctx, auth.AppAuthToken), nil
+}
+
+// Do 代商户发送请求
+func (r *AppAuthManager) Do(ctx context.Context, userId string, req IAliPayRequest, res ResponseSigner) error {
+	auth, err := r.Token(ctx, userId)
+	if err != nil {
+		return err
+	}
+	return r.client.DoRequest(ctx, req, res, WithAppAuthToken(auth.AppAuthToken))
+}
+
+// Query 查询商户令牌的授权信息
+func (r *AppAuthManager) Query(ctx context.Context, userId string) (*OpenAuthTokenAppQueryRes, error) {
+	auth, err := r.Token(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	return r.client.OpenAuthTokenAppQuery(ctx, OpenAuthTokenAppQueryReq{AppAuthToken: auth.AppAuthToken})
+}
+
+// Revoke 删除商户的令牌，商户在支付宝侧解除授权后调用
+func (r *AppAuthManager) Revoke(ctx context.Context, userId string) error {
+	return r.store.Delete(ctx, userId)
+}
+
+func (r *AppAuthManager) load(ctx context.Context, userId string) (*AppAuth, error) {
+	auth, err := r.store.AppAuth(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if auth == nil {
+		return nil, ErrAppAuthNotExist
+	}
+	return auth, nil
+}
+
+func (r *AppAuthManager) expiring(auth *AppAuth) bool {
+	return !auth.ExpiresAt.IsZero() && !r.Now().Add(r.RefreshBefore).Before(auth.ExpiresAt)
+}
+
+// requestToken 换取或者刷新令牌并保存
+func (r *AppAuthManager) requestToken(ctx context.Context, req OpenAuthTokenAppReq) (*AppAuth, error) {
+	res, err := r.client.OpenAuthTokenApp(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	content := res.OpenAuthTokenAppResContent
+	if content == nil {
+		if res.CommonRes != nil {
+			return nil, fmt.Errorf("xpay: %s failed, sub_code: %s, sub_msg: %s", req.RequestApi(), res.CommonRes.SubCode, res.CommonRes.SubMsg)
+		}
+		return nil, fmt.Errorf("xpay: %s failed, empty response", req.RequestApi())
+	}
+	if content.Fail() {
+		return nil, fmt.Errorf("xpay: %s failed, sub_code: %s, sub_msg: %s", req.RequestApi(), content.SubCode, content.SubMsg)
+	}
+	now := r.Now()
+	auth := &AppAuth{
+		UserId:          content.UserId,
+		AuthAppId:       content.AuthAppId,
+		AppAuthToken:    content.AppAuthToken,
+		AppRefreshToken: content.AppRefreshToken,
+		UpdatedAt:       now,
+	}
+	if seconds, err := strconv.ParseInt(content.ExpiresIn, 10, 64); err == nil && seconds > 0 {
+		auth.ExpiresAt = now.Add(time.Duration(seconds) * time.Second)
+	}
+	if seconds, err := strconv.ParseInt(content.ReExpiresIn, 10, 64); err == nil && seconds > 0 {
+		auth.ReExpiresAt = now.Add(time.Duration(seconds) * time.Second)
+	}
+	if err = r.store.Save(ctx, auth); err != nil {
+		return nil, err
+	}
+	return auth, nil
+}
@@ -0,0 +1,137 @@
+package alipay_test
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/try-labs/xpay"
+	"github.com/try-labs/xpay/alipaytest"
+)
+
+/**
+ * @author: Sam
+ * @since:
+ * @date: 2026/10/20 03:20
+ * @desc:
+ */
+
+const isvMerchantId = "2088102150527498"
+
+func newAppAuthManager(t *testing.T) (*AppAuthManager, *alipaytest.Gateway) {
+	t.Helper()
+	g := alipaytest.NewGateway()
+	t.Cleanup(g.Close)
+	isvClient, err := g.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewAppAuthManager(isvClient, alipaytest.NewAppAuthStore(), alipaytest.DefaultAppId, "https://isv.example.com/alipay/auth"), g
+}
+
+func TestAppAuthManager_AuthorizeURL(t *testing.T) {
+	manager, _ := newAppAuthManager(t)
+	authorizeURL, err := manager.AuthorizeURL("s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := SandboxAppToAppAuthURL + "?app_id=" + alipaytest.DefaultAppId + "&redirect_uri=https%3A%2F%2Fisv.example.com%2Falipay%2Fauth&state=s1"
+	if authorizeURL.String() != want {
+		t.Errorf("authorize url = %s, want %s", authorizeURL, want)
+	}
+}
+
+func TestAppAuthManager_HandleCallback(t *testing.T) {
+	manager, g := newAppAuthManager(t)
+	ctx := context.Background()
+	if _, err := manager.HandleCallback(ctx, httptest.NewRequest("GET", "/alipay/auth?state=s1", nil)); err != ErrAppAuthCodeEmpty {
+		t.Fatalf("expect ErrAppAuthCodeEmpty, got %v", err)
+	}
+	code := g.IssueAppAuthCode(isvMerchantId, "2021000000000002")
+	auth, err := manager.HandleCallback(ctx, httptest.NewRequest("GET", "/alipay/auth?state=s1&app_auth_code="+code, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if auth.UserId != isvMerchantId || auth.AuthAppId != "2021000000000002" || len(auth.AppAuthToken) == 0 || auth.ExpiresAt.IsZero() {
+		t.Fatalf("unexpected auth: %+v", auth)
+	}
+	// 授权码只能使用一次
+	if _, err = manager.Exchange(ctx, code); err == nil {
+		t.Error("expect exchange error")
+	}
+
+	// 代商户调用的请求携带app_auth_token
+	merchantCtx, err := manager.Context(ctx, isvMerchantId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	queryRes, err := manager.Query(merchantCtx, isvMerchantId)
+	if err != nil || queryRes.Fail() || queryRes.Status != "valid" {
+		t.Fatalf("%v %v", queryRes, err)
+	}
+	res := new(GenericRes)
+	if err = manager.Do(ctx, isvMerchantId, &FundAccountQueryReq{AlipayUserId: isvMerchantId, AccountType: "ACCTRANS_ACCOUNT"}, res); err != nil {
+		t.Fatal(err)
+	}
+	requests := g.Requests()
+	if got := requests[len(requests)-1].AppAuthToken; got != auth.AppAuthToken {
+		t.Errorf("app_auth_token = %s, want %s", got, auth.AppAuthToken)
+	}
+	if _, err = manager.Token(ctx, "2088000000000000"); !errors.Is(err, ErrAppAuthNotExist) {
+		t.Errorf("expect ErrAppAuthNotExist, got %v", err)
+	}
+}
+
+func TestAppAuthManager_Refresh(t *testing.T) {
+	manager, g := newAppAuthManager(t)
+	now := time.Now()
+	manager.Now = func() time.Time {
+		return now
+	}
+	ctx := context.Background()
+	auth, err := manager.Exchange(ctx, g.IssueAppAuthCode(isvMerchantId, "2021000000000002"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current, err := manager.Token(ctx, isvMerchantId); err != nil || current.AppAuthToken != auth.AppAuthToken {
+		t.Fatalf("%v %v", current, err)
+	}
+	refreshed, err := manager.RefreshExpiring(ctx)
+	if err != nil || len(refreshed) != 0 {
+		t.Fatalf("%v %v", refreshed, err)
+	}
+
+	// 进入刷新窗口后使用令牌时先刷新，原令牌失效
+	now = auth.ExpiresAt.Add(-manager.RefreshBefore + time.Hour)
+	current, err := manager.Token(ctx, isvMerchantId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current.AppAuthToken == auth.AppAuthToken || current.AppRefreshToken == auth.AppRefreshToken {
+		t.Fatalf("token not refreshed: %+v", current)
+	}
+	if origin, ok := g.AppAuth(auth.AppAuthToken); !ok || origin.Status != "invalid" {
+		t.Errorf("unexpected origin auth: %+v", origin)
+	}
+	res := new(GenericRes)
+	err = manager.Do(ctx, isvMerchantId, &FundAccountQueryReq{AlipayUserId: isvMerchantId, AccountType: "ACCTRANS_ACCOUNT"}, res)
+	if err != nil || res.Fail() {
+		t.Fatalf("%v %v", res, err)
+	}
+
+	// 旧令牌直接调用返回错误
+	isvClient, _ := g.Client()
+	res = new(GenericRes)
+	err = isvClient.DoRequest(ContextWithAppAuthToken(ctx, auth.AppAuthToken), &FundAccountQueryReq{AlipayUserId: isvMerchantId, AccountType: "ACCTRANS_ACCOUNT"}, res)
+	if err != nil || res.SubCode != alipaytest.ErrInvalidAppAuthToken.SubCode {
+		t.Fatalf("%v %v", res, err)
+	}
+
+	now = current.ExpiresAt.Add(-time.Hour)
+	refreshed, err = manager.RefreshExpiring(ctx)
+	if err != nil || len(refreshed) != 1 || refreshed[0].AppAuthToken == current.AppAuthToken {
+		t.Fatalf("%v %v", refreshed, err)
+	}
+}
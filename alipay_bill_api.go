@@ -12,7 +12,7 @@ import "encoding/json"
 var _ IAliPayRequest = (*DataBillBalanceQueryReq)(nil)
 
 type DataBillBalanceQueryReq struct {
-	BillUserId string `json:"bill_user_id,omitempty"` // 可选	16 目标查询账户（仅支持部分场景，查询自身时候不需要传递当前字段）。 2088123456789012
+	BillUserId string `json:"bill_user_id,omitempty" validate:"max=16"` // 可选	16 目标查询账户（仅支持部分场景，查询自身时候不需要传递当前字段）。 2088123456789012
 	baseAliPayRequest
 }
 
@@ -21,7 +21,7 @@ func (r *DataBillBalanceQueryReq) RequestApi() string {
 }
 
 func (r *DataBillBalanceQueryReq) DoValidate() error {
-	return nil
+	return ValidateStruct(r)
 }
 
 type DataBillBalanceQueryRes struct {
@@ -31,21 +31,21 @@ type DataBillBalanceQueryRes struct {
 
 type DataBillBalanceQueryResContent struct {
 	CommonRes
-	TotalAmount     string `json:"total_amount"`            // 必选	32 支付宝账户余额 10000.00
-	AvailableAmount string `json:"available_amount"`        // 必选	32 账户可用余额 9000.00
-	FreezeAmount    string `json:"freeze_amount"`           // 必选	32 冻结金额 1000.00
-	SettleAmount    string `json:"settle_amount,omitempty"` // 可选	32 待结算金额 500.00
+	TotalAmount     Money `json:"total_amount"`            // 必选	32 支付宝账户余额 10000.00
+	AvailableAmount Money `json:"available_amount"`        // 必选	32 账户可用余额 9000.00
+	FreezeAmount    Money `json:"freeze_amount"`           // 必选	32 冻结金额 1000.00
+	SettleAmount    Money `json:"settle_amount,omitempty"` // 可选	32 待结算金额 500.00
 }
 
 // /////////////////////////////////////////////
 var _ IAliPayRequest = &DataBillBailQueryReq{}
 
 type DataBillBailQueryReq struct {
-	StartTime  string `json:"start_time"`             // 必选	20 保证金流水创建时间的起始范围 2019-01-01 00:00:00
-	EndTime    string `json:"end_time"`               // 必选	20 保证金流水创建时间的结束范围。与起始时间间隔不超过31天。查询结果为起始时间至结束时间的左闭右开区间 2019-01-02 00:00:00
-	BailType   string `json:"bail_type"`              // 必选	20 保证金类型，目前支持TMALL_BAIL-天猫保证金，TAOBAO_BAIL-淘宝保证金 TMALL_BAIL
-	TransLogId string `json:"trans_log_id,omitempty"` // 可选	255 保证金流水号。如果查询参数中指定流水号，则只查询流水号相关的记录 20190101***
-	BizOrigNo  string `json:"biz_orig_no,omitempty"`  // 可选	255 业务基础订单号。如果查询参数中指定订单号，则只查询相关的记录 1***
+	StartTime  string `json:"start_time" validate:"required,max=20"`                     // 必选	20 保证金流水创建时间的起始范围 2019-01-01 00:00:00
+	EndTime    string `json:"end_time" validate:"required,max=20"`                       // 必选	20 保证金流水创建时间的结束范围。与起始时间间隔不超过31天。查询结果为起始时间至结束时间的左闭右开区间 2019-01-02 00:00:00
+	BailType   string `json:"bail_type" validate:"required,enum=TMALL_BAIL|TAOBAO_BAIL"` // 必选	20 保证金类型，目前支持TMALL_BAIL-天猫保证金，TAOBAO_BAIL-淘宝保证金 TMALL_BAIL
+	TransLogId string `json:"trans_log_id,omitempty" validate:"max=255"`                 // 可选	255 保证金流水号。如果查询参数中指定流水号，则只查询流水号相关的记录 20190101***
+	BizOrigNo  string `json:"biz_orig_no,omitempty" validate:"max=255"`                  // 可选	255 业务基础订单号。如果查询参数中指定订单号，则只查询相关的记录 1***
 	baseAliPayRequest
 }
 
@@ -54,7 +54,7 @@ func (r *DataBillBailQueryReq) RequestApi() string {
 }
 
 func (r *DataBillBailQueryReq) DoValidate() error {
-	return nil
+	return ValidateStruct(r)
 }
 
 type DataBillBailQueryRes struct {
@@ -76,9 +76,301 @@ type BailDetailResult struct {
 	TransDt    string `json:"trans_dt,omitempty"`     // 必选	20 业务发生时间 2019-01-01 00:00:00
 	TransLogId string `json:"trans_log_id,omitempty"` // 必选	255 保证金业务流水号 20190101***
 	BailType   string `json:"bail_type,omitempty"`    // 必选	255 保证金类型描述，仅供参考 天猫保证金
-	Amount     string `json:"amount,omitempty"`       // 必选	32 保证金收支金额 10.00
-	Balance    string `json:"balance,omitempty"`      // 必选	32 保证金余额 1000.00
+	Amount     Money  `json:"amount,omitempty"`       // 必选	32 保证金收支金额 10.00
+	Balance    Money  `json:"balance,omitempty"`      // 必选	32 保证金余额 1000.00
 	Memo       string `json:"memo,omitempty"`         // 可选	255 保证金说明 保证金冻结
 	BizDesc    string `json:"biz_desc,omitempty"`     // 可选	255 业务描述，资金收支对应的详细业务场景信息 余额账户迁入
 	BizOrigNo  string `json:"biz_orig_no,omitempty"`  // 可选	255 业务基础订单号，资金收支对应的原始业务订单唯一识别编号 1***
 }
+
+// /////////////////////////////////////////////
+var _ IAliPayRequest = &DataBillEreceiptApplyReq{}
+
+const (
+	EreceiptTypeFundDetail = "FUND_DETAIL" // 资金业务回单，key为账务流水号
+	EreceiptTypeTrade      = "TRADE"       // 交易回单，key为支付宝交易号
+)
+
+type DataBillEreceiptApplyReq struct {
+	Type       string `json:"type" validate:"required,max=32"`          // 必选	32 申请的类型，FUND_DETAIL（资金业务回单）、TRADE（交易回单） FUND_DETAIL
+	Key        string `json:"key" validate:"required,max=256"`          // 必选	256 根据申请类型传入账务流水号或者支付宝交易号 2019111311001004170000000001
+	BillUserId string `json:"bill_user_id,omitempty" validate:"max=16"` // 可选	16 申请的账号，不传时为当前商户 2088123456789012
+	baseAliPayRequest
+}
+
+func (r *DataBillEreceiptApplyReq) RequestApi() string {
+	return "alipay.data.bill.ereceipt.apply"
+}
+
+func (r *DataBillEreceiptApplyReq) DoValidate() error {
+	return ValidateStruct(r)
+}
+
+type DataBillEreceiptApplyRes struct {
+	DataBillEreceiptApplyResContent `json:"alipay_data_bill_ereceipt_apply_response"`
+	SignCertSn
+}
+
+func (r *DataBillEreceiptApplyRes) String() string {
+	buff, _ := json.Marshal(r)
+	return string(buff)
+}
+
+type DataBillEreceiptApplyResContent struct {
+	CommonRes
+	FileId string `json:"file_id"` // 必选	64 文件申请号，用于查询回单状态 2019111300000001
+}
+
+// /////////////////////////////////////////////
+var _ IAliPayRequest = &DataBillEreceiptQueryReq{}
+
+const (
+	EreceiptStatusInit    = "INIT"    // 处理中
+	EreceiptStatusSuccess = "SUCCESS" // 已生成，可以下载
+	EreceiptStatusFail    = "FAIL"    // 生成失败
+)
+
+type DataBillEreceiptQueryReq struct {
+	FileId     string `json:"file_id" validate:"required,max=64"`       // 必选	64 申请电子回单接口返回的文件申请号 2019111300000001
+	BillUserId string `json:"bill_user_id,omitempty" validate:"max=16"` // 可选	16 申请时传入的账号 2088123456789012
+	baseAliPayRequest
+}
+
+func (r *DataBillEreceiptQueryReq) RequestApi() string {
+	return "alipay.data.bill.ereceipt.query"
+}
+
+func (r *DataBillEreceiptQueryReq) DoValidate() error {
+	return ValidateStruct(r)
+}
+
+type DataBillEreceiptQueryRes struct {
+	DataBillEreceiptQueryResContent `json:"alipay_data_bill_ereceipt_query_response"`
+	SignCertSn
+}
+
+func (r *DataBillEreceiptQueryRes) String() string {
+	buff, _ := json.Marshal(r)
+	return string(buff)
+}
+
+type DataBillEreceiptQueryResContent struct {
+	CommonRes
+	Status       string `json:"status"`                  // 必选	32 处理状态，INIT（处理中）、SUCCESS（成功）、FAIL（失败） SUCCESS
+	DownloadUrl  string `json:"download_url,omitempty"`  // 可选	2048 回单PDF的下载地址，状态为SUCCESS时返回，有效期30秒
+	ErrorMessage string `json:"error_message,omitempty"` // 可选	2048 状态为FAIL时的失败原因 交易不存在
+}
+
+// /////////////////////////////////////////////
+var _ IAliPayRequest = &DataBillAccountLogQueryReq{}
+
+type DataBillAccountLogQueryReq struct {
+	StartTime            string `json:"start_time" validate:"required,max=20"`              // 必选	20 账务流水创建时间的起始范围 2019-01-01 00:00:00
+	EndTime              string `json:"end_time" validate:"required,max=20"`                // 必选	20 账务流水创建时间的结束范围，与起始时间间隔不超过31天，查询结果为左闭右开区间 2019-01-02 00:00:00
+	AlipayOrderNo        string `json:"alipay_order_no,omitempty" validate:"max=64"`        // 可选	64 支付宝订单号，通过支付宝订单号精确查询相关的流水明细 20190101***
+	MerchantOrderNo      string `json:"merchant_order_no,omitempty" validate:"max=64"`      // 可选	64 商户订单号，通过商户订单号精确查询相关的流水明细 TX***
+	PageNo               string `json:"page_no,omitempty" validate:"max=16"`                // 可选	16 分页号，从1开始 1
+	PageSize             string `json:"page_size,omitempty" validate:"max=16"`              // 可选	16 分页大小，最大2000 2000
+	TransCode            string `json:"trans_code,omitempty" validate:"max=256"`            // 可选	256 账务的类型代码，多个用英文逗号分隔 101101,101102
+	AgreementNo          string `json:"agreement_no,omitempty" validate:"max=128"`          // 可选	128 协议授权码，ISV查询时需要传入 20215606000638368888
+	AgreementProductCode string `json:"agreement_product_code,omitempty" validate:"max=32"` // 可选	32 协议产品码 FUND_SIGN_WITHHOLDING
+	BillUserId           string `json:"bill_user_id,omitempty" validate:"max=16"`           // 可选	16 目标查询账户，查询自身时不需要传递 2088123456789012
+	baseAliPayRequest
+}
+
+func (r *DataBillAccountLogQueryReq) RequestApi() string {
+	return "alipay.data.bill.accountlog.query"
+}
+
+func (r *DataBillAccountLogQueryReq) DoValidate() error {
+	return ValidateStruct(r)
+}
+
+type DataBillAccountLogQueryRes struct {
+	DataBillAccountLogQueryResContent `json:"alipay_data_bill_accountlog_query_response"`
+	SignCertSn
+}
+
+func (r *DataBillAccountLogQueryRes) String() string {
+	buff, _ := json.Marshal(r)
+	return string(buff)
+}
+
+type DataBillAccountLogQueryResContent struct {
+	CommonRes
+	PageNo     string            `json:"page_no"`     // 必选	16 分页号 1
+	PageSize   string            `json:"page_size"`   // 必选	16 分页大小 2000
+	TotalSize  string            `json:"total_size"`  // 必选	16 账务明细总数 10000
+	DetailList []*AccountLogItem `json:"detail_list"` // 可选 账务明细列表
+}
+
+type AccountLogItem struct {
+	TransDt             string `json:"trans_dt,omitempty"`               // 必选	20 入账时间 2019-01-01 00:00:00
+	AccountLogId        string `json:"account_log_id,omitempty"`         // 必选	64 支付宝账务流水号，申请电子回单时使用 2019111311001004170000000001
+	AlipayOrderNo       string `json:"alipay_order_no,omitempty"`        // 可选	64 支付宝订单号 20190101***
+	MerchantOrderNo     string `json:"merchant_order_no,omitempty"`      // 可选	64 商户订单号 TX***
+	TransAmount         Money  `json:"trans_amount,omitempty"`           // 必选	32 金额，支出为负数 -10.00
+	Balance             Money  `json:"balance,omitempty"`                // 必选	32 余额 1000.00
+	Type                string `json:"type,omitempty"`                   // 必选	64 账务记录的类型 在线支付
+	OtherAccount        string `json:"other_account,omitempty"`          // 可选	128 对方账户 张*(tes***@sandbox.com)
+	TransMemo           string `json:"trans_memo,omitempty"`             // 可选	1024 收入或者支出的备注 转账
+	Direction           string `json:"direction,omitempty"`              // 必选	8 收支方向，收入、支出 收入
+	BillSource          string `json:"bill_source,omitempty"`            // 可选	64 业务账单来源 其他
+	BizNos              string `json:"biz_nos,omitempty"`                // 可选	256 业务订单号，多个用竖线分隔 20190101***|TX***
+	BizOrigNo           string `json:"biz_orig_no,omitempty"`            // 可选	64 业务基础订单号 1***
+	BizDesc             string `json:"biz_desc,omitempty"`               // 可选	256 业务描述 在线支付
+	MerchantOutRefundNo string `json:"merchant_out_refund_no,omitempty"` // 可选	64 商户退款请求号 RF***
+	ComplementInfo      string `json:"complement_info,omitempty"`        // 可选	1024 补充信息，json格式 {}
+	StoreName           string `json:"store_name,omitempty"`             // 可选	256 门店名称 杭州西湖店
+}
+
+// /////////////////////////////////////////////
+var _ IAliPayRequest = &DataBillSellQueryReq{}
+
+type DataBillSellQueryReq struct {
+	StartTime       string `json:"start_time" validate:"required,max=20"`         // 必选	20 交易创建时间的起始范围 2019-01-01 00:00:00
+	EndTime         string `json:"end_time" validate:"required,max=20"`           // 必选	20 交易创建时间的结束范围，与起始时间间隔不超过31天，查询结果为左闭右开区间 2019-01-02 00:00:00
+	AlipayOrderNo   string `json:"alipay_order_no,omitempty" validate:"max=64"`   // 可选	64 支付宝交易号 20190101***
+	MerchantOrderNo string `json:"merchant_order_no,omitempty" validate:"max=64"` // 可选	64 商户订单号 TX***
+	StoreNo         string `json:"store_no,omitempty" validate:"max=64"`          // 可选	64 门店编号 STORE_001
+	PageNo          string `json:"page_no,omitempty" validate:"max=16"`           // 可选	16 分页号，从1开始 1
+	PageSize        string `json:"page_size,omitempty" validate:"max=16"`         // 可选	16 分页大小，最大2000 2000
+	BillUserId      string `json:"bill_user_id,omitempty" validate:"max=16"`      // 可选	16 目标查询账户，查询自身时不需要传递 2088123456789012
+	baseAliPayRequest
+}
+
+func (r *DataBillSellQueryReq) RequestApi() string {
+	return "alipay.data.bill.sell.query"
+}
+
+func (r *DataBillSellQueryReq) DoValidate() error {
+	return ValidateStruct(r)
+}
+
+type DataBillSellQueryRes struct {
+	DataBillSellQueryResContent `json:"alipay_data_bill_sell_query_response"`
+	SignCertSn
+}
+
+func (r *DataBillSellQueryRes) String() string {
+	buff, _ := json.Marshal(r)
+	return string(buff)
+}
+
+type DataBillSellQueryResContent struct {
+	CommonRes
+	PageNo     string           `json:"page_no"`     // 必选	16 分页号 1
+	PageSize   string           `json:"page_size"`   // 必选	16 分页大小 2000
+	TotalSize  string           `json:"total_size"`  // 必选	16 交易总数 10000
+	DetailList []*TradeBillItem `json:"detail_list"` // 可选 交易明细列表
+}
+
+type TradeBillItem struct {
+	GmtCreate         string `json:"gmt_create,omitempty"`          // 必选	20 交易创建时间 2019-01-01 00:00:00
+	GmtPay            string `json:"gmt_pay,omitempty"`             // 可选	20 交易支付时间 2019-01-01 00:00:00
+	GmtRefund         string `json:"gmt_refund,omitempty"`          // 可选	20 交易退款时间 2019-01-01 00:00:00
+	AlipayOrderNo     string `json:"alipay_order_no,omitempty"`     // 必选	64 支付宝交易号 20190101***
+	MerchantOrderNo   string `json:"merchant_order_no,omitempty"`   // 必选	64 商户订单号 TX***
+	TotalAmount       Money  `json:"total_amount,omitempty"`        // 必选	32 订单金额 10.00
+	RefundAmount      Money  `json:"refund_amount,omitempty"`       // 可选	32 退款金额 1.00
+	OrderStatus       string `json:"order_status,omitempty"`        // 必选	32 订单状态，成功、关闭、等待付款 成功
+	OrderType         string `json:"order_type,omitempty"`          // 必选	32 订单类型 即时到账交易
+	GoodsTitle        string `json:"goods_title,omitempty"`         // 可选	256 商品名称 Iphone6 16G
+	GoodsMemo         string `json:"goods_memo,omitempty"`          // 可选	256 商品备注 测试
+	OtherAccount      string `json:"other_account,omitempty"`       // 可选	128 对方账户 张*(tes***@sandbox.com)
+	StoreNo           string `json:"store_no,omitempty"`            // 可选	64 门店编号 STORE_001
+	StoreName         string `json:"store_name,omitempty"`          // 可选	256 门店名称 杭州西湖店
+	MerchantRefundNo  string `json:"merchant_refund_no,omitempty"`  // 可选	64 商户退款请求号 RF***
+	TradeRefundAmount Money  `json:"trade_refund_amount,omitempty"` // 可选	32 交易累计退款金额 1.00
+}
+
+// /////////////////////////////////////////////
+var _ IAliPayRequest = &DataBillBuyQueryReq{}
+
+type DataBillBuyQueryReq struct {
+	StartTime       string `json:"start_time" validate:"required,max=20"`         // 必选	20 交易创建时间的起始范围 2019-01-01 00:00:00
+	EndTime         string `json:"end_time" validate:"required,max=20"`           // 必选	20 交易创建时间的结束范围，与起始时间间隔不超过31天，查询结果为左闭右开区间 2019-01-02 00:00:00
+	AlipayOrderNo   string `json:"alipay_order_no,omitempty" validate:"max=64"`   // 可选	64 支付宝交易号 20190101***
+	MerchantOrderNo string `json:"merchant_order_no,omitempty" validate:"max=64"` // 可选	64 商户订单号 TX***
+	PageNo          string `json:"page_no,omitempty" validate:"max=16"`           // 可选	16 分页号，从1开始 1
+	PageSize        string `json:"page_size,omitempty" validate:"max=16"`         // 可选	16 分页大小，最大2000 2000
+	BillUserId      string `json:"bill_user_id,omitempty" validate:"max=16"`      // 可选	16 目标查询账户，查询自身时不需要传递 2088123456789012
+	baseAliPayRequest
+}
+
+func (r *DataBillBuyQueryReq) RequestApi() string {
+	return "alipay.data.bill.buy.query"
+}
+
+func (r *DataBillBuyQueryReq) DoValidate() error {
+	return ValidateStruct(r)
+}
+
+type DataBillBuyQueryRes struct {
+	DataBillBuyQueryResContent `json:"alipay_data_bill_buy_query_response"`
+	SignCertSn
+}
+
+func (r *DataBillBuyQueryRes) String() string {
+	buff, _ := json.Marshal(r)
+	return string(buff)
+}
+
+type DataBillBuyQueryResContent struct {
+	CommonRes
+	PageNo     string           `json:"page_no"`     // 必选	16 分页号 1
+	PageSize   string           `json:"page_size"`   // 必选	16 分页大小 2000
+	TotalSize  string           `json:"total_size"`  // 必选	16 交易总数 10000
+	DetailList []*TradeBillItem `json:"detail_list"` // 可选 交易明细列表
+}
+
+// /////////////////////////////////////////////
+var _ IAliPayRequest = &DataBillTransferQueryReq{}
+
+type DataBillTransferQueryReq struct {
+	StartTime  string `json:"start_time" validate:"required,max=20"`    // 必选	20 转账创建时间的起始范围 2019-01-01 00:00:00
+	EndTime    string `json:"end_time" validate:"required,max=20"`      // 必选	20 转账创建时间的结束范围，与起始时间间隔不超过31天，查询结果为左闭右开区间 2019-01-02 00:00:00
+	Type       string `json:"type,omitempty" validate:"max=32"`         // 可选	32 转账类型，不传时查询全部 TRANSFER_OUT
+	PageNo     string `json:"page_no,omitempty" validate:"max=16"`      // 可选	16 分页号，从1开始 1
+	PageSize   string `json:"page_size,omitempty" validate:"max=16"`    // 可选	16 分页大小，最大2000 2000
+	BillUserId string `json:"bill_user_id,omitempty" validate:"max=16"` // 可选	16 目标查询账户，查询自身时不需要传递 2088123456789012
+	baseAliPayRequest
+}
+
+func (r *DataBillTransferQueryReq) RequestApi() string {
+	return "alipay.data.bill.transfer.query"
+}
+
+func (r *DataBillTransferQueryReq) DoValidate() error {
+	return ValidateStruct(r)
+}
+
+type DataBillTransferQueryRes struct {
+	DataBillTransferQueryResContent `json:"alipay_data_bill_transfer_query_response"`
+	SignCertSn
+}
+
+func (r *DataBillTransferQueryRes) String() string {
+	buff, _ := json.Marshal(r)
+	return string(buff)
+}
+
+type DataBillTransferQueryResContent struct {
+	CommonRes
+	PageNo     string              `json:"page_no"`     // 必选	16 分页号 1
+	PageSize   string              `json:"page_size"`   // 必选	16 分页大小 2000
+	TotalSize  string              `json:"total_size"`  // 必选	16 转账总数 10000
+	DetailList []*TransferBillItem `json:"detail_list"` // 可选 转账明细列表
+}
+
+type TransferBillItem struct {
+	TransDt         string `json:"trans_dt,omitempty"`          // 必选	20 转账时间 2019-01-01 00:00:00
+	AlipayOrderNo   string `json:"alipay_order_no,omitempty"`   // 必选	64 支付宝转账单号 20190101***
+	MerchantOrderNo string `json:"merchant_order_no,omitempty"` // 可选	64 商户转账单号 TX***
+	AccountLogId    string `json:"account_log_id,omitempty"`    // 可选	64 支付宝账务流水号 2019111311001004170000000001
+	Type            string `json:"type,omitempty"`              // 必选	32 转账类型 TRANSFER_OUT
+	Amount          Money  `json:"amount,omitempty"`            // 必选	32 转账金额 10.00
+	Status          string `json:"status,omitempty"`            // 必选	32 转账状态 SUCCESS
+	OtherAccount    string `json:"other_account,omitempty"`     // 可选	128 对方账户 张*(tes***@sandbox.com)
+	TransMemo       string `json:"trans_memo,omitempty"`        // 可选	1024 转账备注 工资
+}
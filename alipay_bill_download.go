@@ -0,0 +1,505 @@
+package alipay
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/transform"
+)
+
+/**
+ * @author: Sam
+ * @since: 1.0.0
+ * @date: 2026/10/19 20:10
+ * @desc: 对账单下载及解析
+ *
+ * 账单是一个zip压缩包，包含明细和汇总两个GBK编码的csv文件，明细文件的格式如下：
+ * #支付宝业务明细查询
+ * #账号：[20881234567890120156]
+ * #起始日期：[2023年03月15日 00:00:00]   终止日期：[2023年03月16日 00:00:00]
+ * #-----------------------------------------业务明细列表----------------------------------------
+ * 支付宝交易号,商户订单号,业务类型,...
+ * 2023031522001446881439390001	,20230315170140	,交易	,...
+ * #-----------------------------------------业务明细列表结束------------------------------------
+ * #交易合计：1笔，商家实收：￥88.88元，商家优惠：￥0.00元
+ * #退款合计：0笔，商家实收：￥0.00元，商家优惠：￥0.00元
+ * #导出时间：[2023年03月16日 10:21:01]
+ *
+ * 下载的压缩包先写入临时文件，明细逐行解码，内存占用与账单大小无关。
+ */
+
+const (
+	BillTypeTrade        = "trade"        // 业务账单
+	BillTypeSignCustomer = "signcustomer" // 账务账单
+)
+
+var ErrBillDetailNotFound = errors.New("xpay: bill detail file not found")
+
+// BillDownloader 对账单下载，使用客户端的httpClient下载
+type BillDownloader struct {
+	client *Client
+	// TempDir 下载文件使用的临时目录，为空时使用系统临时目录
+	TempDir string
+}
+
+func NewBillDownloader(client *Client) *BillDownloader {
+	return &BillDownloader{client: client}
+}
+
+// Download 查询账单下载地址并下载，使用完毕后需要调用 BillFile.Close 删除临时文件
+func (r *BillDownloader) Download(ctx context.Context, req DataServiceBillDownloadUrlQueryReq) (*BillFile, error) {
+	res, err := r.client.DataServiceBillDownloadUrlQuery(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if res.Fail() {
+		return nil, fmt.Errorf("xpay: bill download url query failed, sub_code: %s, sub_msg: %s", res.SubCode, res.SubMsg)
+	}
+	return r.DownloadURL(ctx, res.BillDownloadUrl)
+}
+
+// DownloadURL 下载账单，下载地址获取后30秒内有效
+func (r *BillDownloader) DownloadURL(ctx context.Context, billURL string) (*BillFile, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, billURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := r.client.httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("xpay: bill download failed, status: %s", response.Status)
+	}
+	file, err := os.CreateTemp(r.TempDir, "alipay_bill_*.zip")
+	if err != nil {
+		return nil, err
+	}
+	if _, err = io.Copy(file, response.Body); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return nil, err
+	}
+	if err = file.Close(); err != nil {
+		os.Remove(file.Name())
+		return nil, err
+	}
+	billFile, err := OpenBillFile(file.Name())
+	if err != nil {
+		os.Remove(file.Name())
+		return nil, err
+	}
+	billFile.temporary = true
+	return billFile, nil
+}
+
+// BillFile 账单压缩包
+type BillFile struct {
+	path      string
+	temporary bool
+	reader    *zip.ReadCloser
+}
+
+// OpenBillFile 打开已下载的账单压缩包
+func OpenBillFile(path string) (*BillFile, error) {
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	return &BillFile{path: path, reader: reader}, nil
+}
+
+// Names 压缩包内的文件名
+func (r *BillFile) Names() []string {
+	names := make([]string, 0, len(r.reader.File))
+	for _, file := range r.reader.File {
+		names = append(names, billFileName(file))
+	}
+	return names
+}
+
+// TradeRecords 业务账单明细
+func (r *BillFile) TradeRecords() (*TradeBillReader, error) {
+	reader, err := r.openDetail()
+	if err != nil {
+		return nil, err
+	}
+	return &TradeBillReader{billReader: newBillReader(reader, reader)}, nil
+}
+
+// SignCustomerRecords 账务账单明细
+func (r *BillFile) SignCustomerRecords() (*SignCustomerBillReader, error) {
+	reader, err := r.openDetail()
+	if err != nil {
+		return nil, err
+	}
+	return &SignCustomerBillReader{billReader: newBillReader(reader, reader)}, nil
+}
+
+// Close 关闭压缩包，下载的临时文件会被删除
+func (r *BillFile) Close() error {
+	err := r.reader.Close()
+	if r.temporary {
+		if removeErr := os.Remove(r.path); err == nil {
+			err = removeErr
+		}
+	}
+	return err
+}
+
+// openDetail 打开明细文件，汇总文件名包含"汇总"
+func (r *BillFile) openDetail() (io.ReadCloser, error) {
+	for _, file := range r.reader.File {
+		name := billFileName(file)
+		if strings.HasSuffix(name, ".csv") && !strings.Contains(name, "汇总") {
+			return file.Open()
+		}
+	}
+	return nil, ErrBillDetailNotFound
+}
+
+// billFileName 压缩包内的文件名通常为GBK编码
+func billFileName(file *zip.File) string {
+	if utf8.ValidString(file.Name) {
+		return file.Name
+	}
+	name, _, err := transform.String(simplifiedchinese.GBK.NewDecoder(), file.Name)
+	if err != nil {
+		return file.Name
+	}
+	return name
+}
+
+// TradeBillRecord 业务账单明细
+type TradeBillRecord struct {
+	TradeNo                 string `bill:"支付宝交易号"`
+	OutTradeNo              string `bill:"商户订单号"`
+	BizType                 string `bill:"业务类型"` // 交易、退款
+	Subject                 string `bill:"商品名称"`
+	GmtCreate               string `bill:"创建时间"`
+	GmtFinish               string `bill:"完成时间"`
+	StoreId                 string `bill:"门店编号"`
+	StoreName               string `bill:"门店名称"`
+	OperatorId              string `bill:"操作员"`
+	TerminalId              string `bill:"终端号"`
+	BuyerLogonId            string `bill:"对方账户"`
+	TotalAmount             Money  `bill:"订单金额（元）"`
+	ReceiptAmount           Money  `bill:"商家实收（元）"`
+	AlipayRedPacketAmount   Money  `bill:"支付宝红包（元）"`
+	PointAmount             Money  `bill:"集分宝（元）"`
+	AlipayDiscountAmount    Money  `bill:"支付宝优惠（元）"`
+	MerchantDiscountAmount  Money  `bill:"商家优惠（元）"`
+	VoucherAmount           Money  `bill:"券核销金额（元）"`
+	VoucherName             string `bill:"券名称"`
+	MerchantRedPacketAmount Money  `bill:"商家红包消费金额（元）"`
+	CardAmount              Money  `bill:"卡消费金额（元）"`
+	OutRequestNo            string `bill:"退款批次号/请求号"`
+	ServiceFee              Money  `bill:"服务费（元）"`
+	ShareAmount             Money  `bill:"分润（元）"`
+	Remark                  string `bill:"备注"`
+}
+
+// SignCustomerBillRecord 账务账单明细
+type SignCustomerBillRecord struct {
+	AccountLogId string `bill:"账务流水号"`
+	BizNo        string `bill:"业务流水号"`
+	OutTradeNo   string `bill:"商户订单号"`
+	Subject      string `bill:"商品名称"`
+	TransDate    string `bill:"发生时间"`
+	OtherAccount string `bill:"对方账号"`
+	InAmount     Money  `bill:"收入金额（+元）"`
+	OutAmount    Money  `bill:"支出金额（-元）"`
+	Balance      Money  `bill:"账户余额（元）"`
+	Channel      string `bill:"交易渠道"`
+	BizType      string `bill:"业务类型"`
+	Remark       string `bill:"备注"`
+}
+
+// BillInfo 账单头部信息
+type BillInfo struct {
+	Account    string // 账号
+	StartTime  string // 起始日期
+	EndTime    string // 终止日期
+	ExportTime string // 导出时间
+}
+
+// TradeBillSummary 业务账单合计
+type TradeBillSummary struct {
+	BillInfo
+	TradeCount                   int   // 交易笔数
+	TradeReceiptAmount           Money // 交易商家实收
+	TradeMerchantDiscountAmount  Money // 交易商家优惠
+	RefundCount                  int   // 退款笔数
+	RefundReceiptAmount          Money // 退款商家实收，为负数
+	RefundMerchantDiscountAmount Money // 退款商家优惠
+}
+
+// SignCustomerBillSummary 账务账单合计
+type SignCustomerBillSummary struct {
+	BillInfo
+	InCount   int   // 收入笔数
+	InAmount  Money // 收入金额
+	OutCount  int   // 支出笔数
+	OutAmount Money // 支出金额，为负数
+}
+
+var (
+	billAccountPattern = regexp.MustCompile(`账号：\[(.*?)]`)
+	billDatePattern    = regexp.MustCompile(`起始日期：\[(.*?)]\s*终止日期：\[(.*?)]`)
+	billExportPattern  = regexp.MustCompile(`导出时间：\[(.*?)]`)
+	billTradePattern   = regexp.MustCompile(`交易合计：(\d+)笔，商家实收：￥?(-?[\d.]+)元(?:，商家优惠：￥?(-?[\d.]+)元)?`)
+	billRefundPattern  = regexp.MustCompile(`退款合计：(\d+)笔，商家实收：￥?(-?[\d.]+)元(?:，商家优惠：￥?(-?[\d.]+)元)?`)
+	billInPattern      = regexp.MustCompile(`收入合计：(\d+)笔，共(-?[\d.]+)元`)
+	billOutPattern     = regexp.MustCompile(`支出合计：(\d+)笔，共(-?[\d.]+)元`)
+)
+
+// billMaxComments 最多保留的头部信息及合计行数
+const billMaxComments = 64
+
+// billReader 逐行读取明细，#开头的行为头部信息和合计，第一个非#开头的行为表头
+type billReader struct {
+	closer   io.Closer
+	csv      *csv.Reader
+	columns  []string
+	fields   []int // 结构体字段对应的列，-1表示账单中没有该列
+	comments []string
+	line     int // 当前记录的起始行号
+	lines    int // 已读取的行数，按记录及字段中的换行计数，不含csv跳过的空行
+	err      error
+}
+
+func newBillReader(reader io.Reader, closer io.Closer) billReader {
+	csvReader := csv.NewReader(transform.NewReader(reader, simplifiedchinese.GBK.NewDecoder()))
+	csvReader.FieldsPerRecord = -1
+	csvReader.LazyQuotes = true
+	csvReader.ReuseRecord = true
+	return billReader{closer: closer, csv: csvReader}
+}
+
+// next 读取下一条明细写入record，record为结构体指针
+func (r *billReader) next(record interface{}) bool {
+	if r.err != nil {
+		return false
+	}
+	for {
+		fields, err := r.csv.Read()
+		if err == io.EOF {
+			return false
+		}
+		if err != nil {
+			r.err = err
+			return false
+		}
+		r.line = r.lines + 1
+		for _, field := range fields {
+			r.lines += strings.Count(field, "\n")
+		}
+		r.lines++
+		if strings.HasPrefix(fields[0], "#") {
+			// 分隔行不包含"："，不需要保留
+			if comment := strings.Join(fields, ","); strings.Contains(comment, "：") && len(r.comments) < billMaxComments {
+				r.comments = append(r.comments, comment)
+			}
+			continue
+		}
+		if r.columns == nil {
+			r.columns = make([]string, len(fields))
+			for i, field := range fields {
+				r.columns[i] = strings.TrimSpace(field)
+			}
+			continue
+		}
+		if r.err = r.decode(fields, record); r.err != nil {
+			return false
+		}
+		return true
+	}
+}
+
+func (r *billReader) decode(fields []string, record interface{}) error {
+	value := reflect.ValueOf(record).Elem()
+	if r.fields == nil {
+		valueType := value.Type()
+		r.fields = make([]int, valueType.NumField())
+		for i := range r.fields {
+			r.fields[i] = r.columnIndex(valueType.Field(i).Tag.Get("bill"))
+		}
+	}
+	for i, index := range r.fields {
+		if index < 0 || index >= len(fields) {
+			continue
+		}
+		field := strings.TrimSpace(fields[index])
+		if len(field) == 0 {
+			continue
+		}
+		if value.Field(i).Type() != moneyType {
+			value.Field(i).SetString(field)
+			continue
+		}
+		money, err := ParseMoney(field)
+		if err != nil {
+			return fmt.Errorf("xpay: bill line %d column %s: %w", r.line, r.columns[index], err)
+		}
+		value.Field(i).SetInt(int64(money))
+	}
+	return nil
+}
+
+func (r *billReader) columnIndex(name string) int {
+	for i, column := range r.columns {
+		if column == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *billReader) info() BillInfo {
+	var info BillInfo
+	for _, comment := range r.comments {
+		if match := billAccountPattern.FindStringSubmatch(comment); match != nil {
+			info.Account = match[1]
+		}
+		if match := billDatePattern.FindStringSubmatch(comment); match != nil {
+			info.StartTime, info.EndTime = match[1], match[2]
+		}
+		if match := billExportPattern.FindStringSubmatch(comment); match != nil {
+			info.ExportTime = match[1]
+		}
+	}
+	return info
+}
+
+// total 解析合计行，返回笔数及金额
+func (r *billReader) total(pattern *regexp.Regexp) (int, []Money, error) {
+	for _, comment := range r.comments {
+		match := pattern.FindStringSubmatch(comment)
+		if match == nil {
+			continue
+		}
+		count, err := strconv.Atoi(match[1])
+		if err != nil {
+			return 0, nil, err
+		}
+		amounts := make([]Money, len(match)-2)
+		for i, amount := range match[2:] {
+			if len(amount) == 0 {
+				continue
+			}
+			if amounts[i], err = ParseMoney(amount); err != nil {
+				return 0, nil, err
+			}
+		}
+		return count, amounts, nil
+	}
+	return 0, make([]Money, pattern.NumSubexp()-1), nil
+}
+
+// Err 读取过程中的错误
+func (r *billReader) Err() error {
+	return r.err
+}
+
+func (r *billReader) Close() error {
+	return r.closer.Close()
+}
+
+// TradeBillReader 业务账单明细
+type TradeBillReader struct {
+	billReader
+	record *TradeBillRecord
+}
+
+// NewTradeBillReader 从GBK编码的明细文件读取
+func NewTradeBillReader(reader io.ReadCloser) *TradeBillReader {
+	return &TradeBillReader{billReader: newBillReader(reader, reader)}
+}
+
+// Next 读取下一条明细，没有更多明细或者出错时返回false
+func (r *TradeBillReader) Next() bool {
+	record := new(TradeBillRecord)
+	if !r.next(record) {
+		r.record = nil
+		return false
+	}
+	r.record = record
+	return true
+}
+
+// Record 当前明细
+func (r *TradeBillReader) Record() *TradeBillRecord {
+	return r.record
+}
+
+// Summary 账单合计，合计位于文件末尾，读取完全部明细后才能获取
+func (r *TradeBillReader) Summary() (*TradeBillSummary, error) {
+	summary := &TradeBillSummary{BillInfo: r.info()}
+	var amounts []Money
+	var err error
+	if summary.TradeCount, amounts, err = r.total(billTradePattern); err != nil {
+		return nil, err
+	}
+	summary.TradeReceiptAmount, summary.TradeMerchantDiscountAmount = amounts[0], amounts[1]
+	if summary.RefundCount, amounts, err = r.total(billRefundPattern); err != nil {
+		return nil, err
+	}
+	summary.RefundReceiptAmount, summary.RefundMerchantDiscountAmount = amounts[0], amounts[1]
+	return summary, nil
+}
+
+// SignCustomerBillReader 账务账单明细
+type SignCustomerBillReader struct {
+	billReader
+	record *SignCustomerBillRecord
+}
+
+// NewSignCustomerBillReader 从GBK编码的明细文件读取
+func NewSignCustomerBillReader(reader io.ReadCloser) *SignCustomerBillReader {
+	return &SignCustomerBillReader{billReader: newBillReader(reader, reader)}
+}
+
+// Next 读取下一条明细，没有更多明细或者出错时返回false
+func (r *SignCustomerBillReader) Next() bool {
+	record := new(SignCustomerBillRecord)
+	if !r.next(record) {
+		r.record = nil
+		return false
+	}
+	r.record = record
+	return true
+}
+
+// Record 当前明细
+func (r *SignCustomerBillReader) Record() *SignCustomerBillRecord {
+	return r.record
+}
+
+// Summary 账单合计，合计位于文件末尾，读取完全部明细后才能获取
+func (r *SignCustomerBillReader) Summary() (*SignCustomerBillSummary, error) {
+	summary := &SignCustomerBillSummary{BillInfo: r.info()}
+	var amounts []Money
+	var err error
+	if summary.InCount, amounts, err = r.total(billInPattern); err != nil {
+		return nil, err
+	}
+	summary.InAmount = amounts[0]
+	if summary.OutCount, amounts, err = r.total(billOutPattern); err != nil {
+		return nil, err
+	}
+	summary.OutAmount = amounts[0]
+	return summary, nil
+}
@@ -0,0 +1,253 @@
+package alipay_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	. "github.com/try-labs/xpay"
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+/**
+ * @author: Sam
+ * @since:
+ * @date: 2026/10/19 20:30
+ * @desc:
+ */
+
+const tradeBillHeader = `#支付宝业务明细查询
+#账号：[20881021774994500156]
+#起始日期：[2026年10月18日 00:00:00]   终止日期：[2026年10月19日 00:00:00]
+#-----------------------------------------业务明细列表----------------------------------------
+支付宝交易号,商户订单号,业务类型,商品名称,创建时间,完成时间,门店编号,门店名称,操作员,终端号,对方账户,订单金额（元）,商家实收（元）,支付宝红包（元）,集分宝（元）,支付宝优惠（元）,商家优惠（元）,券核销金额（元）,券名称,商家红包消费金额（元）,卡消费金额（元）,退款批次号/请求号,服务费（元）,分润（元）,备注
+`
+
+const tradeBillFooter = `#-----------------------------------------业务明细列表结束------------------------------------
+#交易合计：%d笔，商家实收：￥%s元，商家优惠：￥0.00元
+#退款合计：%d笔，商家实收：￥%s元，商家优惠：￥0.00元
+#导出时间：[2026年10月19日 09:21:01]
+`
+
+func gbk(t *testing.T, s string) []byte {
+	t.Helper()
+	buff, err := simplifiedchinese.GBK.NewEncoder().Bytes([]byte(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buff
+}
+
+// billZip 与支付宝一致，文件名和内容均为GBK编码
+func billZip(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buff bytes.Buffer
+	writer := zip.NewWriter(&buff)
+	for name, content := range files {
+		file, err := writer.CreateHeader(&zip.FileHeader{Name: string(gbk(t, name)), Method: zip.Deflate, NonUTF8: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = file.Write(gbk(t, content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buff.Bytes()
+}
+
+func downloadBill(t *testing.T, billType, billDate string) *BillFile {
+	t.Helper()
+	downloader := NewBillDownloader(client)
+	downloader.TempDir = t.TempDir()
+	billFile, err := downloader.Download(context.Background(), DataServiceBillDownloadUrlQueryReq{BillType: billType, BillDate: billDate})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return billFile
+}
+
+func TestBillDownloader_TradeBill(t *testing.T) {
+	detail := tradeBillHeader +
+		"2026101822001446881439390001	,20261018000001	,交易	,测试商品	,2026-10-18 10:00:00,2026-10-18 10:00:05,,,,,tes***@sandbox.com	,88.88,88.88,0.00,0.00,0.00,0.00,0.00,,0.00,0.00,,-0.53,0.00,\n" +
+		"2026101822001446881439390002	,20261018000002	,交易	,\"商品,带逗号\",2026-10-18 11:00:00,2026-10-18 11:00:05,,,,,tes***@sandbox.com	,11.12,11.12,0.00,0.00,0.00,0.00,0.00,,0.00,0.00,,-0.07,0.00,\n" +
+		"2026101822001446881439390001	,20261018000001	,退款	,测试商品	,2026-10-18 10:00:00,2026-10-18 12:00:00,,,,,tes***@sandbox.com	,88.88,-8.88,0.00,0.00,0.00,0.00,0.00,,0.00,0.00,20261018000001-1	,0.05,0.00,部分退款\n" +
+		fmt.Sprintf(tradeBillFooter, 2, "100.00", 1, "-8.88")
+	gateway.SetBill(BillTypeTrade, "2026-10-18", billZip(t, map[string]string{
+		"20881021774994500156_20261018_业务明细.csv":     detail,
+		"20881021774994500156_20261018_业务明细(汇总).csv": "#支付宝业务汇总查询\n",
+	}))
+	billFile := downloadBill(t, BillTypeTrade, "2026-10-18")
+	names := strings.Join(billFile.Names(), ";")
+	if !strings.Contains(names, "业务明细.csv") || !strings.Contains(names, "业务明细(汇总).csv") {
+		t.Errorf("unexpected names: %s", names)
+	}
+	reader, err := billFile.TradeRecords()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var records []*TradeBillRecord
+	var receipt Money
+	for reader.Next() {
+		records = append(records, reader.Record())
+		receipt = receipt.Add(reader.Record().ReceiptAmount)
+	}
+	if err = reader.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("unexpected records: %d", len(records))
+	}
+	first, refund := records[0], records[2]
+	if first.TradeNo != "2026101822001446881439390001" || first.BizType != "交易" || first.TotalAmount != MustParseMoney("88.88") || first.ServiceFee != MustParseMoney("-0.53") {
+		t.Errorf("unexpected record: %+v", first)
+	}
+	if records[1].Subject != "商品,带逗号" {
+		t.Errorf("unexpected subject: %s", records[1].Subject)
+	}
+	if refund.BizType != "退款" || refund.ReceiptAmount != MustParseMoney("-8.88") || refund.OutRequestNo != "20261018000001-1" || refund.Remark != "部分退款" {
+		t.Errorf("unexpected refund: %+v", refund)
+	}
+	summary, err := reader.Summary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Account != "20881021774994500156" || summary.StartTime != "2026年10月18日 00:00:00" || summary.ExportTime != "2026年10月19日 09:21:01" {
+		t.Errorf("unexpected info: %+v", summary.BillInfo)
+	}
+	if summary.TradeCount != 2 || summary.TradeReceiptAmount != MustParseMoney("100.00") || summary.RefundCount != 1 || summary.RefundReceiptAmount != MustParseMoney("-8.88") {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+	if receipt != summary.TradeReceiptAmount.Add(summary.RefundReceiptAmount) {
+		t.Errorf("receipt %s does not match summary", receipt)
+	}
+	reader.Close()
+	if err = billFile.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBillDownloader_SignCustomerBill(t *testing.T) {
+	detail := `#支付宝账务明细查询
+#账号：[20881021774994500156]
+#起始日期：[2026年10月17日 00:00:00]   终止日期：[2026年10月18日 00:00:00]
+#-----------------------------------------账务明细列表----------------------------------------
+账务流水号,业务流水号,商户订单号,商品名称,发生时间,对方账号,收入金额（+元）,支出金额（-元）,账户余额（元）,交易渠道,业务类型,备注
+314990001	,2026101722001446881439390001	,20261017000001	,测试商品	,2026-10-17 10:00:05	,tes***@sandbox.com	,88.88	,	,1088.88	,支付宝	,在线支付	,
+314990002	,2026101722001446881439390001	,20261017000001	,测试商品	,2026-10-17 10:00:05	,支付宝（中国）网络技术有限公司	,	,-0.53	,1088.35	,支付宝	,交易服务费	,
+#-----------------------------------------账务明细列表结束------------------------------------
+#支出合计：1笔，共-0.53元
+#收入合计：1笔，共88.88元
+#导出时间：[2026年10月18日 09:21:01]
+`
+	gateway.SetBill(BillTypeSignCustomer, "2026-10-17", billZip(t, map[string]string{"20881021774994500156_20261017_账务明细.csv": detail}))
+	billFile := downloadBill(t, BillTypeSignCustomer, "2026-10-17")
+	defer billFile.Close()
+	reader, err := billFile.SignCustomerRecords()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+	var records []*SignCustomerBillRecord
+	for reader.Next() {
+		records = append(records, reader.Record())
+	}
+	if err = reader.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[0].InAmount != MustParseMoney("88.88") || records[1].OutAmount != MustParseMoney("-0.53") || records[1].Balance != MustParseMoney("1088.35") || records[1].BizType != "交易服务费" {
+		t.Errorf("unexpected records: %+v %+v", records[0], records[1])
+	}
+	summary, err := reader.Summary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.InCount != 1 || summary.InAmount != MustParseMoney("88.88") || summary.OutCount != 1 || summary.OutAmount != MustParseMoney("-0.53") {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+}
+
+func TestBillDownloader_LargeBill(t *testing.T) {
+	const count = 50000
+	var detail strings.Builder
+	detail.WriteString(tradeBillHeader)
+	for i := 0; i < count; i++ {
+		fmt.Fprintf(&detail, "20261016220014468814%08d	,2026101600%06d	,交易	,测试商品	,2026-10-16 10:00:00,2026-10-16 10:00:05,,,,,tes***@sandbox.com	,1.01,1.01,0.00,0.00,0.00,0.00,0.00,,0.00,0.00,,-0.01,0.00,\n", i, i)
+	}
+	fmt.Fprintf(&detail, tradeBillFooter, count, NewMoneyFromCents(101*count).String(), 0, "0.00")
+	gateway.SetBill(BillTypeTrade, "2026-10-16", billZip(t, map[string]string{"20881021774994500156_20261016_业务明细.csv": detail.String()}))
+	billFile := downloadBill(t, BillTypeTrade, "2026-10-16")
+	defer billFile.Close()
+	reader, err := billFile.TradeRecords()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+	rows := 0
+	var receipt Money
+	for reader.Next() {
+		rows++
+		receipt = receipt.Add(reader.Record().ReceiptAmount)
+	}
+	if err = reader.Err(); err != nil {
+		t.Fatal(err)
+	}
+	summary, err := reader.Summary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rows != count || summary.TradeCount != count || receipt != summary.TradeReceiptAmount {
+		t.Errorf("rows %d, receipt %s, summary %+v", rows, receipt, summary)
+	}
+}
+
+func TestTradeBillReader_InvalidAmount(t *testing.T) {
+	detail := tradeBillHeader + "2026101822001446881439390001	,20261018000001	,交易	,测试商品	,,,,,,,,abc,88.88\n"
+	reader := NewTradeBillReader(io.NopCloser(bytes.NewReader(gbk(t, detail))))
+	if reader.Next() {
+		t.Fatal("invalid amount should fail")
+	}
+	if err := reader.Err(); err == nil || !strings.Contains(err.Error(), "line 6 column 订单金额（元）") {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	// 商品名称跨行时按实际行号报错
+	detail = tradeBillHeader + "2026101822001446881439390001	,20261018000001	,交易	,\"测试\r\n商品\",,,,,,,,88.88,88.88\n" +
+		"2026101822001446881439390002	,20261018000002	,交易	,测试商品	,,,,,,,,abc,88.88\n"
+	reader = NewTradeBillReader(io.NopCloser(bytes.NewReader(gbk(t, detail))))
+	if !reader.Next() || reader.Record().Subject != "测试\n商品" {
+		t.Fatalf("unexpected record: %+v %v", reader.Record(), reader.Err())
+	}
+	if reader.Next() {
+		t.Fatal("invalid amount should fail")
+	}
+	if err := reader.Err(); err == nil || !strings.Contains(err.Error(), "line 8 column 订单金额（元）") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestBillDownloader_TempFileRemoved(t *testing.T) {
+	gateway.SetBill(BillTypeTrade, "2026-10-15", billZip(t, map[string]string{"20881021774994500156_20261015_业务明细.csv": tradeBillHeader}))
+	downloader := NewBillDownloader(client)
+	downloader.TempDir = t.TempDir()
+	billFile, err := downloader.Download(context.Background(), DataServiceBillDownloadUrlQueryReq{BillType: BillTypeTrade, BillDate: "2026-10-15"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = billFile.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := os.ReadDir(downloader.TempDir); len(entries) != 0 {
+		t.Errorf("temp file should be removed: %v", entries)
+	}
+	if _, err = downloader.Download(context.Background(), DataServiceBillDownloadUrlQueryReq{BillType: BillTypeTrade, BillDate: "2026-10-01"}); err == nil {
+		t.Error("bill not exist should fail")
+	}
+}
@@ -0,0 +1,296 @@
+package alipay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+/**
+ * @author: Sam
+ * @since: 1.0.0
+ * @date: 2026/10/20 01:40
+ * @desc: 账务明细及交易、转账账单的分页遍历
+ *
+ * alipay.data.bill.accountlog.query、sell.query、buy.query、transfer.query 的起止时间间隔不能超过31天，
+ * 每页最多2000条。迭代器将时间范围按 BillQueryMaxRange 拆分为多个左闭右开的时间窗口，每个窗口内按 page_no 逐页查询，
+ * 每次查询前检查ctx，ctx结束后 Next 返回false，Err 返回ctx的错误。
+ */
+
+const (
+	// BillQueryMaxRange 单次查询的最大时间跨度
+	BillQueryMaxRange = 31 * 24 * time.Hour
+	// BillQueryMaxPageSize 单页的最大条数，迭代器未指定 page_size 时使用
+	BillQueryMaxPageSize = 2000
+
+	billQueryTimeLayout = "2006-01-02 15:04:05"
+)
+
+var ErrBillQueryRange = errors.New("xpay: bill query end time must be after start time")
+
+// billWindow 一个查询时间窗口
+type billWindow struct {
+	start string
+	end   string
+}
+
+// billPager 按时间窗口和页码遍历账单查询接口
+type billPager struct {
+	ctx      context.Context
+	windows  []billWindow
+	pageNo   int
+	pageSize int
+	err      error
+}
+
+// billPageFetcher 查询时间窗口内的一页，返回本页条数及窗口内的总条数
+type billPageFetcher func(window billWindow, pageNo, pageSize int) (count, total int, err error)
+
+// newBillPager 将 [start, end) 按最大跨度拆分为时间窗口，时间按客户端的时区格式化
+func newBillPager(ctx context.Context, start, end time.Time, pageSize string, location *time.Location) *billPager {
+	pager := &billPager{ctx: ctx, pageSize: BillQueryMaxPageSize}
+	if size, err := strconv.Atoi(pageSize); err == nil && size > 0 {
+		pager.pageSize = size
+	}
+	if !end.After(start) {
+		pager.err = ErrBillQueryRange
+		return pager
+	}
+	start, end = start.In(location), end.In(location)
+	for windowStart := start; windowStart.Before(end); windowStart = windowStart.Add(BillQueryMaxRange) {
+		windowEnd := windowStart.Add(BillQueryMaxRange)
+		if windowEnd.After(end) {
+			windowEnd = end
+		}
+		pager.windows = append(pager.windows, billWindow{start: windowStart.Format(billQueryTimeLayout), end: windowEnd.Format(billQueryTimeLayout)})
+	}
+	return pager
+}
+
+// next 查询下一页，当前窗口读完后进入下一个窗口，返回false表示没有更多数据或者出错
+func (r *billPager) next(fetch billPageFetcher) bool {
+	for r.err == nil && len(r.windows) > 0 {
+		if err := r.ctx.Err(); err != nil {
+			r.err = err
+			return false
+		}
+		r.pageNo++
+		count, total, err := fetch(r.windows[0], r.pageNo, r.pageSize)
+		if err != nil {
+			r.err = err
+			return false
+		}
+		if count == 0 || r.pageNo*r.pageSize >= total {
+			r.windows, r.pageNo = r.windows[1:], 0
+		}
+		if count > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func billQueryFailed(api string, res *CommonRes) error {
+	return fmt.Errorf("xpay: %s failed, sub_code: %s, sub_msg: %s", api, res.SubCode, res.SubMsg)
+}
+
+// billTotalSize 解析 total_size，未返回时按本页是否已满判断是否还有下一页
+func billTotalSize(totalSize string, pageNo, pageSize, count int) int {
+	if total, err := strconv.Atoi(totalSize); err == nil {
+		return total
+	}
+	if count < pageSize {
+		return pageNo * pageSize
+	}
+	return pageNo*pageSize + 1
+}
+
+/////////////////////////////////////////////
+
+// AccountLogIterator 遍历账务明细，由 Client.AccountLogs 创建
+type AccountLogIterator struct {
+	client *Client
+	pager  *billPager
+	req    DataBillAccountLogQueryReq
+	list   []*AccountLogItem
+	detail *AccountLogItem
+}
+
+// Next 读取下一条明细，没有更多明细或者出错时返回false
+func (r *AccountLogIterator) Next() bool {
+	for len(r.list) == 0 {
+		if !r.pager.next(r.fetch) {
+			r.detail = nil
+			return false
+		}
+	}
+	r.detail, r.list = r.list[0], r.list[1:]
+	return true
+}
+
+func (r *AccountLogIterator) fetch(window billWindow, pageNo, pageSize int) (int, int, error) {
+	r.req.StartTime, r.req.EndTime = window.start, window.end
+	r.req.PageNo, r.req.PageSize = strconv.Itoa(pageNo), strconv.Itoa(pageSize)
+	res, err := r.client.DataBillAccountLogQuery(r.pager.ctx, r.req)
+	if err != nil {
+		return 0, 0, err
+	}
+	if res.Fail() {
+		return 0, 0, billQueryFailed(r.req.RequestApi(), &res.CommonRes)
+	}
+	r.list = res.DetailList
+	return len(res.DetailList), billTotalSize(res.TotalSize, pageNo, pageSize, len(res.DetailList)), nil
+}
+
+// Detail 当前明细
+func (r *AccountLogIterator) Detail() *AccountLogItem {
+	return r.detail
+}
+
+// Err 查询过程中的错误
+func (r *AccountLogIterator) Err() error {
+	return r.pager.err
+}
+
+/////////////////////////////////////////////
+
+// SellBillIterator 遍历商户作为卖家的交易明细，由 Client.SellBills 创建
+type SellBillIterator struct {
+	client *Client
+	pager  *billPager
+	req    DataBillSellQueryReq
+	list   []*TradeBillItem
+	detail *TradeBillItem
+}
+
+// Next 读取下一条明细，没有更多明细或者出错时返回false
+func (r *SellBillIterator) Next() bool {
+	for len(r.list) == 0 {
+		if !r.pager.next(r.fetch) {
+			r.detail = nil
+			return false
+		}
+	}
+	r.detail, r.list = r.list[0], r.list[1:]
+	return true
+}
+
+func (r *SellBillIterator) fetch(window billWindow, pageNo, pageSize int) (int, int, error) {
+	r.req.StartTime, r.req.EndTime = window.start, window.end
+	r.req.PageNo, r.req.PageSize = strconv.Itoa(pageNo), strconv.Itoa(pageSize)
+	res, err := r.client.DataBillSellQuery(r.pager.ctx, r.req)
+	if err != nil {
+		return 0, 0, err
+	}
+	if res.Fail() {
+		return 0, 0, billQueryFailed(r.req.RequestApi(), &res.CommonRes)
+	}
+	r.list = res.DetailList
+	return len(res.DetailList), billTotalSize(res.TotalSize, pageNo, pageSize, len(res.DetailList)), nil
+}
+
+// Detail 当前明细
+func (r *SellBillIterator) Detail() *TradeBillItem {
+	return r.detail
+}
+
+// Err 查询过程中的错误
+func (r *SellBillIterator) Err() error {
+	return r.pager.err
+}
+
+/////////////////////////////////////////////
+
+// BuyBillIterator 遍历商户作为买家的交易明细，由 Client.BuyBills 创建
+type BuyBillIterator struct {
+	client *Client
+	pager  *billPager
+	req    DataBillBuyQueryReq
+	list   []*TradeBillItem
+	detail *TradeBillItem
+}
+
+// Next 读取下一条明细，没有更多明细或者出错时返回false
+func (r *BuyBillIterator) Next() bool {
+	for len(r.list) == 0 {
+		if !r.pager.next(r.fetch) {
+			r.detail = nil
+			return false
+		}
+	}
+	r.detail, r.list = r.list[0], r.list[1:]
+	return true
+}
+
+func (r *BuyBillIterator) fetch(window billWindow, pageNo, pageSize int) (int, int, error) {
+	r.req.StartTime, r.req.EndTime = window.start, window.end
+	r.req.PageNo, r.req.PageSize = strconv.Itoa(pageNo), strconv.Itoa(pageSize)
+	res, err := r.client.DataBillBuyQuery(r.pager.ctx, r.req)
+	if err != nil {
+		return 0, 0, err
+	}
+	if res.Fail() {
+		return 0, 0, billQueryFailed(r.req.RequestApi(), &res.CommonRes)
+	}
+	r.list = res.DetailList
+	return len(res.DetailList), billTotalSize(res.TotalSize, pageNo, pageSize, len(res.DetailList)), nil
+}
+
+// Detail 当前明细
+func (r *BuyBillIterator) Detail() *TradeBillItem {
+	return r.detail
+}
+
+// Err 查询过程中的错误
+func (r *BuyBillIterator) Err() error {
+	return r.pager.err
+}
+
+/////////////////////////////////////////////
+
+// TransferBillIterator 遍历转账明细，由 Client.TransferBills 创建
+type TransferBillIterator struct {
+	client *Client
+	pager  *billPager
+	req    DataBillTransferQueryReq
+	list   []*TransferBillItem
+	detail *TransferBillItem
+}
+
+// Next 读取下一条明细，没有更多明细或者出错时返回false
+func (r *TransferBillIterator) Next() bool {
+	for len(r.list) == 0 {
+		if !r.pager.next(r.fetch) {
+			r.detail = nil
+			return false
+		}
+	}
+	r.detail, r.list = r.list[0], r.list[1:]
+	return true
+}
+
+func (r *TransferBillIterator) fetch(window billWindow, pageNo, pageSize int) (int, int, error) {
+	r.req.StartTime, r.req.EndTime = window.start, window.end
+	r.req.PageNo, r.req.PageSize = strconv.Itoa(pageNo), strconv.Itoa(pageSize)
+	res, err := r.client.DataBillTransferQuery(r.pager.ctx, r.req)
+	if err != nil {
+		return 0, 0, err
+	}
+	if res.Fail() {
+		return 0, 0, billQueryFailed(r.req.RequestApi(), &res.CommonRes)
+	}
+	r.list = res.DetailList
+	return len(res.DetailList), billTotalSize(res.TotalSize, pageNo, pageSize, len(res.DetailList)), nil
+}
+
+// Detail 当前明细
+func (r *TransferBillIterator) Detail() *TransferBillItem {
+	return r.detail
+}
+
+// Err 查询过程中的错误
+func (r *TransferBillIterator) Err() error {
+	return r.pager.err
+}
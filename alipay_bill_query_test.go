@@ -0,0 +1,107 @@
+package alipay_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/try-labs/xpay"
+	"github.com/try-labs/xpay/alipaytest"
+)
+
+/**
+ * @author: Sam
+ * @since:
+ * @date: 2026/10/20 01:40
+ * @desc:
+ */
+
+func TestClient_AccountLogs(t *testing.T) {
+	cst := time.FixedZone("CST", 8*3600)
+	now := time.Date(2026, 8, 1, 10, 0, 0, 0, cst)
+	g := alipaytest.NewGateway(alipaytest.WithLocation(cst), alipaytest.WithNow(func() time.Time { return now }))
+	defer g.Close()
+	billClient, err := g.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	// 8月1日起每10天一笔交易，共7笔，跨越3个31天的查询窗口
+	for i := 0; i < 7; i++ {
+		now = time.Date(2026, 8, 1+10*i, 10, 0, 0, 0, cst)
+		res, err := billClient.TradePay(ctx, TradePayReq{OutTradeNo: fmt.Sprintf("2026080100%02d", i), Scene: "bar_code", AuthCode: "28763443825664394", TotalAmount: MustParseMoney("10.00"), Subject: "账务明细"})
+		if err != nil || !res.Success() {
+			t.Fatalf("unexpected pay result: %+v %v", res, err)
+		}
+	}
+	now = now.Add(time.Hour)
+	if res, err := billClient.TradeRefund(ctx, TradeRefundReq{OutTradeNo: "202608010006", OutRequestNo: "202608010006R1", RefundAmount: MustParseMoney("3.00")}); err != nil || !res.Success() {
+		t.Fatalf("unexpected refund result: %+v %v", res, err)
+	}
+
+	start, end := time.Date(2026, 8, 1, 0, 0, 0, 0, cst), time.Date(2026, 10, 15, 0, 0, 0, 0, cst)
+	queries := len(g.Requests())
+	logs := billClient.AccountLogs(ctx, DataBillAccountLogQueryReq{PageSize: "2"}, start, end)
+	var merchantOrderNos []string
+	var amount Money
+	for logs.Next() {
+		merchantOrderNos = append(merchantOrderNos, logs.Detail().MerchantOrderNo)
+		amount = amount.Add(logs.Detail().TransAmount)
+	}
+	if err = logs.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(merchantOrderNos) != 8 || merchantOrderNos[0] != "202608010000" || merchantOrderNos[7] != "202608010006" || amount != MustParseMoney("67.00") {
+		t.Errorf("unexpected account logs: %v %s", merchantOrderNos, amount)
+	}
+	// 3个窗口分别有4、3、1条明细，每页2条
+	var windows []string
+	for _, req := range g.Requests()[queries:] {
+		var content DataBillAccountLogQueryReq
+		json.Unmarshal(req.BizContent, &content)
+		windows = append(windows, content.StartTime+"~"+content.EndTime+"#"+content.PageNo)
+	}
+	expected := []string{
+		"2026-08-01 00:00:00~2026-09-01 00:00:00#1", "2026-08-01 00:00:00~2026-09-01 00:00:00#2",
+		"2026-09-01 00:00:00~2026-10-02 00:00:00#1", "2026-09-01 00:00:00~2026-10-02 00:00:00#2",
+		"2026-10-02 00:00:00~2026-10-15 00:00:00#1",
+	}
+	if fmt.Sprint(windows) != fmt.Sprint(expected) {
+		t.Errorf("unexpected queries: %v", windows)
+	}
+	// 其他时区的时间按客户端的时区格式化
+	queries = len(g.Requests())
+	if logs = billClient.AccountLogs(ctx, DataBillAccountLogQueryReq{}, start.UTC(), end.UTC()); !logs.Next() {
+		t.Fatalf("unexpected error: %v", logs.Err())
+	}
+	var content DataBillAccountLogQueryReq
+	json.Unmarshal(g.Requests()[queries].BizContent, &content)
+	if content.StartTime != "2026-08-01 00:00:00" || content.EndTime != "2026-09-01 00:00:00" {
+		t.Errorf("unexpected window: %s~%s", content.StartTime, content.EndTime)
+	}
+
+	sells := billClient.SellBills(ctx, DataBillSellQueryReq{MerchantOrderNo: "202608010003"}, start, end)
+	if !sells.Next() || sells.Detail().AlipayOrderNo == "" || sells.Detail().TotalAmount != MustParseMoney("10.00") || sells.Next() || sells.Err() != nil {
+		t.Errorf("unexpected sell bills: %+v %v", sells.Detail(), sells.Err())
+	}
+	if buys := billClient.BuyBills(ctx, DataBillBuyQueryReq{}, start, end); buys.Next() || buys.Err() != nil {
+		t.Errorf("unexpected buy bills: %v", buys.Err())
+	}
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	logs = billClient.AccountLogs(cancelCtx, DataBillAccountLogQueryReq{PageSize: "2"}, start, end)
+	for i := 0; logs.Next(); i++ {
+		if i == 1 {
+			cancel()
+		}
+	}
+	if logs.Err() != context.Canceled {
+		t.Errorf("unexpected error: %v", logs.Err())
+	}
+	if transfers := billClient.TransferBills(ctx, DataBillTransferQueryReq{}, end, start); transfers.Next() || transfers.Err() != ErrBillQueryRange {
+		t.Errorf("unexpected error: %v", transfers.Err())
+	}
+}
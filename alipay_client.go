@@ -3,6 +3,7 @@ package alipay
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/url"
@@ -49,6 +50,13 @@ func SetClientOptIsProd(isProd bool) ClientOptFunc {
 	}
 }
 
+// SetClientOptEncryptKey 设置开放平台配置的AES密钥，用于解密小程序获取的敏感数据
+func SetClientOptEncryptKey(encryptKey string) ClientOptFunc {
+	return func(client *Client) {
+		client.encryptKey = encryptKey
+	}
+}
+
 func SetServerUrl(serverUrl string) ClientOptFunc {
 	return func(client *Client) {
 		client.serverUrl = serverUrl
@@ -64,6 +72,8 @@ type Client struct {
 	location *time.Location
 	// http 请求客户端
 	httpClient *http.Client
+	// 接口内容加密的AES密钥，base64编码
+	encryptKey string
 	SignVerifier
 	RequestObjectBuilder
 }
@@ -74,11 +84,12 @@ func NewClient(signVerifier SignVerifier, optsFunc ...ClientOptFunc) (*Client, e
 		httpClient:   http.DefaultClient,
 		location:     time.Local,
 		SignVerifier: signVerifier,
-		RequestObjectBuilder: &RequestAliPayObjectBuilder{
-			location: time.Local,
-		},
 	}
 	ClientOptsFunc(optsFunc).apply(client)
+	// 请求时间戳与客户端使用相同的时区
+	client.RequestObjectBuilder = &RequestAliPayObjectBuilder{
+		location: client.location,
+	}
 	if client.isProd {
 		client.serverUrl = ProductionGatewayURL
 	}
@@ -87,17 +98,8 @@ func NewClient(signVerifier SignVerifier, optsFunc ...ClientOptFunc) (*Client, e
 
 // TradePagePay alipay.trade.page.pay(统一收单下单并支付页面接口) https://opendocs.alipay.com/open/028r8t
 func (r *Client) TradePagePay(req TradePagePayReq) (*url.URL, error) {
-	var err error
-	if err = req.DoValidate(); err != nil {
-		return nil, err
-	}
-	var commonReqParam *CommonReqParam
-	if commonReqParam, err = r.buildRequestObject(&req, WithNotifyUrl(req.NotifyUrl), WithReturnUrl(req.ReturnUrl)); err != nil {
-		return nil, err
-	}
-	r.SetSignContent(commonReqParam)
-	var encode string
-	if encode, err = r.Encode(); err != nil {
+	encode, _, err := r.encodeRequest(&req, WithNotifyUrl(req.NotifyUrl), WithReturnUrl(req.ReturnUrl))
+	if err != nil {
 		return nil, err
 	}
 	return url.Parse(r.serverUrl + "?" + encode)
@@ -124,6 +126,66 @@ func (r *Client) TradeRefund(ctx context.Context, req TradeRefundReq) (*TradeRef
 	return res, err
 }
 
+// TradeRoyaltyRelationBind alipay.trade.royalty.relation.bind(分账关系绑定) https://opendocs.alipay.com/open/02c7hq
+func (r *Client) TradeRoyaltyRelationBind(ctx context.Context, req TradeRoyaltyRelationBindReq) (*TradeRoyaltyRelationBindRes, error) {
+	res := new(TradeRoyaltyRelationBindRes)
+	err := r.DoRequest(ctx, &req, res)
+	return res, err
+}
+
+// TradeRoyaltyRelationUnbind alipay.trade.royalty.relation.unbind(分账关系解绑) https://opendocs.alipay.com/open/02c7hr
+func (r *Client) TradeRoyaltyRelationUnbind(ctx context.Context, req TradeRoyaltyRelationUnbindReq) (*TradeRoyaltyRelationUnbindRes, error) {
+	res := new(TradeRoyaltyRelationUnbindRes)
+	err := r.DoRequest(ctx, &req, res)
+	return res, err
+}
+
+// TradeRoyaltyRelationBatchQuery alipay.trade.royalty.relation.batchquery(分账关系查询) https://opendocs.alipay.com/open/02c7hs
+func (r *Client) TradeRoyaltyRelationBatchQuery(ctx context.Context, req TradeRoyaltyRelationBatchQueryReq) (*TradeRoyaltyRelationBatchQueryRes, error) {
+	res := new(TradeRoyaltyRelationBatchQueryRes)
+	err := r.DoRequest(ctx, &req, res)
+	return res, err
+}
+
+// TradeRoyaltyRateQuery alipay.trade.royalty.rate.query(分账比例查询) https://opendocs.alipay.com/open/02pj6l
+func (r *Client) TradeRoyaltyRateQuery(ctx context.Context, req TradeRoyaltyRateQueryReq) (*TradeRoyaltyRateQueryRes, error) {
+	res := new(TradeRoyaltyRateQueryRes)
+	err := r.DoRequest(ctx, &req, res)
+	return res, err
+}
+
+// TradeOrderSettle alipay.trade.order.settle(统一收单交易结算接口) https://opendocs.alipay.com/open/028xqz
+// 未指定 SettleableAmount 时先查询待分账金额，请求前校验分账金额之和不超过该金额
+func (r *Client) TradeOrderSettle(ctx context.Context, req TradeOrderSettleReq) (*TradeOrderSettleRes, error) {
+	res := new(TradeOrderSettleRes)
+	if req.SettleableAmount == nil {
+		onSettleRes, err := r.TradeOrderOnSettleQuery(ctx, TradeOrderOnSettleQueryReq{TradeNo: req.TradeNo})
+		if err != nil {
+			return res, err
+		}
+		if onSettleRes.Fail() {
+			return res, fmt.Errorf("xpay: trade order onsettle query failed, sub_code: %s, sub_msg: %s", onSettleRes.SubCode, onSettleRes.SubMsg)
+		}
+		req.SettleableAmount = &onSettleRes.UnsettledAmount
+	}
+	err := r.DoRequest(ctx, &req, res)
+	return res, err
+}
+
+// TradeOrderSettleQuery alipay.trade.order.settle.query(交易分账查询接口) https://opendocs.alipay.com/open/02pj6m
+func (r *Client) TradeOrderSettleQuery(ctx context.Context, req TradeOrderSettleQueryReq) (*TradeOrderSettleQueryRes, error) {
+	res := new(TradeOrderSettleQueryRes)
+	err := r.DoRequest(ctx, &req, res)
+	return res, err
+}
+
+// TradeOrderOnSettleQuery alipay.trade.order.onsettle.query(分账剩余金额查询) https://opendocs.alipay.com/open/d87dc009_alipay.trade.order.onsettle.query
+func (r *Client) TradeOrderOnSettleQuery(ctx context.Context, req TradeOrderOnSettleQueryReq) (*TradeOrderOnSettleQueryRes, error) {
+	res := new(TradeOrderOnSettleQueryRes)
+	err := r.DoRequest(ctx, &req, res)
+	return res, err
+}
+
 // TradeFastPayRefundQuery alipay.trade.fastpay.refund.query(统一收单交易退款查询) https://opendocs.alipay.com/open/028sma
 func (r *Client) TradeFastPayRefundQuery(ctx context.Context, req TradeFastPayRefundQueryReq) (*TradeFastPayRefundQueryRes, error) {
 	res := new(TradeFastPayRefundQueryRes)
@@ -140,44 +202,66 @@ func (r *Client) DataServiceBillDownloadUrlQuery(ctx context.Context, req DataSe
 
 // TradeWapPay alipay.trade.wap.pay(手机网站支付接口2.0) https://opendocs.alipay.com/open/02ivbs?scene=21&ref=api
 func (r *Client) TradeWapPay(req TradeWapPayReq) (*url.URL, error) {
-	var commonReqParam *CommonReqParam
-	var err error
-	if err = req.DoValidate(); err != nil {
+	encode, _, err := r.encodeRequest(&req, WithNotifyUrl(req.NotifyUrl), WithReturnUrl(req.ReturnUrl))
+	if err != nil {
 		return nil, err
 	}
-	if commonReqParam, err = r.buildRequestObject(&req, WithNotifyUrl(req.NotifyUrl), WithReturnUrl(req.ReturnUrl)); err != nil {
+	return url.Parse(r.serverUrl + "?" + encode)
+}
+
+// TradeAppPay alipay.trade.app.pay(app支付接口2.0) https://opendocs.alipay.com/open/02e7gq?ref=api&scene=20
+// 返回的 OrderString 为签名后的订单串，由服务端下发给客户端，直接传给支付宝SDK唤起支付
+func (r *Client) TradeAppPay(req TradeAppPayReq) (*TradeAppPayResult, error) {
+	encode, commonReqParam, err := r.encodeRequest(&req, WithNotifyUrl(req.NotifyUrl), WithReturnUrl(req.ReturnUrl))
+	if err != nil {
 		return nil, err
 	}
-	r.SetSignContent(commonReqParam)
-	var encode string
-	if encode, err = r.Encode(); err != nil {
+	var gatewayURL *url.URL
+	if gatewayURL, err = url.Parse(r.serverUrl + "?" + encode); err != nil {
 		return nil, err
 	}
-	return url.Parse(r.serverUrl + "?" + encode)
+	var expireAt time.Time
+	if expireAt, err = r.tradeExpireAt(commonReqParam.Timestamp, req.TimeExpire); err != nil {
+		return nil, err
+	}
+	return &TradeAppPayResult{OrderString: encode, GatewayURL: gatewayURL, ExpireAt: expireAt}, nil
 }
 
-// TradeAppPay alipay.trade.app.pay(app支付接口2.0) https://opendocs.alipay.com/open/02e7gq?ref=api&scene=20
-func (r *Client) TradeAppPay(req TradeAppPayReq) (*url.URL, error) {
-	var commonReqParam *CommonReqParam
-	var err error
-	if err = req.DoValidate(); err != nil {
+// TradeMergePreCreate alipay.trade.merge.precreate(统一收单合并支付预创建接口) https://opendocs.alipay.com/open/028xr9
+// 子订单的支付结果通过 TradeMergeNotify 合并通知
+func (r *Client) TradeMergePreCreate(ctx context.Context, req TradeMergePreCreateReq) (*TradeMergePreCreateRes, error) {
+	res := new(TradeMergePreCreateRes)
+	err := r.DoRequest(ctx, &req, res, WithNotifyUrl(req.NotifyUrl))
+	return res, err
+}
+
+// TradeWapMergePay alipay.trade.wap.merge.pay(无线Wap合并支付接口2.0) https://opendocs.alipay.com/open/028xra
+func (r *Client) TradeWapMergePay(req TradeWapMergePayReq) (*url.URL, error) {
+	encode, _, err := r.encodeRequest(&req, WithReturnUrl(req.ReturnUrl))
+	if err != nil {
 		return nil, err
 	}
-	if commonReqParam, err = r.buildRequestObject(&req, WithNotifyUrl(req.NotifyUrl), WithReturnUrl(req.ReturnUrl)); err != nil {
+	return url.Parse(r.serverUrl + "?" + encode)
+}
+
+// TradeAppMergePay alipay.trade.app.merge.pay(App合并支付接口) https://opendocs.alipay.com/open/028py8
+// 返回的 OrderString 为签名后的订单串，由服务端下发给客户端，直接传给支付宝SDK唤起支付
+func (r *Client) TradeAppMergePay(req TradeAppMergePayReq) (*TradeAppMergePayResult, error) {
+	encode, _, err := r.encodeRequest(&req)
+	if err != nil {
 		return nil, err
 	}
-	r.SetSignContent(commonReqParam)
-	var encode string
-	if encode, err = r.Encode(); err != nil {
+	var gatewayURL *url.URL
+	if gatewayURL, err = url.Parse(r.serverUrl + "?" + encode); err != nil {
 		return nil, err
 	}
-	return url.Parse(r.serverUrl + "?" + encode)
+	return &TradeAppMergePayResult{OrderString: encode, GatewayURL: gatewayURL}, nil
 }
 
 // TradePreCreate https://opendocs.alipay.com/open/02ekfg?scene=19 alipay.trade.precreate(统一收单线下交易预创建)
 func (r *Client) TradePreCreate(ctx context.Context, req TradePreCreateReq) (*TradePreCreateRes, error) {
 	res := new(TradePreCreateRes)
-	err := r.DoRequest(ctx, &req, res)
+	err := r.DoRequest(ctx, &req, res, WithNotifyUrl(req.NotifyUrl))
 	return res, err
 }
 
@@ -216,6 +300,68 @@ func (r *Client) DataBillBailQuery(ctx context.Context, req DataBillBailQueryReq
 	return res, err
 }
 
+// DataBillEreceiptApply alipay.data.bill.ereceipt.apply(申请电子回单) https://opendocs.alipay.com/open/1aad1956_alipay.data.bill.ereceipt.apply
+func (r *Client) DataBillEreceiptApply(ctx context.Context, req DataBillEreceiptApplyReq) (*DataBillEreceiptApplyRes, error) {
+	res := new(DataBillEreceiptApplyRes)
+	err := r.DoRequest(ctx, &req, res)
+	return res, err
+}
+
+// DataBillEreceiptQuery alipay.data.bill.ereceipt.query(查询电子回单状态) https://opendocs.alipay.com/open/30b94a2f_alipay.data.bill.ereceipt.query
+func (r *Client) DataBillEreceiptQuery(ctx context.Context, req DataBillEreceiptQueryReq) (*DataBillEreceiptQueryRes, error) {
+	res := new(DataBillEreceiptQueryRes)
+	err := r.DoRequest(ctx, &req, res)
+	return res, err
+}
+
+// DataBillAccountLogQuery alipay.data.bill.accountlog.query(支付宝商家账户账务明细查询) https://opendocs.alipay.com/open/02awe5
+func (r *Client) DataBillAccountLogQuery(ctx context.Context, req DataBillAccountLogQueryReq) (*DataBillAccountLogQueryRes, error) {
+	res := new(DataBillAccountLogQueryRes)
+	err := r.DoRequest(ctx, &req, res)
+	return res, err
+}
+
+// AccountLogs 遍历 [start, end) 内的账务明细，时间范围超过31天时拆分查询，req.PageSize 为空时每页2000条
+func (r *Client) AccountLogs(ctx context.Context, req DataBillAccountLogQueryReq, start, end time.Time) *AccountLogIterator {
+	return &AccountLogIterator{client: r, pager: newBillPager(ctx, start, end, req.PageSize, r.location), req: req}
+}
+
+// DataBillSellQuery alipay.data.bill.sell.query(支付宝商家账户卖出交易查询) https://opendocs.alipay.com/open/02awe4
+func (r *Client) DataBillSellQuery(ctx context.Context, req DataBillSellQueryReq) (*DataBillSellQueryRes, error) {
+	res := new(DataBillSellQueryRes)
+	err := r.DoRequest(ctx, &req, res)
+	return res, err
+}
+
+// SellBills 遍历 [start, end) 内的卖出交易明细，时间范围超过31天时拆分查询，req.PageSize 为空时每页2000条
+func (r *Client) SellBills(ctx context.Context, req DataBillSellQueryReq, start, end time.Time) *SellBillIterator {
+	return &SellBillIterator{client: r, pager: newBillPager(ctx, start, end, req.PageSize, r.location), req: req}
+}
+
+// DataBillBuyQuery alipay.data.bill.buy.query(支付宝商家账户买入交易查询) https://opendocs.alipay.com/open/02awe6
+func (r *Client) DataBillBuyQuery(ctx context.Context, req DataBillBuyQueryReq) (*DataBillBuyQueryRes, error) {
+	res := new(DataBillBuyQueryRes)
+	err := r.DoRequest(ctx, &req, res)
+	return res, err
+}
+
+// BuyBills 遍历 [start, end) 内的买入交易明细，时间范围超过31天时拆分查询，req.PageSize 为空时每页2000条
+func (r *Client) BuyBills(ctx context.Context, req DataBillBuyQueryReq, start, end time.Time) *BuyBillIterator {
+	return &BuyBillIterator{client: r, pager: newBillPager(ctx, start, end, req.PageSize, r.location), req: req}
+}
+
+// DataBillTransferQuery alipay.data.bill.transfer.query(支付宝商家账户充值，转账，提现查询) https://opendocs.alipay.com/open/02awe7
+func (r *Client) DataBillTransferQuery(ctx context.Context, req DataBillTransferQueryReq) (*DataBillTransferQueryRes, error) {
+	res := new(DataBillTransferQueryRes)
+	err := r.DoRequest(ctx, &req, res)
+	return res, err
+}
+
+// TransferBills 遍历 [start, end) 内的转账明细，时间范围超过31天时拆分查询，req.PageSize 为空时每页2000条
+func (r *Client) TransferBills(ctx context.Context, req DataBillTransferQueryReq, start, end time.Time) *TransferBillIterator {
+	return &TransferBillIterator{client: r, pager: newBillPager(ctx, start, end, req.PageSize, r.location), req: req}
+}
+
 // TradeCreate alipay.trade.create(统一收单交易创建接口) https://opendocs.alipay.com/mini/03l5wn
 func (r *Client) TradeCreate(ctx context.Context, req TradeCreateReq) (*TradeCreateRes, error) {
 	res := new(TradeCreateRes)
@@ -246,17 +392,8 @@ func (r *Client) FundAccountQuery(ctx context.Context, req FundAccountQueryReq)
 
 // UserInfoAuth alipay.user.info.auth(用户登录授权) https://opendocs.alipay.com/open/02aile
 func (r *Client) UserInfoAuth(req UserInfoAuthReq) (*url.URL, error) {
-	var err error
-	if err = req.DoValidate(); err != nil {
-		return nil, err
-	}
-	var commonReqParam *CommonReqParam
-	if commonReqParam, err = r.buildRequestObject(&req, WithReturnUrl(req.ReturnUrl)); err != nil {
-		return nil, err
-	}
-	r.SetSignContent(commonReqParam)
-	var encode string
-	if encode, err = r.Encode(); err != nil {
+	encode, _, err := r.encodeRequest(&req, WithReturnUrl(req.ReturnUrl))
+	if err != nil {
 		return nil, err
 	}
 	return url.Parse(r.serverUrl + "?" + encode)
@@ -269,6 +406,13 @@ func (r *Client) OpenAuthTokenApp(ctx context.Context, req OpenAuthTokenAppReq)
 	return res, err
 }
 
+// OpenAuthTokenAppQuery alipay.open.auth.token.app.query(查询某个应用授权AppAuthToken的授权信息) https://opendocs.alipay.com/isv/04hgcp
+func (r *Client) OpenAuthTokenAppQuery(ctx context.Context, req OpenAuthTokenAppQueryReq) (*OpenAuthTokenAppQueryRes, error) {
+	res := new(OpenAuthTokenAppQueryRes)
+	err := r.DoRequest(ctx, &req, res)
+	return res, err
+}
+
 // UserCertifyOpenInitialize alipay.user.certify.open.initialize(身份认证初始化服务) https://opendocs.alipay.com/open/02ahjy
 func (r *Client) UserCertifyOpenInitialize(ctx context.Context, req UserCertifyOpenInitializeReq) (*UserCertifyOpenInitializeRes, error) {
 	res := new(UserCertifyOpenInitializeRes)
@@ -283,19 +427,33 @@ func (r *Client) UserCertifyOpenQuery(ctx context.Context, req UserCertifyOpenQu
 	return res, err
 }
 
-// UserCertifyOpenCertify alipay.user.certify.open.certify(身份认证开始认证) https://opendocs.alipay.com/open/02ahk0
-func (r *Client) UserCertifyOpenCertify(ctx context.Context, req UserCertifyOpenCertifyReq) (*url.URL, error) {
-	var err error
-	if err = req.DoValidate(); err != nil {
-		return nil, err
-	}
-	var commonReqParam *CommonReqParam
-	if commonReqParam, err = r.buildRequestObject(&req, WithReturnUrl(req.ReturnUrl)); err != nil {
+// UserAgreementPageSign alipay.user.agreement.page.sign(支付宝个人协议页面签约接口) https://opendocs.alipay.com/open/02fkan
+func (r *Client) UserAgreementPageSign(req UserAgreementPageSignReq) (*url.URL, error) {
+	encode, _, err := r.encodeRequest(&req, WithNotifyUrl(req.NotifyUrl), WithReturnUrl(req.ReturnUrl))
+	if err != nil {
 		return nil, err
 	}
-	r.SetSignContent(commonReqParam)
-	var encode string
-	if encode, err = r.Encode(); err != nil {
+	return url.Parse(r.serverUrl + "?" + encode)
+}
+
+// UserAgreementQuery alipay.user.agreement.query(支付宝个人代扣协议查询接口) https://opendocs.alipay.com/open/02fkao
+func (r *Client) UserAgreementQuery(ctx context.Context, req UserAgreementQueryReq) (*UserAgreementQueryRes, error) {
+	res := new(UserAgreementQueryRes)
+	err := r.DoRequest(ctx, &req, res)
+	return res, err
+}
+
+// UserAgreementUnsign alipay.user.agreement.unsign(支付宝个人代扣协议解约接口) https://opendocs.alipay.com/open/02fkar
+func (r *Client) UserAgreementUnsign(ctx context.Context, req UserAgreementUnsignReq) (*UserAgreementUnsignRes, error) {
+	res := new(UserAgreementUnsignRes)
+	err := r.DoRequest(ctx, &req, res, WithNotifyUrl(req.NotifyUrl))
+	return res, err
+}
+
+// UserCertifyOpenCertify alipay.user.certify.open.certify(身份认证开始认证) https://opendocs.alipay.com/open/02ahk0
+func (r *Client) UserCertifyOpenCertify(ctx context.Context, req UserCertifyOpenCertifyReq) (*url.URL, error) {
+	encode, _, err := r.encodeRequest(&req, WithReturnUrl(req.ReturnUrl))
+	if err != nil {
 		return nil, err
 	}
 	return url.Parse(r.serverUrl + "?" + encode)
@@ -330,6 +488,77 @@ func (r *Client) FundTransCommonQuery(ctx context.Context, req FundTransCommonQu
 	return res, err
 }
 
+// FundBatchUniTransfer alipay.fund.batch.uni.transfer(批量转账接口) https://opendocs.alipay.com/open/02fkbm
+func (r *Client) FundBatchUniTransfer(ctx context.Context, req FundBatchUniTransferReq) (*FundBatchUniTransferRes, error) {
+	res := new(FundBatchUniTransferRes)
+	err := r.DoRequest(ctx, &req, res)
+	return res, err
+}
+
+// FundBatchDetailQuery alipay.fund.batch.detail.query(批量转账明细查询接口) https://opendocs.alipay.com/open/02fkbn
+func (r *Client) FundBatchDetailQuery(ctx context.Context, req FundBatchDetailQueryReq) (*FundBatchDetailQueryRes, error) {
+	res := new(FundBatchDetailQueryRes)
+	err := r.DoRequest(ctx, &req, res)
+	return res, err
+}
+
+// FundBatchDetails 按页遍历批次的转账明细，req.PageNum 为空时从第1页开始
+func (r *Client) FundBatchDetails(ctx context.Context, req FundBatchDetailQueryReq) *FundBatchDetailIterator {
+	if req.PageNum < 1 {
+		req.PageNum = 1
+	}
+	return &FundBatchDetailIterator{client: r, ctx: ctx, req: req}
+}
+
+// FundBatchClose alipay.fund.batch.close(批量转账关单接口) https://opendocs.alipay.com/open/02fkbo
+func (r *Client) FundBatchClose(ctx context.Context, req FundBatchCloseReq) (*FundBatchCloseRes, error) {
+	res := new(FundBatchCloseRes)
+	err := r.DoRequest(ctx, &req, res)
+	return res, err
+}
+
+// FundAuthOrderAppFreeze alipay.fund.auth.order.app.freeze(线上资金授权冻结接口) https://opendocs.alipay.com/open/02f912
+// 返回的 OrderString 为签名后的订单串，由服务端下发给客户端，直接传给支付宝SDK唤起授权
+func (r *Client) FundAuthOrderAppFreeze(req FundAuthOrderAppFreezeReq) (*FundAuthOrderAppFreezeResult, error) {
+	encode, _, err := r.encodeRequest(&req, WithNotifyUrl(req.NotifyUrl))
+	if err != nil {
+		return nil, err
+	}
+	var gatewayURL *url.URL
+	if gatewayURL, err = url.Parse(r.serverUrl + "?" + encode); err != nil {
+		return nil, err
+	}
+	return &FundAuthOrderAppFreezeResult{OrderString: encode, GatewayURL: gatewayURL}, nil
+}
+
+// FundAuthOrderFreeze alipay.fund.auth.order.freeze(资金授权冻结接口) https://opendocs.alipay.com/open/02fkb9
+func (r *Client) FundAuthOrderFreeze(ctx context.Context, req FundAuthOrderFreezeReq) (*FundAuthOrderFreezeRes, error) {
+	res := new(FundAuthOrderFreezeRes)
+	err := r.DoRequest(ctx, &req, res, WithNotifyUrl(req.NotifyUrl))
+	return res, err
+}
+
+// FundAuthOrderUnfreeze alipay.fund.auth.order.unfreeze(资金授权解冻接口) https://opendocs.alipay.com/open/02fkbc
+func (r *Client) FundAuthOrderUnfreeze(ctx context.Context, req FundAuthOrderUnfreezeReq) (*FundAuthOrderUnfreezeRes, error) {
+	res := new(FundAuthOrderUnfreezeRes)
+	err := r.DoRequest(ctx, &req, res, WithNotifyUrl(req.NotifyUrl))
+	return res, err
+}
+
+// FundAuthOperationDetailQuery alipay.fund.auth.operation.detail.query(资金授权操作查询接口) https://opendocs.alipay.com/open/02fkbd
+func (r *Client) FundAuthOperationDetailQuery(ctx context.Context, req FundAuthOperationDetailQueryReq) (*FundAuthOperationDetailQueryRes, error) {
+	res := new(FundAuthOperationDetailQueryRes)
+	err := r.DoRequest(ctx, &req, res)
+	return res, err
+}
+
+// FundAuthOrderVoucherCreate alipay.fund.auth.order.voucher.create(资金授权发码接口) https://opendocs.alipay.com/open/02fkba
+func (r *Client) FundAuthOrderVoucherCreate(ctx context.Context, req FundAuthOrderVoucherCreateReq) (*FundAuthOrderVoucherCreateRes, error) {
+	res := new(FundAuthOrderVoucherCreateRes)
+	err := r.DoRequest(ctx, &req, res, WithNotifyUrl(req.NotifyUrl))
+	return res, err
+}
+
 // CommerceCityFacilitatorVoucherGenerate alipay.commerce.cityfacilitator.voucher.generate(地铁购票核销码发码) https://opendocs.alipay.com/open/02ars7
 func (r *Client) CommerceCityFacilitatorVoucherGenerate(ctx context.Context, req CommerceCityFacilitatorVoucherGenerateReq) (*CommerceCityFacilitatorVoucherGenerateRes, error) {
 	res := new(CommerceCityFacilitatorVoucherGenerateRes)
@@ -358,6 +587,13 @@ func (r *Client) CommerceCityFacilitatorVoucherBatchQuery(ctx context.Context, r
 	return res, err
 }
 
+// Execute 调用任意接口，用于SDK尚未封装的接口，响应同样会验签
+func (r *Client) Execute(ctx context.Context, req GenericReq, opts ...commonParamOpt) (*GenericRes, error) {
+	res := new(GenericRes)
+	err := r.DoRequest(ctx, &req, res, opts...)
+	return res, err
+}
+
 // DoRequest 发送请求
 func (r *Client) DoRequest(ctx context.Context, req IAliPayRequest, responseParam ResponseSigner, opts ...commonParamOpt) error {
 	if err := req.DoValidate(); err != nil {
@@ -407,6 +643,43 @@ func (r *Client) DoRequest(ctx context.Context, req IAliPayRequest, responsePara
 //	return buff, responseParam, err
 //}
 
+// encodeRequest 校验并签名请求参数，返回编码后的参数串，用于页面跳转、表单提交和app支付等不需要服务端发起请求的接口
+func (r *Client) encodeRequest(req IAliPayRequest, opts ...commonParamOpt) (string, *CommonReqParam, error) {
+	var err error
+	if err = req.DoValidate(); err != nil {
+		return "", nil, err
+	}
+	var commonReqParam *CommonReqParam
+	if commonReqParam, err = r.buildRequestObject(req, opts...); err != nil {
+		return "", nil, err
+	}
+	var encode string
+	if encode, err = r.signEncode(commonReqParam); err != nil {
+		return "", nil, err
+	}
+	return encode, commonReqParam, nil
+}
+
+// signEncode 签名并编码请求参数，签名策略保存了待签名的参数，并发请求时需要串行执行
+func (r *Client) signEncode(param *CommonReqParam) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.SetSignContent(param)
+	return r.Encode()
+}
+
+// tradeExpireAt 计算订单的失效时间，未指定time_expire时按照支付宝默认的订单超时时间计算
+func (r *Client) tradeExpireAt(timestamp, timeExpire string) (time.Time, error) {
+	if len(timeExpire) > 0 {
+		return time.ParseInLocation(time.DateTime, timeExpire, r.location)
+	}
+	start, err := time.ParseInLocation(time.DateTime, timestamp, r.location)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return start.Add(DefaultTradeTimeout), nil
+}
+
 // 具体请求
 func (r *Client) doRequest(ctx context.Context, req IAliPayRequest, opts ...commonParamOpt) ([]byte, error) {
 	deadline, ok := ctx.Deadline()
@@ -415,13 +688,16 @@ func (r *Client) doRequest(ctx context.Context, req IAliPayRequest, opts ...comm
 	}
 	var err error
 	var newRequest *http.Request
+	// ctx中的应用授权令牌优先级低于显式传入的参数
+	if appAuthToken, ok := AppAuthTokenFromContext(ctx); ok {
+		opts = append([]commonParamOpt{WithAppAuthToken(appAuthToken)}, opts...)
+	}
 	var commonReqParam *CommonReqParam
 	if commonReqParam, err = r.buildRequestObject(req, opts...); err != nil {
 		return nil, err
 	}
-	r.SetSignContent(commonReqParam)
 	var encode string
-	if encode, err = r.Encode(); err != nil {
+	if encode, err = r.signEncode(commonReqParam); err != nil {
 		return nil, err
 	}
 	if newRequest, err = http.NewRequestWithContext(ctx, req.RequestHttpMethod(), r.serverUrl, strings.NewReader(encode)); err != nil {
@@ -0,0 +1,87 @@
+package alipay
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"net/url"
+	"testing"
+	"time"
+)
+
+/**
+ * @author: Sam
+ * @since:
+ * @date: 2026/10/19 14:05
+ * @desc:
+ */
+
+// newLocalTestClient 使用临时生成的密钥创建客户端，仅用于不需要访问网关的测试
+func newLocalTestClient(t *testing.T) *Client {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateKey := base64.StdEncoding.EncodeToString(x509.MarshalPKCS1PrivateKey(key))
+	publicBuff, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKey := base64.StdEncoding.EncodeToString(publicBuff)
+	location, _ := time.LoadLocation("Asia/Shanghai")
+	client, err := NewClient(NewNormalRSA2SignStrategy("2021000000000000", privateKey, publicKey, publicKey), SetClientOptLocation(location))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestClient_TradeAppPayResult(t *testing.T) {
+	client := newLocalTestClient(t)
+	req := TradeAppPayReq{OutTradeNo: "221010101221122", TotalAmount: MustParseMoney("9.99"), Subject: "测试产品"}
+	result, err := client.TradeAppPay(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values, err := url.ParseQuery(result.OrderString)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values.Get("method") != "alipay.trade.app.pay" || len(values.Get("sign")) == 0 {
+		t.Errorf("unexpected order string: %s", result.OrderString)
+	}
+	if result.GatewayURL.RawQuery != result.OrderString || result.GatewayURL.Host != "openapi.alipaydev.com" {
+		t.Errorf("unexpected gateway url: %s", result.GatewayURL)
+	}
+	timestamp, _ := time.ParseInLocation(time.DateTime, values.Get("timestamp"), client.location)
+	if !result.ExpireAt.Equal(timestamp.Add(DefaultTradeTimeout)) {
+		t.Errorf("expire at %s, timestamp %s", result.ExpireAt, timestamp)
+	}
+
+	req.TimeExpire = "2030-01-02 15:04:05"
+	if result, err = client.TradeAppPay(req); err != nil {
+		t.Fatal(err)
+	}
+	if result.ExpireAt.Format(time.DateTime) != req.TimeExpire || result.ExpireAt.Location() != client.location {
+		t.Errorf("expire at %s", result.ExpireAt)
+	}
+}
+
+func TestTradeCreateRes_MiniProgramTradePayJSON(t *testing.T) {
+	res := &TradeCreateRes{}
+	res.Code = "10000"
+	res.TradeNo = "2015042321001004720200028594"
+	payload, err := res.MiniProgramTradePayJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payload != `{"tradeNO":"2015042321001004720200028594"}` {
+		t.Errorf("unexpected payload: %s", payload)
+	}
+	res.Code = "40004"
+	if _, err = res.MiniProgramTradePayJSON(); err == nil {
+		t.Error("error expected for failed response")
+	}
+}
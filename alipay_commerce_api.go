@@ -1,9 +1,6 @@
 package alipay
 
-import (
-	"encoding/json"
-	"fmt"
-)
+import "encoding/json"
 
 /**
  * @author: Sam
@@ -15,27 +12,19 @@ import (
 var _ IAliPayRequest = &CommerceCityFacilitatorVoucherGenerateReq{}
 
 type CommerceCityFacilitatorVoucherGenerateReq struct {
-	CityCode    string `json:"city_code"`            // 必选	30 城市编码请参考查询 中华人民共和国行政区划代码。 已支持城市：广州 440100，深圳 440300，杭州330100。
-	TradeNo     string `json:"trade_no"`             // 必选	100 支付宝交易号（交易支付时，必须通过指定sellerId：2088121612215201，将钱支付到指定的中间户中）
-	TradeFee    string `json:"trade_fee"`            // 必选	20 订单总金额，元为单位 10.00
-	TicketNum   string `json:"ticket_num"`           // 必选	20 地铁票购票数量 5
-	TicketType  string `json:"ticket_type"`          // 必选	60 地铁票种类，枚举支持： *oneway。
-	SiteBegin   string `json:"site_begin,omitempty"` // 可选	30 起点站站点编码 02490301
-	SiteEnd     string `json:"site_end,omitempty"`   // 可选	30 终点站站点编码 02490305
-	TicketPrice string `json:"ticket_price"`         // 必选	20 单张票价，元为单价 5.00
+	CityCode    string `json:"city_code" validate:"required,max=30"`                   // 必选	30 城市编码请参考查询 中华人民共和国行政区划代码。 已支持城市：广州 440100，深圳 440300，杭州330100。
+	TradeNo     string `json:"trade_no" validate:"required,max=100"`                   // 必选	100 支付宝交易号（交易支付时，必须通过指定sellerId：2088121612215201，将钱支付到指定的中间户中）
+	TradeFee    Money  `json:"trade_fee" validate:"required,amount=0.01~100000000"`    // 必选	20 订单总金额，元为单位 10.00
+	TicketNum   string `json:"ticket_num" validate:"required,max=20"`                  // 必选	20 地铁票购票数量 5
+	TicketType  string `json:"ticket_type" validate:"required,max=60"`                 // 必选	60 地铁票种类，枚举支持： *oneway。
+	SiteBegin   string `json:"site_begin,omitempty" validate:"max=30"`                 // 可选	30 起点站站点编码 02490301
+	SiteEnd     string `json:"site_end,omitempty" validate:"max=30"`                   // 可选	30 终点站站点编码 02490305
+	TicketPrice Money  `json:"ticket_price" validate:"required,amount=0.01~100000000"` // 必选	20 单张票价，元为单价 5.00
 	baseAliPayRequest
 }
 
 func (r *CommerceCityFacilitatorVoucherGenerateReq) DoValidate() error {
-	cityCodeLen := len(r.CityCode)
-	if cityCodeLen == 0 || cityCodeLen > 30 {
-		return fmt.Errorf("城市编码city_code参数长度为%d，不符合长度1-30个字符的要求", cityCodeLen)
-	}
-	tradeNoLen := len(r.TradeNo)
-	if tradeNoLen == 0 || tradeNoLen > 100 {
-		return fmt.Errorf("支付宝交易号trade_no参数长度为%d，不符合长度1-100个字符的要求", tradeNoLen)
-	}
-	return nil
+	return ValidateStruct(r)
 }
 
 func (r *CommerceCityFacilitatorVoucherGenerateReq) RequestApi() string {
@@ -63,21 +52,13 @@ func (r *CommerceCityFacilitatorVoucherGenerateRes) String() string {
 var _ IAliPayRequest = &CommerceCityFacilitatorVoucherRefundReq{}
 
 type CommerceCityFacilitatorVoucherRefundReq struct {
-	CityCode string `json:"city_code"` // 必选	30 城市编码请参考查询 中华人民共和国行政区划代码。 已支持城市：广州 440100，深圳 440300，杭州330100。
-	TradeNo  string `json:"trade_no"`  // 必选	100 支付宝交易号（交易支付时，必须通过指定sellerId：2088121612215201，将钱支付到指定的中间户中）
+	CityCode string `json:"city_code" validate:"required,max=30"` // 必选	30 城市编码请参考查询 中华人民共和国行政区划代码。 已支持城市：广州 440100，深圳 440300，杭州330100。
+	TradeNo  string `json:"trade_no" validate:"required,max=100"` // 必选	100 支付宝交易号（交易支付时，必须通过指定sellerId：2088121612215201，将钱支付到指定的中间户中）
 	baseAliPayRequest
 }
 
 func (r *CommerceCityFacilitatorVoucherRefundReq) DoValidate() error {
-	cityCodeLen := len(r.CityCode)
-	if cityCodeLen == 0 || cityCodeLen > 30 {
-		return fmt.Errorf("城市编码city_code参数长度为%d，不符合长度1-30个字符的要求", cityCodeLen)
-	}
-	tradeNoLen := len(r.TradeNo)
-	if tradeNoLen == 0 || tradeNoLen > 100 {
-		return fmt.Errorf("支付宝交易号trade_no参数长度为%d，不符合长度1-100个字符的要求", tradeNoLen)
-	}
-	return nil
+	return ValidateStruct(r)
 }
 
 func (r *CommerceCityFacilitatorVoucherRefundReq) RequestApi() string {
@@ -103,16 +84,12 @@ func (r *CommerceCityFacilitatorVoucherRefundRes) String() string {
 var _ IAliPayRequest = &CommerceCityFacilitatorStationQueryReq{}
 
 type CommerceCityFacilitatorStationQueryReq struct {
-	CityCode string `json:"city_code"` // 必选	30 城市编码请参考查询 中华人民共和国行政区划代码。 已支持城市：广州 440100，深圳 440300，杭州330100。
+	CityCode string `json:"city_code" validate:"required,max=30"` // 必选	30 城市编码请参考查询 中华人民共和国行政区划代码。 已支持城市：广州 440100，深圳 440300，杭州330100。
 	baseAliPayRequest
 }
 
 func (r *CommerceCityFacilitatorStationQueryReq) DoValidate() error {
-	cityCodeLen := len(r.CityCode)
-	if cityCodeLen == 0 || cityCodeLen > 30 {
-		return fmt.Errorf("城市编码city_code参数长度为%d，不符合长度1-30个字符的要求", cityCodeLen)
-	}
-	return nil
+	return ValidateStruct(r)
 }
 
 func (r *CommerceCityFacilitatorStationQueryReq) RequestApi() string {
@@ -144,17 +121,13 @@ type StationDetailInfo struct {
 var _ IAliPayRequest = &CommerceCityFacilitatorVoucherBatchQueryReq{}
 
 type CommerceCityFacilitatorVoucherBatchQueryReq struct {
-	CityCode string   `json:"city_code"` // 必选	30 城市编码请参考查询 中华人民共和国行政区划代码。 已支持城市：广州 440100，深圳 440300，杭州330100。
-	TradeNos []string `json:"trade_nos"` // 必选	800 支付宝交易号列表
+	CityCode string   `json:"city_code" validate:"required,max=30"` // 必选	30 城市编码请参考查询 中华人民共和国行政区划代码。 已支持城市：广州 440100，深圳 440300，杭州330100。
+	TradeNos []string `json:"trade_nos" validate:"required"`        // 必选	800 支付宝交易号列表
 	baseAliPayRequest
 }
 
 func (r *CommerceCityFacilitatorVoucherBatchQueryReq) DoValidate() error {
-	cityCodeLen := len(r.CityCode)
-	if cityCodeLen == 0 || cityCodeLen > 30 {
-		return fmt.Errorf("城市编码city_code参数长度为%d，不符合长度1-30个字符的要求", cityCodeLen)
-	}
-	return nil
+	return ValidateStruct(r)
 }
 func (r *CommerceCityFacilitatorVoucherBatchQueryReq) RequestApi() string {
 	return "alipay.commerce.cityfacilitator.voucher.batchquery"
@@ -177,12 +150,12 @@ func (r *CommerceCityFacilitatorVoucherBatchQueryRes) String() string {
 
 type TicketDetailInfo struct {
 	TradeNo          string `json:"trade_no"`                     // 必选	100 支付宝交易号 0123456789
-	Amount           string `json:"amount"`                       // 必选	20 总金额，元为单位 10.00
+	Amount           Money  `json:"amount"`                       // 必选	20 总金额，元为单位 10.00
 	StartStation     string `json:"start_station,omitempty"`      // 可选	80 起点站编码 12300002
 	EndStation       string `json:"end_station,omitempty"`        // 可选	80 终点站编码 21003002
 	Quantity         string `json:"quantity"`                     // 必选	20 票数量 8
 	Status           string `json:"status"`                       // 必选	40 订单状态 SUCCESS
-	TicketPrice      string `json:"ticket_price"`                 // 必选	20 单价，元为单位 5.00
+	TicketPrice      Money  `json:"ticket_price"`                 // 必选	20 单价，元为单位 5.00
 	StartStationName string `json:"start_station_name,omitempty"` // 可选	80 起点站中文名称 蓝村路
 	EndStationName   string `json:"end_station_name,omitempty"`   // 可选	80 终点站中文名称 浦电路
 	TicketType       string `json:"ticket_type"`                  // 必选	60 票类型 oneway
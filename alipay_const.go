@@ -2,6 +2,7 @@ package alipay
 
 import (
 	"errors"
+	"time"
 )
 
 /**
@@ -46,6 +47,19 @@ const (
 	TradeFinished     TradeStatus = "TRADE_FINISHED" //（交易结束，不可退款）
 )
 
+// AgreementStatus 代扣协议状态
+type AgreementStatus string
+
+const (
+	AgreementTemp   AgreementStatus = "TEMP"   //（暂存，协议未生效）
+	AgreementNormal AgreementStatus = "NORMAL" //（正常，可以扣款）
+	AgreementStop   AgreementStatus = "STOP"   //（暂停）
+	AgreementUnsign AgreementStatus = "UNSIGN" //（已解约，只出现在解约通知中）
+)
+
+// RefundSuccess 退款查询返回的退款状态，只有该状态表示退款成功，为空表示退款不存在
+const RefundSuccess = "REFUND_SUCCESS"
+
 // 销售产品码 product_code
 const (
 	// FastInstantTradePay PC网站
@@ -57,8 +71,21 @@ const (
 	// FaceToFacePayment 当面付产品
 	FaceToFacePayment string = "FACE_TO_FACE_PAYMENT"
 	TransAccountNoPwd string = "TRANS_ACCOUNT_NO_PWD"
+	// BatchApiToAcc 批量转账到支付宝账户
+	BatchApiToAcc string = "BATCH_API_TO_ACC"
+	// GeneralWithholding 商家扣款，按代扣协议扣款时使用
+	GeneralWithholding string = "GENERAL_WITHHOLDING"
+	// CyclePayAuthP 周期扣款的个人签约产品码 personal_product_code
+	CyclePayAuthP string = "CYCLE_PAY_AUTH_P"
+	// PreAuthPay 资金预授权，冻结和授权转支付时使用
+	PreAuthPay string = "PREAUTH_PAY"
+	// PreAuthOnline 线上资金授权冻结，app冻结时使用
+	PreAuthOnline string = "PRE_AUTH_ONLINE"
 )
 
+// DefaultTradeTimeout 未指定 time_expire 时支付宝默认的订单超时时间
+const DefaultTradeTimeout = 15 * 24 * time.Hour
+
 // VerificationScene 校验场景
 type VerificationScene int
 
@@ -71,6 +98,8 @@ var ErrTrans = errors.New("xpay:transform error")
 var ErrNotContainsSignData = errors.New("xpay:not contains sign data error")
 var ErrRequestTimeout = errors.New("xpay: request timeout error")
 var ErrRequest = errors.New("xpay: request  error")
+var ErrTradeNoEmpty = errors.New("xpay: trade_no is empty")
+var ErrInvalidBizContent = errors.New("xpay: biz_content is not valid json")
 
 // exclude key
 const (
@@ -0,0 +1,129 @@
+package alipay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+/**
+ * @author: Sam
+ * @since: 1.0.0
+ * @date: 2026/10/20 01:10
+ * @desc: 电子回单申请及下载
+ *
+ * alipay.data.bill.ereceipt.apply 返回文件申请号后回单异步生成，需要轮询 alipay.data.bill.ereceipt.query
+ * 直到状态为 SUCCESS，再从返回的 download_url 下载PDF，下载地址的有效期较短，查询到后立即下载。
+ */
+
+var (
+	ErrEreceiptFailed  = errors.New("xpay: ereceipt generation failed")
+	ErrEreceiptTimeout = errors.New("xpay: ereceipt not ready before max wait")
+)
+
+type ereceiptOption struct {
+	pollInterval time.Duration
+	maxInterval  time.Duration
+	maxWait      time.Duration
+}
+
+type EreceiptOpt func(option *ereceiptOption)
+
+// WithEreceiptPollInterval 第一次查询前的等待时间，之后每次翻倍，默认1秒
+func WithEreceiptPollInterval(interval time.Duration) EreceiptOpt {
+	return func(option *ereceiptOption) {
+		option.pollInterval = interval
+	}
+}
+
+// WithEreceiptMaxInterval 查询间隔的上限，默认10秒
+func WithEreceiptMaxInterval(interval time.Duration) EreceiptOpt {
+	return func(option *ereceiptOption) {
+		option.maxInterval = interval
+	}
+}
+
+// WithEreceiptMaxWait 从申请开始等待回单生成的总时长，默认2分钟
+func WithEreceiptMaxWait(maxWait time.Duration) EreceiptOpt {
+	return func(option *ereceiptOption) {
+		option.maxWait = maxWait
+	}
+}
+
+// DownloadEreceipt 申请电子回单，按退避间隔查询直到回单生成，使用客户端的httpClient将PDF写入w，返回最后一次查询的结果。
+// 回单生成失败时返回 ErrEreceiptFailed，超过等待时间时返回 ErrEreceiptTimeout，查询请求失败时继续查询直到超时
+func (r *Client) DownloadEreceipt(ctx context.Context, req DataBillEreceiptApplyReq, w io.Writer, opts ...EreceiptOpt) (*DataBillEreceiptQueryRes, error) {
+	option := ereceiptOption{pollInterval: time.Second, maxInterval: 10 * time.Second, maxWait: 2 * time.Minute}
+	for _, opt := range opts {
+		opt(&option)
+	}
+	applyRes, err := r.DataBillEreceiptApply(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if applyRes.Fail() {
+		return nil, fmt.Errorf("xpay: ereceipt apply failed, sub_code: %s, sub_msg: %s", applyRes.SubCode, applyRes.SubMsg)
+	}
+	res, err := r.waitEreceipt(ctx, DataBillEreceiptQueryReq{FileId: applyRes.FileId, BillUserId: req.BillUserId}, option)
+	if err != nil {
+		return res, err
+	}
+	return res, r.downloadEreceipt(ctx, res.DownloadUrl, w)
+}
+
+// waitEreceipt 轮询回单状态直到 SUCCESS 或者 FAIL
+func (r *Client) waitEreceipt(ctx context.Context, req DataBillEreceiptQueryReq, option ereceiptOption) (*DataBillEreceiptQueryRes, error) {
+	deadline := time.Now().Add(option.maxWait)
+	interval := option.pollInterval
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+	var res *DataBillEreceiptQueryRes
+	for {
+		select {
+		case <-ctx.Done():
+			return res, ctx.Err()
+		case <-timer.C:
+		}
+		queryRes, err := r.DataBillEreceiptQuery(ctx, req)
+		if err == nil && queryRes.Success() {
+			res = queryRes
+			switch res.Status {
+			case EreceiptStatusSuccess:
+				return res, nil
+			case EreceiptStatusFail:
+				return res, fmt.Errorf("%w: %s", ErrEreceiptFailed, res.ErrorMessage)
+			}
+		}
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return res, ErrEreceiptTimeout
+		}
+		if interval *= 2; interval > option.maxInterval {
+			interval = option.maxInterval
+		}
+		if interval > remaining {
+			interval = remaining
+		}
+		timer.Reset(interval)
+	}
+}
+
+func (r *Client) downloadEreceipt(ctx context.Context, downloadURL string, w io.Writer) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, downloadURL, nil)
+	if err != nil {
+		return err
+	}
+	response, err := r.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("xpay: ereceipt download failed, status: %s", response.Status)
+	}
+	_, err = io.Copy(w, response.Body)
+	return err
+}
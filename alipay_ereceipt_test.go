@@ -0,0 +1,58 @@
+package alipay_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/try-labs/xpay"
+	"github.com/try-labs/xpay/alipaytest"
+)
+
+/**
+ * @author: Sam
+ * @since:
+ * @date: 2026/10/20 01:10
+ * @desc:
+ */
+
+func TestClient_DownloadEreceipt(t *testing.T) {
+	g := alipaytest.NewGateway()
+	defer g.Close()
+	ereceiptClient, err := g.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	opts := []EreceiptOpt{WithEreceiptPollInterval(10 * time.Millisecond), WithEreceiptMaxInterval(40 * time.Millisecond), WithEreceiptMaxWait(time.Second)}
+
+	g.SetEreceiptPending(3)
+	var pdf bytes.Buffer
+	res, err := ereceiptClient.DownloadEreceipt(ctx, DataBillEreceiptApplyReq{Type: EreceiptTypeFundDetail, Key: "20261020110000001"}, &pdf, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Status != EreceiptStatusSuccess || g.Calls("alipay.data.bill.ereceipt.query") != 4 {
+		t.Errorf("unexpected result: %+v", res)
+	}
+	if !bytes.HasPrefix(pdf.Bytes(), []byte("%PDF-")) || !bytes.Contains(pdf.Bytes(), []byte("20261020110000001")) {
+		t.Errorf("unexpected pdf: %q", pdf.String())
+	}
+
+	// 交易不存在时回单生成失败
+	g.SetEreceiptPending(0)
+	pdf.Reset()
+	res, err = ereceiptClient.DownloadEreceipt(ctx, DataBillEreceiptApplyReq{Type: EreceiptTypeTrade, Key: "2026102022001446880000000001"}, &pdf, opts...)
+	if !errors.Is(err, ErrEreceiptFailed) || res.Status != EreceiptStatusFail || pdf.Len() != 0 {
+		t.Errorf("unexpected result: %+v %v", res, err)
+	}
+
+	g.SetEreceiptPending(100)
+	start := time.Now()
+	res, err = ereceiptClient.DownloadEreceipt(ctx, DataBillEreceiptApplyReq{Type: EreceiptTypeFundDetail, Key: "20261020110000002"}, &pdf, WithEreceiptPollInterval(10*time.Millisecond), WithEreceiptMaxWait(100*time.Millisecond))
+	if err != ErrEreceiptTimeout || res.Status != EreceiptStatusInit || time.Since(start) > time.Second {
+		t.Errorf("unexpected result: %+v %v", res, err)
+	}
+}
@@ -0,0 +1,237 @@
+package alipay
+
+import (
+	"context"
+	"time"
+)
+
+/**
+ * @author: Sam
+ * @since: 1.0.0
+ * @date: 2026/10/19 21:40
+ * @desc: 当面付条码支付流程
+ *
+ * alipay.trade.pay 返回10003(等待用户输入密码)、20000(系统异常)或者请求超时时支付结果未知，
+ * 需要轮询 alipay.trade.query 直到交易状态确定，超过等待时间仍未支付时调用 alipay.trade.cancel 撤销交易。
+ * 文档：https://opendocs.alipay.com/open/194/105170
+ */
+
+const (
+	CodeWaitBuyerPay     = "10003"               // 支付处理中，等待用户输入密码
+	CodeUnknownError     = "20000"               // 服务不可用，结果未知
+	SubCodeSysError      = "ACQ.SYSTEM_ERROR"    // 系统繁忙，结果未知
+	SubCodeTradeNotExist = "ACQ.TRADE_NOT_EXIST" // 交易不存在
+)
+
+// FaceToFaceOutcome 条码支付的最终结果
+type FaceToFaceOutcome string
+
+const (
+	FaceToFacePaid      FaceToFaceOutcome = "PAID"      // 支付成功
+	FaceToFaceFailed    FaceToFaceOutcome = "FAILED"    // 支付失败，例如付款码无效、余额不足
+	FaceToFaceClosed    FaceToFaceOutcome = "CLOSED"    // 轮询期间交易被关闭
+	FaceToFaceCancelled FaceToFaceOutcome = "CANCELLED" // 超过等待时间后已撤销，用户已付款的交易已全额退款
+	FaceToFaceUnknown   FaceToFaceOutcome = "UNKNOWN"   // 撤销重试后仍然失败或者ctx结束，需要人工处理或稍后重新撤销
+)
+
+// FaceToFaceAction 流程中调用的接口
+type FaceToFaceAction string
+
+const (
+	FaceToFaceActionPay    FaceToFaceAction = "pay"
+	FaceToFaceActionQuery  FaceToFaceAction = "query"
+	FaceToFaceActionCancel FaceToFaceAction = "cancel"
+)
+
+// FaceToFaceEvent 一次接口调用的结果
+type FaceToFaceEvent struct {
+	Time         time.Time        `json:"time"`
+	Action       FaceToFaceAction `json:"action"`
+	Code         string           `json:"code,omitempty"`
+	SubCode      string           `json:"sub_code,omitempty"`
+	SubMsg       string           `json:"sub_msg,omitempty"`
+	TradeStatus  TradeStatus      `json:"trade_status,omitempty"`  // 交易查询返回的交易状态
+	CancelAction string           `json:"cancel_action,omitempty"` // 撤销触发的动作 close 或 refund
+	Error        string           `json:"error,omitempty"`         // 请求失败，例如超时、验签失败
+}
+
+// FaceToFaceResult 条码支付的结果及接口调用记录
+type FaceToFaceResult struct {
+	Outcome    FaceToFaceOutcome  `json:"outcome"`
+	OutTradeNo string             `json:"out_trade_no"`
+	TradeNo    string             `json:"trade_no,omitempty"`
+	Pay        *TradePayRes       `json:"pay,omitempty"`    // 支付接口的响应
+	Query      *TradeQueryRes     `json:"query,omitempty"`  // 最后一次成功的交易查询
+	Cancel     *TradeCancelRes    `json:"cancel,omitempty"` // 最后一次撤销的响应
+	Events     []*FaceToFaceEvent `json:"events"`
+}
+
+type faceToFaceOption struct {
+	pollInterval  time.Duration
+	maxWait       time.Duration
+	cancelRetries int
+}
+
+type FaceToFaceOpt func(option *faceToFaceOption)
+
+// WithPollInterval 交易查询的间隔，撤销重试也使用该间隔，默认5秒
+func WithPollInterval(interval time.Duration) FaceToFaceOpt {
+	return func(option *faceToFaceOption) {
+		option.pollInterval = interval
+	}
+}
+
+// WithMaxWait 从发起支付开始等待用户付款的总时长，超过后撤销交易，默认30秒
+func WithMaxWait(maxWait time.Duration) FaceToFaceOpt {
+	return func(option *faceToFaceOption) {
+		option.maxWait = maxWait
+	}
+}
+
+// WithCancelRetries 撤销失败或者返回需要重试时的重试次数，默认3次
+func WithCancelRetries(retries int) FaceToFaceOpt {
+	return func(option *faceToFaceOption) {
+		option.cancelRetries = retries
+	}
+}
+
+// faceToFacePay 一次条码支付流程的状态
+type faceToFacePay struct {
+	client *Client
+	option faceToFaceOption
+	result *FaceToFaceResult
+}
+
+// FaceToFacePay 条码支付，支付结果未知时轮询交易状态，超过等待时间后撤销交易，返回最终结果。
+// 只有请求参数校验失败或者ctx结束时返回error，ctx结束时结果为 FaceToFaceUnknown
+func (r *Client) FaceToFacePay(ctx context.Context, req TradePayReq, opts ...FaceToFaceOpt) (*FaceToFaceResult, error) {
+	if err := req.DoValidate(); err != nil {
+		return nil, err
+	}
+	f := &faceToFacePay{
+		client: r,
+		option: faceToFaceOption{pollInterval: 5 * time.Second, maxWait: 30 * time.Second, cancelRetries: 3},
+		result: &FaceToFaceResult{OutTradeNo: req.OutTradeNo, Events: []*FaceToFaceEvent{}},
+	}
+	for _, opt := range opts {
+		opt(&f.option)
+	}
+	deadline := time.Now().Add(f.option.maxWait)
+
+	res, err := r.TradePay(ctx, req)
+	event := f.event(FaceToFaceActionPay, err)
+	if err == nil {
+		f.result.Pay = res
+		event.Code, event.SubCode, event.SubMsg = res.Code, res.SubCode, res.SubMsg
+		if len(res.TradeNo) > 0 {
+			f.result.TradeNo = res.TradeNo
+		}
+		switch {
+		case res.Success():
+			return f.finish(FaceToFacePaid), nil
+		case !resultUnknown(&res.CommonRes):
+			return f.finish(FaceToFaceFailed), nil
+		}
+	}
+
+	if outcome, err := f.poll(ctx, deadline); err != nil || len(outcome) > 0 {
+		return f.finish(outcome), err
+	}
+	return f.cancel(ctx)
+}
+
+// resultUnknown 业务结果未知，需要查询或者重试
+func resultUnknown(res *CommonRes) bool {
+	return res.Code == CodeWaitBuyerPay || res.Code == CodeUnknownError || res.SubCode == SubCodeSysError
+}
+
+func (f *faceToFacePay) event(action FaceToFaceAction, err error) *FaceToFaceEvent {
+	event := &FaceToFaceEvent{Time: time.Now(), Action: action}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	f.result.Events = append(f.result.Events, event)
+	return event
+}
+
+func (f *faceToFacePay) finish(outcome FaceToFaceOutcome) *FaceToFaceResult {
+	if len(outcome) == 0 {
+		outcome = FaceToFaceUnknown
+	}
+	f.result.Outcome = outcome
+	return f.result
+}
+
+// sleep 等待一个轮询间隔，ctx结束时返回error
+func (f *faceToFacePay) sleep(ctx context.Context) error {
+	timer := time.NewTimer(f.option.pollInterval)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// poll 轮询交易状态，交易状态确定时返回最终结果，超过等待时间仍未确定时返回空
+func (f *faceToFacePay) poll(ctx context.Context, deadline time.Time) (FaceToFaceOutcome, error) {
+	for time.Now().Add(f.option.pollInterval).Before(deadline) {
+		if err := f.sleep(ctx); err != nil {
+			return "", err
+		}
+		res, err := f.client.TradeQuery(ctx, TradeQueryReq{OutTradeNo: f.result.OutTradeNo})
+		event := f.event(FaceToFaceActionQuery, err)
+		if err != nil {
+			if ctx.Err() != nil {
+				return "", ctx.Err()
+			}
+			continue
+		}
+		event.Code, event.SubCode, event.SubMsg, event.TradeStatus = res.Code, res.SubCode, res.SubMsg, res.TradeStatus
+		// 交易不存在说明支付请求尚未到达支付宝，继续查询，撤销时会关闭该订单号防止后续付款
+		if res.Fail() {
+			continue
+		}
+		f.result.Query = res
+		f.result.TradeNo = res.TradeNo
+		switch res.TradeStatus {
+		case TradeSuccess, TradeFinished:
+			return FaceToFacePaid, nil
+		case TradeClosed:
+			return FaceToFaceClosed, nil
+		}
+	}
+	return "", nil
+}
+
+// cancel 撤销交易，失败或者返回需要重试时按轮询间隔重试
+func (f *faceToFacePay) cancel(ctx context.Context) (*FaceToFaceResult, error) {
+	for i := 0; i <= f.option.cancelRetries; i++ {
+		if i > 0 {
+			if err := f.sleep(ctx); err != nil {
+				return f.finish(FaceToFaceUnknown), err
+			}
+		}
+		res, err := f.client.TradeCancel(ctx, TradeCancelReq{OutTradeNo: f.result.OutTradeNo})
+		event := f.event(FaceToFaceActionCancel, err)
+		if err != nil {
+			if ctx.Err() != nil {
+				return f.finish(FaceToFaceUnknown), ctx.Err()
+			}
+			continue
+		}
+		f.result.Cancel = res
+		event.Code, event.SubCode, event.SubMsg, event.CancelAction = res.Code, res.SubCode, res.SubMsg, res.Action
+		if len(res.TradeNo) > 0 {
+			f.result.TradeNo = res.TradeNo
+		}
+		if res.Success() && res.RetryFlag != "Y" {
+			return f.finish(FaceToFaceCancelled), nil
+		}
+		if res.Fail() && res.RetryFlag != "Y" && !resultUnknown(&res.CommonRes) {
+			break
+		}
+	}
+	return f.finish(FaceToFaceUnknown), nil
+}
@@ -0,0 +1,141 @@
+package alipay_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/try-labs/xpay"
+	"github.com/try-labs/xpay/alipaytest"
+)
+
+/**
+ * @author: Sam
+ * @since:
+ * @date: 2026/10/19 21:40
+ * @desc:
+ */
+
+func faceToFaceReq(outTradeNo, authCode string) TradePayReq {
+	return TradePayReq{OutTradeNo: outTradeNo, TotalAmount: MustParseMoney("18.88"), Subject: "测试商品", AuthCode: authCode, Scene: "bar_code"}
+}
+
+func faceToFaceOpts() []FaceToFaceOpt {
+	return []FaceToFaceOpt{WithPollInterval(10 * time.Millisecond), WithMaxWait(200 * time.Millisecond), WithCancelRetries(2)}
+}
+
+func actions(result *FaceToFaceResult) map[FaceToFaceAction]int {
+	count := make(map[FaceToFaceAction]int)
+	for _, event := range result.Events {
+		count[event.Action]++
+	}
+	return count
+}
+
+func TestClient_FaceToFacePay_Paid(t *testing.T) {
+	result, err := client.FaceToFacePay(context.Background(), faceToFaceReq("20261019214001", "281234567890123401"), faceToFaceOpts()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Outcome != FaceToFacePaid || len(result.Events) != 1 || len(result.TradeNo) == 0 || result.Pay.ReceiptAmount != MustParseMoney("18.88") {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestClient_FaceToFacePay_WaitPassword(t *testing.T) {
+	gateway.RequirePassword("281234567890123402")
+	queries := gateway.Calls("alipay.trade.query")
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		// 第一次查询之后用户输入密码完成付款
+		for gateway.Calls("alipay.trade.query") == queries {
+			time.Sleep(time.Millisecond)
+		}
+		if err := gateway.PayTrade("20261019214002", ""); err != nil {
+			t.Error(err)
+		}
+	}()
+	result, err := client.FaceToFacePay(context.Background(), faceToFaceReq("20261019214002", "281234567890123402"), faceToFaceOpts()...)
+	<-done
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Outcome != FaceToFacePaid || result.Events[0].Code != "10003" || result.Query.TradeStatus != TradeSuccess {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if count := actions(result); count[FaceToFaceActionQuery] != gateway.Calls("alipay.trade.query")-queries || count[FaceToFaceActionCancel] != 0 {
+		t.Errorf("unexpected events: %v", count)
+	}
+}
+
+func TestClient_FaceToFacePay_Cancelled(t *testing.T) {
+	gateway.RequirePassword("281234567890123403")
+	result, err := client.FaceToFacePay(context.Background(), faceToFaceReq("20261019214003", "281234567890123403"), faceToFaceOpts()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := actions(result)
+	if result.Outcome != FaceToFaceCancelled || count[FaceToFaceActionQuery] == 0 || count[FaceToFaceActionCancel] != 1 || result.Cancel.Action != "close" {
+		t.Errorf("unexpected result: %+v %v", result, count)
+	}
+	if trade, _ := gateway.Trade("20261019214003"); trade.Status != TradeClosed {
+		t.Errorf("trade should be closed: %s", trade.Status)
+	}
+	last := result.Events[len(result.Events)-1]
+	if last.Action != FaceToFaceActionCancel || last.CancelAction != "close" || result.Events[0].Time.After(last.Time) {
+		t.Errorf("unexpected timeline: %+v", result.Events)
+	}
+}
+
+func TestClient_FaceToFacePay_Failed(t *testing.T) {
+	gateway.InjectError("alipay.trade.pay", alipaytest.ErrBuyerBalanceNotEnough, 1)
+	queries := gateway.Calls("alipay.trade.query")
+	result, err := client.FaceToFacePay(context.Background(), faceToFaceReq("20261019214004", "281234567890123404"), faceToFaceOpts()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Outcome != FaceToFaceFailed || result.Events[0].SubCode != "ACQ.BUYER_BALANCE_NOT_ENOUGH" || gateway.Calls("alipay.trade.query") != queries {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+// TestClient_FaceToFacePay_CancelRetry 支付返回系统异常且交易未创建，查询一直返回交易不存在，撤销失败两次后成功
+func TestClient_FaceToFacePay_CancelRetry(t *testing.T) {
+	defer gateway.ClearErrors()
+	gateway.InjectError("alipay.trade.pay", alipaytest.ErrSystemError, 1)
+	gateway.InjectError("alipay.trade.cancel", alipaytest.ErrSystemError, 2)
+	result, err := client.FaceToFacePay(context.Background(), faceToFaceReq("20261019214005", "281234567890123405"), faceToFaceOpts()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Outcome != FaceToFaceCancelled || actions(result)[FaceToFaceActionCancel] != 3 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if result.Events[1].SubCode != "ACQ.TRADE_NOT_EXIST" {
+		t.Errorf("unexpected query event: %+v", result.Events[1])
+	}
+
+	gateway.RequirePassword("281234567890123406")
+	gateway.InjectError("alipay.trade.cancel", alipaytest.ErrSystemError, 0)
+	result, err = client.FaceToFacePay(context.Background(), faceToFaceReq("20261019214006", "281234567890123406"), faceToFaceOpts()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Outcome != FaceToFaceUnknown || actions(result)[FaceToFaceActionCancel] != 3 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestClient_FaceToFacePay_Context(t *testing.T) {
+	gateway.RequirePassword("281234567890123407")
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	result, err := client.FaceToFacePay(ctx, faceToFaceReq("20261019214007", "281234567890123407"), WithPollInterval(10*time.Millisecond))
+	if err != context.DeadlineExceeded || result.Outcome != FaceToFaceUnknown {
+		t.Errorf("unexpected result: %v %+v", err, result)
+	}
+	if _, err = client.FaceToFacePay(context.Background(), faceToFaceReq("20261019214008", "")); err == nil {
+		t.Error("missing auth_code should fail")
+	}
+}
@@ -0,0 +1,145 @@
+package alipay
+
+import (
+	"bytes"
+	"errors"
+	"html/template"
+	"net/url"
+	"sort"
+	"strconv"
+)
+
+/**
+ * @author: Sam
+ * @since: 1.0.0
+ * @date: 2026/10/19 15:10
+ * @desc: 页面支付的表单及iframe输出，参数较多时使用POST表单提交，避免GET地址超出浏览器和代理的长度限制
+ */
+
+var ErrQrPayMode = errors.New("xpay: qr_pay_mode must be one of 0, 1, 3, 4 for iframe")
+var ErrQrcodeWidth = errors.New("xpay: qrcode_width must be a positive integer when qr_pay_mode is 4")
+
+var payFormTemplate = template.Must(template.New("payForm").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"></head>
+<body>
+<form name="punchout_form" method="post" action="{{.Action}}">
+{{- range .Fields}}
+<input type="hidden" name="{{.Name}}" value="{{.Value}}">
+{{- end}}
+<input type="submit" value="立即支付" style="display:none">
+</form>
+<script{{if .Nonce}} nonce="{{.Nonce}}"{{end}}>document.forms["punchout_form"].submit();</script>
+</body>
+</html>
+`))
+
+var payIframeTemplate = template.Must(template.New("payIframe").Parse(
+	`<iframe src="{{.Src}}" width="{{.Width}}" height="{{.Height}}" frameborder="0" scrolling="no"></iframe>`))
+
+type formField struct {
+	Name  string
+	Value string
+}
+
+type formOption struct {
+	nonce string
+}
+
+type FormOpt func(option *formOption)
+
+// WithFormNonce 设置自动提交脚本的nonce，用于开启了CSP(Content-Security-Policy)的页面
+func WithFormNonce(nonce string) FormOpt {
+	return func(option *formOption) {
+		option.nonce = nonce
+	}
+}
+
+// TradePagePayForm alipay.trade.page.pay(统一收单下单并支付页面接口) 以自动提交的POST表单形式输出
+func (r *Client) TradePagePayForm(req TradePagePayReq, opts ...FormOpt) (string, error) {
+	encode, _, err := r.encodeRequest(&req, WithNotifyUrl(req.NotifyUrl), WithReturnUrl(req.ReturnUrl))
+	if err != nil {
+		return "", err
+	}
+	return r.renderPayForm(encode, opts...)
+}
+
+// TradeWapPayForm alipay.trade.wap.pay(手机网站支付接口2.0) 以自动提交的POST表单形式输出
+func (r *Client) TradeWapPayForm(req TradeWapPayReq, opts ...FormOpt) (string, error) {
+	encode, _, err := r.encodeRequest(&req, WithNotifyUrl(req.NotifyUrl), WithReturnUrl(req.ReturnUrl))
+	if err != nil {
+		return "", err
+	}
+	return r.renderPayForm(encode, opts...)
+}
+
+// TradePagePayIframe alipay.trade.page.pay 订单码前置模式，输出嵌入商户页面的iframe
+// qr_pay_mode 取值 0、1、3 时按支付宝要求的最小尺寸输出，取值 4 时使用 qrcode_width 作为宽高，跳转模式 2 不支持iframe
+func (r *Client) TradePagePayIframe(req TradePagePayReq) (string, error) {
+	width, height, err := qrPayModeSize(req.QrPayMode, req.QrcodeWidth)
+	if err != nil {
+		return "", err
+	}
+	var payURL *url.URL
+	if payURL, err = r.TradePagePay(req); err != nil {
+		return "", err
+	}
+	var buff bytes.Buffer
+	data := struct {
+		Src           string
+		Width, Height int
+	}{payURL.String(), width, height}
+	if err = payIframeTemplate.Execute(&buff, data); err != nil {
+		return "", err
+	}
+	return buff.String(), nil
+}
+
+func qrPayModeSize(qrPayMode, qrcodeWidth string) (int, int, error) {
+	switch qrPayMode {
+	case "0":
+		return 600, 300, nil
+	case "1":
+		return 300, 600, nil
+	case "3":
+		return 75, 75, nil
+	case "4":
+		width, err := strconv.Atoi(qrcodeWidth)
+		if err != nil || width <= 0 {
+			return 0, 0, ErrQrcodeWidth
+		}
+		return width, width, nil
+	}
+	return 0, 0, ErrQrPayMode
+}
+
+// renderPayForm 签名后的参数全部放在表单中提交，charset放在地址上以便网关正确解码表单
+func (r *Client) renderPayForm(encode string, opts ...FormOpt) (string, error) {
+	option := new(formOption)
+	for _, opt := range opts {
+		opt(option)
+	}
+	values, err := url.ParseQuery(encode)
+	if err != nil {
+		return "", err
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	fields := make([]formField, 0, len(keys))
+	for _, key := range keys {
+		fields = append(fields, formField{Name: key, Value: values.Get(key)})
+	}
+	data := struct {
+		Action string
+		Fields []formField
+		Nonce  string
+	}{r.serverUrl + "?charset=" + CharsetUTF8, fields, option.nonce}
+	var buff bytes.Buffer
+	if err = payFormTemplate.Execute(&buff, data); err != nil {
+		return "", err
+	}
+	return buff.String(), nil
+}
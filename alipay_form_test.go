@@ -0,0 +1,82 @@
+package alipay
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+/**
+ * @author: Sam
+ * @since:
+ * @date: 2026/10/19 15:40
+ * @desc:
+ */
+
+var hiddenInputRegexp = regexp.MustCompile(`<input type="hidden" name="([^"]+)" value="([^"]*)">`)
+
+func TestClient_TradePagePayForm(t *testing.T) {
+	client := newLocalTestClient(t)
+	goods := NewGoodsDetail("1111", "iphone", 1, MustParseMoney("9.99")).SetShowURL("https://example.com/a?b=1&c=<2>")
+	req := NewTradePagePayReq("20150320010101001", MustParseMoney("9.99"), `测试"title"`, WithGoodsDetail([]*GoodsDetail{goods}))
+	req.ReturnUrl = "https://example.com/return"
+	page, err := client.TradePagePayForm(*req, WithFormNonce("r4nd0m"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(page, `action="https://openapi.alipaydev.com/gateway.do?charset=utf-8"`) {
+		t.Errorf("unexpected form action: %s", page)
+	}
+	if !strings.Contains(page, `<script nonce="r4nd0m">`) {
+		t.Errorf("nonce expected: %s", page)
+	}
+	values := url.Values{}
+	for _, match := range hiddenInputRegexp.FindAllStringSubmatch(page, -1) {
+		values.Set(match[1], html.UnescapeString(match[2]))
+	}
+	if values.Get("method") != "alipay.trade.page.pay" || values.Get("return_url") != req.ReturnUrl || len(values.Get("sign")) == 0 {
+		t.Errorf("unexpected form fields: %v", values)
+	}
+	if !strings.Contains(values.Get("biz_content"), `"subject":"测试\"title\""`) {
+		t.Errorf("unexpected biz_content: %s", values.Get("biz_content"))
+	}
+
+	page, err = client.TradeWapPayForm(*NewTradeWapPayReq("20150320010101001", MustParseMoney("9.99"), "title"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(page, "<script>") || !strings.Contains(page, `value="alipay.trade.wap.pay"`) {
+		t.Errorf("unexpected wap form: %s", page)
+	}
+}
+
+func TestClient_TradePagePayIframe(t *testing.T) {
+	client := newLocalTestClient(t)
+	cases := []struct {
+		mode, width string
+		want        string
+		err         error
+	}{
+		{"0", "", `width="600" height="300"`, nil},
+		{"1", "", `width="300" height="600"`, nil},
+		{"3", "", `width="75" height="75"`, nil},
+		{"4", "120", `width="120" height="120"`, nil},
+		{"4", "abc", "", ErrQrcodeWidth},
+		{"2", "", "", ErrQrPayMode},
+		{"", "", "", ErrQrPayMode},
+	}
+	for _, c := range cases {
+		req := NewTradePagePayReq("20150320010101001", MustParseMoney("9.99"), "title", WithQrcodeWidth(c.width))
+		req.QrPayMode = c.mode
+		page, err := client.TradePagePayIframe(*req)
+		if err != c.err {
+			t.Errorf("mode %s: err = %v, want %v", c.mode, err, c.err)
+			continue
+		}
+		if err == nil && (!strings.HasPrefix(page, `<iframe src="https://openapi.alipaydev.com/gateway.do?`) || !strings.Contains(page, c.want)) {
+			t.Errorf("mode %s: unexpected iframe %s", c.mode, page)
+		}
+	}
+}
@@ -1,8 +1,10 @@
 package alipay
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"net/url"
 )
 
 /**
@@ -15,16 +17,13 @@ import (
 var _ IAliPayRequest = &FundAccountQueryReq{}
 
 type FundAccountQueryReq struct {
-	AlipayUserId string `json:"alipay_user_id"`         //	必选	28 支付宝会员 id。 2088301409188095
-	AccountType  string `json:"account_type,omitempty"` // 特殊可选	30 查询的账号类型，查询余额账户值为ACCTRANS_ACCOUNT。必填。
+	AlipayUserId string `json:"alipay_user_id" validate:"required,max=28"` //	必选	28 支付宝会员 id。 2088301409188095
+	AccountType  string `json:"account_type,omitempty" validate:"max=30"`  // 特殊可选	30 查询的账号类型，查询余额账户值为ACCTRANS_ACCOUNT。必填。
 	baseAliPayRequest
 }
 
 func (r *FundAccountQueryReq) DoValidate() error {
-	if len(r.AlipayUserId) == 0 {
-		return fmt.Errorf("支付宝会员alipay_user_id 参数必传")
-	}
-	return nil
+	return ValidateStruct(r)
 }
 
 func (r *FundAccountQueryReq) RequestApi() string {
@@ -38,8 +37,8 @@ type FundAccountQueryRes struct {
 
 type FundAccountQueryResContent struct {
 	CommonRes
-	AvailableAmount string `json:"available_amount"`        // 必选	15 账户可用余额，单位元，精确到小数点后两位。 26.45
-	FreezeAmount    string `json:"freeze_amount,omitempty"` // 可选	15 当前支付宝账户的实时冻结余额 11.11
+	AvailableAmount Money `json:"available_amount"`        // 必选	15 账户可用余额，单位元，精确到小数点后两位。 26.45
+	FreezeAmount    Money `json:"freeze_amount,omitempty"` // 可选	15 当前支付宝账户的实时冻结余额 11.11
 }
 
 func (r *FundAccountQueryRes) String() string {
@@ -52,26 +51,23 @@ func (r *FundAccountQueryRes) String() string {
 var _ IAliPayRequest = &FundTransToAccountTransferReq{}
 
 type FundTransToAccountTransferReq struct {
-	OutBizNo  string `json:"out_biz_no"` // 必选	64 商户转账唯一订单号。发起转账来源方定义的转账单据ID，用于将转账回执通知给来源方。 不同来源方给出的ID可以重复，同一个来源方必须保证其ID的唯一性。 只支持半角英文、数字，及“-”、“_”。
-	PayeeType string `json:"payee_type"` // 必选	20 收款方账户类型。可取值：
+	OutBizNo  string `json:"out_biz_no" validate:"required,max=64"`                                          // 必选	64 商户转账唯一订单号。发起转账来源方定义的转账单据ID，用于将转账回执通知给来源方。 不同来源方给出的ID可以重复，同一个来源方必须保证其ID的唯一性。 只支持半角英文、数字，及“-”、“_”。
+	PayeeType string `json:"payee_type" validate:"required,enum=ALIPAY_USERID|ALIPAY_LOGONID|ALIPAY_OPENID"` // 必选	20 收款方账户类型。可取值：
 	/*
 		1、ALIPAY_USERID：支付宝账号对应的支付宝唯一用户号。以2088开头的16位纯数字组成。
 		2、ALIPAY_LOGONID：支付宝登录号，支持邮箱和手机号格式。
 		2、ALIPAY_OPENID：支付宝openid
 	*/
-	PayeeAccount  string `json:"payee_account"`             // 必选	100 收款方账户。与payee_type配合使用。付款方和收款方不能是同一个账户。
-	Amount        string `json:"amount"`                    // 必选	16 转账金额，单位：元。 只支持2位小数，小数点前最大支持13位，金额必须大于等于0.1元。 最大转账金额以实际签约的限额为准。
-	PayerShowName string `json:"payer_show_name,omitempty"` // 可选	100 付款方姓名（最长支持100个英文/50个汉字）。显示在收款方的账单详情页。如果该字段不传，则默认显示付款方的支付宝认证姓名或单位名称。
-	PayeeRealName string `json:"payee_real_name,omitempty"` // 可选	100 收款方真实姓名（最长支持100个英文/50个汉字）。 如果本参数不为空，则会校验该账户在支付宝登记的实名是否与收款方真实姓名一致。
-	Remark        string `json:"remark,omitempty"`          // 可选	200 转账备注（支持200个英文/100个汉字）。 当付款方为企业账户，且转账金额达到（大于等于）50000元，remark不能为空。收款方可见，会展示在收款用户的收支详情中。
+	PayeeAccount  string `json:"payee_account" validate:"required,max=100"`              // 必选	100 收款方账户。与payee_type配合使用。付款方和收款方不能是同一个账户。
+	Amount        Money  `json:"amount" validate:"required,amount=0.1~9999999999999.99"` // 必选	16 转账金额，单位：元。 只支持2位小数，小数点前最大支持13位，金额必须大于等于0.1元。 最大转账金额以实际签约的限额为准。
+	PayerShowName string `json:"payer_show_name,omitempty" validate:"max=100"`           // 可选	100 付款方姓名（最长支持100个英文/50个汉字）。显示在收款方的账单详情页。如果该字段不传，则默认显示付款方的支付宝认证姓名或单位名称。
+	PayeeRealName string `json:"payee_real_name,omitempty" validate:"max=100"`           // 可选	100 收款方真实姓名（最长支持100个英文/50个汉字）。 如果本参数不为空，则会校验该账户在支付宝登记的实名是否与收款方真实姓名一致。
+	Remark        string `json:"remark,omitempty" validate:"max=200"`                    // 可选	200 转账备注（支持200个英文/100个汉字）。 当付款方为企业账户，且转账金额达到（大于等于）50000元，remark不能为空。收款方可见，会展示在收款用户的收支详情中。
 	baseAliPayRequest
 }
 
 func (r *FundTransToAccountTransferReq) DoValidate() error {
-	if len(r.OutBizNo) == 0 {
-		return fmt.Errorf("商户转账唯一订单号out_biz_no参数必传")
-	}
-	return nil
+	return ValidateStruct(r)
 }
 
 func (r *FundTransToAccountTransferReq) RequestApi() string {
@@ -98,16 +94,13 @@ func (r *FundTransToAccountTransferRes) String() string {
 /////////////////////////////////////////////
 
 type FundTransOrderQueryReq struct {
-	OutBizNo string `json:"out_biz_no"`         // 必选	64 商户转账唯一订单号：发起转账来源方定义的转账单据号。请求时对应的参数，原样返回。
-	OrderId  string `json:"order_id,omitempty"` // 可选	64 支付宝转账单据号，成功一定返回，失败可能不返回也可能返回。
+	OutBizNo string `json:"out_biz_no" validate:"required,max=64"` // 必选	64 商户转账唯一订单号：发起转账来源方定义的转账单据号。请求时对应的参数，原样返回。
+	OrderId  string `json:"order_id,omitempty" validate:"max=64"`  // 可选	64 支付宝转账单据号，成功一定返回，失败可能不返回也可能返回。
 	baseAliPayRequest
 }
 
 func (r *FundTransOrderQueryReq) DoValidate() error {
-	if len(r.OutBizNo) == 0 {
-		return fmt.Errorf("商户转账唯一订单号out_biz_no参数必传")
-	}
-	return nil
+	return ValidateStruct(r)
 }
 
 func (r *FundTransOrderQueryReq) RequestApi() string {
@@ -133,7 +126,7 @@ type FundTransOrderQueryResContent struct {
 	*/
 	PayDate        string `json:"pay_date,omitempty"`         // 可选	20 支付时间，格式为yyyy-MM-dd HH:mm:ss，转账失败不返回。 2013-01-01 08:08:08
 	ArrivalTimeEnd string `json:"arrival_time_end,omitempty"` // 可选	20 预计到账时间，转账到银行卡专用，格式为yyyy-MM-dd HH:mm:ss，转账受理失败不返回。 注意： 此参数为预计时间，可能与实际到账时间有较大误差，不能作为实际到账时间使用，仅供参考用途。
-	OrderFee       string `json:"order_fee,omitempty"`        // 可选	20 预计收费金额（元），转账到银行卡专用，数字格式，精确到小数点后2位，转账失败或转账受理失败不返回。 0.02
+	OrderFee       Money  `json:"order_fee,omitempty"`        // 可选	20 预计收费金额（元），转账到银行卡专用，数字格式，精确到小数点后2位，转账失败或转账受理失败不返回。 0.02
 	FailReason     string `json:"fail_reason,omitempty"`      // 可选	100 查询到的订单状态为FAIL失败或REFUND退票时，返回具体的原因。 单笔额度超限
 	OutBizNo       string `json:"out_biz_no,omitempty"`       // 可选	64 发起转账来源方定义的转账单据号。 该参数的赋值均以查询结果中 的 out_biz_no 为准。 如果查询失败，不返回该参数。
 	ErrorCode      string `json:"error_code,omitempty"`       // 可选	100 查询失败时，本参数为错误代 码。 查询成功不返回。 对于退票订单，不返回该参数。
@@ -150,14 +143,14 @@ func (r *FundTransOrderQueryRes) String() string {
 var _ IAliPayRequest = &FundTransUniTransferReq{}
 
 type FundTransUniTransferReq struct {
-	OutBizNo       string      `json:"out_biz_no"`                // 必选	64 商家侧唯一订单号，由商家自定义。对于不同转账请求，商家需保证该订单号在自身系统唯一。
-	TransAmount    string      `json:"trans_amount"`              // 必选	20 订单总金额，单位为元，不支持千位分隔符，精确到小数点后两位，取值范围[0.1,100000000]。
-	ProductCode    string      `json:"product_code"`              // 必选	64 销售产品码。单笔无密转账固定为 TRANS_ACCOUNT_NO_PWD。
-	BizScene       string      `json:"biz_scene"`                 // 必选	64 业务场景。单笔无密转账固定为 DIRECT_TRANSFER。
-	OrderTitle     string      `json:"order_title"`               // 必选	128 转账业务的标题，用于在支付宝用户的账单里显示。
-	PayeeInfo      Participant `json:"payee_info"`                // 必选        收款方信息
-	Remark         string      `json:"remark,omitempty"`          // 可选	200 业务备注。 201905代发
-	BusinessParams string      `json:"business_params,omitempty"` // 可选	2048 转账业务请求的扩展参数，支持传入的扩展参数如下：
+	OutBizNo       string      `json:"out_biz_no" validate:"required,max=64"`                 // 必选	64 商家侧唯一订单号，由商家自定义。对于不同转账请求，商家需保证该订单号在自身系统唯一。
+	TransAmount    Money       `json:"trans_amount" validate:"required,amount=0.1~100000000"` // 必选	20 订单总金额，单位为元，不支持千位分隔符，精确到小数点后两位，取值范围[0.1,100000000]。
+	ProductCode    string      `json:"product_code" validate:"required,max=64"`               // 必选	64 销售产品码。单笔无密转账固定为 TRANS_ACCOUNT_NO_PWD。
+	BizScene       string      `json:"biz_scene" validate:"required,max=64"`                  // 必选	64 业务场景。单笔无密转账固定为 DIRECT_TRANSFER。
+	OrderTitle     string      `json:"order_title" validate:"required,max=128"`               // 必选	128 转账业务的标题，用于在支付宝用户的账单里显示。
+	PayeeInfo      Participant `json:"payee_info" validate:"required"`                        // 必选        收款方信息
+	Remark         string      `json:"remark,omitempty" validate:"max=200"`                   // 可选	200 业务备注。 201905代发
+	BusinessParams string      `json:"business_params,omitempty" validate:"max=2048"`         // 可选	2048 转账业务请求的扩展参数，支持传入的扩展参数如下：
 	/*
 		payer_show_name_use_alias：是否展示付款方别名，可选，收款方在支付宝账单中可见。枚举支持：
 		* true：展示别名，将展示商家支付宝在商家中心 商户信息 > 商户基本信息 页面配置的 商户别名。
@@ -168,10 +161,7 @@ type FundTransUniTransferReq struct {
 }
 
 func (r *FundTransUniTransferReq) DoValidate() error {
-	if len(r.OutBizNo) == 0 {
-		return fmt.Errorf("商户转账唯一订单号out_biz_no参数必传")
-	}
-	return nil
+	return ValidateStruct(r)
 }
 
 func (r *FundTransUniTransferReq) RequestApi() string {
@@ -179,17 +169,17 @@ func (r *FundTransUniTransferReq) RequestApi() string {
 }
 
 type Participant struct {
-	Identity string `json:"identity"` // 	必选	64 参与方的标识 ID。
+	Identity string `json:"identity" validate:"required,max=64"` // 	必选	64 参与方的标识 ID。
 	/*
 		当 identity_type=ALIPAY_USER_ID 时，填写支付宝用户 UID。示例值：2088123412341234。
 		当 identity_type=ALIPAY_LOGON_ID 时，填写支付宝登录号。示例值：186xxxxxxxx。
 	*/
-	IdentityType string `json:"identity_type"` //	必选	64 参与方的标识类型，目前支持
+	IdentityType string `json:"identity_type" validate:"required,enum=ALIPAY_USER_ID|ALIPAY_LOGON_ID"` //	必选	64 参与方的标识类型，目前支持
 	/* 如下枚举：
 	ALIPAY_USER_ID：支付宝会员的用户 ID，可通过 获取会员信息 能力获取。
 	ALIPAY_LOGON_ID：支付宝登录号，支持邮箱和手机号格式。
 	*/
-	Name string `json:"name,omitempty"` // 可选	128 参与方真实姓名。如果非空，将校验收款支付宝账号姓名一致性。 当 identity_type=ALIPAY_LOGON_ID 时，本字段必填。若传入该属性，则在支付宝回单中将会显示这个属性。
+	Name string `json:"name,omitempty" validate:"required_if=IdentityType ALIPAY_LOGON_ID,max=128"` // 可选	128 参与方真实姓名。如果非空，将校验收款支付宝账号姓名一致性。 当 identity_type=ALIPAY_LOGON_ID 时，本字段必填。若传入该属性，则在支付宝回单中将会显示这个属性。
 }
 
 type FundTransUniTransferRes struct {
@@ -215,34 +205,34 @@ func (r *FundTransUniTransferRes) String() string {
 var _ IAliPayRequest = &FundTransCommonQueryReq{}
 
 type FundTransCommonQueryReq struct {
-	ProductCode string `json:"product_code,omitempty"` //	可选	64
+	ProductCode string `json:"product_code,omitempty" validate:"required_with=OutBizNo,max=64"` //	可选	64
 	/*
 		销售产品码，商家和支付宝签约的产品码，如果传递了out_biz_no则该字段为必传。可传值如下：
 		STD_RED_PACKET：现金红包
 		TRANS_ACCOUNT_NO_PWD：单笔无密转账到支付宝账户
 		TRANS_BANKCARD_NO_PWD：单笔无密转账到银行卡
 	*/
-	BizScene string `json:"biz_scene,omitempty"` //  可选 64 描述特定的业务场景，如果传递了out_biz_no则该字段为必传。可取的业务场景如下：
+	BizScene string `json:"biz_scene,omitempty" validate:"required_with=OutBizNo,max=64"` //  可选 64 描述特定的业务场景，如果传递了out_biz_no则该字段为必传。可取的业务场景如下：
 	/*
 		PERSONAL_PAY：C2C现金红包-发红包；
 		PERSONAL_COLLECTION：C2C现金红包-领红包；
 		REFUND：C2C现金红包-红包退回；
 		DIRECT_TRANSFER：B2C现金红包、单笔无密转账
 	*/
-	OutBizNo string `json:"out_biz_no,omitempty"` // 可选	64 商户转账唯一订单号，发起转账来源方定义的转账单据ID。
+	OutBizNo string `json:"out_biz_no,omitempty" validate:"anyof=order,max=64"` // 可选	64 商户转账唯一订单号，发起转账来源方定义的转账单据ID。
 	/*
 		本参数和order_id（支付宝转账单据号）、pay_fund_order_id（支付宝支付资金流水号）三者不能同时为空。
 		当三者同时传入时，将用pay_fund_order_id（支付宝支付资金流水号）进行查询，忽略其余两者；
 		当本参数和支付宝转账单据号同时提供时，将用支付宝转账单据号进行查询，忽略本参数。
 	*/
-	OrderId string `json:"order_id,omitempty"` // 可选	32
+	OrderId string `json:"order_id,omitempty" validate:"anyof=order,max=32"` // 可选	32
 	/*
 		支付宝转账单据号。 本参数和out_biz_no（商户转账唯一订单号）、pay_fund_order_id（支付宝支付资金流水号）三者不能同时为空。
 		当三者同时传入时，将用pay_fund_order_id（支付宝支付资金流水号）进行查询，忽略其余两者；
 		当本参数和pay_fund_order_id（支付宝支付资金流水号）同时提供时，将用支付宝支付资金流水号进行查询，忽略本参数；
 		当本参数和out_biz_no（商户转账唯一订单号）同时提供时，将用本参数进行查询，忽略商户转账唯一订单号。
 	*/
-	PayFundOrderId string `json:"pay_fund_order_id,omitempty"` // 可选	32
+	PayFundOrderId string `json:"pay_fund_order_id,omitempty" validate:"anyof=order,max=32"` // 可选	32
 	/*
 		支付宝支付资金流水号。本参数和支付宝转账单据号、商户转账唯一订单号三者不能同时为空。
 		当本参数和out_biz_no（商户转账唯一订单号）、order_id（支付宝转账单据号）同时提供时，将用本参数进行查询，忽略其余两者；
@@ -253,10 +243,7 @@ type FundTransCommonQueryReq struct {
 }
 
 func (r *FundTransCommonQueryReq) DoValidate() error {
-	if len(r.OutBizNo) == 0 {
-		return fmt.Errorf("商户转账唯一订单号out_biz_no参数必传")
-	}
-	return nil
+	return ValidateStruct(r)
 }
 
 func (r *FundTransCommonQueryReq) RequestApi() string {
@@ -272,11 +259,11 @@ type FundTransCommonQueryResContent struct {
 	OrderId            string `json:"order_id"`                        // 必选	32 支付宝转账单据号，查询失败不返回。
 	PayFundOrderId     string `json:"pay_fund_order_id,omitempty"`     // 可选	32 支付宝支付资金流水号，转账失败不返回。
 	OutBizNo           string `json:"out_biz_no"`                      // 必选	64 商户订单号
-	TransAmount        string `json:"trans_amount,omitempty"`          // 可选	16 付款金额，收银台场景下付款成功后的支付金额，订单状态为SUCCESS才返回，其他状态不返回。 付款金额，单位为元，精确到小数点后两位：32.00
+	TransAmount        Money  `json:"trans_amount,omitempty"`          // 可选	16 付款金额，收银台场景下付款成功后的支付金额，订单状态为SUCCESS才返回，其他状态不返回。 付款金额，单位为元，精确到小数点后两位：32.00
 	Status             string `json:"status"`                          // 必选	64 转账单据状态。可能出现的状态如下： SUCCESS：转账成功； WAIT_PAY：等待支付； CLOSED：订单超时关闭； FAIL：失败（适用于"单笔转账到银行卡"）； DEALING：处理中（适用于"单笔转账到银行卡"）； REFUND：退票（适用于"单笔转账到银行卡"）； alipay.fund.trans.app.pay涉及的状态： WAIT_PAY、SUCCESS、CLOSED alipay.fund.trans.refund涉及的状态：SUCCESS alipay.fund.trans.uni.transfer涉及的状态：SUCCESS、FAIL、DEALING、REFUND
 	PayDate            string `json:"pay_date,omitempty"`              // 可选	20 支付时间，格式为yyyy-MM-dd HH:mm:ss，转账失败不返回。2013-01-01 08:08:08
 	ArrivalTimeEnd     string `json:"arrival_time_end,omitempty"`      // 可选	20 预计到账时间，转账到银行卡专用，格式为yyyy-MM-dd HH:mm:ss，转账受理失败不返回。 注意： 此参数为预计时间，可能与实际到账时间有较大误差，不能作为实际到账时间使用，仅供参考用途。
-	OrderFee           string `json:"order_fee,omitempty"`             // 可选	20 预计收费金额（元），转账到银行卡专用，数字格式，精确到小数点后2位，转账失败或转账受理失败不返回。 0.02
+	OrderFee           Money  `json:"order_fee,omitempty"`             // 可选	20 预计收费金额（元），转账到银行卡专用，数字格式，精确到小数点后2位，转账失败或转账受理失败不返回。 0.02
 	ErrorCode          string `json:"error_code,omitempty"`            // 可选	64 查询到的订单状态为FAIL失败或REFUND退票时，返回错误代码
 	FailReason         string `json:"fail_reason,omitempty"`           // 可选	128 查询到的订单状态为FAIL失败或REFUND退票时，返回具体的原因。
 	SubOrderErrorCode  string `json:"sub_order_error_code,omitempty"`  // 可选	64 特殊场景提供，当子单出现异常导致主单失败或者退款时，会提供此字段，用于透出子单具体的错误场景
@@ -288,3 +275,530 @@ func (r *FundTransCommonQueryRes) String() string {
 	buff, _ := json.Marshal(r)
 	return string(buff)
 }
+
+/////////////////////////////////////////////
+
+// BatchTransferMaxCount 单个批次的最大转账笔数
+const BatchTransferMaxCount = 1000
+
+var _ IAliPayRequest = &FundBatchUniTransferReq{}
+
+type FundBatchUniTransferReq struct {
+	OutBatchNo       string             `json:"out_batch_no" validate:"required,max=32"`                     // 必选	32 商户批次号，商户系统内唯一。
+	ProductCode      string             `json:"product_code" validate:"required,max=32"`                     // 必选	32 销售产品码。批量转账到支付宝账户固定为 BATCH_API_TO_ACC。
+	BizScene         string             `json:"biz_scene" validate:"required,max=32"`                        // 必选	32 业务场景。批量代发固定为 MESSAGE_BATCH_PAY。
+	OrderTitle       string             `json:"order_title" validate:"required,max=32"`                      // 必选	32 批次标题，用于在支付宝用户的账单里显示。 2019年5月工资
+	TotalTransAmount Money              `json:"total_trans_amount" validate:"required,amount=0.1~100000000"` // 必选	20 批次总金额，单位为元，必须与明细金额之和一致。
+	TotalCount       int                `json:"total_count" validate:"required,range=1~1000"`                // 必选	10 批次总笔数，必须与明细笔数一致，最多1000笔。
+	TransOrderList   []*BatchTransOrder `json:"trans_order_list" validate:"required,max=1000"`               // 必选	   转账明细，明细的商户订单号在批次内不能重复。
+	Remark           string             `json:"remark,omitempty" validate:"max=200"`                         // 可选	200 业务备注。
+	TimeExpire       string             `json:"time_expire,omitempty" validate:"max=32"`                     // 可选	32 绝对超时时间，格式为yyyy-MM-dd HH:mm，超时未支付的批次自动关闭。
+	PassbackParams   string             `json:"passback_params,omitempty" validate:"max=1024"`               // 可选	1024 回传参数，在批次状态变化的通知中原样传回。
+	BusinessParams   string             `json:"business_params,omitempty" validate:"max=2048"`               // 可选	2048 转账业务请求的扩展参数。
+	baseAliPayRequest
+}
+
+type BatchTransOrder struct {
+	OutBizNo    string      `json:"out_biz_no" validate:"required,max=64"`                 // 必选	64 商户明细订单号，批次内唯一。
+	TransAmount Money       `json:"trans_amount" validate:"required,amount=0.1~100000000"` // 必选	20 明细金额，单位为元，精确到小数点后两位。
+	PayeeInfo   Participant `json:"payee_info" validate:"required"`                        // 必选	   收款方信息
+	Remark      string      `json:"remark,omitempty" validate:"max=200"`                   // 可选	200 明细备注。
+}
+
+// DoValidate 除标签规则外，校验明细笔数、明细商户订单号唯一以及明细金额之和
+func (r *FundBatchUniTransferReq) DoValidate() error {
+	var errs ValidationErrors
+	if err := ValidateStruct(r); err != nil {
+		errs = err.(ValidationErrors)
+	}
+	if len(r.TransOrderList) > 0 && len(r.TransOrderList) != r.TotalCount {
+		errs = append(errs, &FieldError{Field: "total_count", Code: ValidationCodeMismatch,
+			Message: fmt.Sprintf("参数total_count为%d，与明细笔数%d不一致", r.TotalCount, len(r.TransOrderList))})
+	}
+	var sum Money
+	outBizNos := make(map[string]bool, len(r.TransOrderList))
+	for i, order := range r.TransOrderList {
+		if order == nil {
+			continue
+		}
+		sum = sum.Add(order.TransAmount)
+		if len(order.OutBizNo) == 0 {
+			continue
+		}
+		if outBizNos[order.OutBizNo] {
+			path := fmt.Sprintf("trans_order_list[%d].out_biz_no", i)
+			errs = append(errs, &FieldError{Field: path, Code: ValidationCodeUnique, Message: fmt.Sprintf("参数%s的值%s重复", path, order.OutBizNo)})
+		}
+		outBizNos[order.OutBizNo] = true
+	}
+	if len(r.TransOrderList) > 0 && sum != r.TotalTransAmount {
+		errs = append(errs, &FieldError{Field: "total_trans_amount", Code: ValidationCodeMismatch,
+			Message: fmt.Sprintf("参数total_trans_amount为%s，与明细金额之和%s不一致", r.TotalTransAmount, sum)})
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+func (r *FundBatchUniTransferReq) RequestApi() string {
+	return "alipay.fund.batch.uni.transfer"
+}
+
+type FundBatchUniTransferRes struct {
+	FundBatchUniTransferResContent `json:"alipay_fund_batch_uni_transfer_response"`
+	SignCertSn
+}
+
+type FundBatchUniTransferResContent struct {
+	CommonRes
+	OutBatchNo   string `json:"out_batch_no"`     // 必选	32 商户批次号
+	BatchTransId string `json:"batch_trans_id"`   // 必选	64 支付宝批次单号
+	Status       string `json:"status,omitempty"` // 可选	32 批次状态。INIT：初始化；WAIT_PAY：等待支付；DEALING：处理中；SUCCESS：成功；PARTIAL_SUCCESS：部分成功；FAIL：失败；DISUSE：已关闭
+}
+
+func (r *FundBatchUniTransferRes) String() string {
+	buff, _ := json.Marshal(r)
+	return string(buff)
+}
+
+/////////////////////////////////////////////
+
+var _ IAliPayRequest = &FundBatchDetailQueryReq{}
+
+type FundBatchDetailQueryReq struct {
+	BatchTransId string `json:"batch_trans_id,omitempty" validate:"anyof=batch,max=64"` // 可选	64 支付宝批次单号，与商户批次号不能同时为空。
+	OutBatchNo   string `json:"out_batch_no,omitempty" validate:"anyof=batch,max=32"`   // 可选	32 商户批次号，与支付宝批次单号不能同时为空。
+	ProductCode  string `json:"product_code" validate:"required,max=32"`                // 必选	32 销售产品码，与转账时一致。
+	BizScene     string `json:"biz_scene" validate:"required,max=32"`                   // 必选	32 业务场景，与转账时一致。
+	DetailStatus string `json:"detail_status,omitempty" validate:"max=32"`              // 可选	32 按明细状态过滤。SUCCESS、FAIL、DEALING、REFUND
+	OutBizNo     string `json:"out_biz_no,omitempty" validate:"max=64"`                 // 可选	64 按商户明细订单号过滤。
+	PageNum      int    `json:"page_num,omitempty" validate:"range=0~100000"`           // 可选	10 页码，从1开始，默认为1。
+	PageSize     int    `json:"page_size,omitempty" validate:"range=0~1000"`            // 可选	10 每页笔数，默认为20，最大1000。
+	baseAliPayRequest
+}
+
+func (r *FundBatchDetailQueryReq) DoValidate() error {
+	return ValidateStruct(r)
+}
+
+func (r *FundBatchDetailQueryReq) RequestApi() string {
+	return "alipay.fund.batch.detail.query"
+}
+
+type FundBatchDetailQueryRes struct {
+	FundBatchDetailQueryResContent `json:"alipay_fund_batch_detail_query_response"`
+	SignCertSn
+}
+
+type FundBatchDetailQueryResContent struct {
+	CommonRes
+	BatchTransId     string              `json:"batch_trans_id"`               // 必选	64 支付宝批次单号
+	OutBatchNo       string              `json:"out_batch_no"`                 // 必选	32 商户批次号
+	ProductCode      string              `json:"product_code,omitempty"`       // 可选	32 销售产品码
+	BizScene         string              `json:"biz_scene,omitempty"`          // 可选	32 业务场景
+	BatchStatus      string              `json:"batch_status"`                 // 必选	32 批次状态，取值同 FundBatchUniTransferResContent.Status
+	TotalAmount      Money               `json:"total_amount"`                 // 必选	20 批次总金额
+	SuccessAmount    Money               `json:"success_amount,omitempty"`     // 可选	20 成功金额
+	FailAmount       Money               `json:"fail_amount,omitempty"`        // 可选	20 失败金额
+	TotalItemCount   int                 `json:"total_item_count"`             // 必选	10 批次总笔数
+	SuccessItemCount int                 `json:"success_item_count,omitempty"` // 可选	10 成功笔数
+	FailItemCount    int                 `json:"fail_item_count,omitempty"`    // 可选	10 失败笔数
+	GmtCreate        string              `json:"gmt_create,omitempty"`         // 可选	20 批次创建时间，格式为yyyy-MM-dd HH:mm:ss
+	GmtFinish        string              `json:"gmt_finish,omitempty"`         // 可选	20 批次完成时间，格式为yyyy-MM-dd HH:mm:ss
+	PageNum          int                 `json:"page_num"`                     // 必选	10 当前页码
+	PageSize         int                 `json:"page_size"`                    // 必选	10 每页笔数
+	TotalPageCount   int                 `json:"total_page_count"`             // 必选	10 总页数
+	AccDetailList    []*BatchTransDetail `json:"acc_detail_list,omitempty"`    // 可选	   当前页的转账明细
+}
+
+type BatchTransDetail struct {
+	DetailId    string      `json:"detail_id"`             // 必选	64 支付宝明细单号
+	OutBizNo    string      `json:"out_biz_no"`            // 必选	64 商户明细订单号
+	TransAmount Money       `json:"trans_amount"`          // 必选	20 明细金额
+	Status      string      `json:"status"`                // 必选	32 明细状态。INIT、DEALING、SUCCESS、FAIL、REFUND
+	PayeeInfo   Participant `json:"payee_info"`            // 必选	   收款方信息
+	ErrorCode   string      `json:"error_code,omitempty"`  // 可选	64 明细失败或者退票时的错误码
+	FailReason  string      `json:"fail_reason,omitempty"` // 可选	128 明细失败或者退票的原因
+	Remark      string      `json:"remark,omitempty"`      // 可选	200 明细备注
+	GmtCreate   string      `json:"gmt_create,omitempty"`  // 可选	20 明细创建时间
+	GmtFinish   string      `json:"gmt_finish,omitempty"`  // 可选	20 明细完成时间
+}
+
+func (r *FundBatchDetailQueryRes) String() string {
+	buff, _ := json.Marshal(r)
+	return string(buff)
+}
+
+// FundBatchDetailIterator 按页遍历批次的转账明细，由 Client.FundBatchDetails 创建
+type FundBatchDetailIterator struct {
+	client *Client
+	ctx    context.Context
+	req    FundBatchDetailQueryReq
+	batch  *FundBatchDetailQueryRes
+	list   []*BatchTransDetail
+	detail *BatchTransDetail
+	done   bool
+	err    error
+}
+
+// Next 读取下一条明细，当前页读完后查询下一页，没有更多明细或者出错时返回false
+func (r *FundBatchDetailIterator) Next() bool {
+	for len(r.list) == 0 {
+		if r.done || r.err != nil {
+			r.detail = nil
+			return false
+		}
+		r.fetch()
+	}
+	r.detail, r.list = r.list[0], r.list[1:]
+	return true
+}
+
+func (r *FundBatchDetailIterator) fetch() {
+	res, err := r.client.FundBatchDetailQuery(r.ctx, r.req)
+	if err != nil {
+		r.err = err
+		return
+	}
+	if res.Fail() {
+		r.err = fmt.Errorf("xpay: fund batch detail query failed, sub_code: %s, sub_msg: %s", res.SubCode, res.SubMsg)
+		return
+	}
+	r.batch, r.list = res, res.AccDetailList
+	r.done = len(res.AccDetailList) == 0 || r.req.PageNum >= res.TotalPageCount
+	r.req.PageNum++
+}
+
+// Detail 当前明细
+func (r *FundBatchDetailIterator) Detail() *BatchTransDetail {
+	return r.detail
+}
+
+// Batch 最近一次查询返回的批次信息，尚未查询时为nil
+func (r *FundBatchDetailIterator) Batch() *FundBatchDetailQueryRes {
+	return r.batch
+}
+
+// Err 查询过程中的错误
+func (r *FundBatchDetailIterator) Err() error {
+	return r.err
+}
+
+/////////////////////////////////////////////
+
+var _ IAliPayRequest = &FundBatchCloseReq{}
+
+type FundBatchCloseReq struct {
+	BatchTransId string `json:"batch_trans_id" validate:"required,max=64"` // 必选	64 支付宝批次单号
+	ProductCode  string `json:"product_code" validate:"required,max=32"`   // 必选	32 销售产品码，与转账时一致。
+	BizScene     string `json:"biz_scene" validate:"required,max=32"`      // 必选	32 业务场景，与转账时一致。
+	baseAliPayRequest
+}
+
+func (r *FundBatchCloseReq) DoValidate() error {
+	return ValidateStruct(r)
+}
+
+func (r *FundBatchCloseReq) RequestApi() string {
+	return "alipay.fund.batch.close"
+}
+
+type FundBatchCloseRes struct {
+	FundBatchCloseResContent `json:"alipay_fund_batch_close_response"`
+	SignCertSn
+}
+
+type FundBatchCloseResContent struct {
+	CommonRes
+	BatchTransId string `json:"batch_trans_id"` // 必选	64 支付宝批次单号
+	Status       string `json:"status"`         // 必选	32 批次状态，关闭成功为 DISUSE
+}
+
+func (r *FundBatchCloseRes) String() string {
+	buff, _ := json.Marshal(r)
+	return string(buff)
+}
+
+/////////////////////////////////////////////
+
+// 资金授权订单状态 order_status
+const (
+	FundAuthOrderInit       = "INIT"       // 初始状态，用户未完成授权
+	FundAuthOrderAuthorized = "AUTHORIZED" // 已授权，有剩余冻结金额
+	FundAuthOrderFinish     = "FINISH"     // 完成，冻结金额已全部解冻或者转支付
+	FundAuthOrderClosed     = "CLOSED"     // 关闭，授权超时未完成
+)
+
+// 资金授权操作状态 status
+const (
+	FundAuthOperationInit    = "INIT"    // 初始状态，等待用户授权
+	FundAuthOperationSuccess = "SUCCESS" // 成功
+	FundAuthOperationClosed  = "CLOSED"  // 关闭
+)
+
+// 资金授权操作类型 operation_type
+const (
+	FundAuthOperationFreeze   = "FREEZE"
+	FundAuthOperationUnfreeze = "UNFREEZE"
+	FundAuthOperationPay      = "PAY"
+)
+
+// 预授权转支付的确认模式 auth_confirm_mode
+const (
+	AuthConfirmModeComplete    = "COMPLETE"
+	AuthConfirmModeNotComplete = "NOT_COMPLETE"
+)
+
+var _ IAliPayRequest = &FundAuthOrderAppFreezeReq{}
+
+// FundAuthOrderAppFreezeReq alipay.fund.auth.order.app.freeze(线上资金授权冻结接口)，生成订单串由客户端唤起支付宝完成授权
+type FundAuthOrderAppFreezeReq struct {
+	OutOrderNo         string `json:"out_order_no" validate:"required,max=64"`                             // 必选	64 商户授权资金订单号，不能包含除中文、英文、数字以外的字符，创建后不能修改，需要保证在商户端不重复。 8077735255938023
+	OutRequestNo       string `json:"out_request_no" validate:"required,max=64"`                           // 必选	64 商户本次资金操作的请求流水号，用于标识请求流水的唯一性，需要保证在商户端不重复。 8077735255938032
+	OrderTitle         string `json:"order_title" validate:"required,max=100"`                             // 必选	100 业务订单的简单描述，如商品名称等 预授权冻结
+	Amount             Money  `json:"amount" validate:"required,amount=0.01~100000000"`                    // 必选	11 需要冻结的金额，单位为：元（人民币），精确到小数点后两位 0.01
+	ProductCode        string `json:"product_code" validate:"required,enum=PRE_AUTH_ONLINE"`               // 必选	32 销售产品码，后续新接入预授权当面付的业务，新当面资金授权取值PRE_AUTH，境外预授权取值OVERSEAS_INSTORE_AUTH。 PRE_AUTH_ONLINE
+	PayeeUserId        string `json:"payee_user_id,omitempty" validate:"max=32"`                           // 可选	32 收款方支付宝用户号 2088102000275795
+	PayeeLogonId       string `json:"payee_logon_id,omitempty" validate:"max=100"`                         // 可选	100 收款方支付宝账号（Email或手机号） 159****5620
+	TimeoutExpress     string `json:"timeout_express,omitempty" validate:"max=64"`                         // 可选	64 该笔订单允许的最晚付款时间，逾期将关闭该笔订单。取值范围：1m～15d。m-分钟，h-小时，d-天。 该参数数值不接受小数点，如 1.5h，可转换为90m 2d
+	ExtraParam         string `json:"extra_param,omitempty" validate:"max=300"`                            // 可选	300 业务扩展参数，json格式，如 {"category":"RENT_PHONE","outStoreCode":"charge001"}
+	BusinessParams     string `json:"business_params,omitempty" validate:"max=512"`                        // 可选	512 商户传入业务信息，具体值要和支付宝约定，json格式
+	DepositProductMode string `json:"deposit_product_mode,omitempty" validate:"enum=POSTPAY|DEPOSIT_ONLY"` // 可选	32 免押受理台模式。根据免押不同业务模式将开通受理台区分三种模式，商家可根据调用预授权冻结接口传入的参数决定该笔免押订单选择哪种受理模式。 POSTPAY
+	SceneCode          string `json:"scene_code,omitempty" validate:"max=64"`                              // 可选	64 场景码，预授权冻结的业务场景 ONLINE_AUTH_COMMON_SCENE
+	// 自己添加
+	NotifyUrl string `json:"-" url:"-"` // 可选	256 支付宝服务器主动通知商户服务器里指定的页面http/https路径。
+	baseAliPayRequest
+}
+
+func (r *FundAuthOrderAppFreezeReq) DoValidate() error {
+	return ValidateStruct(r)
+}
+
+func (r *FundAuthOrderAppFreezeReq) RequestApi() string {
+	return "alipay.fund.auth.order.app.freeze"
+}
+
+// FundAuthOrderAppFreezeResult app冻结的下单结果
+type FundAuthOrderAppFreezeResult struct {
+	OrderString string   // 签名后的订单串，原样下发给客户端，作为支付宝SDK的orderStr参数
+	GatewayURL  *url.URL // 网关地址拼接订单串后的完整地址
+}
+
+func (r *FundAuthOrderAppFreezeResult) String() string {
+	return r.OrderString
+}
+
+/////////////////////////////////////////////
+
+var _ IAliPayRequest = &FundAuthOrderFreezeReq{}
+
+// FundAuthOrderFreezeReq alipay.fund.auth.order.freeze(资金授权冻结接口)，商户扫描用户的付款码冻结资金
+type FundAuthOrderFreezeReq struct {
+	AuthCode       string `json:"auth_code" validate:"required,max=64"`                           // 必选	64 支付授权码，25~30开头的长度为16~24位的数字，实际字符串长度以开发者获取的付款码长度为准 28763443825664394
+	AuthCodeType   string `json:"auth_code_type" validate:"required,enum=bar_code|security_code"` // 必选	32 授权码类型。bar_code：付款码；security_code：刷脸标识串 bar_code
+	OutOrderNo     string `json:"out_order_no" validate:"required,max=64"`                        // 必选	64 商户授权资金订单号，需要保证在商户端不重复 8077735255938023
+	OutRequestNo   string `json:"out_request_no" validate:"required,max=64"`                      // 必选	64 商户本次资金操作的请求流水号，需要保证在商户端不重复 8077735255938032
+	OrderTitle     string `json:"order_title" validate:"required,max=100"`                        // 必选	100 业务订单的简单描述，如商品名称等 预授权冻结
+	Amount         Money  `json:"amount" validate:"required,amount=0.01~100000000"`               // 必选	11 需要冻结的金额，单位为：元（人民币），精确到小数点后两位 0.01
+	PayeeUserId    string `json:"payee_user_id,omitempty" validate:"max=32"`                      // 可选	32 收款方支付宝用户号 2088102000275795
+	PayeeLogonId   string `json:"payee_logon_id,omitempty" validate:"max=100"`                    // 可选	100 收款方支付宝账号（Email或手机号） 159****5620
+	PayTimeout     string `json:"pay_timeout,omitempty" validate:"max=5"`                         // 可选	5 该笔订单允许的最晚付款时间，逾期将关闭该笔订单。取值范围：1m～15d 2d
+	ProductCode    string `json:"product_code,omitempty" validate:"max=32"`                       // 可选	32 销售产品码，新接入预授权当面付的业务传PRE_AUTH，不传默认为PRE_AUTH PRE_AUTH
+	SceneCode      string `json:"scene_code,omitempty" validate:"max=64"`                         // 可选	64 场景码 HOTEL
+	ExtraParam     string `json:"extra_param,omitempty" validate:"max=300"`                       // 可选	300 业务扩展参数，json格式
+	BusinessParams string `json:"business_params,omitempty" validate:"max=512"`                   // 可选	512 商户传入业务信息，json格式
+	// 自己添加
+	NotifyUrl string `json:"-" url:"-"` // 可选	256 支付宝服务器主动通知商户服务器里指定的页面http/https路径。
+	baseAliPayRequest
+}
+
+func (r *FundAuthOrderFreezeReq) DoValidate() error {
+	return ValidateStruct(r)
+}
+
+func (r *FundAuthOrderFreezeReq) RequestApi() string {
+	return "alipay.fund.auth.order.freeze"
+}
+
+type FundAuthOrderFreezeRes struct {
+	FundAuthOrderFreezeResContent `json:"alipay_fund_auth_order_freeze_response"`
+	SignCertSn
+}
+
+type FundAuthOrderFreezeResContent struct {
+	CommonRes
+	AuthNo       string `json:"auth_no"`                  // 必选	64 支付宝的资金授权订单号 2014070800002001550000014417
+	OutOrderNo   string `json:"out_order_no"`             // 必选	64 商户的授权资金订单号 8077735255938023
+	OperationId  string `json:"operation_id"`             // 必选	64 支付宝的资金操作流水号 20140216010020120000000001
+	OutRequestNo string `json:"out_request_no"`           // 必选	64 商户本次资金操作的请求流水号 8077735255938032
+	Amount       Money  `json:"amount"`                   // 必选	11 本次操作冻结的金额，单位为：元（人民币） 100.00
+	Status       string `json:"status"`                   // 必选	32 资金预授权明细的状态，INIT：初始；SUCCESS: 成功；CLOSED：关闭 SUCCESS
+	PayerUserId  string `json:"payer_user_id,omitempty"`  // 可选	32 付款方支付宝用户号 2088102000275795
+	PayerLogonId string `json:"payer_logon_id,omitempty"` // 可选	100 付款方支付宝账号 159****5620
+	GmtTrans     string `json:"gmt_trans,omitempty"`      // 可选 资金授权成功时间，格式：YYYY-MM-DD HH:MM:SS 2014-09-15 11:23:04
+	CreditAmount Money  `json:"credit_amount,omitempty"`  // 可选	11 本次冻结操作中信用冻结金额 0.01
+	FundAmount   Money  `json:"fund_amount,omitempty"`    // 可选	11 本次冻结操作中自有资金冻结金额 0.01
+}
+
+func (r *FundAuthOrderFreezeRes) String() string {
+	buff, _ := json.Marshal(r)
+	return string(buff)
+}
+
+/////////////////////////////////////////////
+
+var _ IAliPayRequest = &FundAuthOrderUnfreezeReq{}
+
+// FundAuthOrderUnfreezeReq alipay.fund.auth.order.unfreeze(资金授权解冻接口)
+type FundAuthOrderUnfreezeReq struct {
+	AuthNo       string `json:"auth_no" validate:"required,max=64"`               // 必选	64 支付宝资金授权订单号 2014070800002001550000014417
+	OutRequestNo string `json:"out_request_no" validate:"required,max=64"`        // 必选	64 商户本次资金操作的请求流水号，同一商户每次不同的资金操作请求，商户请求流水号不要重复 8077735255938032
+	Amount       Money  `json:"amount" validate:"required,amount=0.01~100000000"` // 必选	11 本次操作解冻的金额，单位为：元（人民币），精确到小数点后两位 100.00
+	Remark       string `json:"remark" validate:"required,max=100"`               // 必选	100 商户对本次解冻操作的附言描述 2015年4月份解冻
+	ExtraParam   string `json:"extra_param,omitempty" validate:"max=300"`         // 可选	300 解冻扩展信息，json格式
+	// 自己添加
+	NotifyUrl string `json:"-" url:"-"` // 可选	256 支付宝服务器主动通知商户服务器里指定的页面http/https路径。
+	baseAliPayRequest
+}
+
+func (r *FundAuthOrderUnfreezeReq) DoValidate() error {
+	return ValidateStruct(r)
+}
+
+func (r *FundAuthOrderUnfreezeReq) RequestApi() string {
+	return "alipay.fund.auth.order.unfreeze"
+}
+
+type FundAuthOrderUnfreezeRes struct {
+	FundAuthOrderUnfreezeResContent `json:"alipay_fund_auth_order_unfreeze_response"`
+	SignCertSn
+}
+
+type FundAuthOrderUnfreezeResContent struct {
+	CommonRes
+	AuthNo       string `json:"auth_no"`                 // 必选	64 支付宝的资金授权订单号 2014070800002001550000014417
+	OutOrderNo   string `json:"out_order_no"`            // 必选	64 商户的授权资金订单号 8077735255938023
+	OperationId  string `json:"operation_id"`            // 必选	64 支付宝的资金操作流水号 20140216010020120000000001
+	OutRequestNo string `json:"out_request_no"`          // 必选	64 商户本次资金操作的请求流水号 8077735255938032
+	Amount       Money  `json:"amount"`                  // 必选	11 本次操作解冻的金额，单位为：元（人民币） 100.00
+	Status       string `json:"status"`                  // 必选	32 资金操作流水的状态，INIT：初始；SUCCESS：成功；CLOSED：关闭 SUCCESS
+	GmtTrans     string `json:"gmt_trans,omitempty"`     // 可选 授权资金解冻成功时间 2014-09-15 11:23:04
+	CreditAmount Money  `json:"credit_amount,omitempty"` // 可选	11 本次解冻操作中信用解冻金额 0.01
+	FundAmount   Money  `json:"fund_amount,omitempty"`   // 可选	11 本次解冻操作中自有资金解冻金额 0.01
+}
+
+func (r *FundAuthOrderUnfreezeRes) String() string {
+	buff, _ := json.Marshal(r)
+	return string(buff)
+}
+
+/////////////////////////////////////////////
+
+var _ IAliPayRequest = &FundAuthOperationDetailQueryReq{}
+
+// FundAuthOperationDetailQueryReq alipay.fund.auth.operation.detail.query(资金授权操作查询接口)
+type FundAuthOperationDetailQueryReq struct {
+	AuthNo        string `json:"auth_no,omitempty" validate:"anyof=order,max=64"`              // 特殊可选	64 支付宝授权资金订单号，与商户的授权资金订单号不能同时为空 2014070800002001550000014417
+	OutOrderNo    string `json:"out_order_no,omitempty" validate:"anyof=order,max=64"`         // 特殊可选	64 商户的授权资金订单号，与支付宝的授权资金订单号不能同时为空 8077735255938023
+	OperationId   string `json:"operation_id,omitempty" validate:"anyof=operation,max=64"`     // 特殊可选	64 支付宝的授权资金操作流水号，与商户的授权资金操作流水号不能同时为空 20140216010020120000000001
+	OutRequestNo  string `json:"out_request_no,omitempty" validate:"anyof=operation,max=64"`   // 特殊可选	64 商户的授权资金操作流水号，与支付宝的授权资金操作流水号不能同时为空 8077735255938032
+	OperationType string `json:"operation_type,omitempty" validate:"enum=FREEZE|UNFREEZE|PAY"` // 可选	32 需要查询的授权资金操作类型，不传默认查询冻结操作 FREEZE
+	baseAliPayRequest
+}
+
+func (r *FundAuthOperationDetailQueryReq) DoValidate() error {
+	return ValidateStruct(r)
+}
+
+func (r *FundAuthOperationDetailQueryReq) RequestApi() string {
+	return "alipay.fund.auth.operation.detail.query"
+}
+
+type FundAuthOperationDetailQueryRes struct {
+	FundAuthOperationDetailQueryResContent `json:"alipay_fund_auth_operation_detail_query_response"`
+	SignCertSn
+}
+
+type FundAuthOperationDetailQueryResContent struct {
+	CommonRes
+	AuthNo                  string `json:"auth_no"`                              // 必选	64 支付宝资金授权订单号 2014070800002001550000014417
+	OutOrderNo              string `json:"out_order_no"`                         // 必选	64 商户的授权资金订单号 8077735255938023
+	OrderStatus             string `json:"order_status"`                         // 必选	32 资金授权单据状态，INIT：初始；AUTHORIZED：已授权；FINISH：完成；CLOSED：关闭 AUTHORIZED
+	TotalFreezeAmount       Money  `json:"total_freeze_amount"`                  // 必选	11 订单累计的冻结金额，单位为：元（人民币） 120.00
+	RestAmount              Money  `json:"rest_amount"`                          // 必选	11 订单总共剩余的冻结金额，单位为：元（人民币） 10.00
+	TotalPayAmount          Money  `json:"total_pay_amount"`                     // 必选	11 订单累计用于支付的金额，单位为：元（人民币） 100.00
+	OrderTitle              string `json:"order_title,omitempty"`                // 可选	100 业务订单的简单描述 预授权冻结
+	PayerLogonId            string `json:"payer_logon_id,omitempty"`             // 可选	100 付款方支付宝账号登录号 user@domain.com
+	PayerUserId             string `json:"payer_user_id,omitempty"`              // 可选	32 付款方支付宝账号UID 2088202980128123
+	ExtraParam              string `json:"extra_param,omitempty"`                // 可选	300 商户请求时传入的业务扩展参数
+	OperationId             string `json:"operation_id"`                         // 必选	64 支付宝资金操作流水号 20140216010020120000000001
+	OutRequestNo            string `json:"out_request_no"`                       // 必选	64 商户资金操作的请求流水号 8077735255938032
+	Amount                  Money  `json:"amount"`                               // 必选	11 该笔资金操作流水operation_id对应的操作金额，单位为：元（人民币） 100.00
+	OperationType           string `json:"operation_type"`                       // 必选	32 支付宝资金操作类型，FREEZE：冻结；UNFREEZE：解冻；PAY：支付 FREEZE
+	Status                  string `json:"status"`                               // 必选	32 资金操作流水的状态，INIT：初始；SUCCESS：成功；CLOSED：关闭 SUCCESS
+	Remark                  string `json:"remark,omitempty"`                     // 可选	100 商户对本次操作的附言描述 解冻
+	GmtCreate               string `json:"gmt_create,omitempty"`                 // 可选 资金授权单据操作流水创建时间 2014-09-15 11:23:04
+	GmtTrans                string `json:"gmt_trans,omitempty"`                  // 可选 支付宝账务处理成功时间 2014-09-15 11:23:04
+	CreditAmount            Money  `json:"credit_amount,omitempty"`              // 可选	11 本次操作中信用金额 0.01
+	FundAmount              Money  `json:"fund_amount,omitempty"`                // 可选	11 本次操作中自有资金金额 0.01
+	TotalFreezeCreditAmount Money  `json:"total_freeze_credit_amount,omitempty"` // 可选	11 累计冻结信用金额 0.01
+	TotalFreezeFundAmount   Money  `json:"total_freeze_fund_amount,omitempty"`   // 可选	11 累计冻结自有资金金额 0.01
+	TotalPayCreditAmount    Money  `json:"total_pay_credit_amount,omitempty"`    // 可选	11 累计用于支付的信用金额 0.01
+	TotalPayFundAmount      Money  `json:"total_pay_fund_amount,omitempty"`      // 可选	11 累计用于支付的自有资金金额 0.01
+	RestCreditAmount        Money  `json:"rest_credit_amount,omitempty"`         // 可选	11 剩余的冻结信用金额 0.01
+	RestFundAmount          Money  `json:"rest_fund_amount,omitempty"`           // 可选	11 剩余的冻结自有资金金额 0.01
+}
+
+func (r *FundAuthOperationDetailQueryRes) String() string {
+	buff, _ := json.Marshal(r)
+	return string(buff)
+}
+
+/////////////////////////////////////////////
+
+var _ IAliPayRequest = &FundAuthOrderVoucherCreateReq{}
+
+// FundAuthOrderVoucherCreateReq alipay.fund.auth.order.voucher.create(资金授权发码接口)，生成二维码由用户扫码完成授权
+type FundAuthOrderVoucherCreateReq struct {
+	OutOrderNo   string `json:"out_order_no" validate:"required,max=64"`          // 必选	64 商户授权资金订单号，需要保证在商户端不重复 8077735255938023
+	OutRequestNo string `json:"out_request_no" validate:"required,max=64"`        // 必选	64 商户本次资金操作的请求流水号，需要保证在商户端不重复 8077735255938032
+	OrderTitle   string `json:"order_title" validate:"required,max=100"`          // 必选	100 业务订单的简单描述，如商品名称等 预授权冻结
+	Amount       Money  `json:"amount" validate:"required,amount=0.01~100000000"` // 必选	11 需要冻结的金额，单位为：元（人民币），精确到小数点后两位 0.01
+	PayeeUserId  string `json:"payee_user_id,omitempty" validate:"max=32"`        // 可选	32 收款方支付宝用户号 2088102000275795
+	PayeeLogonId string `json:"payee_logon_id,omitempty" validate:"max=100"`      // 可选	100 收款方支付宝账号（Email或手机号） 159****5620
+	PayTimeout   string `json:"pay_timeout,omitempty" validate:"max=5"`           // 可选	5 该笔订单允许的最晚付款时间，逾期将关闭该笔订单。取值范围：1m～15d 2d
+	ProductCode  string `json:"product_code,omitempty" validate:"max=32"`         // 可选	32 销售产品码，不传默认为PRE_AUTH PRE_AUTH
+	SceneCode    string `json:"scene_code,omitempty" validate:"max=64"`           // 可选	64 场景码 HOTEL
+	ExtraParam   string `json:"extra_param,omitempty" validate:"max=300"`         // 可选	300 业务扩展参数，json格式
+	// 自己添加
+	NotifyUrl string `json:"-" url:"-"` // 可选	256 支付宝服务器主动通知商户服务器里指定的页面http/https路径。
+	baseAliPayRequest
+}
+
+func (r *FundAuthOrderVoucherCreateReq) DoValidate() error {
+	return ValidateStruct(r)
+}
+
+func (r *FundAuthOrderVoucherCreateReq) RequestApi() string {
+	return "alipay.fund.auth.order.voucher.create"
+}
+
+type FundAuthOrderVoucherCreateRes struct {
+	FundAuthOrderVoucherCreateResContent `json:"alipay_fund_auth_order_voucher_create_response"`
+	SignCertSn
+}
+
+type FundAuthOrderVoucherCreateResContent struct {
+	CommonRes
+	OutOrderNo   string `json:"out_order_no"`         // 必选	64 商户的授权资金订单号 8077735255938023
+	OutRequestNo string `json:"out_request_no"`       // 必选	64 商户本次资金操作的请求流水号 8077735255938032
+	CodeType     string `json:"code_type,omitempty"`  // 可选	32 码类型，目前只支持二维码 qrCode
+	CodeValue    string `json:"code_value,omitempty"` // 可选	1024 当前发码请求生成的二维码码串，商户端可以利用二维码生成工具根据该码串值生成对应的二维码 https://qr.alipay.com/baxxxxx
+	CodeUrl      string `json:"code_url,omitempty"`   // 可选	1024 生成的带有支付宝logo的二维码地址 https://mdn.alipay.com/xxxxx
+}
+
+func (r *FundAuthOrderVoucherCreateRes) String() string {
+	buff, _ := json.Marshal(r)
+	return string(buff)
+}
@@ -0,0 +1,257 @@
+package alipay_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/try-labs/xpay"
+	"github.com/try-labs/xpay/alipaytest"
+)
+
+/**
+ * @author: Sam
+ * @since:
+ * @date: 2026/10/19 23:40
+ * @desc:
+ */
+
+func batchTransferReq(outBatchNo string, count int) FundBatchUniTransferReq {
+	req := FundBatchUniTransferReq{
+		OutBatchNo:  outBatchNo,
+		ProductCode: BatchApiToAcc,
+		BizScene:    "MESSAGE_BATCH_PAY",
+		OrderTitle:  "10月工资",
+		TotalCount:  count,
+	}
+	for i := 1; i <= count; i++ {
+		req.TransOrderList = append(req.TransOrderList, &BatchTransOrder{
+			OutBizNo:    fmt.Sprintf("%s-%03d", outBatchNo, i),
+			TransAmount: MustParseMoney("1.50"),
+			PayeeInfo:   Participant{Identity: fmt.Sprintf("2088%012d", i), IdentityType: "ALIPAY_USER_ID"},
+		})
+		req.TotalTransAmount = req.TotalTransAmount.Add(MustParseMoney("1.50"))
+	}
+	return req
+}
+
+func TestFundBatchUniTransferReq_DoValidate(t *testing.T) {
+	req := batchTransferReq("B20261019001", 3)
+	if err := req.DoValidate(); err != nil {
+		t.Fatal(err)
+	}
+	req.TotalCount = 4
+	req.TotalTransAmount = MustParseMoney("4.00")
+	req.TransOrderList[2].OutBizNo = req.TransOrderList[0].OutBizNo
+	errs, ok := req.DoValidate().(ValidationErrors)
+	if !ok || len(errs) != 3 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	for _, field := range []string{"total_count", "total_trans_amount", "trans_order_list[2].out_biz_no"} {
+		if !errs.HasField(field) {
+			t.Errorf("%s should fail: %v", field, errs)
+		}
+	}
+	if req = batchTransferReq("B20261019002", BatchTransferMaxCount+1); !req.DoValidate().(ValidationErrors).HasField("total_count") {
+		t.Error("batch size should be limited")
+	}
+}
+
+func TestClient_FundBatchUniTransfer(t *testing.T) {
+	notified := make(chan *FundBatchNotifyReq, 1)
+	var batchClient *Client
+	merchant := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		notifyReq, err := batchClient.FundBatchNotify(r)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		notified <- notifyReq
+		io.WriteString(w, "success")
+	}))
+	defer merchant.Close()
+	g := alipaytest.NewGateway(alipaytest.WithNotifyURL(merchant.URL), alipaytest.WithBalance(MustParseMoney("100.00")))
+	defer g.Close()
+	batchClient, err := g.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	req := batchTransferReq("B20261019003", 45)
+	res, err := batchClient.FundBatchUniTransfer(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Success() || res.Status != "WAIT_PAY" || len(res.BatchTransId) == 0 {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	g.SetTransferStatus("B20261019003-045", "FAIL", alipaytest.ErrPayeeNotExist.SubCode, alipaytest.ErrPayeeNotExist.SubMsg)
+	if err = g.PayBatch(req.OutBatchNo); err != nil {
+		t.Fatal(err)
+	}
+	notifyReq := <-notified
+	if changed := notifyReq.BizContent; changed.BatchTransId != res.BatchTransId || changed.BatchStatus != "PARTIAL_SUCCESS" || changed.SuccessItemCount != 44 || changed.FailAmount != MustParseMoney("1.50") {
+		t.Errorf("unexpected notification: %+v", changed)
+	}
+
+	queries := g.Calls("alipay.fund.batch.detail.query")
+	details := batchClient.FundBatchDetails(ctx, FundBatchDetailQueryReq{BatchTransId: res.BatchTransId, ProductCode: req.ProductCode, BizScene: req.BizScene})
+	var count int
+	var last *BatchTransDetail
+	for details.Next() {
+		count++
+		last = details.Detail()
+	}
+	if err = details.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if count != 45 || g.Calls("alipay.fund.batch.detail.query") != queries+3 || details.Batch().SuccessAmount != MustParseMoney("66.00") {
+		t.Errorf("unexpected details: %d %+v", count, details.Batch())
+	}
+	if last.OutBizNo != "B20261019003-045" || last.Status != "FAIL" || last.FailReason != alipaytest.ErrPayeeNotExist.SubMsg {
+		t.Errorf("unexpected detail: %+v", last)
+	}
+
+	// 只有待支付的批次可以关闭
+	closeRes, err := batchClient.FundBatchClose(ctx, FundBatchCloseReq{BatchTransId: res.BatchTransId, ProductCode: req.ProductCode, BizScene: req.BizScene})
+	if err != nil || closeRes.SubCode != alipaytest.ErrBatchStatusError.SubCode {
+		t.Errorf("unexpected result: %+v %v", closeRes, err)
+	}
+	res, err = batchClient.FundBatchUniTransfer(ctx, batchTransferReq("B20261019004", 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	closeRes, err = batchClient.FundBatchClose(ctx, FundBatchCloseReq{BatchTransId: res.BatchTransId, ProductCode: req.ProductCode, BizScene: req.BizScene})
+	if err != nil || closeRes.Status != "DISUSE" {
+		t.Errorf("unexpected result: %+v %v", closeRes, err)
+	}
+	if notifyReq = <-notified; notifyReq.BizContent.BatchStatus != "DISUSE" {
+		t.Errorf("unexpected notification: %+v", notifyReq.BizContent)
+	}
+}
+
+func TestTradePayReq_AuthNo(t *testing.T) {
+	req := TradePayReq{OutTradeNo: "P20261020001", TotalAmount: MustParseMoney("10.00"), Subject: "租金", ProductCode: PreAuthPay, AuthNo: "2026102020010000000000000001", AuthConfirmMode: AuthConfirmModeComplete}
+	if err := req.DoValidate(); err != nil {
+		t.Fatal(err)
+	}
+	req.ProductCode = FaceToFacePayment
+	if errs, ok := req.DoValidate().(ValidationErrors); !ok || len(errs) != 1 || errs[0].Field != "product_code" || errs[0].Code != ValidationCodeMismatch {
+		t.Errorf("product_code should be PREAUTH_PAY with auth_no: %v", errs)
+	}
+	req.ProductCode = ""
+	if errs, ok := req.DoValidate().(ValidationErrors); !ok || !errs.HasField("product_code") {
+		t.Errorf("product_code should be required with auth_no: %v", errs)
+	}
+	req.ProductCode, req.AuthNo = PreAuthPay, ""
+	if errs, ok := req.DoValidate().(ValidationErrors); !ok || !errs.HasField("auth_code") {
+		t.Errorf("auth_code should be required without auth_no: %v", errs)
+	}
+	req.AuthCode, req.AuthConfirmMode = "285516572327851289", "FINISH"
+	if errs, ok := req.DoValidate().(ValidationErrors); !ok || len(errs) != 1 || !errs.HasField("auth_confirm_mode") {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+}
+
+func TestClient_FundAuth(t *testing.T) {
+	g := alipaytest.NewGateway()
+	defer g.Close()
+	authClient, err := g.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	notified := make(chan *FundAuthNotifyReq, 2)
+	merchant := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		notifyReq, err := authClient.FundAuthNotify(r)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		notified <- notifyReq
+		io.WriteString(w, "success")
+	}))
+	defer merchant.Close()
+	ctx := context.Background()
+
+	// 当面冻结100元押金
+	freezeRes, err := authClient.FundAuthOrderFreeze(ctx, FundAuthOrderFreezeReq{
+		AuthCode:     "285516572327851289",
+		AuthCodeType: "bar_code",
+		OutOrderNo:   "FA20261020001",
+		OutRequestNo: "FR20261020001",
+		OrderTitle:   "充电宝押金",
+		Amount:       MustParseMoney("100.00"),
+		ProductCode:  "PRE_AUTH",
+		NotifyUrl:    merchant.URL,
+	})
+	if err != nil || freezeRes.Status != FundAuthOperationSuccess || len(freezeRes.AuthNo) == 0 {
+		t.Fatalf("%v %v", freezeRes, err)
+	}
+	if frozen := <-notified; frozen.Unfrozen() || frozen.AuthNo != freezeRes.AuthNo || frozen.Amount != MustParseMoney("100.00") {
+		t.Errorf("unexpected notify: %v", frozen)
+	}
+
+	// 部分解冻
+	unfreezeReq := FundAuthOrderUnfreezeReq{AuthNo: freezeRes.AuthNo, OutRequestNo: "UF20261020001", Amount: MustParseMoney("20.00"), Remark: "归还部分押金"}
+	unfreezeRes, err := authClient.FundAuthOrderUnfreeze(ctx, unfreezeReq)
+	if err != nil || unfreezeRes.Status != FundAuthOperationSuccess {
+		t.Fatalf("%v %v", unfreezeRes, err)
+	}
+	if unfrozen := <-notified; !unfrozen.Unfrozen() || unfrozen.RestAmount != MustParseMoney("80.00") {
+		t.Errorf("unexpected notify: %v", unfrozen)
+	}
+	// 重复的请求流水号不会重复解冻
+	if again, err := authClient.FundAuthOrderUnfreeze(ctx, unfreezeReq); err != nil || again.OperationId != unfreezeRes.OperationId {
+		t.Errorf("unfreeze should be idempotent: %v %v", again, err)
+	}
+
+	// 转支付50元并结束授权，剩余30元自动解冻
+	payRes, err := authClient.TradePay(ctx, TradePayReq{OutTradeNo: "P20261020001", TotalAmount: MustParseMoney("50.00"), Subject: "租金", ProductCode: PreAuthPay, AuthNo: freezeRes.AuthNo, AuthConfirmMode: AuthConfirmModeComplete})
+	if err != nil || payRes.Fail() {
+		t.Fatalf("%v %v", payRes, err)
+	}
+	if unfrozen := <-notified; !unfrozen.Unfrozen() || unfrozen.Amount != MustParseMoney("30.00") || !unfrozen.RestAmount.IsZero() {
+		t.Errorf("unexpected notify: %v", unfrozen)
+	}
+	queryRes, err := authClient.FundAuthOperationDetailQuery(ctx, FundAuthOperationDetailQueryReq{OutOrderNo: "FA20261020001", OutRequestNo: "P20261020001", OperationType: FundAuthOperationPay})
+	if err != nil || queryRes.OrderStatus != FundAuthOrderFinish || queryRes.TotalPayAmount != MustParseMoney("50.00") || queryRes.Amount != MustParseMoney("50.00") {
+		t.Fatalf("%v %v", queryRes, err)
+	}
+	unfreezeReq.OutRequestNo = "UF20261020002"
+	if res, _ := authClient.FundAuthOrderUnfreeze(ctx, unfreezeReq); res.SubCode != alipaytest.ErrAuthOrderStatusError.SubCode {
+		t.Errorf("finished order should not be unfrozen: %v", res)
+	}
+
+	// app冻结，用户确认后才能转支付
+	appRes, err := authClient.FundAuthOrderAppFreeze(FundAuthOrderAppFreezeReq{OutOrderNo: "FA20261020002", OutRequestNo: "FR20261020002", OrderTitle: "租机押金", Amount: MustParseMoney("3000.00"), ProductCode: PreAuthOnline, NotifyUrl: merchant.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth, err := g.SubmitFundAuthOrderString(appRes.OrderString)
+	if err != nil || auth.Status != FundAuthOrderInit {
+		t.Fatalf("%+v %v", auth, err)
+	}
+	if res, _ := authClient.TradePay(ctx, TradePayReq{OutTradeNo: "P20261020002", TotalAmount: MustParseMoney("10.00"), Subject: "租金", ProductCode: PreAuthPay, AuthNo: auth.AuthNo}); res.SubCode != alipaytest.ErrAuthOrderStatusError.SubCode {
+		t.Errorf("unconfirmed order should not be paid: %v", res)
+	}
+	if err = g.ConfirmFundAuth("FA20261020002", ""); err != nil {
+		t.Fatal(err)
+	}
+	if frozen := <-notified; frozen.OutOrderNo != "FA20261020002" || frozen.PayerUserId != alipaytest.DefaultBuyerId {
+		t.Errorf("unexpected notify: %v", frozen)
+	}
+	if res, _ := authClient.TradePay(ctx, TradePayReq{OutTradeNo: "P20261020003", TotalAmount: MustParseMoney("3000.01"), Subject: "租金", ProductCode: PreAuthPay, AuthNo: auth.AuthNo}); res.SubCode != alipaytest.ErrAuthPayAmountExceed.SubCode {
+		t.Errorf("pay amount should not exceed rest amount: %v", res)
+	}
+
+	voucherRes, err := authClient.FundAuthOrderVoucherCreate(ctx, FundAuthOrderVoucherCreateReq{OutOrderNo: "FA20261020003", OutRequestNo: "FR20261020003", OrderTitle: "房卡押金", Amount: MustParseMoney("500.00")})
+	if err != nil || len(voucherRes.CodeValue) == 0 {
+		t.Fatalf("%v %v", voucherRes, err)
+	}
+	if auth, _ = g.FundAuth("FA20261020003"); auth.Status != FundAuthOrderInit {
+		t.Errorf("unexpected order: %+v", auth)
+	}
+}
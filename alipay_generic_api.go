@@ -0,0 +1,94 @@
+package alipay
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+/**
+ * @author: Sam
+ * @since: 1.0.0
+ * @date: 2026/10/19 19:10
+ * @desc: 通用请求，用于调用SDK尚未封装的接口
+ */
+
+// GenericReq 通用请求，BizContent 原样作为biz_content发送
+type GenericReq struct {
+	baseAliPayRequest
+	Method     string          `json:"-" validate:"required"` // 接口名称 alipay.trade.query
+	BizContent json.RawMessage `json:"-"`                     // 业务参数
+}
+
+// NewGenericReq bizContent 可以是结构体、map或者json字符串
+func NewGenericReq(method string, bizContent interface{}) (*GenericReq, error) {
+	req := &GenericReq{Method: method}
+	switch v := bizContent.(type) {
+	case nil:
+	case json.RawMessage:
+		req.BizContent = v
+	case []byte:
+		req.BizContent = v
+	case string:
+		req.BizContent = json.RawMessage(v)
+	default:
+		buff, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		req.BizContent = buff
+	}
+	if len(req.BizContent) > 0 && !json.Valid(req.BizContent) {
+		return nil, ErrInvalidBizContent
+	}
+	return req, nil
+}
+
+func (r *GenericReq) RequestApi() string {
+	return r.Method
+}
+
+func (r *GenericReq) DoValidate() error {
+	return ValidateStruct(r)
+}
+
+func (r *GenericReq) MarshalJSON() ([]byte, error) {
+	if len(r.BizContent) == 0 {
+		return []byte("{}"), nil
+	}
+	return r.BizContent, nil
+}
+
+// GenericRes 通用响应，Content 为xxx_response对应的原始内容
+type GenericRes struct {
+	CommonRes
+	Content json.RawMessage
+	SignCertSn
+}
+
+func (r *GenericRes) UnmarshalJSON(buff []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(buff, &fields); err != nil {
+		return err
+	}
+	for key, value := range fields {
+		var err error
+		switch {
+		case key == ExcludeKeySign:
+			err = json.Unmarshal(value, &r.Sign)
+		case key == "alipay_cert_sn":
+			err = json.Unmarshal(value, &r.AlipayCertSn)
+		case strings.HasSuffix(key, "_response"):
+			r.Content = value
+			err = json.Unmarshal(value, &r.CommonRes)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Unmarshal 将响应内容解析到v
+func (r *GenericRes) Unmarshal(v interface{}) error {
+	return json.Unmarshal(r.Content, v)
+}
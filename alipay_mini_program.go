@@ -0,0 +1,88 @@
+package alipay
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+/**
+ * @author: Sam
+ * @since: 1.0.0
+ * @date: 2026/10/20 04:40
+ * @desc: 小程序敏感数据解密
+ *
+ * 小程序通过 my.getPhoneNumber 等接口获取的数据为AES加密的 response 和支付宝的签名 sign，
+ * 需要先使用支付宝公钥对 "response" (包含双引号)验签，再使用开放平台配置的AES密钥解密。
+ * 公钥和证书两种模式下均使用当前的支付宝公钥验签。
+ */
+
+var ErrEncryptKeyEmpty = errors.New("xpay: encrypt key is empty, use SetClientOptEncryptKey")
+
+// MiniProgramEncryptedData 小程序端获取的加密数据，可以由前端原样提交到服务端解析
+type MiniProgramEncryptedData struct {
+	Response    string `json:"response"`               // AES加密的数据
+	Sign        string `json:"sign"`                   // 支付宝对加密数据的签名
+	SignType    string `json:"sign_type,omitempty"`    // 签名类型 RSA2
+	EncryptType string `json:"encrypt_type,omitempty"` // 加密类型 AES
+	Charset     string `json:"charset,omitempty"`      // 字符集 UTF-8
+}
+
+// MiniProgramData 解密后的数据，code为10000时表示获取成功
+type MiniProgramData struct {
+	CommonRes
+	Mobile  string          `json:"mobile,omitempty"` // 手机号，my.getPhoneNumber 返回
+	Content json.RawMessage `json:"-"`                // 解密后的原始内容
+}
+
+// Unmarshal 将解密后的内容解析到v，用于获取手机号以外的敏感字段
+func (r *MiniProgramData) Unmarshal(v interface{}) error {
+	return json.Unmarshal(r.Content, v)
+}
+
+func (r *MiniProgramData) String() string {
+	return string(r.Content)
+}
+
+// DecryptMiniProgramData 验签并解密小程序获取的敏感数据 https://opendocs.alipay.com/common/02mse3
+func (r *Client) DecryptMiniProgramData(encrypted, sign string) (*MiniProgramData, error) {
+	if len(r.encryptKey) == 0 {
+		return nil, ErrEncryptKeyEmpty
+	}
+	key, err := ParseAESKey(r.encryptKey)
+	if err != nil {
+		return nil, err
+	}
+	// 加密数据的待验签内容为带双引号的密文
+	if err = r.VerifySign(AsyncVerificationScene, sign, []byte(`"`+encrypted+`"`)); err != nil {
+		return nil, fmt.Errorf("xpay: mini program data verify sign failed: %w", err)
+	}
+	buff, err := AESDecrypt(encrypted, key)
+	if err != nil {
+		return nil, err
+	}
+	data := &MiniProgramData{Content: buff}
+	if err = json.Unmarshal(buff, data); err != nil {
+		return nil, err
+	}
+	// 解密失败的原因以驼峰形式的subCode、subMsg返回
+	if data.Fail() && len(data.SubCode) == 0 {
+		var camel struct {
+			SubCode string `json:"subCode"`
+			SubMsg  string `json:"subMsg"`
+		}
+		if err = json.Unmarshal(buff, &camel); err == nil {
+			data.SubCode, data.SubMsg = camel.SubCode, camel.SubMsg
+		}
+	}
+	return data, nil
+}
+
+// DecryptMiniProgramResponse 解析并解密小程序端提交的完整加密数据
+func (r *Client) DecryptMiniProgramResponse(buff []byte) (*MiniProgramData, error) {
+	encrypted := new(MiniProgramEncryptedData)
+	if err := json.Unmarshal(buff, encrypted); err != nil {
+		return nil, err
+	}
+	return r.DecryptMiniProgramData(encrypted.Response, encrypted.Sign)
+}
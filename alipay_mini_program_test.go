@@ -0,0 +1,87 @@
+package alipay_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/try-labs/xpay"
+	"github.com/try-labs/xpay/alipaytest"
+)
+
+/**
+ * @author: Sam
+ * @since:
+ * @date: 2026/10/20 04:40
+ * @desc:
+ */
+
+func TestClient_DecryptMiniProgramData(t *testing.T) {
+	for _, mode := range []struct {
+		name string
+		opts []alipaytest.Option
+	}{
+		{"public_key", nil},
+		{"cert", []alipaytest.Option{alipaytest.WithCertMode()}},
+	} {
+		t.Run(mode.name, func(t *testing.T) {
+			g := alipaytest.NewGateway(mode.opts...)
+			defer g.Close()
+			miniClient, err := g.Client()
+			if err != nil {
+				t.Fatal(err)
+			}
+			encrypted, err := g.EncryptPhoneNumber("13800000000")
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, err := miniClient.DecryptMiniProgramData(encrypted.Response, encrypted.Sign)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if data.Fail() || data.Mobile != "13800000000" {
+				t.Errorf("unexpected data: %s", data)
+			}
+			buff, _ := json.Marshal(encrypted)
+			if data, err = miniClient.DecryptMiniProgramResponse(buff); err != nil || data.Mobile != "13800000000" {
+				t.Errorf("%v %v", data, err)
+			}
+			// 密文被篡改时验签失败
+			if _, err = miniClient.DecryptMiniProgramData(encrypted.Response[1:], encrypted.Sign); err == nil {
+				t.Error("expect verify sign error")
+			}
+		})
+	}
+}
+
+func TestClient_DecryptMiniProgramData_Fields(t *testing.T) {
+	g := alipaytest.NewGateway()
+	defer g.Close()
+	miniClient, err := g.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := g.EncryptMiniProgramData([]byte(`{"code":"40003","msg":"Insufficient Conditions","subCode":"isv.invalid-auth-relations","subMsg":"无效的授权关系"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := miniClient.DecryptMiniProgramData(encrypted.Response, encrypted.Sign)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !data.Fail() || data.SubCode != "isv.invalid-auth-relations" || len(data.Mobile) > 0 {
+		t.Errorf("unexpected data: %s", data)
+	}
+	var fields map[string]string
+	if err = data.Unmarshal(&fields); err != nil || fields["subCode"] != "isv.invalid-auth-relations" {
+		t.Errorf("%v %v", fields, err)
+	}
+
+	noKeyClient, _ := g.Client(SetClientOptEncryptKey(""))
+	if _, err = noKeyClient.DecryptMiniProgramData(encrypted.Response, encrypted.Sign); err != ErrEncryptKeyEmpty {
+		t.Errorf("expect ErrEncryptKeyEmpty, got %v", err)
+	}
+	invalidKeyClient, _ := g.Client(SetClientOptEncryptKey("aW52YWxpZA=="))
+	if _, err = invalidKeyClient.DecryptMiniProgramData(encrypted.Response, encrypted.Sign); err != ErrAESKey {
+		t.Errorf("expect ErrAESKey, got %v", err)
+	}
+}
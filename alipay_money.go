@@ -0,0 +1,230 @@
+package alipay
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+/**
+ * @author: Sam
+ * @since: 1.0.0
+ * @date: 2026/10/19 10:12
+ * @desc: 金额类型，以分为单位精确存储，序列化为支付宝要求的"0.00"格式
+ */
+
+// Money 金额，内部以分为单位存储，避免浮点数带来的精度问题
+type Money int64
+
+const (
+	// MinTradeAmount 交易金额下限 0.01元
+	MinTradeAmount Money = 1
+	// MaxTradeAmount 交易金额上限 100000000元
+	MaxTradeAmount Money = 100000000 * 100
+	// MinTransAmount 转账金额下限 0.1元
+	MinTransAmount Money = 10
+)
+
+var ErrMoneyFormat = errors.New("xpay: money format error")
+var ErrMoneyPrecision = errors.New("xpay: money precision error, at most 2 decimal places")
+var ErrMoneyOverflow = errors.New("xpay: money overflow")
+
+// maxMoneyYuan 解析时允许的最大整数位，防止int64溢出
+const maxMoneyYuan = 1e15
+
+// NewMoneyFromCents 以分为单位创建金额
+func NewMoneyFromCents(cents int64) Money {
+	return Money(cents)
+}
+
+// NewMoneyFromYuan 以元为单位创建金额
+func NewMoneyFromYuan(yuan int64) Money {
+	return Money(yuan * 100)
+}
+
+// ParseMoney 解析以元为单位的金额字符串，如"9.99"、"100"、"-0.5"，最多支持两位小数
+func ParseMoney(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	if len(s) == 0 {
+		return 0, ErrMoneyFormat
+	}
+	negative := false
+	switch s[0] {
+	case '-':
+		negative = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+	intPart, fracPart := s, ""
+	if index := strings.IndexByte(s, '.'); index >= 0 {
+		intPart, fracPart = s[:index], s[index+1:]
+	}
+	if len(intPart) == 0 && len(fracPart) == 0 {
+		return 0, ErrMoneyFormat
+	}
+	if !isDigits(intPart) || !isDigits(fracPart) {
+		return 0, ErrMoneyFormat
+	}
+	// 去掉小数部分末尾多余的0，如"1.500"
+	fracPart = strings.TrimRight(fracPart, "0")
+	if len(fracPart) > 2 {
+		return 0, ErrMoneyPrecision
+	}
+	var yuan int64
+	if len(intPart) > 0 {
+		var err error
+		if yuan, err = strconv.ParseInt(intPart, 10, 64); err != nil || yuan > maxMoneyYuan {
+			return 0, ErrMoneyOverflow
+		}
+	}
+	var cents int64
+	if len(fracPart) > 0 {
+		cents, _ = strconv.ParseInt((fracPart + "0")[:2], 10, 64)
+	}
+	value := yuan*100 + cents
+	if negative {
+		value = -value
+	}
+	return Money(value), nil
+}
+
+// MustParseMoney 解析金额，失败时panic，适用于常量金额
+func MustParseMoney(s string) Money {
+	m, err := ParseMoney(s)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Cents 以分为单位的金额
+func (m Money) Cents() int64 {
+	return int64(m)
+}
+
+// String 格式化为支付宝要求的"0.00"格式
+func (m Money) String() string {
+	value := int64(m)
+	sign := ""
+	if value < 0 {
+		sign = "-"
+		value = -value
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, value/100, value%100)
+}
+
+// Add 金额相加
+func (m Money) Add(other Money) Money {
+	return m + other
+}
+
+// Sub 金额相减
+func (m Money) Sub(other Money) Money {
+	return m - other
+}
+
+// Cmp 比较金额，m < other 返回-1，相等返回0，m > other 返回1
+func (m Money) Cmp(other Money) int {
+	switch {
+	case m < other:
+		return -1
+	case m > other:
+		return 1
+	}
+	return 0
+}
+
+// IsZero 是否为0
+func (m Money) IsZero() bool {
+	return m == 0
+}
+
+// IsPositive 是否大于0
+func (m Money) IsPositive() bool {
+	return m > 0
+}
+
+// IsNegative 是否小于0
+func (m Money) IsNegative() bool {
+	return m < 0
+}
+
+// InRange 金额是否在 [min, max] 区间内
+func (m Money) InRange(min, max Money) bool {
+	return m >= min && m <= max
+}
+
+// ValidateRange 校验金额是否在 [min, max] 区间内
+func (m Money) ValidateRange(min, max Money) error {
+	if !m.InRange(min, max) {
+		return fmt.Errorf("金额%s不在取值范围[%s,%s]内", m, min, max)
+	}
+	return nil
+}
+
+// SumMoney 金额求和
+func SumMoney(list ...Money) Money {
+	var total Money
+	for _, m := range list {
+		total += m
+	}
+	return total
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + m.String() + `"`), nil
+}
+
+// UnmarshalJSON 支持 "9.99"、9.99、"" 以及 null 几种格式
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	str := string(data)
+	if len(data) >= 2 && data[0] == '"' && data[len(data)-1] == '"' {
+		var err error
+		if str, err = strconv.Unquote(str); err != nil {
+			return err
+		}
+	}
+	if len(strings.TrimSpace(str)) == 0 {
+		*m = 0
+		return nil
+	}
+	value, err := ParseMoney(str)
+	if err != nil {
+		return err
+	}
+	*m = value
+	return nil
+}
+
+func (m Money) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+func (m *Money) UnmarshalText(text []byte) error {
+	if len(bytes.TrimSpace(text)) == 0 {
+		*m = 0
+		return nil
+	}
+	value, err := ParseMoney(string(text))
+	if err != nil {
+		return err
+	}
+	*m = value
+	return nil
+}
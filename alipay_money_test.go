@@ -0,0 +1,133 @@
+package alipay
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+/**
+ * @author: Sam
+ * @since:
+ * @date: 2026/10/19 10:40
+ * @desc:
+ */
+
+func TestParseMoney(t *testing.T) {
+	cases := []struct {
+		input string
+		cents int64
+		err   error
+	}{
+		{"9.99", 999, nil},
+		{"100", 10000, nil},
+		{"0.1", 10, nil},
+		{".5", 50, nil},
+		{"1.500", 150, nil},
+		{"-10.00", -1000, nil},
+		{" 100000000.00 ", 10000000000, nil},
+		{"0.001", 0, ErrMoneyPrecision},
+		{"1,000.00", 0, ErrMoneyFormat},
+		{"abc", 0, ErrMoneyFormat},
+		{"", 0, ErrMoneyFormat},
+		{".", 0, ErrMoneyFormat},
+	}
+	for _, c := range cases {
+		m, err := ParseMoney(c.input)
+		if err != c.err {
+			t.Errorf("ParseMoney(%q) err = %v, want %v", c.input, err, c.err)
+			continue
+		}
+		if err == nil && m.Cents() != c.cents {
+			t.Errorf("ParseMoney(%q) = %d, want %d", c.input, m.Cents(), c.cents)
+		}
+	}
+}
+
+func TestMoney_String(t *testing.T) {
+	cases := map[Money]string{
+		0:                      "0.00",
+		1:                      "0.01",
+		999:                    "9.99",
+		-150:                   "-1.50",
+		NewMoneyFromYuan(100):  "100.00",
+		MaxTradeAmount:         "100000000.00",
+		MustParseMoney("0.10"): "0.10",
+	}
+	for m, want := range cases {
+		if got := m.String(); got != want {
+			t.Errorf("Money(%d).String() = %s, want %s", int64(m), got, want)
+		}
+	}
+}
+
+func TestMoney_Arithmetic(t *testing.T) {
+	a := MustParseMoney("0.10")
+	b := MustParseMoney("0.20")
+	if sum := a.Add(b); sum != MustParseMoney("0.30") {
+		t.Errorf("0.10 + 0.20 = %s", sum)
+	}
+	if diff := a.Sub(b); diff.String() != "-0.10" {
+		t.Errorf("0.10 - 0.20 = %s", diff)
+	}
+	if a.Cmp(b) != -1 || b.Cmp(a) != 1 || a.Cmp(a) != 0 {
+		t.Errorf("Cmp result error")
+	}
+	if total := SumMoney(a, b, a); total.String() != "0.40" {
+		t.Errorf("SumMoney = %s", total)
+	}
+}
+
+func TestMoney_ValidateRange(t *testing.T) {
+	if err := MustParseMoney("0.01").ValidateRange(MinTradeAmount, MaxTradeAmount); err != nil {
+		t.Error(err)
+	}
+	if err := MustParseMoney("100000000").ValidateRange(MinTradeAmount, MaxTradeAmount); err != nil {
+		t.Error(err)
+	}
+	if err := Money(0).ValidateRange(MinTradeAmount, MaxTradeAmount); err == nil {
+		t.Error("0.00 should be out of range")
+	}
+	if err := MustParseMoney("100000000.01").ValidateRange(MinTradeAmount, MaxTradeAmount); err == nil {
+		t.Error("100000000.01 should be out of range")
+	}
+}
+
+func TestMoney_JSON(t *testing.T) {
+	goods := NewGoodsDetail("1111", "iphone", 1, MustParseMoney("9.99"))
+	buff, err := json.Marshal(goods)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"goods_id":"1111","goods_name":"iphone","quantity":1,"price":"9.99"}`
+	if string(buff) != want {
+		t.Errorf("marshal = %s, want %s", buff, want)
+	}
+
+	// 可选金额为0时不输出
+	buff, _ = json.Marshal(TradeCreateReq{OutTradeNo: "1", TotalAmount: MustParseMoney("1.00")})
+	var object map[string]interface{}
+	_ = json.Unmarshal(buff, &object)
+	if _, ok := object["discountable_amount"]; ok {
+		t.Errorf("discountable_amount should be omitted: %s", buff)
+	}
+	// 需要传0.00的可选金额使用指针
+	var zero Money
+	buff, _ = json.Marshal(TradeCreateReq{OutTradeNo: "1", TotalAmount: MustParseMoney("1.00"), UndiscountableAmount: &zero})
+	object = nil
+	_ = json.Unmarshal(buff, &object)
+	if object["undiscountable_amount"] != "0.00" {
+		t.Errorf("undiscountable_amount should be 0.00: %s", buff)
+	}
+
+	var res TradeQueryResContent
+	data := `{"total_amount":"88.88","buyer_pay_amount":88.8,"point_amount":"","receipt_amount":null}`
+	if err = json.Unmarshal([]byte(data), &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.TotalAmount != 8888 || res.BuyerPayAmount != 8880 || res.PointAmount != 0 || res.ReceiptAmount != 0 {
+		t.Errorf("unmarshal result error: %+v", res)
+	}
+	if err = json.Unmarshal([]byte(`{"total_amount":"8.888"}`), &res); err == nil {
+		t.Error("precision error expected")
+	}
+}
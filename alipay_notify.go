@@ -9,10 +9,10 @@ package alipay
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
-	"sort"
-	"strings"
+	"net/url"
 )
 
 type NotifyReq struct {
@@ -31,12 +31,12 @@ type NotifyReq struct {
 	BuyerId          string      `json:"buyer_id,omitempty"`           // 可选  16 买家支付宝账号 ID。以 2088 开头的纯 16 位数字 20881***524333
 	SellerId         string      `json:"seller_id,omitempty"`          // 可选  30 卖家支付宝账号 ID。以 2088 开头的纯 16 位数字 20881***2239364
 	TradeStatus      TradeStatus `json:"trade_status,omitempty"`       // 可选 32 交易状态。交易目前所处状态，详情可查看下表 交易状态说明 TRADE_CLOSED
-	TotalAmount      string      `json:"total_amount,omitempty"`       // 可选 11 订单金额。本次交易支付订单金额，单位为人民币（元），精确到小数点后 2 位 20.00
-	ReceiptAmount    string      `json:"receipt_amount,omitempty"`     // 可选 11 实收金额。商家在交易中实际收到的款项，单位为人民币（元），精确到小数点后 2 位 15.00
-	InvoiceAmount    string      `json:"invoice_amount,omitempty"`     // 可选 11 开票金额。用户在交易中支付的可开发票的金额，单位为人民币（元），精确到小数点后 2 位 13.88
-	BuyerPayAmount   string      `json:"buyer_pay_amount,omitempty"`   // 可选 11 用户在交易中支付的金额，单位为人民币（元），精确到小数点后 2 位 12.00
-	PointAmount      string      `json:"point_amount,omitempty"`       // 可选 11 使用集分宝支付金额，单位为人民币（元），精确到小数点后 2 位 12.00
-	RefundFee        string      `json:"refund_fee,omitempty"`         // 可选 11 总退款金额。退款通知中，返回总退款金额，单位为人民币（元），精确到小数点后 2 位 2.58
+	TotalAmount      Money       `json:"total_amount,omitempty"`       // 可选 11 订单金额。本次交易支付订单金额，单位为人民币（元），精确到小数点后 2 位 20.00
+	ReceiptAmount    Money       `json:"receipt_amount,omitempty"`     // 可选 11 实收金额。商家在交易中实际收到的款项，单位为人民币（元），精确到小数点后 2 位 15.00
+	InvoiceAmount    Money       `json:"invoice_amount,omitempty"`     // 可选 11 开票金额。用户在交易中支付的可开发票的金额，单位为人民币（元），精确到小数点后 2 位 13.88
+	BuyerPayAmount   Money       `json:"buyer_pay_amount,omitempty"`   // 可选 11 用户在交易中支付的金额，单位为人民币（元），精确到小数点后 2 位 12.00
+	PointAmount      Money       `json:"point_amount,omitempty"`       // 可选 11 使用集分宝支付金额，单位为人民币（元），精确到小数点后 2 位 12.00
+	RefundFee        Money       `json:"refund_fee,omitempty"`         // 可选 11 总退款金额。退款通知中，返回总退款金额，单位为人民币（元），精确到小数点后 2 位 2.58
 	Subject          string      `json:"subject,omitempty"`            // 可选 256 订单标题/商品标题/交易标题/订单关键字等，是请求时对应参数，会在通知中原样传回 XXXX交易
 	Body             string      `json:"body,omitempty"`               // 可选 400 商品描述。该订单的备注、描述、明细等。对应请求时的 body 参数，会在通知中原样传回 XXX交易内容
 	GmtCreate        string      `json:"gmt_create,omitempty"`         // 可选 交易创建时间。格式为 yyyy-MM-dd HH:mm:ss 2018-08-25 15:34:42
@@ -65,20 +65,29 @@ type VocherDetail struct {
 	//ALIPAY_CASH_VOUCHER：平台优惠券，支付宝或第三方出资。
 	//ALICREDIT_INTFREE_VOUCHER：花呗分期券，该券仅做订单外的工作呗分期费用减免，并不抵扣订单内支付金额。
 	//注意：不排除未来新增其它类型的可能，商家接入时请注意兼容性，避免硬编码。
-	Amount                string              `json:"amount"`                            // 必填 11 优惠金额。优惠金额中，由商家出资的金额。 10.00
-	MerchantContribute    string              `json:"merchant_contribute,omitempty"`     // 可选 11 商家出资金额。优惠金额中，由商家出资的金额。 9.00
-	OtherContribute       string              `json:"other_contribute,omitempty"`        // 可选 11 其他出资方出资金额。可能是支付宝，可能是品牌商，或者其他方，也可能是他们的共同出资。 1.00
+	Amount                Money               `json:"amount"`                            // 必填 11 优惠金额。优惠金额中，由商家出资的金额。 10.00
+	MerchantContribute    Money               `json:"merchant_contribute,omitempty"`     // 可选 11 商家出资金额。优惠金额中，由商家出资的金额。 9.00
+	OtherContribute       Money               `json:"other_contribute,omitempty"`        // 可选 11 其他出资方出资金额。可能是支付宝，可能是品牌商，或者其他方，也可能是他们的共同出资。 1.00
 	OtherContributeDetail []*ContributeDetail `json:"other_contribute_detail,omitempty"` // 可选 优惠券的其他出资方明细
 	Memo                  string              `json:"memo,omitempty"`                    // 可选  256 优惠券备注信息。 学生专用优惠
 }
 
 type ContributeDetail struct {
 	ContributeType   string `json:"contribute_type,omitempty"`   // 可选 32 出资方类型，如品牌商出资、支付宝平台出资等。 PLATFORM
-	ContributeAmount string `json:"contribute_amount,omitempty"` // 可选 8 出资方金额
+	ContributeAmount Money  `json:"contribute_amount,omitempty"` // 可选 8 出资方金额
 }
 
 // 通知逻辑
 func (r *Client) doNotify(request *http.Request) (*NotifyReq, error) {
+	notifyParam := new(NotifyReq)
+	if _, err := r.verifyNotify(request, notifyParam); err != nil {
+		return nil, err
+	}
+	return notifyParam, nil
+}
+
+// verifyNotify 解析通知参数并验签，通知参数解码到notifyParam
+func (r *Client) verifyNotify(request *http.Request, notifyParam interface{}) (url.Values, error) {
 	var err error
 	if err = request.ParseForm(); err != nil {
 		return nil, err
@@ -95,25 +104,15 @@ func (r *Client) doNotify(request *http.Request) (*NotifyReq, error) {
 	if buff, err = json.Marshal(notifyParamMap); err != nil {
 		return nil, err
 	}
-	var notifyParam = new(NotifyReq)
 	if err = json.Unmarshal(buff, notifyParam); err != nil {
 		return nil, err
 	}
-	var keyValueList = make([]string, 0, len(notifyParamMap))
-	for key, value := range notifyParamMap {
-		// 删除sign、sign_type
-		if key == ExcludeKeySign || key == ExcludeKeySignType {
-			continue
-		}
-		keyValueList = append(keyValueList, key+"="+value)
-	}
-	sort.Strings(keyValueList)
-	if err = r.VerifySign(AsyncVerificationScene, notifyParam.Sign, []byte(strings.Join(keyValueList, "&")), notifyParam.AlipayCertSn); err != nil {
+	if err = r.VerifySign(AsyncVerificationScene, notifyParamMap[ExcludeKeySign], []byte(NotifySignContent(urlValues)), notifyParamMap["alipay_cert_sn"]); err != nil {
 		log.Println("校验参数err", err)
 		return nil, err
 	}
 	log.Println("verification success")
-	return notifyParam, err
+	return urlValues, nil
 }
 
 // AsyncNotify 异步通知
@@ -127,3 +126,281 @@ func (r *Client) SyncNotify(request *http.Request) (*NotifyReq, error) {
 	log.Println("sync notify verification ")
 	return r.doNotify(request)
 }
+
+// FundBatchOrderChangedMethod 批次状态变化的消息通知
+const FundBatchOrderChangedMethod = "alipay.fund.batch.order.changed"
+
+// FundBatchNotifyReq 批量转账的消息通知，发送到应用网关地址，业务参数在biz_content中
+type FundBatchNotifyReq struct {
+	NotifyId     string `json:"notify_id"`     // 必填 通知校验 ID
+	UtcTimestamp string `json:"utc_timestamp"` // 必填 通知的发送时间，毫秒时间戳
+	MsgMethod    string `json:"msg_method"`    // 必填 消息接口名称 alipay.fund.batch.order.changed
+	AppId        string `json:"app_id"`        // 必填 支付宝应用的APPID
+	Version      string `json:"version"`       // 必填 版本号 1.1
+	Charset      string `json:"charset"`       // 必填 编码格式
+	SignType     string `json:"sign_type"`     // 必填 签名类型
+	Sign         string `json:"sign"`          // 必填 签名
+	// BizContent 由biz_content解码
+	BizContent FundBatchOrderChanged `json:"-"`
+	// 证书签名特有
+	AlipayCertSn string `json:"alipay_cert_sn,omitempty"`
+}
+
+func (r FundBatchNotifyReq) String() string {
+	buff, _ := json.Marshal(r)
+	return string(buff)
+}
+
+// FundBatchOrderChanged 批次状态变化的业务参数
+type FundBatchOrderChanged struct {
+	BatchTransId     string `json:"batch_trans_id"`               // 必填 64 支付宝批次单号
+	OutBatchNo       string `json:"out_batch_no"`                 // 必填 32 商户批次号
+	ProductCode      string `json:"product_code,omitempty"`       // 可选 32 销售产品码
+	BizScene         string `json:"biz_scene,omitempty"`          // 可选 32 业务场景
+	BatchStatus      string `json:"batch_status"`                 // 必填 32 批次状态。SUCCESS、PARTIAL_SUCCESS、FAIL、DISUSE
+	TotalAmount      Money  `json:"total_amount,omitempty"`       // 可选 20 批次总金额
+	SuccessAmount    Money  `json:"success_amount,omitempty"`     // 可选 20 成功金额
+	FailAmount       Money  `json:"fail_amount,omitempty"`        // 可选 20 失败金额
+	TotalItemCount   int    `json:"total_item_count,omitempty"`   // 可选 10 批次总笔数
+	SuccessItemCount int    `json:"success_item_count,omitempty"` // 可选 10 成功笔数
+	FailItemCount    int    `json:"fail_item_count,omitempty"`    // 可选 10 失败笔数
+	PassbackParams   string `json:"passback_params,omitempty"`    // 可选 1024 转账时传入的回传参数
+	GmtFinish        string `json:"gmt_finish,omitempty"`         // 可选 20 批次完成时间
+}
+
+// FundBatchNotify 批量转账的消息通知，msg_method不是 alipay.fund.batch.order.changed 时返回error
+func (r *Client) FundBatchNotify(request *http.Request) (*FundBatchNotifyReq, error) {
+	log.Println("fund batch notify verification ")
+	notifyParam := new(FundBatchNotifyReq)
+	urlValues, err := r.verifyNotify(request, notifyParam)
+	if err != nil {
+		return nil, err
+	}
+	if notifyParam.MsgMethod != FundBatchOrderChangedMethod {
+		return nil, fmt.Errorf("xpay: unexpected msg_method %s", notifyParam.MsgMethod)
+	}
+	if err = json.Unmarshal([]byte(urlValues.Get("biz_content")), &notifyParam.BizContent); err != nil {
+		return nil, err
+	}
+	return notifyParam, nil
+}
+
+// 代扣协议的通知类型
+const (
+	NotifyTypeAgreementSign   = "dut_user_sign"
+	NotifyTypeAgreementUnsign = "dut_user_unsign"
+)
+
+// UserAgreementNotifyReq 代扣协议的签约、解约通知
+type UserAgreementNotifyReq struct {
+	NotifyId            string          `json:"notify_id"`                       // 必填 128 通知校验 ID
+	NotifyTime          string          `json:"notify_time"`                     // 必填 通知的发送时间
+	NotifyType          string          `json:"notify_type"`                     // 必填 64 签约为 dut_user_sign，解约为 dut_user_unsign
+	SignType            string          `json:"sign_type"`                       // 必填 10 签名类型
+	Sign                string          `json:"sign"`                            // 必填 344 签名
+	AppId               string          `json:"app_id"`                          // 必填 32 支付宝应用的APPID
+	AuthAppId           string          `json:"auth_app_id,omitempty"`           // 可选 32 授权方的APPID
+	Charset             string          `json:"charset,omitempty"`               // 可选 10 编码格式
+	Version             string          `json:"version,omitempty"`               // 可选 3 接口版本
+	AgreementNo         string          `json:"agreement_no"`                    // 必填 64 支付宝系统中用以唯一标识用户签约记录的编号
+	ExternalAgreementNo string          `json:"external_agreement_no,omitempty"` // 可选 32 商户签约号
+	PersonalProductCode string          `json:"personal_product_code"`           // 必填 64 个人签约产品码
+	SignScene           string          `json:"sign_scene"`                      // 必填 64 协议签约场景
+	Status              AgreementStatus `json:"status"`                          // 必填 16 协议状态，签约为 NORMAL，解约为 UNSIGN
+	AlipayUserId        string          `json:"alipay_user_id"`                  // 必填 16 用户的支付宝账号对应的支付宝唯一用户号
+	AlipayLogonId       string          `json:"alipay_logon_id,omitempty"`       // 可选 100 用户的支付宝登录账号
+	ExternalLogonId     string          `json:"external_logon_id,omitempty"`     // 可选 100 用户在商户网站的登录账号
+	PartnerId           string          `json:"partner_id,omitempty"`            // 可选 16 签约的商户ID
+	SignTime            string          `json:"sign_time,omitempty"`             // 可选 协议签约时间，格式为 yyyy-MM-dd HH:mm:ss
+	ValidTime           string          `json:"valid_time,omitempty"`            // 可选 协议生效时间
+	InvalidTime         string          `json:"invalid_time,omitempty"`          // 可选 协议失效时间
+	UnsignTime          string          `json:"unsign_time,omitempty"`           // 可选 协议解约时间，解约通知返回
+	SingleQuota         Money           `json:"single_quota,omitempty"`          // 可选 单笔代扣额度
+	// 证书签名特有
+	AlipayCertSn string `json:"alipay_cert_sn,omitempty"`
+}
+
+// Unsigned 是否为解约通知
+func (r *UserAgreementNotifyReq) Unsigned() bool {
+	return r.NotifyType == NotifyTypeAgreementUnsign
+}
+
+func (r UserAgreementNotifyReq) String() string {
+	buff, _ := json.Marshal(r)
+	return string(buff)
+}
+
+// AgreementNotify 代扣协议的签约、解约通知，notify_type不是 dut_user_sign 或者 dut_user_unsign 时返回error
+func (r *Client) AgreementNotify(request *http.Request) (*UserAgreementNotifyReq, error) {
+	log.Println("agreement notify verification ")
+	notifyParam := new(UserAgreementNotifyReq)
+	if _, err := r.verifyNotify(request, notifyParam); err != nil {
+		return nil, err
+	}
+	if notifyParam.NotifyType != NotifyTypeAgreementSign && notifyParam.NotifyType != NotifyTypeAgreementUnsign {
+		return nil, fmt.Errorf("xpay: unexpected notify_type %s", notifyParam.NotifyType)
+	}
+	return notifyParam, nil
+}
+
+// 资金授权的通知类型
+const (
+	NotifyTypeFundAuthFreeze   = "fund_auth_freeze"
+	NotifyTypeFundAuthUnfreeze = "fund_auth_unfreeze"
+)
+
+// FundAuthNotifyReq 资金授权冻结、解冻成功后的通知
+type FundAuthNotifyReq struct {
+	NotifyId            string `json:"notify_id"`                       // 必填 128 通知校验 ID
+	NotifyTime          string `json:"notify_time"`                     // 必填 通知的发送时间
+	NotifyType          string `json:"notify_type"`                     // 必填 64 冻结为 fund_auth_freeze，解冻为 fund_auth_unfreeze
+	SignType            string `json:"sign_type"`                       // 必填 10 签名类型
+	Sign                string `json:"sign"`                            // 必填 344 签名
+	AppId               string `json:"app_id"`                          // 必填 32 支付宝应用的APPID
+	Charset             string `json:"charset,omitempty"`               // 可选 10 编码格式
+	Version             string `json:"version,omitempty"`               // 可选 3 接口版本
+	AuthNo              string `json:"auth_no"`                         // 必填 64 支付宝资金授权订单号
+	OutOrderNo          string `json:"out_order_no"`                    // 必填 64 商户的授权资金订单号
+	OperationId         string `json:"operation_id"`                    // 必填 64 支付宝的资金操作流水号
+	OutRequestNo        string `json:"out_request_no"`                  // 必填 64 商户本次资金操作的请求流水号
+	OperationType       string `json:"operation_type"`                  // 必填 32 资金操作类型，FREEZE、UNFREEZE
+	Amount              Money  `json:"amount"`                          // 必填 11 本次操作的金额
+	Status              string `json:"status"`                          // 必填 32 资金操作流水的状态，SUCCESS、CLOSED
+	GmtCreate           string `json:"gmt_create,omitempty"`            // 可选 操作创建时间
+	GmtTrans            string `json:"gmt_trans,omitempty"`             // 可选 操作成功时间
+	PayerLogonId        string `json:"payer_logon_id,omitempty"`        // 可选 100 付款方支付宝账号
+	PayerUserId         string `json:"payer_user_id,omitempty"`         // 可选 32 付款方支付宝用户号
+	PayeeLogonId        string `json:"payee_logon_id,omitempty"`        // 可选 100 收款方支付宝账号
+	PayeeUserId         string `json:"payee_user_id,omitempty"`         // 可选 32 收款方支付宝用户号
+	TotalFreezeAmount   Money  `json:"total_freeze_amount,omitempty"`   // 可选 11 累计冻结金额
+	TotalUnfreezeAmount Money  `json:"total_unfreeze_amount,omitempty"` // 可选 11 累计解冻金额
+	TotalPayAmount      Money  `json:"total_pay_amount,omitempty"`      // 可选 11 累计支付金额
+	RestAmount          Money  `json:"rest_amount,omitempty"`           // 可选 11 剩余冻结金额
+	CreditAmount        Money  `json:"credit_amount,omitempty"`         // 可选 11 本次操作中信用金额
+	FundAmount          Money  `json:"fund_amount,omitempty"`           // 可选 11 本次操作中自有资金金额
+	// 证书签名特有
+	AlipayCertSn string `json:"alipay_cert_sn,omitempty"`
+}
+
+// Unfrozen 是否为解冻通知
+func (r *FundAuthNotifyReq) Unfrozen() bool {
+	return r.NotifyType == NotifyTypeFundAuthUnfreeze
+}
+
+func (r FundAuthNotifyReq) String() string {
+	buff, _ := json.Marshal(r)
+	return string(buff)
+}
+
+// FundAuthNotify 资金授权冻结、解冻的通知，notify_type不是 fund_auth_freeze 或者 fund_auth_unfreeze 时返回error
+func (r *Client) FundAuthNotify(request *http.Request) (*FundAuthNotifyReq, error) {
+	log.Println("fund auth notify verification ")
+	notifyParam := new(FundAuthNotifyReq)
+	if _, err := r.verifyNotify(request, notifyParam); err != nil {
+		return nil, err
+	}
+	if notifyParam.NotifyType != NotifyTypeFundAuthFreeze && notifyParam.NotifyType != NotifyTypeFundAuthUnfreeze {
+		return nil, fmt.Errorf("xpay: unexpected notify_type %s", notifyParam.NotifyType)
+	}
+	return notifyParam, nil
+}
+
+// NotifyTypeTradeMergeStatusSync 合并支付的通知类型
+const NotifyTypeTradeMergeStatusSync = "trade_merge_status_sync"
+
+// TradeMergeNotifyReq 合并支付的异步通知，子订单的交易信息在order_details中
+type TradeMergeNotifyReq struct {
+	NotifyTime   string      `json:"notify_time"`              // 必填 通知的发送时间。格式为 yyyy-MM-dd HH:mm:ss
+	NotifyType   string      `json:"notify_type"`              // 必填 64 通知类型 trade_merge_status_sync
+	NotifyId     string      `json:"notify_id"`                // 必填 128 通知校验 ID
+	Charset      string      `json:"charset"`                  // 必填 10 编码格式
+	Version      string      `json:"version"`                  // 必填 3 调用的接口版本
+	SignType     string      `json:"sign_type"`                // 必填 10 签名类型
+	Sign         string      `json:"sign"`                     // 必填 344 签名
+	AppId        string      `json:"app_id"`                   // 必填 32 支付宝应用的APPID
+	AuthAppId    string      `json:"auth_app_id"`              // 必填 32 授权方的APPID
+	PreOrderNo   string      `json:"pre_order_no"`             // 必填 64 合并支付的预下单号
+	OutMergeNo   string      `json:"out_merge_no,omitempty"`   // 可选 64 合并单号
+	BuyerId      string      `json:"buyer_id,omitempty"`       // 可选 16 买家支付宝账号 ID
+	BuyerLogonId string      `json:"buyer_logon_id,omitempty"` // 可选 100 买家支付宝账号
+	TradeStatus  TradeStatus `json:"trade_status,omitempty"`   // 可选 32 合并支付的交易状态
+	TotalAmount  Money       `json:"total_amount,omitempty"`   // 可选 11 子订单金额之和
+	GmtPayment   string      `json:"gmt_payment,omitempty"`    // 可选 交易付款时间。格式为 yyyy-MM-dd HH:mm:ss
+	// OrderDetails 由order_details解码
+	OrderDetails []*MergeOrderNotifyDetail `json:"-"`
+	// 证书签名特有
+	AlipayCertSn string `json:"alipay_cert_sn,omitempty"`
+}
+
+// MergeOrderNotifyDetail 合并支付通知中的子订单
+type MergeOrderNotifyDetail struct {
+	AppId          string      `json:"app_id"`                    // 必填 32 子订单的应用ID
+	OutTradeNo     string      `json:"out_trade_no"`              // 必填 64 子订单的商户订单号
+	TradeNo        string      `json:"trade_no"`                  // 必填 64 子订单的支付宝交易号
+	SellerId       string      `json:"seller_id,omitempty"`       // 可选 30 卖家支付宝账号 ID
+	TradeStatus    TradeStatus `json:"trade_status"`              // 必填 32 子订单的交易状态
+	TotalAmount    Money       `json:"total_amount"`              // 必填 11 子订单金额
+	ReceiptAmount  Money       `json:"receipt_amount,omitempty"`  // 可选 11 子订单实收金额
+	Subject        string      `json:"subject,omitempty"`         // 可选 256 子订单标题
+	Body           string      `json:"body,omitempty"`            // 可选 400 子订单描述
+	PassbackParams string      `json:"passback_params,omitempty"` // 可选 512 子订单的回传参数
+	GmtCreate      string      `json:"gmt_create,omitempty"`      // 可选 子订单创建时间
+	GmtPayment     string      `json:"gmt_payment,omitempty"`     // 可选 子订单付款时间
+}
+
+func (r TradeMergeNotifyReq) String() string {
+	buff, _ := json.Marshal(r)
+	return string(buff)
+}
+
+// Trades 按子订单拆分为trade_status_sync的交易通知，便于复用单笔交易的通知处理逻辑。
+// 拆分的通知不是支付宝发送的，不包含签名，不能再次验签，真实性已由 TradeMergeNotify 对合并通知验签确认；
+// 同一合并通知拆分出的通知 notify_id 相同
+func (r *TradeMergeNotifyReq) Trades() []*NotifyReq {
+	trades := make([]*NotifyReq, 0, len(r.OrderDetails))
+	for _, detail := range r.OrderDetails {
+		gmtPayment := detail.GmtPayment
+		if len(gmtPayment) == 0 {
+			gmtPayment = r.GmtPayment
+		}
+		trades = append(trades, &NotifyReq{
+			NotifyTime:     r.NotifyTime,
+			NotifyType:     "trade_status_sync",
+			NotifyId:       r.NotifyId,
+			Charset:        r.Charset,
+			Version:        r.Version,
+			AuthAppId:      r.AuthAppId,
+			TradeNo:        detail.TradeNo,
+			AppId:          detail.AppId,
+			OutTradeNo:     detail.OutTradeNo,
+			BuyerId:        r.BuyerId,
+			SellerId:       detail.SellerId,
+			TradeStatus:    detail.TradeStatus,
+			TotalAmount:    detail.TotalAmount,
+			ReceiptAmount:  detail.ReceiptAmount,
+			Subject:        detail.Subject,
+			Body:           detail.Body,
+			GmtCreate:      detail.GmtCreate,
+			GmtPayment:     gmtPayment,
+			PassbackParams: detail.PassbackParams,
+		})
+	}
+	return trades
+}
+
+// TradeMergeNotify 合并支付的异步通知，notify_type不是 trade_merge_status_sync 时返回error
+func (r *Client) TradeMergeNotify(request *http.Request) (*TradeMergeNotifyReq, error) {
+	log.Println("trade merge notify verification ")
+	notifyParam := new(TradeMergeNotifyReq)
+	urlValues, err := r.verifyNotify(request, notifyParam)
+	if err != nil {
+		return nil, err
+	}
+	if notifyParam.NotifyType != NotifyTypeTradeMergeStatusSync {
+		return nil, fmt.Errorf("xpay: unexpected notify_type %s", notifyParam.NotifyType)
+	}
+	if err = json.Unmarshal([]byte(urlValues.Get("order_details")), &notifyParam.OrderDetails); err != nil {
+		return nil, err
+	}
+	return notifyParam, nil
+}
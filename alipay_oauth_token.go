@@ -0,0 +1,226 @@
+package alipay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+/**
+ * @author: Sam
+ * @since: 1.0.0
+ * @date: 2026/10/20 04:00
+ * @desc: 用户授权令牌管理
+ *
+ * Exchange 使用用户授权码换取 access_token，以 auth_start 为起点计算令牌和刷新令牌的失效时间，按用户的 user_id 写入 OauthTokenStore。
+ * Token 在令牌失效前 RefreshBefore 内自动刷新，同一用户的并发刷新只会发送一次请求，其他调用等待该请求的结果。
+ * 刷新请求使用独立的ctx，超时为 RefreshTimeout，发起刷新的调用提前结束不影响其他等待的调用。
+ * 刷新令牌也已失效时返回 ErrOauthTokenExpired，需要用户重新授权。
+ */
+
+var ErrOauthTokenNotExist = errors.New("xpay: oauth token not exist")
+var ErrOauthTokenExpired = errors.New("xpay: oauth refresh token expired, user needs to authorize again")
+
+// OauthToken 用户授权令牌
+type OauthToken struct {
+	UserId       string    `json:"user_id"`       // 支付宝用户的唯一标识
+	AccessToken  string    `json:"access_token"`  // 访问令牌
+	RefreshToken string    `json:"refresh_token"` // 刷新令牌
+	AuthStart    time.Time `json:"auth_start"`    // 授权开始时间，作为有效期计算的起点
+	ExpiresAt    time.Time `json:"expires_at"`    // 访问令牌的失效时间
+	ReExpiresAt  time.Time `json:"re_expires_at"` // 刷新令牌的失效时间
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// OauthTokenStore 用户授权令牌的存储
+type OauthTokenStore interface {
+	// OauthToken 按用户的user_id查询，不存在时返回nil
+	OauthToken(ctx context.Context, userId string) (*OauthToken, error)
+	// Save 新增或者按 UserId 更新令牌
+	Save(ctx context.Context, token *OauthToken) error
+	// Delete 删除用户的令牌
+	Delete(ctx context.Context, userId string) error
+}
+
+// oauthRefreshCall 正在进行的刷新，同一用户的其他调用等待done关闭后共享结果
+type oauthRefreshCall struct {
+	done  chan struct{}
+	token *OauthToken
+	err   error
+}
+
+// OauthTokenManager 用户授权令牌管理
+type OauthTokenManager struct {
+	client     *Client
+	store      OauthTokenStore
+	mu         sync.Mutex
+	refreshing map[string]*oauthRefreshCall
+
+	// RefreshBefore 访问令牌失效前多久开始刷新，默认10分钟
+	RefreshBefore time.Duration
+	// RefreshTimeout 刷新请求的超时时间，默认30秒
+	RefreshTimeout time.Duration
+	Now            func() time.Time
+}
+
+func NewOauthTokenManager(client *Client, store OauthTokenStore) *OauthTokenManager {
+	return &OauthTokenManager{
+		client:         client,
+		store:          store,
+		refreshing:     make(map[string]*oauthRefreshCall),
+		RefreshBefore:  10 * time.Minute,
+		RefreshTimeout: 30 * time.Second,
+		Now:            time.Now,
+	}
+}
+
+// Exchange 使用用户授权码换取令牌并保存
+func (r *OauthTokenManager) Exchange(ctx context.Context, code string) (*OauthToken, error) {
+	return r.requestToken(ctx, OauthTokenReq{GrantType: GrantAuthorizationCode, Code: code})
+}
+
+// Token 用户当前有效的令牌，即将失效时先刷新
+func (r *OauthTokenManager) Token(ctx context.Context, userId string) (*OauthToken, error) {
+	token, err := r.load(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if !r.expiring(token) {
+		return token, nil
+	}
+	return r.refresh(ctx, userId, false)
+}
+
+// Refresh 立即刷新用户的令牌
+func (r *OauthTokenManager) Refresh(ctx context.Context, userId string) (*OauthToken, error) {
+	return r.refresh(ctx, userId, true)
+}
+
+// Revoke 删除用户的令牌，用户取消授权后调用
+func (r *OauthTokenManager) Revoke(ctx context.Context, userId string) error {
+	return r.store.Delete(ctx, userId)
+}
+
+// UserInfoShare 使用用户的令牌查询会员信息
+func (r *OauthTokenManager) UserInfoShare(ctx context.Context, userId string) (*UserInfoShareRes, error) {
+	token, err := r.Token(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	return r.client.UserInfoShare(ctx, UserInfoShareReq{AuthToken: token.AccessToken})
+}
+
+// Do 以用户授权发送请求，令牌作为公共参数 auth_token 传递
+func (r *OauthTokenManager) Do(ctx context.Context, userId string, req IAliPayRequest, res ResponseSigner) error {
+	token, err := r.Token(ctx, userId)
+	if err != nil {
+		return err
+	}
+	return r.client.DoRequest(ctx, req, res, WithAuthToken(token.AccessToken))
+}
+
+func (r *OauthTokenManager) load(ctx context.Context, userId string) (*OauthToken, error) {
+	token, err := r.store.OauthToken(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if token == nil {
+		return nil, ErrOauthTokenNotExist
+	}
+	return token, nil
+}
+
+func (r *OauthTokenManager) expiring(token *OauthToken) bool {
+	return !r.Now().Add(r.RefreshBefore).Before(token.ExpiresAt)
+}
+
+// refresh 合并同一用户的并发刷新，force为false时等待期间其他调用已经刷新则直接返回新令牌。
+// 每个调用只等待自己的ctx，ctx结束时返回ctx的错误，刷新仍会继续
+func (r *OauthTokenManager) refresh(ctx context.Context, userId string, force bool) (*OauthToken, error) {
+	r.mu.Lock()
+	call, ok := r.refreshing[userId]
+	if !ok {
+		call = &oauthRefreshCall{done: make(chan struct{})}
+		r.refreshing[userId] = call
+		go r.runRefresh(call, userId, force)
+	}
+	r.mu.Unlock()
+	select {
+	case <-call.done:
+		return call.token, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// runRefresh 使用不受调用方取消影响的ctx刷新，完成后唤醒全部等待的调用
+func (r *OauthTokenManager) runRefresh(call *oauthRefreshCall, userId string, force bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.RefreshTimeout)
+	defer cancel()
+	call.token, call.err = r.doRefresh(ctx, userId, force)
+	r.mu.Lock()
+	delete(r.refreshing, userId)
+	r.mu.Unlock()
+	close(call.done)
+}
+
+func (r *OauthTokenManager) doRefresh(ctx context.Context, userId string, force bool) (*OauthToken, error) {
+	token, err := r.load(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if !force && !r.expiring(token) {
+		return token, nil
+	}
+	if !token.ReExpiresAt.IsZero() && !r.Now().Before(token.ReExpiresAt) {
+		return nil, ErrOauthTokenExpired
+	}
+	return r.requestToken(ctx, OauthTokenReq{GrantType: GrantRefreshToken, RefreshToken: token.RefreshToken})
+}
+
+// requestToken 换取或者刷新令牌并保存
+func (r *OauthTokenManager) requestToken(ctx context.Context, req OauthTokenReq) (*OauthToken, error) {
+	res, err := r.client.SystemOauthToken(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	content := res.OauthTokenResContent
+	if content == nil {
+		if res.CommonRes != nil {
+			return nil, fmt.Errorf("xpay: %s failed, sub_code: %s, sub_msg: %s", req.RequestApi(), res.CommonRes.SubCode, res.CommonRes.SubMsg)
+		}
+		return nil, fmt.Errorf("xpay: %s failed, empty response", req.RequestApi())
+	}
+	if content.Fail() {
+		return nil, fmt.Errorf("xpay: %s failed, sub_code: %s, sub_msg: %s", req.RequestApi(), content.SubCode, content.SubMsg)
+	}
+	now := r.Now()
+	authStart := now
+	if len(content.AuthStart) > 0 {
+		if authStart, err = time.ParseInLocation(time.DateTime, content.AuthStart, r.client.location); err != nil {
+			return nil, fmt.Errorf("xpay: invalid auth_start %s: %w", content.AuthStart, err)
+		}
+	}
+	expiresIn, err := strconv.ParseInt(content.ExpiresIn, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("xpay: invalid expires_in %s: %w", content.ExpiresIn, err)
+	}
+	token := &OauthToken{
+		UserId:       content.UserId,
+		AccessToken:  content.AccessToken,
+		RefreshToken: content.RefreshToken,
+		AuthStart:    authStart,
+		ExpiresAt:    authStart.Add(time.Duration(expiresIn) * time.Second),
+		UpdatedAt:    now,
+	}
+	if reExpiresIn, err := strconv.ParseInt(content.ReExpiresIn, 10, 64); err == nil && reExpiresIn > 0 {
+		token.ReExpiresAt = authStart.Add(time.Duration(reExpiresIn) * time.Second)
+	}
+	if err = r.store.Save(ctx, token); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
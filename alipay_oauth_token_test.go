@@ -0,0 +1,143 @@
+package alipay_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/try-labs/xpay"
+	"github.com/try-labs/xpay/alipaytest"
+)
+
+/**
+ * @author: Sam
+ * @since:
+ * @date: 2026/10/20 04:00
+ * @desc:
+ */
+
+const oauthUserId = "2088102177840001"
+
+func TestOauthTokenManager(t *testing.T) {
+	cst, _ := time.LoadLocation("Asia/Shanghai")
+	now := time.Date(2026, 10, 20, 10, 0, 0, 0, cst)
+	nowFunc := func() time.Time { return now }
+	g := alipaytest.NewGateway(alipaytest.WithLocation(cst), alipaytest.WithNow(nowFunc))
+	defer g.Close()
+	oauthClient, err := g.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager := NewOauthTokenManager(oauthClient, alipaytest.NewOauthTokenStore())
+	manager.Now = nowFunc
+	ctx := context.Background()
+
+	token, err := manager.Exchange(ctx, g.IssueOauthCode(oauthUserId))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.UserId != oauthUserId || !token.AuthStart.Equal(now) || !token.ExpiresAt.Equal(now.Add(alipaytest.OauthExpiresIn*time.Second)) ||
+		!token.ReExpiresAt.Equal(now.Add(alipaytest.OauthReExpiresIn*time.Second)) {
+		t.Fatalf("unexpected token: %+v", token)
+	}
+	res, err := manager.UserInfoShare(ctx, oauthUserId)
+	if err != nil || res.Fail() || res.UserId != oauthUserId {
+		t.Fatalf("%v %v", res, err)
+	}
+	if _, err = manager.Token(ctx, "2088000000000000"); !errors.Is(err, ErrOauthTokenNotExist) {
+		t.Errorf("expect ErrOauthTokenNotExist, got %v", err)
+	}
+
+	// 进入刷新窗口后并发调用只刷新一次
+	now = token.ExpiresAt.Add(-time.Minute)
+	var wg sync.WaitGroup
+	tokens := make([]*OauthToken, 10)
+	for i := range tokens {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			tokens[i], _ = manager.Token(ctx, oauthUserId)
+		}(i)
+	}
+	wg.Wait()
+	if calls := g.Calls("alipay.system.oauth.token"); calls != 2 {
+		t.Fatalf("oauth token calls = %d, want 2", calls)
+	}
+	for _, item := range tokens {
+		if item == nil || item.AccessToken != tokens[0].AccessToken || item.AccessToken == token.AccessToken {
+			t.Fatalf("unexpected token: %+v", item)
+		}
+	}
+	// 原令牌刷新后失效
+	shareRes, err := oauthClient.UserInfoShare(ctx, UserInfoShareReq{AuthToken: token.AccessToken})
+	if err != nil || shareRes.SubCode != alipaytest.ErrInvalidAuthToken.SubCode {
+		t.Fatalf("%v %v", shareRes, err)
+	}
+	// 令牌通过公共参数auth_token传递
+	genericRes := new(GenericRes)
+	if err = manager.Do(ctx, oauthUserId, &UserInfoShareReq{AuthToken: tokens[0].AccessToken}, genericRes); err != nil || genericRes.Fail() {
+		t.Fatalf("%v %v", genericRes, err)
+	}
+	requests := g.Requests()
+	if got := requests[len(requests)-1].AuthToken; got != tokens[0].AccessToken {
+		t.Errorf("auth_token = %s, want %s", got, tokens[0].AccessToken)
+	}
+
+	// 刷新令牌失效后需要重新授权
+	now = tokens[0].ReExpiresAt
+	if _, err = manager.Token(ctx, oauthUserId); !errors.Is(err, ErrOauthTokenExpired) {
+		t.Errorf("expect ErrOauthTokenExpired, got %v", err)
+	}
+}
+
+// TestOauthTokenManager_RefreshCanceled 发起刷新的调用取消后，其他等待的调用仍然得到新令牌
+func TestOauthTokenManager_RefreshCanceled(t *testing.T) {
+	g := alipaytest.NewGateway()
+	defer g.Close()
+	oauthClient, err := g.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager := NewOauthTokenManager(oauthClient, alipaytest.NewOauthTokenStore())
+	token, err := manager.Exchange(context.Background(), g.IssueOauthCode(oauthUserId))
+	if err != nil {
+		t.Fatal(err)
+	}
+	started, release := make(chan struct{}), make(chan struct{})
+	var once sync.Once
+	g.Use(func(req *alipaytest.Request) error {
+		if req.Method == "alipay.system.oauth.token" {
+			once.Do(func() { close(started) })
+			<-release
+		}
+		return nil
+	})
+
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := manager.Refresh(leaderCtx, oauthUserId)
+		leaderErr <- err
+	}()
+	<-started
+	waiter := make(chan *OauthToken, 1)
+	go func() {
+		refreshed, err := manager.Refresh(context.Background(), oauthUserId)
+		if err != nil {
+			t.Error(err)
+		}
+		waiter <- refreshed
+	}()
+	// 等待第二个调用加入刷新后再取消第一个调用
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+	if err = <-leaderErr; err != context.Canceled {
+		t.Errorf("expect context.Canceled, got %v", err)
+	}
+	close(release)
+	if refreshed := <-waiter; refreshed == nil || refreshed.AccessToken == token.AccessToken {
+		t.Errorf("unexpected token: %+v", refreshed)
+	}
+}
@@ -1,9 +1,6 @@
 package alipay
 
-import (
-	"encoding/json"
-	"fmt"
-)
+import "encoding/json"
 
 /**
  * @author: Sam
@@ -14,27 +11,18 @@ import (
 var _ IAliPayRequest = &OpenAuthTokenAppReq{}
 
 type OpenAuthTokenAppReq struct {
-	GrantType    string `json:"grant_type"`              // 必选	20 授权方式。支持： 1.authorization_code，表示换取使用用户授权码code换取授权令牌access_token。 2.refresh_token，表示使用refresh_token刷新获取新授权令牌。
-	Code         string `json:"code,omitempty"`          // 可选 40 授权码，用户对应用授权后得到。本参数在 grant_type 为 authorization_code 时必填；为 refresh_token 时不填。 4b203fe6c11548bcabd8da5bb087a83b
-	RefreshToken string `json:"refresh_token,omitempty"` //	可选	40 刷新令牌，上次换取访问令牌时得到。本参数在 grant_type 为 authorization_code 时不填；为 refresh_token 时必填，且该值来源于此接口的返回值 app_refresh_token（即至少需要通过 grant_type=authorization_code 调用此接口一次才能获取）。	201208134b203fe6c11548bcabd8da5bb087a83b
+	GrantType    string `json:"grant_type" validate:"required,enum=authorization_code|refresh_token"`          // 必选	20 授权方式。支持： 1.authorization_code，表示换取使用用户授权码code换取授权令牌access_token。 2.refresh_token，表示使用refresh_token刷新获取新授权令牌。
+	Code         string `json:"code,omitempty" validate:"required_if=GrantType authorization_code,max=40"`     // 可选 40 授权码，用户对应用授权后得到。本参数在 grant_type 为 authorization_code 时必填；为 refresh_token 时不填。 4b203fe6c11548bcabd8da5bb087a83b
+	RefreshToken string `json:"refresh_token,omitempty" validate:"required_if=GrantType refresh_token,max=40"` //	可选	40 刷新令牌，上次换取访问令牌时得到。本参数在 grant_type 为 authorization_code 时不填；为 refresh_token 时必填，且该值来源于此接口的返回值 app_refresh_token（即至少需要通过 grant_type=authorization_code 调用此接口一次才能获取）。	201208134b203fe6c11548bcabd8da5bb087a83b
 	baseAliPayRequest
 }
 
 func (r *OpenAuthTokenAppReq) DoValidate() error {
-	if r.GrantType != GrantAuthorizationCode && r.GrantType != GrantRefreshToken {
-		return fmt.Errorf("参数grant_type必传")
-	}
-	if r.GrantType == GrantAuthorizationCode && r.Code == "" {
-		return fmt.Errorf("参数code在grant_type 为 authorization_code 时必填；为 refresh_token 时不填。")
-	}
-	if r.GrantType == GrantRefreshToken && r.RefreshToken == "" {
-		return fmt.Errorf("参数code在grant_type 为 refresh_token  时必填；为 authorization_code 时不填。")
-	}
-	return nil
+	return ValidateStruct(r)
 }
 
 func (r *OpenAuthTokenAppReq) RequestApi() string {
-	return "alipay.system.oauth.token"
+	return "alipay.open.auth.token.app"
 }
 
 type OpenAuthTokenAppRes struct {
@@ -57,3 +45,42 @@ type OpenAuthTokenAppResContent struct {
 	ExpiresIn       string `json:"expires_in"`        // 必选	16 该字段已作废，应用令牌长期有效，接入方不需要消费该字段 123456
 	ReExpiresIn     string `json:"re_expires_in"`     // 必选	16 刷新令牌的有效时间（从接口调用时间作为起始时间），单位到秒 123456
 }
+
+///////////////////////////////////////////////
+
+var _ IAliPayRequest = &OpenAuthTokenAppQueryReq{}
+
+type OpenAuthTokenAppQueryReq struct {
+	AppAuthToken string `json:"app_auth_token" validate:"required,max=128"` // 必选	128 应用授权令牌 201509BBdcba1e3347de4e75ba3fed2c9abebE36
+	baseAliPayRequest
+}
+
+func (r *OpenAuthTokenAppQueryReq) DoValidate() error {
+	return ValidateStruct(r)
+}
+
+func (r *OpenAuthTokenAppQueryReq) RequestApi() string {
+	return "alipay.open.auth.token.app.query"
+}
+
+type OpenAuthTokenAppQueryRes struct {
+	OpenAuthTokenAppQueryResContent `json:"alipay_open_auth_token_app_query_response"`
+	SignCertSn
+}
+
+func (r *OpenAuthTokenAppQueryRes) String() string {
+	buff, _ := json.Marshal(r)
+	return string(buff)
+}
+
+type OpenAuthTokenAppQueryResContent struct {
+	CommonRes
+	UserId      string   `json:"user_id"`                  // 必选	16 授权商户的user_id 2088102150169800
+	AuthAppId   string   `json:"auth_app_id"`              // 必选	20 授权商户的appid 2013111800001989
+	ExpiresIn   int64    `json:"expires_in"`               // 必选	16 应用授权令牌失效时间，单位到秒 31536000
+	AuthMethods []string `json:"auth_methods"`             // 必选	 当前app_auth_token的授权接口列表 ["alipay.open.auth.token.app.query"]
+	AuthStart   string   `json:"auth_start"`               // 必选	 授权生效时间 2015-11-03 01:59:57
+	AuthEnd     string   `json:"auth_end"`                 // 必选	 授权失效时间 2016-11-03 01:59:57
+	Status      string   `json:"status"`                   // 必选	10 valid：有效状态；invalid：无效状态 valid
+	IsByAppAuth bool     `json:"is_by_app_auth,omitempty"` // 可选	 是否是通过应用授权的方式授权，如果是通过应用授权方式授权的返回true，否则返回false true
+}
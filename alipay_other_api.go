@@ -1,9 +1,6 @@
 package alipay
 
-import (
-	"encoding/json"
-	"fmt"
-)
+import "encoding/json"
 
 /**
  * @author: Sam
@@ -13,9 +10,9 @@ import (
  */
 
 type OauthTokenReq struct {
-	GrantType    string `json:"grant_type"`              // 必选	20 授权方式。支持： 1.authorization_code，表示换取使用用户授权码code换取授权令牌access_token。 2.refresh_token，表示使用refresh_token刷新获取新授权令牌。
-	Code         string `json:"code,omitempty"`          // 可选 40 授权码，用户对应用授权后得到。本参数在 grant_type 为 authorization_code 时必填；为 refresh_token 时不填。 4b203fe6c11548bcabd8da5bb087a83b
-	RefreshToken string `json:"refresh_token,omitempty"` //	可选	40 刷新令牌，上次换取访问令牌时得到。本参数在 grant_type 为 authorization_code 时不填；为 refresh_token 时必填，且该值来源于此接口的返回值 app_refresh_token（即至少需要通过 grant_type=authorization_code 调用此接口一次才能获取）。	201208134b203fe6c11548bcabd8da5bb087a83b
+	GrantType    string `json:"grant_type" validate:"required,enum=authorization_code|refresh_token"`          // 必选	20 授权方式。支持： 1.authorization_code，表示换取使用用户授权码code换取授权令牌access_token。 2.refresh_token，表示使用refresh_token刷新获取新授权令牌。
+	Code         string `json:"code,omitempty" validate:"required_if=GrantType authorization_code,max=40"`     // 可选 40 授权码，用户对应用授权后得到。本参数在 grant_type 为 authorization_code 时必填；为 refresh_token 时不填。 4b203fe6c11548bcabd8da5bb087a83b
+	RefreshToken string `json:"refresh_token,omitempty" validate:"required_if=GrantType refresh_token,max=40"` //	可选	40 刷新令牌，上次换取访问令牌时得到。本参数在 grant_type 为 authorization_code 时不填；为 refresh_token 时必填，且该值来源于此接口的返回值 app_refresh_token（即至少需要通过 grant_type=authorization_code 调用此接口一次才能获取）。	201208134b203fe6c11548bcabd8da5bb087a83b
 	baseAliPayRequest
 }
 
@@ -26,16 +23,7 @@ func (r *OauthTokenReq) RequestApi() string {
 }
 
 func (r *OauthTokenReq) DoValidate() error {
-	if r.GrantType != GrantAuthorizationCode && r.GrantType != GrantRefreshToken {
-		return fmt.Errorf("参数grant_type必传")
-	}
-	if r.GrantType == GrantAuthorizationCode && r.Code == "" {
-		return fmt.Errorf("参数code在grant_type 为 authorization_code 时必填；为 refresh_token 时不填。")
-	}
-	if r.GrantType == GrantRefreshToken && r.RefreshToken == "" {
-		return fmt.Errorf("参数code在grant_type 为 refresh_token  时必填；为 authorization_code 时不填。")
-	}
-	return nil
+	return ValidateStruct(r)
 }
 
 /*
@@ -80,16 +68,12 @@ type OauthTokenResContent struct {
 var _ IAliPayRequest = &UserInfoShareReq{}
 
 type UserInfoShareReq struct {
-	AuthToken string `json:"auth_token"` // 必选	40 用户授权令牌，同 access_token（用户访问令牌）。针对用户授权接口，获取用户相关数据时，用于标识用户授权关系。需使用 auth_code（用户授权码）换取此令牌，详情见 用户授权
+	AuthToken string `json:"auth_token" validate:"required,max=40"` // 必选	40 用户授权令牌，同 access_token（用户访问令牌）。针对用户授权接口，获取用户相关数据时，用于标识用户授权关系。需使用 auth_code（用户授权码）换取此令牌，详情见 用户授权
 	baseAliPayRequest
 }
 
 func (r *UserInfoShareReq) DoValidate() error {
-	length := len(r.AuthToken)
-	if length != 40 {
-		return fmt.Errorf("当前用户授权令牌auth_token参数长度为%d，不符合长度40要求", length)
-	}
-	return nil
+	return ValidateStruct(r)
 }
 
 func (r *UserInfoShareReq) RequestApi() string {
@@ -120,26 +104,23 @@ func (r *UserInfoShareRes) String() string {
 var _ IAliPayRequest = &UserInfoAuthReq{}
 
 type UserInfoAuthReq struct {
-	Scopes []string `json:"scopes"` //	必选	1024	接口权限值，枚举如下：
+	Scopes []string `json:"scopes" validate:"required"` //	必选	1024	接口权限值，枚举如下：
 	/*
 	* auth_base：以auth_base为scope发起的网页授权，用于获取进入页面的用户的 userId，并且是静默授权并自动跳转到回调页的。用户感知的就是直接进入了回调页（通常是业务页面）。
 	* auth_user：以auth_user为scope发起的网页授权，是用来获取用户的基本信息的（比如头像、昵称等）。但这种授权需要用户手动同意，用户同意后，就可在授权后获取到该用户的基本信息。
 	 */
-	State string `json:"state"` // 必选	100	商户自定义参数，只允许base64字符（长度小于等于100）。 说明：
+	State string `json:"state" validate:"required,max=100"` // 必选	100	商户自定义参数，只允许base64字符（长度小于等于100）。 说明：
 	/*
 	* 传入时将在用户授权后，重定向到redirect_uri 时会原样回传给商户。 为防止CSRF攻击。
 	* 建议开发者请求授权时传入state参数，该参数要做到既不可预测，又可以证明客户端和当前第三方网站的登录认证状态存在关联。	init
 	 */
 	// 自己添加
-	ReturnUrl string `json:"return_url,omitempty" url:"return_url,omitempty"` // 可选	256 HTTP/HTTPS开头字符串
+	ReturnUrl string `json:"return_url,omitempty" url:"return_url,omitempty" validate:"max=256"` // 可选	256 HTTP/HTTPS开头字符串
 	baseAliPayRequest
 }
 
 func (r *UserInfoAuthReq) DoValidate() error {
-	if len(r.Scopes) == 0 {
-		return fmt.Errorf("接口权限值 scopes 参数必填")
-	}
-	return nil
+	return ValidateStruct(r)
 }
 
 func (r *UserInfoAuthReq) RequestApi() string {
@@ -0,0 +1,321 @@
+package alipay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+/**
+ * @author: Sam
+ * @since: 1.0.0
+ * @date: 2026/10/19 23:10
+ * @desc: 批量付款
+ *
+ * 每个收款方的 out_biz_no 在调用 alipay.fund.trans.uni.transfer 之前写入 PayoutStore，支付宝保证同一 out_biz_no 只转账一次。
+ * 请求超时或者返回系统繁忙时转账结果未知，只能通过 alipay.fund.trans.common.query 查询确认，不能更换 out_biz_no 重新转账。
+ * 处理中(DEALING)的转账同样通过查询跟进，转账成功后可能因收款账户异常退票(REFUND)。
+ */
+
+var (
+	ErrPayoutDuplicated = errors.New("xpay: biz_no duplicated in payout batch")
+	ErrPayoutConflict   = errors.New("xpay: biz_no already used by a payout with different payee")
+)
+
+// payoutFailSubCodes 转账接口明确未受理的错误码，其余错误(例如 SYSTEM_ERROR)均视为结果未知
+var payoutFailSubCodes = map[string]bool{
+	"PAYEE_NOT_EXIST":             true, // 收款账号不存在
+	"PAYEE_USER_INFO_ERROR":       true, // 收款方姓名或者证件信息不一致
+	"PAYEE_ACC_OCUPIED":           true, // 收款登录号对应多个账户
+	"PAYEE_ACCOUNT_STATUS_ERROR":  true, // 收款账户状态异常
+	"PAYEE_USERINFO_STATUS_ERROR": true, // 收款账户状态异常
+	"PAYER_BALANCE_NOT_ENOUGH":    true, // 付款方余额不足
+	"BALANCE_IS_NOT_ENOUGH":       true, // 付款方余额不足
+	"PAYER_STATUS_ERROR":          true, // 付款账户状态异常
+	"PAYER_PAYEE_CANNOT_SAME":     true, // 收付款方不能相同
+	"EXCEED_LIMIT_SM_AMOUNT":      true, // 单笔金额超限
+	"EXCEED_LIMIT_SM_MIN_AMOUNT":  true, // 单笔金额低于最低限额
+	"EXCEED_LIMIT_DM_AMOUNT":      true, // 日累计金额超限
+	"EXCEED_LIMIT_MM_AMOUNT":      true, // 月累计金额超限
+	"PERMIT_CHECK_PERM_LIMITED":   true, // 付款方权限受限
+	"PRODUCT_NOT_SIGN":            true, // 未签约转账产品
+	"INVALID_PARAMETER":           true, // 参数有误
+}
+
+// PayoutStatus 付款记录的状态
+type PayoutStatus string
+
+const (
+	PayoutPending PayoutStatus = "PENDING" // 已生成商户转账单号，转账结果未确认
+	PayoutDealing PayoutStatus = "DEALING" // 支付宝处理中
+	PayoutSuccess PayoutStatus = "SUCCESS" // 转账成功
+	PayoutFail    PayoutStatus = "FAIL"    // 转账失败，未扣款
+	PayoutRefund  PayoutStatus = "REFUND"  // 转账成功后退票，资金已退回
+)
+
+// Final 是否为最终状态
+func (r PayoutStatus) Final() bool {
+	return r == PayoutSuccess || r == PayoutFail || r == PayoutRefund
+}
+
+// Payee 一个收款方
+type Payee struct {
+	// BizNo 商户付款单号，例如结算单号，同一批次内唯一，用于生成商户转账单号
+	BizNo        string `json:"biz_no"`
+	Identity     string `json:"identity"`      // 收款方标识
+	IdentityType string `json:"identity_type"` // ALIPAY_USER_ID、ALIPAY_LOGON_ID
+	Name         string `json:"name,omitempty"`
+	Amount       Money  `json:"amount"`
+	OrderTitle   string `json:"order_title"`
+	Remark       string `json:"remark,omitempty"`
+}
+
+// PayoutRecord 一笔付款
+type PayoutRecord struct {
+	BatchNo        string       `json:"batch_no"`
+	Payee          Payee        `json:"payee"`
+	OutBizNo       string       `json:"out_biz_no"` // 商户转账单号
+	OrderId        string       `json:"order_id,omitempty"`
+	PayFundOrderId string       `json:"pay_fund_order_id,omitempty"`
+	Status         PayoutStatus `json:"status"`
+	ErrorCode      string       `json:"error_code,omitempty"`  // 转账失败或者退票时的错误码
+	FailReason     string       `json:"fail_reason,omitempty"` // 转账失败或者退票的原因
+	PayDate        string       `json:"pay_date,omitempty"`
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+}
+
+// PayoutStore 付款记录的存储
+type PayoutStore interface {
+	// Payout 按批次号和商户付款单号查询付款记录，不存在时返回nil
+	Payout(ctx context.Context, batchNo, bizNo string) (*PayoutRecord, error)
+	// Save 新增或者按 BatchNo 和 Payee.BizNo 更新付款记录
+	Save(ctx context.Context, record *PayoutRecord) error
+}
+
+// PayoutLedger 批次的付款结果，Records 与收款方的顺序一致
+type PayoutLedger struct {
+	BatchNo       string               `json:"batch_no"`
+	Records       []*PayoutRecord      `json:"records"`
+	Counts        map[PayoutStatus]int `json:"counts"`
+	SuccessAmount Money                `json:"success_amount"` // 转账成功的金额，不含退票
+}
+
+// Done 是否全部付款都已是最终状态，否则需要再次调用 PayoutEngine.Run 跟进
+func (r *PayoutLedger) Done() bool {
+	for _, record := range r.Records {
+		if record == nil || !record.Status.Final() {
+			return false
+		}
+	}
+	return true
+}
+
+// PayoutEngine 批量付款
+type PayoutEngine struct {
+	client *Client
+	store  PayoutStore
+
+	Concurrency   int           // 同时处理的收款方数量，默认8
+	QueryTimes    int           // 结果未知或者处理中时的查询次数，默认5次，仍未确认时保持原状态
+	QueryInterval time.Duration // 查询间隔，默认2秒
+	ProductCode   string        // 默认 TRANS_ACCOUNT_NO_PWD
+	BizScene      string        // 默认 DIRECT_TRANSFER
+	// NewOutBizNo 生成商户转账单号，默认为 批次号_商户付款单号
+	NewOutBizNo func(batchNo, bizNo string) string
+}
+
+func NewPayoutEngine(client *Client, store PayoutStore) *PayoutEngine {
+	return &PayoutEngine{
+		client:        client,
+		store:         store,
+		Concurrency:   8,
+		QueryTimes:    5,
+		QueryInterval: 2 * time.Second,
+		ProductCode:   TransAccountNoPwd,
+		BizScene:      "DIRECT_TRANSFER",
+		NewOutBizNo: func(batchNo, bizNo string) string {
+			return fmt.Sprintf("%s_%s", batchNo, bizNo)
+		},
+	}
+}
+
+// Run 执行批次付款并返回付款结果。同一批次可以重复执行，已有记录的收款方沿用原商户转账单号，
+// 先查询确认，查询到转账不存在时才使用原商户转账单号重新提交，已是最终状态的记录不再处理。
+// 已有记录的收款方、金额等与本次不一致时返回 ErrPayoutConflict，不会使用原商户转账单号提交新的内容。
+// 存储失败或者ctx结束时返回error，已处理的结果同样包含在返回的 PayoutLedger 中
+func (r *PayoutEngine) Run(ctx context.Context, batchNo string, payees []Payee) (*PayoutLedger, error) {
+	bizNos := make(map[string]bool, len(payees))
+	for _, payee := range payees {
+		if bizNos[payee.BizNo] {
+			return nil, ErrPayoutDuplicated
+		}
+		bizNos[payee.BizNo] = true
+		req := r.transferReq(&PayoutRecord{OutBizNo: r.NewOutBizNo(batchNo, payee.BizNo), Payee: payee})
+		if err := req.DoValidate(); err != nil {
+			return nil, err
+		}
+	}
+	concurrency := r.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	ledger := &PayoutLedger{BatchNo: batchNo, Records: make([]*PayoutRecord, len(payees)), Counts: make(map[PayoutStatus]int)}
+	errs := make([]error, len(payees))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := range payees {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			ledger.Records[i], errs[i] = r.payout(ctx, batchNo, payees[i])
+		}(i)
+	}
+	wg.Wait()
+	var err error
+	for i, record := range ledger.Records {
+		if err == nil && errs[i] != nil {
+			err = errs[i]
+		}
+		if record == nil {
+			continue
+		}
+		ledger.Counts[record.Status]++
+		if record.Status == PayoutSuccess {
+			ledger.SuccessAmount = ledger.SuccessAmount.Add(record.Payee.Amount)
+		}
+	}
+	return ledger, err
+}
+
+func (r *PayoutEngine) transferReq(record *PayoutRecord) FundTransUniTransferReq {
+	return FundTransUniTransferReq{
+		OutBizNo:    record.OutBizNo,
+		TransAmount: record.Payee.Amount,
+		ProductCode: r.ProductCode,
+		BizScene:    r.BizScene,
+		OrderTitle:  record.Payee.OrderTitle,
+		PayeeInfo:   Participant{Identity: record.Payee.Identity, IdentityType: record.Payee.IdentityType, Name: record.Payee.Name},
+		Remark:      record.Payee.Remark,
+	}
+}
+
+// payout 处理一个收款方，新的记录写入存储后提交转账，已有的记录先查询确认
+func (r *PayoutEngine) payout(ctx context.Context, batchNo string, payee Payee) (*PayoutRecord, error) {
+	record, err := r.store.Payout(ctx, batchNo, payee.BizNo)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		now := time.Now()
+		record = &PayoutRecord{
+			BatchNo:   batchNo,
+			Payee:     payee,
+			OutBizNo:  r.NewOutBizNo(batchNo, payee.BizNo),
+			Status:    PayoutPending,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		if err = r.store.Save(ctx, record); err != nil {
+			return nil, err
+		}
+		return record, r.submit(ctx, record)
+	}
+	if record.Payee != payee {
+		return record, ErrPayoutConflict
+	}
+	if record.Status.Final() {
+		return record, nil
+	}
+	exist, err := r.query(ctx, record)
+	if err != nil || record.Status.Final() {
+		return record, err
+	}
+	if !exist && record.Status == PayoutPending {
+		return record, r.submit(ctx, record)
+	}
+	return record, r.follow(ctx, record)
+}
+
+func (r *PayoutEngine) save(ctx context.Context, record *PayoutRecord) error {
+	record.UpdatedAt = time.Now()
+	return r.store.Save(ctx, record)
+}
+
+// submit 提交转账，支付宝明确返回未受理的错误码时记录失败原因，结果未知、处理中或者其他错误时通过查询跟进
+func (r *PayoutEngine) submit(ctx context.Context, record *PayoutRecord) error {
+	res, err := r.client.FundTransUniTransfer(ctx, r.transferReq(record))
+	switch {
+	case err != nil || resultUnknown(&res.CommonRes):
+	case res.Fail() && payoutFailSubCodes[res.SubCode]:
+		record.Status, record.ErrorCode, record.FailReason = PayoutFail, res.SubCode, res.SubMsg
+		return r.save(ctx, record)
+	case res.Fail():
+		// SYSTEM_ERROR 等无法确认是否已受理，保持待确认，使用原商户转账单号查询
+	case PayoutStatus(res.Status) == PayoutSuccess:
+		record.OrderId, record.PayFundOrderId, record.PayDate = res.OrderId, res.PayFundOrderId, res.TransDate
+		record.Status = PayoutSuccess
+		return r.save(ctx, record)
+	default:
+		// 处理中或者退票时通过查询获取最新状态及失败原因
+		record.OrderId, record.PayFundOrderId, record.PayDate = res.OrderId, res.PayFundOrderId, res.TransDate
+		record.Status = PayoutDealing
+		if err = r.save(ctx, record); err != nil {
+			return err
+		}
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return r.follow(ctx, record)
+}
+
+// follow 按查询间隔查询转账结果，达到查询次数仍未确认时保持原状态，等待下一次 Run
+func (r *PayoutEngine) follow(ctx context.Context, record *PayoutRecord) error {
+	for i := 0; i < r.QueryTimes && !record.Status.Final(); i++ {
+		timer := time.NewTimer(r.QueryInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		if _, err := r.query(ctx, record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// query 查询转账结果并更新记录，返回转账单是否存在。请求失败或者系统繁忙时视为存在，不会重新提交
+func (r *PayoutEngine) query(ctx context.Context, record *PayoutRecord) (bool, error) {
+	res, err := r.client.FundTransCommonQuery(ctx, FundTransCommonQueryReq{ProductCode: r.ProductCode, BizScene: r.BizScene, OutBizNo: record.OutBizNo})
+	if err != nil {
+		return true, ctx.Err()
+	}
+	if res.Fail() {
+		return res.SubCode != "ORDER_NOT_EXIST", nil
+	}
+	status := PayoutStatus(res.Status)
+	switch status {
+	case PayoutSuccess, PayoutFail, PayoutRefund:
+	default:
+		// INIT、WAIT_PAY、DEALING 等中间状态
+		status = PayoutDealing
+	}
+	if res.TransAmount.IsPositive() && res.TransAmount != record.Payee.Amount {
+		return true, fmt.Errorf("xpay: payout %s amount %s does not match record %s", record.OutBizNo, res.TransAmount, record.Payee.Amount)
+	}
+	record.Status, record.OrderId, record.PayFundOrderId, record.PayDate = status, res.OrderId, res.PayFundOrderId, res.PayDate
+	record.ErrorCode, record.FailReason = res.ErrorCode, res.FailReason
+	if len(record.FailReason) == 0 {
+		record.FailReason = res.SubOrderFailReason
+	}
+	return true, r.save(ctx, record)
+}
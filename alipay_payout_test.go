@@ -0,0 +1,199 @@
+package alipay_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/try-labs/xpay"
+	"github.com/try-labs/xpay/alipaytest"
+)
+
+/**
+ * @author: Sam
+ * @since:
+ * @date: 2026/10/19 23:10
+ * @desc:
+ */
+
+func newPayoutEngine(t *testing.T) (*PayoutEngine, *dropTransport) {
+	t.Helper()
+	transport := &dropTransport{drops: make(map[string]int)}
+	payoutClient, err := gateway.Client(SetClientOptHttpClient(&http.Client{Transport: transport}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine := NewPayoutEngine(payoutClient, alipaytest.NewPayoutStore())
+	engine.QueryInterval = 5 * time.Millisecond
+	return engine, transport
+}
+
+func payee(bizNo, amount string) Payee {
+	return Payee{BizNo: bizNo, Identity: "2088" + bizNo, IdentityType: "ALIPAY_USER_ID", Amount: MustParseMoney(amount), OrderTitle: "佣金结算"}
+}
+
+func TestPayoutEngine_Run(t *testing.T) {
+	ctx := context.Background()
+	engine, transport := newPayoutEngine(t)
+	payees := make([]Payee, 0, 20)
+	for i := 1; i <= 20; i++ {
+		payees = append(payees, payee(fmt.Sprintf("S%03d", i), "1.00"))
+	}
+	gateway.SetTransferStatus("PB001_S002", "FAIL", alipaytest.ErrPayeeNotExist.SubCode, alipaytest.ErrPayeeNotExist.SubMsg)
+	gateway.SetTransferStatus("PB001_S003", "REFUND", "PAYEE_ACCOUNT_STATUS_ERROR", "收款账户状态异常")
+	gateway.SetTransferStatus("PB001_S004", "DEALING", "", "")
+	queries := gateway.Calls("alipay.fund.trans.common.query")
+	go func() {
+		for gateway.Calls("alipay.fund.trans.common.query") == queries {
+			time.Sleep(time.Millisecond)
+		}
+		gateway.SetTransferStatus("PB001_S004", "SUCCESS", "", "")
+	}()
+	// 响应丢失后通过查询确认，不会重复转账
+	transport.drop("alipay.fund.trans.uni.transfer", 3)
+	balance := gateway.Balance()
+	ledger, err := engine.Run(ctx, "PB001", payees)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ledger.Done() || ledger.Counts[PayoutSuccess] != 18 || ledger.Counts[PayoutFail] != 1 || ledger.Counts[PayoutRefund] != 1 {
+		t.Fatalf("unexpected counts: %v", ledger.Counts)
+	}
+	if ledger.SuccessAmount != MustParseMoney("18.00") || gateway.Balance() != balance.Sub(ledger.SuccessAmount) {
+		t.Errorf("unexpected amount: %s %s", ledger.SuccessAmount, balance.Sub(gateway.Balance()))
+	}
+	if record := ledger.Records[1]; record.Status != PayoutFail || record.ErrorCode != "PAYEE_NOT_EXIST" || record.FailReason != "收款账号不存在" {
+		t.Errorf("unexpected record: %+v", record)
+	}
+	if record := ledger.Records[2]; record.Status != PayoutRefund || record.FailReason != "收款账户状态异常" || len(record.OrderId) == 0 {
+		t.Errorf("unexpected record: %+v", record)
+	}
+	if record := ledger.Records[3]; record.Status != PayoutSuccess || record.OutBizNo != "PB001_S004" {
+		t.Errorf("unexpected record: %+v", record)
+	}
+
+	// 重复执行时不再转账
+	transfers := gateway.Calls("alipay.fund.trans.uni.transfer")
+	again, err := engine.Run(ctx, "PB001", payees)
+	if err != nil || !again.Done() || again.Counts[PayoutSuccess] != 18 || gateway.Calls("alipay.fund.trans.uni.transfer") != transfers {
+		t.Errorf("unexpected ledger: %v %v", again.Counts, err)
+	}
+}
+
+func TestPayoutEngine_Unresolved(t *testing.T) {
+	ctx := context.Background()
+	engine, transport := newPayoutEngine(t)
+	engine.QueryTimes = 2
+	payees := []Payee{payee("S001", "5.00")}
+	gateway.SetTransferStatus("PB002_S001", "DEALING", "", "")
+	transport.drop("alipay.fund.trans.uni.transfer", 1)
+	ledger, err := engine.Run(ctx, "PB002", payees)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ledger.Done() || ledger.Records[0].Status != PayoutDealing {
+		t.Fatalf("unexpected record: %+v", ledger.Records[0])
+	}
+	gateway.SetTransferStatus("PB002_S001", "SUCCESS", "", "")
+	transfers := gateway.Calls("alipay.fund.trans.uni.transfer")
+	if ledger, err = engine.Run(ctx, "PB002", payees); err != nil || ledger.Records[0].Status != PayoutSuccess {
+		t.Errorf("unexpected record: %+v %v", ledger.Records[0], err)
+	}
+	if gateway.Calls("alipay.fund.trans.uni.transfer") != transfers {
+		t.Error("dealing payout should not be submitted again")
+	}
+}
+
+func TestPayoutEngine_NotReached(t *testing.T) {
+	ctx := context.Background()
+	engine, _ := newPayoutEngine(t)
+	engine.QueryTimes = 1
+	payees := []Payee{payee("S001", "3.00")}
+	// 系统繁忙时转账未受理，查询到转账不存在，保持待确认
+	gateway.InjectError("alipay.fund.trans.uni.transfer", alipaytest.ErrSystemError, 1)
+	ledger, err := engine.Run(ctx, "PB003", payees)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record := ledger.Records[0]; record.Status != PayoutPending || len(record.OrderId) > 0 {
+		t.Fatalf("unexpected record: %+v", record)
+	}
+	if _, ok := gateway.Transfer("PB003_S001"); ok {
+		t.Fatal("transfer should not exist")
+	}
+	// 再次执行时使用原商户转账单号提交
+	ledger, err = engine.Run(ctx, "PB003", payees)
+	if err != nil || ledger.Records[0].Status != PayoutSuccess {
+		t.Errorf("unexpected record: %+v %v", ledger.Records[0], err)
+	}
+	if transfer, ok := gateway.Transfer("PB003_S001"); !ok || transfer.TransAmount != MustParseMoney("3.00") {
+		t.Errorf("unexpected transfer: %+v", transfer)
+	}
+
+	// 同一商户付款单号的金额或者收款方与原记录不一致
+	transfers := gateway.Calls("alipay.fund.trans.uni.transfer")
+	changed := payee("S001", "3.50")
+	if ledger, err = engine.Run(ctx, "PB003", []Payee{changed}); err != ErrPayoutConflict || ledger.Records[0].Payee.Amount != MustParseMoney("3.00") {
+		t.Errorf("unexpected result: %+v %v", ledger.Records[0], err)
+	}
+	changed = payee("S001", "3.00")
+	changed.Identity = "2088S002"
+	if _, err = engine.Run(ctx, "PB003", []Payee{changed}); err != ErrPayoutConflict {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if gateway.Calls("alipay.fund.trans.uni.transfer") != transfers {
+		t.Error("conflicting payout should not be submitted")
+	}
+
+	if _, err = engine.Run(ctx, "PB004", []Payee{payee("S001", "1.00"), payee("S001", "2.00")}); err != ErrPayoutDuplicated {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestPayoutEngine_SystemError(t *testing.T) {
+	ctx := context.Background()
+	g := alipaytest.NewGateway()
+	defer g.Close()
+	payoutClient, err := g.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := alipaytest.NewPayoutStore()
+	engine := NewPayoutEngine(payoutClient, store)
+	engine.QueryInterval = 5 * time.Millisecond
+	// 支付宝已受理转账，但返回 40004/SYSTEM_ERROR
+	if _, err = payoutClient.FundTransUniTransfer(ctx, FundTransUniTransferReq{
+		OutBizNo:    "PB005_S001",
+		TransAmount: MustParseMoney("4.00"),
+		ProductCode: TransAccountNoPwd,
+		BizScene:    "DIRECT_TRANSFER",
+		OrderTitle:  "佣金结算",
+		PayeeInfo:   Participant{Identity: "2088S001", IdentityType: "ALIPAY_USER_ID"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	balance := g.Balance()
+	g.InjectError("alipay.fund.trans.uni.transfer", alipaytest.ErrTransSystemError, 1)
+	var pending *PayoutRecord
+	g.Use(func(req *alipaytest.Request) error {
+		if req.Method == "alipay.fund.trans.common.query" && pending == nil {
+			pending, _ = store.Payout(ctx, "PB005", "S001")
+		}
+		return nil
+	})
+	ledger, err := engine.Run(ctx, "PB005", []Payee{payee("S001", "4.00")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending == nil || pending.Status != PayoutPending || len(pending.ErrorCode) > 0 {
+		t.Fatalf("record should stay pending before query: %+v", pending)
+	}
+	if record := ledger.Records[0]; record.Status != PayoutSuccess || len(record.OrderId) == 0 {
+		t.Errorf("unexpected record: %+v", record)
+	}
+	if g.Balance() != balance || g.Calls("alipay.fund.trans.uni.transfer") != 2 {
+		t.Errorf("transfer should not be submitted again: %s %d", balance.Sub(g.Balance()), g.Calls("alipay.fund.trans.uni.transfer"))
+	}
+}
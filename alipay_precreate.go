@@ -0,0 +1,288 @@
+package alipay
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"sync"
+	"time"
+
+	"rsc.io/qr"
+)
+
+/**
+ * @author: Sam
+ * @since: 1.0.0
+ * @date: 2026/10/19 22:10
+ * @desc: 当面付扫码支付流程
+ *
+ * alipay.trade.precreate 返回二维码码串，生成二维码图片后展示给用户扫码，
+ * 通过异步通知或者 alipay.trade.query 轮询确认支付结果，超过有效期仍未支付时调用 alipay.trade.close 关闭交易。
+ */
+
+// QrLevel 二维码纠错等级，等级越高可被遮挡的面积越大，相同码串生成的二维码越密
+type QrLevel int
+
+const (
+	QrLevelL QrLevel = QrLevel(qr.L) // 约7%
+	QrLevelM QrLevel = QrLevel(qr.M) // 约15%
+	QrLevelQ QrLevel = QrLevel(qr.Q) // 约25%
+	QrLevelH QrLevel = QrLevel(qr.H) // 约30%
+)
+
+// qrQuietZone 二维码四周的空白区域，单位为模块
+const qrQuietZone = 4
+
+type preCreateOption struct {
+	qrSize       int
+	qrLevel      QrLevel
+	pollInterval time.Duration
+	expire       time.Duration
+}
+
+type PreCreateOpt func(option *preCreateOption)
+
+// WithQrSize 二维码图片的边长，单位为像素，默认256
+func WithQrSize(size int) PreCreateOpt {
+	return func(option *preCreateOption) {
+		option.qrSize = size
+	}
+}
+
+// WithQrLevel 二维码纠错等级，默认 QrLevelM
+func WithQrLevel(level QrLevel) PreCreateOpt {
+	return func(option *preCreateOption) {
+		option.qrLevel = level
+	}
+}
+
+// WithQrPollInterval 交易查询的间隔，默认3秒
+func WithQrPollInterval(interval time.Duration) PreCreateOpt {
+	return func(option *preCreateOption) {
+		option.pollInterval = interval
+	}
+}
+
+// WithQrExpire 二维码的有效期，超过后关闭交易，默认2小时，与支付宝二维码的有效期一致
+func WithQrExpire(expire time.Duration) PreCreateOpt {
+	return func(option *preCreateOption) {
+		option.expire = expire
+	}
+}
+
+// PreCreateResult 扫码支付的结果
+type PreCreateResult struct {
+	TradeStatus TradeStatus    `json:"trade_status"` // TRADE_SUCCESS、TRADE_FINISHED 表示已支付，TRADE_CLOSED 表示已关闭
+	TradeNo     string         `json:"trade_no,omitempty"`
+	Expired     bool           `json:"expired"`          // 超过有效期后由 alipay.trade.close 关闭
+	Notify      *NotifyReq     `json:"notify,omitempty"` // 通过异步通知确认时不为空
+	Query       *TradeQueryRes `json:"query,omitempty"`  // 通过交易查询确认时不为空
+}
+
+// Paid 是否已支付
+func (r *PreCreateResult) Paid() bool {
+	return r.TradeStatus == TradeSuccess || r.TradeStatus == TradeFinished
+}
+
+// PreCreateSession 一笔扫码支付，由 NewPreCreateSession 创建
+type PreCreateSession struct {
+	client   *Client
+	option   preCreateOption
+	code     *qr.Code
+	notified chan *NotifyReq
+
+	mu     sync.Mutex
+	result *PreCreateResult
+
+	OutTradeNo string
+	QrCode     string    // 二维码码串
+	ExpireAt   time.Time // 二维码过期时间
+}
+
+// NewPreCreateSession 调用 alipay.trade.precreate 并生成二维码
+func (r *Client) NewPreCreateSession(ctx context.Context, req TradePreCreateReq, opts ...PreCreateOpt) (*PreCreateSession, error) {
+	option := preCreateOption{qrSize: 256, qrLevel: QrLevelM, pollInterval: 3 * time.Second, expire: 2 * time.Hour}
+	for _, opt := range opts {
+		opt(&option)
+	}
+	res, err := r.TradePreCreate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if res.Fail() {
+		return nil, fmt.Errorf("xpay: trade precreate failed, sub_code: %s, sub_msg: %s", res.SubCode, res.SubMsg)
+	}
+	code, err := qr.Encode(res.QrCode, qr.Level(option.qrLevel))
+	if err != nil {
+		return nil, err
+	}
+	return &PreCreateSession{
+		client:     r,
+		option:     option,
+		code:       code,
+		notified:   make(chan *NotifyReq, 1),
+		OutTradeNo: req.OutTradeNo,
+		QrCode:     res.QrCode,
+		ExpireAt:   time.Now().Add(option.expire),
+	}, nil
+}
+
+// Image 二维码图片，边长不足以容纳全部模块时按每个模块1像素输出
+func (r *PreCreateSession) Image() image.Image {
+	modules := r.code.Size + 2*qrQuietZone
+	scale := r.option.qrSize / modules
+	size := r.option.qrSize
+	if scale < 1 {
+		scale, size = 1, modules
+	}
+	offset := (size-modules*scale)/2 + qrQuietZone*scale
+	img := image.NewPaletted(image.Rect(0, 0, size, size), color.Palette{color.White, color.Black})
+	for y := 0; y < r.code.Size; y++ {
+		for x := 0; x < r.code.Size; x++ {
+			if !r.code.Black(x, y) {
+				continue
+			}
+			for dy := 0; dy < scale; dy++ {
+				for dx := 0; dx < scale; dx++ {
+					img.SetColorIndex(offset+x*scale+dx, offset+y*scale+dy, 1)
+				}
+			}
+		}
+	}
+	return img
+}
+
+// PNG 二维码PNG图片
+func (r *PreCreateSession) PNG() ([]byte, error) {
+	var buff bytes.Buffer
+	if err := png.Encode(&buff, r.Image()); err != nil {
+		return nil, err
+	}
+	return buff.Bytes(), nil
+}
+
+// SVG 二维码SVG图片，相邻的黑色模块合并为一段路径
+func (r *PreCreateSession) SVG() string {
+	modules := r.code.Size + 2*qrQuietZone
+	var buff bytes.Buffer
+	fmt.Fprintf(&buff, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, r.option.qrSize, r.option.qrSize, modules, modules)
+	fmt.Fprintf(&buff, `<rect width="%d" height="%d" fill="#fff"/><path fill="#000" d="`, modules, modules)
+	for y := 0; y < r.code.Size; y++ {
+		for x := 0; x < r.code.Size; {
+			if !r.code.Black(x, y) {
+				x++
+				continue
+			}
+			start := x
+			for x < r.code.Size && r.code.Black(x, y) {
+				x++
+			}
+			fmt.Fprintf(&buff, "M%d %dh%dv1h-%dz", start+qrQuietZone, y+qrQuietZone, x-start, x-start)
+		}
+	}
+	buff.WriteString(`"/></svg>`)
+	return buff.String()
+}
+
+// Notify 传入已验签的异步通知，商户订单号不一致或者交易状态不是最终状态时忽略并返回false
+func (r *PreCreateSession) Notify(notifyReq *NotifyReq) bool {
+	if notifyReq == nil || notifyReq.OutTradeNo != r.OutTradeNo {
+		return false
+	}
+	switch notifyReq.TradeStatus {
+	case TradeSuccess, TradeFinished, TradeClosed:
+	default:
+		return false
+	}
+	select {
+	case r.notified <- notifyReq:
+	default:
+	}
+	return true
+}
+
+// Wait 等待支付结果，收到异步通知或者查询到最终的交易状态时返回，超过有效期时关闭交易。
+// ctx结束或者请求失败(如验签失败)时返回error，交易保持原状态，可以再次调用 Wait
+func (r *PreCreateSession) Wait(ctx context.Context) (*PreCreateResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.result != nil {
+		return r.result, nil
+	}
+	timer := time.NewTimer(r.nextPoll())
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case notifyReq := <-r.notified:
+			r.result = &PreCreateResult{TradeStatus: notifyReq.TradeStatus, TradeNo: notifyReq.TradeNo, Notify: notifyReq}
+			return r.result, nil
+		case <-timer.C:
+		}
+		result, err := r.query(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if result == nil && !time.Now().Before(r.ExpireAt) {
+			result, err = r.close(ctx)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if result != nil {
+			r.result = result
+			return result, nil
+		}
+		timer.Reset(r.nextPoll())
+	}
+}
+
+// nextPoll 距离下一次查询的时间，不晚于二维码过期时间，过期后关闭未成功时仍按查询间隔重试
+func (r *PreCreateSession) nextPoll() time.Duration {
+	if remaining := time.Until(r.ExpireAt); remaining > 0 && remaining < r.option.pollInterval {
+		return remaining
+	}
+	return r.option.pollInterval
+}
+
+// query 查询交易状态，交易状态不是最终状态时返回nil
+func (r *PreCreateSession) query(ctx context.Context) (*PreCreateResult, error) {
+	res, err := r.client.TradeQuery(ctx, TradeQueryReq{OutTradeNo: r.OutTradeNo})
+	if err != nil {
+		return nil, sessionErr(ctx, err)
+	}
+	switch res.TradeStatus {
+	case TradeSuccess, TradeFinished, TradeClosed:
+		if res.Success() {
+			return &PreCreateResult{TradeStatus: res.TradeStatus, TradeNo: res.TradeNo, Query: res}, nil
+		}
+	}
+	return nil, nil
+}
+
+// close 关闭过期的交易，用户扫码前交易不存在时同样视为已关闭，关闭失败时重新查询，可能在关闭前完成了支付
+func (r *PreCreateSession) close(ctx context.Context) (*PreCreateResult, error) {
+	res, err := r.client.TradeClose(ctx, TradeCloseReq{OutTradeNo: r.OutTradeNo})
+	if err != nil {
+		return nil, sessionErr(ctx, err)
+	}
+	if res.Success() || res.SubCode == SubCodeTradeNotExist {
+		return &PreCreateResult{TradeStatus: TradeClosed, TradeNo: res.TradeNo, Expired: true}, nil
+	}
+	return r.query(ctx)
+}
+
+// sessionErr ctx结束时返回ctx的错误，否则返回请求的错误。请求前已超过截止时间时ctx可能尚未结束，同样返回ctx超时
+func sessionErr(ctx context.Context, err error) error {
+	if err == ErrRequestTimeout {
+		return context.DeadlineExceeded
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
@@ -0,0 +1,212 @@
+package alipay_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/try-labs/xpay"
+	"github.com/try-labs/xpay/alipaytest"
+)
+
+/**
+ * @author: Sam
+ * @since:
+ * @date: 2026/10/19 22:10
+ * @desc:
+ */
+
+func preCreateReq(outTradeNo string) TradePreCreateReq {
+	return TradePreCreateReq{OutTradeNo: outTradeNo, TotalAmount: MustParseMoney("28.88"), Subject: "测试商品"}
+}
+
+func TestPreCreateSession_Image(t *testing.T) {
+	session, err := client.NewPreCreateSession(context.Background(), preCreateReq("20261019221001"), WithQrSize(300), WithQrLevel(QrLevelH))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(session.QrCode, "https://qr.alipay.com/") {
+		t.Errorf("unexpected qr_code: %s", session.QrCode)
+	}
+	buff, err := session.PNG()
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(bytes.NewReader(buff))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bounds := img.Bounds(); bounds.Dx() != 300 || bounds.Dy() != 300 {
+		t.Errorf("unexpected bounds: %v", bounds)
+	}
+	// 左上角为空白区域，其后为定位图案
+	if r, _, _, _ := img.At(0, 0).RGBA(); r == 0 {
+		t.Error("quiet zone should be white")
+	}
+	if r, _, _, _ := img.At(150, 150).RGBA(); r != 0 && r != 0xffff {
+		t.Error("image should be black and white")
+	}
+
+	svg := session.SVG()
+	if !strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="300" height="300"`) || !strings.Contains(svg, `M4 4h7v1h-7z`) {
+		t.Errorf("unexpected svg: %s", svg)
+	}
+
+	low, err := client.NewPreCreateSession(context.Background(), preCreateReq("20261019221002"), WithQrSize(10), WithQrLevel(QrLevelL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 边长不足时每个模块1像素，纠错等级越低模块越少
+	if size := low.Image().Bounds().Dx(); size >= img.Bounds().Dx() || size < 21+8 {
+		t.Errorf("unexpected size: %d", size)
+	}
+}
+
+func TestPreCreateSession_WaitNotify(t *testing.T) {
+	var session *PreCreateSession
+	ready := make(chan struct{})
+	merchant := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-ready
+		notifyReq, err := client.AsyncNotify(r)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		session.Notify(notifyReq)
+		io.WriteString(w, "success")
+	}))
+	defer merchant.Close()
+	req := preCreateReq("20261019221003")
+	req.NotifyUrl = merchant.URL
+	session, err := client.NewPreCreateSession(context.Background(), req, WithQrPollInterval(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	close(ready)
+	if err = gateway.PayTrade(req.OutTradeNo, ""); err != nil {
+		t.Fatal(err)
+	}
+	result, err := session.Wait(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Paid() || result.Notify == nil || result.Query != nil || len(result.TradeNo) == 0 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if again, _ := session.Wait(context.Background()); again != result {
+		t.Error("wait should return the same result")
+	}
+}
+
+func TestPreCreateSession_WaitQuery(t *testing.T) {
+	session, err := client.NewPreCreateSession(context.Background(), preCreateReq("20261019221004"), WithQrPollInterval(10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if session.Notify(&NotifyReq{OutTradeNo: "20261019221099", TradeStatus: TradeSuccess}) || session.Notify(&NotifyReq{OutTradeNo: session.OutTradeNo, TradeStatus: TradeWaitBuyerPay}) {
+		t.Error("notification should be ignored")
+	}
+	time.AfterFunc(30*time.Millisecond, func() {
+		if err := gateway.PayTrade(session.OutTradeNo, ""); err != nil {
+			t.Error(err)
+		}
+	})
+	result, err := session.Wait(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Paid() || result.Query == nil || result.Expired {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestPreCreateSession_Expire(t *testing.T) {
+	session, err := client.NewPreCreateSession(context.Background(), preCreateReq("20261019221005"), WithQrPollInterval(time.Hour), WithQrExpire(30*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err = session.Wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("unexpected error: %v", err)
+	}
+	closes := gateway.Calls("alipay.trade.close")
+	result, err := session.Wait(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Paid() || !result.Expired || result.TradeStatus != TradeClosed || gateway.Calls("alipay.trade.close") != closes+1 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if trade, _ := gateway.Trade(session.OutTradeNo); trade.Status != TradeClosed {
+		t.Errorf("trade should be closed: %s", trade.Status)
+	}
+}
+
+// TestPreCreateSession_ExpireCloseFail 过期后关闭一直失败时仍按查询间隔重试
+func TestPreCreateSession_ExpireCloseFail(t *testing.T) {
+	defer gateway.ClearErrors()
+	session, err := client.NewPreCreateSession(context.Background(), preCreateReq("20261019221006"), WithQrPollInterval(20*time.Millisecond), WithQrExpire(time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	gateway.InjectError("alipay.trade.close", alipaytest.ErrSystemError, 0)
+	queries, closes := gateway.Calls("alipay.trade.query"), gateway.Calls("alipay.trade.close")
+	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+	defer cancel()
+	if _, err = session.Wait(ctx); err != context.DeadlineExceeded {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if n := gateway.Calls("alipay.trade.close") - closes; n == 0 || n > 10 {
+		t.Errorf("unexpected close calls: %d", n)
+	}
+	if n := gateway.Calls("alipay.trade.query") - queries; n > 20 {
+		t.Errorf("unexpected query calls: %d", n)
+	}
+}
+
+type failingTransport struct {
+	base http.RoundTripper
+	fail atomic.Bool
+}
+
+func (t *failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.fail.Load() {
+		return nil, errors.New("connection refused")
+	}
+	return t.base.RoundTrip(req)
+}
+
+// TestPreCreateSession_QueryError 查询请求失败时返回错误而不是继续轮询
+func TestPreCreateSession_QueryError(t *testing.T) {
+	transport := &failingTransport{base: gateway.Server.Client().Transport}
+	failingClient, err := gateway.Client(SetClientOptHttpClient(&http.Client{Transport: transport}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, err := failingClient.NewPreCreateSession(context.Background(), preCreateReq("20261019221007"), WithQrPollInterval(10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	transport.fail.Store(true)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err = session.Wait(ctx); err == nil || err == context.DeadlineExceeded {
+		t.Errorf("unexpected error: %v", err)
+	}
+	transport.fail.Store(false)
+	if err = gateway.PayTrade(session.OutTradeNo, ""); err != nil {
+		t.Fatal(err)
+	}
+	if result, err := session.Wait(ctx); err != nil || !result.Paid() {
+		t.Errorf("%+v %v", result, err)
+	}
+}
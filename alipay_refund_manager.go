@@ -0,0 +1,322 @@
+package alipay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+/**
+ * @author: Sam
+ * @since: 1.0.0
+ * @date: 2026/10/19 22:40
+ * @desc: 退款管理
+ *
+ * 部分退款时每笔退款需要唯一的 out_request_no，重试时必须使用相同的退款请求号，支付宝保证同一请求号只退款一次。
+ * 退款请求号在调用支付宝之前写入 RefundStore，请求失败后可以使用原请求号重试，
+ * 退款结果以 alipay.trade.fastpay.refund.query 返回 refund_status=REFUND_SUCCESS 为准。
+ */
+
+var ErrRefundExceeded = errors.New("xpay: refund amount exceeds refundable balance")
+var ErrRefundConflict = errors.New("xpay: biz_no already used by a refund with different amount")
+var ErrRefundNotFound = errors.New("xpay: refund record not found")
+var ErrRefundNotPending = errors.New("xpay: refund is not pending")
+
+// RefundRecordStatus 退款记录的状态
+type RefundRecordStatus string
+
+const (
+	RefundRecordPending RefundRecordStatus = "PENDING" // 已生成退款请求号，退款结果未确认
+	RefundRecordSuccess RefundRecordStatus = "SUCCESS" // 退款查询或者退款通知确认退款成功
+	RefundRecordFailed  RefundRecordStatus = "FAILED"  // 支付宝明确返回退款失败，不占用可退金额
+)
+
+// RefundRecord 一笔退款
+type RefundRecord struct {
+	OutTradeNo   string             `json:"out_trade_no"`
+	OutRequestNo string             `json:"out_request_no"`   // 退款请求号
+	BizNo        string             `json:"biz_no,omitempty"` // 商户退款单号
+	TradeNo      string             `json:"trade_no,omitempty"`
+	TotalAmount  Money              `json:"total_amount"` // 交易金额
+	RefundAmount Money              `json:"refund_amount"`
+	RefundReason string             `json:"refund_reason,omitempty"`
+	Status       RefundRecordStatus `json:"status"`
+	SubCode      string             `json:"sub_code,omitempty"` // 退款失败时的错误码
+	SubMsg       string             `json:"sub_msg,omitempty"`
+	GmtRefund    string             `json:"gmt_refund,omitempty"` // 退款时间
+	CreatedAt    time.Time          `json:"created_at"`
+	UpdatedAt    time.Time          `json:"updated_at"`
+}
+
+// RefundStore 退款记录的存储，多个进程共用时需要由存储保证同一交易的退款串行执行
+type RefundStore interface {
+	// Refunds 交易的全部退款记录，按创建时间排序，没有记录时返回空
+	Refunds(ctx context.Context, outTradeNo string) ([]*RefundRecord, error)
+	// Save 新增或者按 OutTradeNo 和 OutRequestNo 更新退款记录
+	Save(ctx context.Context, record *RefundRecord) error
+}
+
+// RefundApply 退款申请
+type RefundApply struct {
+	OutTradeNo   string
+	TotalAmount  Money // 交易金额，用于计算可退金额
+	RefundAmount Money
+	RefundReason string
+	// BizNo 商户退款单号，例如售后单号。相同的BizNo重复申请时使用原退款请求号，为空时每次申请都是一笔新的退款
+	BizNo string
+}
+
+// RefundManager 退款管理
+type RefundManager struct {
+	client *Client
+	store  RefundStore
+	mu     sync.Mutex
+
+	Retries       int           // 请求失败或者返回系统繁忙时的重试次数，默认2次
+	RetryInterval time.Duration // 重试间隔，默认1秒
+	// NewRequestNo 生成退款请求号，seq从1开始，默认为 商户订单号-seq
+	NewRequestNo func(outTradeNo string, seq int) string
+}
+
+func NewRefundManager(client *Client, store RefundStore) *RefundManager {
+	return &RefundManager{
+		client:        client,
+		store:         store,
+		Retries:       2,
+		RetryInterval: time.Second,
+		NewRequestNo: func(outTradeNo string, seq int) string {
+			return fmt.Sprintf("%s-%d", outTradeNo, seq)
+		},
+	}
+}
+
+// Refund 申请退款，退款请求号写入存储后调用 alipay.trade.refund，并通过退款查询确认结果。
+// 重试后仍然请求失败时返回退款记录和error，退款记录保持 RefundRecordPending，可以通过 Retry 或者相同的BizNo重新申请
+func (r *RefundManager) Refund(ctx context.Context, apply RefundApply) (*RefundRecord, error) {
+	req := TradeRefundReq{OutTradeNo: apply.OutTradeNo, RefundAmount: apply.RefundAmount, RefundReason: apply.RefundReason}
+	if err := req.DoValidate(); err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	records, err := r.store.Refunds(ctx, apply.OutTradeNo)
+	if err != nil {
+		return nil, err
+	}
+	if len(apply.BizNo) > 0 {
+		for _, record := range records {
+			if record.BizNo != apply.BizNo {
+				continue
+			}
+			if record.RefundAmount != apply.RefundAmount {
+				return record, ErrRefundConflict
+			}
+			if record.Status != RefundRecordPending {
+				return record, nil
+			}
+			return r.submit(ctx, record)
+		}
+	}
+	// 先确认未完成的退款，避免按过期的状态计算可退金额
+	for _, record := range records {
+		if record.Status == RefundRecordPending {
+			if err = r.confirm(ctx, record); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if apply.RefundAmount.Cmp(refundable(apply.TotalAmount, records)) > 0 {
+		return nil, ErrRefundExceeded
+	}
+	now := time.Now()
+	record := &RefundRecord{
+		OutTradeNo:   apply.OutTradeNo,
+		OutRequestNo: r.NewRequestNo(apply.OutTradeNo, len(records)+1),
+		BizNo:        apply.BizNo,
+		TotalAmount:  apply.TotalAmount,
+		RefundAmount: apply.RefundAmount,
+		RefundReason: apply.RefundReason,
+		Status:       RefundRecordPending,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err = r.store.Save(ctx, record); err != nil {
+		return nil, err
+	}
+	return r.submit(ctx, record)
+}
+
+// refundable 可退金额，未确认的退款同样占用可退金额
+func refundable(totalAmount Money, records []*RefundRecord) Money {
+	balance := totalAmount
+	for _, record := range records {
+		if record.Status != RefundRecordFailed {
+			balance = balance.Sub(record.RefundAmount)
+		}
+	}
+	return balance
+}
+
+// Refundable 交易的可退金额
+func (r *RefundManager) Refundable(ctx context.Context, outTradeNo string, totalAmount Money) (Money, error) {
+	records, err := r.store.Refunds(ctx, outTradeNo)
+	if err != nil {
+		return 0, err
+	}
+	return refundable(totalAmount, records), nil
+}
+
+// Retry 使用原退款请求号重新提交未确认的退款
+func (r *RefundManager) Retry(ctx context.Context, outTradeNo, outRequestNo string) (*RefundRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	record, err := r.find(ctx, outTradeNo, outRequestNo)
+	if err != nil {
+		return nil, err
+	}
+	if record.Status != RefundRecordPending {
+		return record, ErrRefundNotPending
+	}
+	return r.submit(ctx, record)
+}
+
+// Confirm 通过退款查询确认未完成的退款
+func (r *RefundManager) Confirm(ctx context.Context, outTradeNo, outRequestNo string) (*RefundRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	record, err := r.find(ctx, outTradeNo, outRequestNo)
+	if err != nil {
+		return nil, err
+	}
+	if record.Status == RefundRecordPending {
+		err = r.confirm(ctx, record)
+	}
+	return record, err
+}
+
+// HandleNotify 处理已验签的退款通知，out_biz_no为退款请求号，refund_fee为交易的累计退款金额。
+// 不是退款通知时返回nil，确认后的累计退款金额小于refund_fee时返回error，说明存在不是通过 RefundManager 发起的退款
+func (r *RefundManager) HandleNotify(ctx context.Context, notifyReq *NotifyReq) (*RefundRecord, error) {
+	if len(notifyReq.OutBizNo) == 0 || len(notifyReq.GmtRefund) == 0 {
+		return nil, nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	records, err := r.store.Refunds(ctx, notifyReq.OutTradeNo)
+	if err != nil {
+		return nil, err
+	}
+	var current *RefundRecord
+	var refunded Money
+	for _, record := range records {
+		if record.OutRequestNo == notifyReq.OutBizNo && record.Status != RefundRecordSuccess {
+			record.Status, record.TradeNo, record.GmtRefund = RefundRecordSuccess, notifyReq.TradeNo, notifyReq.GmtRefund
+			if err = r.save(ctx, record); err != nil {
+				return nil, err
+			}
+		}
+		if record.OutRequestNo == notifyReq.OutBizNo {
+			current = record
+		}
+		// 通知可能乱序到达，累计退款金额不足时确认其他未完成的退款
+		if record.Status == RefundRecordSuccess {
+			refunded = refunded.Add(record.RefundAmount)
+		}
+	}
+	if current == nil {
+		return nil, ErrRefundNotFound
+	}
+	for _, record := range records {
+		if refunded.Cmp(notifyReq.RefundFee) >= 0 {
+			break
+		}
+		if record.Status == RefundRecordPending {
+			if err = r.confirm(ctx, record); err != nil {
+				return current, err
+			}
+			if record.Status == RefundRecordSuccess {
+				refunded = refunded.Add(record.RefundAmount)
+			}
+		}
+	}
+	if refunded.Cmp(notifyReq.RefundFee) < 0 {
+		return current, fmt.Errorf("xpay: refund_fee %s exceeds confirmed refund amount %s", notifyReq.RefundFee, refunded)
+	}
+	return current, nil
+}
+
+func (r *RefundManager) find(ctx context.Context, outTradeNo, outRequestNo string) (*RefundRecord, error) {
+	records, err := r.store.Refunds(ctx, outTradeNo)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		if record.OutRequestNo == outRequestNo {
+			return record, nil
+		}
+	}
+	return nil, ErrRefundNotFound
+}
+
+func (r *RefundManager) save(ctx context.Context, record *RefundRecord) error {
+	record.UpdatedAt = time.Now()
+	return r.store.Save(ctx, record)
+}
+
+// submit 提交退款，请求失败或者系统繁忙时使用相同的退款请求号重试
+func (r *RefundManager) submit(ctx context.Context, record *RefundRecord) (*RefundRecord, error) {
+	req := TradeRefundReq{
+		OutTradeNo:   record.OutTradeNo,
+		RefundAmount: record.RefundAmount,
+		RefundReason: record.RefundReason,
+		OutRequestNo: record.OutRequestNo,
+	}
+	var res *TradeRefundRes
+	var err error
+	for i := 0; ; i++ {
+		res, err = r.client.TradeRefund(ctx, req)
+		if err == nil && !resultUnknown(&res.CommonRes) {
+			break
+		}
+		if err == nil {
+			err = fmt.Errorf("xpay: trade refund failed, sub_code: %s, sub_msg: %s", res.SubCode, res.SubMsg)
+		}
+		if i >= r.Retries || ctx.Err() != nil {
+			return record, err
+		}
+		timer := time.NewTimer(r.RetryInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return record, ctx.Err()
+		case <-timer.C:
+		}
+	}
+	if res.Fail() {
+		record.Status, record.SubCode, record.SubMsg = RefundRecordFailed, res.SubCode, res.SubMsg
+		if err = r.save(ctx, record); err != nil {
+			return record, err
+		}
+		return record, fmt.Errorf("xpay: trade refund failed, sub_code: %s, sub_msg: %s", res.SubCode, res.SubMsg)
+	}
+	record.TradeNo = res.TradeNo
+	return record, r.confirm(ctx, record)
+}
+
+// confirm 查询退款结果，退款不存在或者处理中时保持 RefundRecordPending
+func (r *RefundManager) confirm(ctx context.Context, record *RefundRecord) error {
+	res, err := r.client.TradeFastPayRefundQuery(ctx, TradeFastPayRefundQueryReq{OutTradeNo: record.OutTradeNo, OutRequestNo: record.OutRequestNo})
+	if err != nil {
+		return err
+	}
+	if res.Fail() || res.RefundStatus != RefundSuccess {
+		return nil
+	}
+	if res.RefundAmount != record.RefundAmount {
+		return fmt.Errorf("xpay: refund %s amount %s does not match record %s", record.OutRequestNo, res.RefundAmount, record.RefundAmount)
+	}
+	record.Status, record.TradeNo, record.GmtRefund = RefundRecordSuccess, res.TradeNo, res.GMTRefundPay
+	return r.save(ctx, record)
+}
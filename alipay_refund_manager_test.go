@@ -0,0 +1,196 @@
+package alipay_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/try-labs/xpay"
+	"github.com/try-labs/xpay/alipaytest"
+)
+
+/**
+ * @author: Sam
+ * @since:
+ * @date: 2026/10/19 22:40
+ * @desc:
+ */
+
+// dropTransport 请求到达网关后丢弃指定接口的响应，模拟响应超时
+type dropTransport struct {
+	mu    sync.Mutex
+	drops map[string]int
+}
+
+func (r *dropTransport) drop(method string, times int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.drops[method] = times
+}
+
+func (r *dropTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	body, err := io.ReadAll(request.Body)
+	if err != nil {
+		return nil, err
+	}
+	request.Body = io.NopCloser(bytes.NewReader(body))
+	values, _ := url.ParseQuery(string(body))
+	response, err := http.DefaultTransport.RoundTrip(request)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if method := values.Get("method"); r.drops[method] > 0 {
+		r.drops[method]--
+		response.Body.Close()
+		return nil, errors.New("read: connection reset by peer")
+	}
+	return response, nil
+}
+
+func newRefundManager(t *testing.T) (*RefundManager, *dropTransport) {
+	t.Helper()
+	transport := &dropTransport{drops: make(map[string]int)}
+	refundClient, err := gateway.Client(SetClientOptHttpClient(&http.Client{Transport: transport}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager := NewRefundManager(refundClient, alipaytest.NewRefundStore())
+	manager.RetryInterval = time.Millisecond
+	return manager, transport
+}
+
+func paidTrade(t *testing.T, outTradeNo string, totalAmount Money) {
+	t.Helper()
+	res, err := client.TradePreCreate(context.Background(), TradePreCreateReq{OutTradeNo: outTradeNo, TotalAmount: totalAmount, Subject: "测试商品"})
+	if err != nil || res.Fail() {
+		t.Fatal(err, res)
+	}
+	if err = gateway.PayTrade(outTradeNo, ""); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRefundManager_Refund(t *testing.T) {
+	ctx := context.Background()
+	manager, _ := newRefundManager(t)
+	paidTrade(t, "20261019224001", MustParseMoney("100.00"))
+	apply := RefundApply{OutTradeNo: "20261019224001", TotalAmount: MustParseMoney("100.00"), RefundAmount: MustParseMoney("30.00"), BizNo: "AS001"}
+	record, err := manager.Refund(ctx, apply)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.Status != RefundRecordSuccess || record.OutRequestNo != "20261019224001-1" || len(record.GmtRefund) == 0 {
+		t.Errorf("unexpected record: %+v", record)
+	}
+	// 相同的BizNo不会重复退款
+	again, err := manager.Refund(ctx, apply)
+	if err != nil || again.OutRequestNo != record.OutRequestNo {
+		t.Errorf("unexpected record: %+v %v", again, err)
+	}
+	apply.RefundAmount = MustParseMoney("40.00")
+	if _, err = manager.Refund(ctx, apply); err != ErrRefundConflict {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err = manager.Refund(ctx, RefundApply{OutTradeNo: apply.OutTradeNo, TotalAmount: apply.TotalAmount, RefundAmount: MustParseMoney("70.01")}); err != ErrRefundExceeded {
+		t.Errorf("unexpected error: %v", err)
+	}
+	record, err = manager.Refund(ctx, RefundApply{OutTradeNo: apply.OutTradeNo, TotalAmount: apply.TotalAmount, RefundAmount: MustParseMoney("70.00")})
+	if err != nil || record.Status != RefundRecordSuccess || record.OutRequestNo != "20261019224001-2" {
+		t.Errorf("unexpected record: %+v %v", record, err)
+	}
+	if balance, _ := manager.Refundable(ctx, apply.OutTradeNo, apply.TotalAmount); !balance.IsZero() {
+		t.Errorf("unexpected balance: %s", balance)
+	}
+	if refunds := gateway.Refunds(apply.OutTradeNo); len(refunds) != 2 {
+		t.Errorf("unexpected refunds: %d", len(refunds))
+	}
+}
+
+func TestRefundManager_NetworkFailure(t *testing.T) {
+	ctx := context.Background()
+	manager, transport := newRefundManager(t)
+	manager.Retries = 0
+	paidTrade(t, "20261019224002", MustParseMoney("50.00"))
+	apply := RefundApply{OutTradeNo: "20261019224002", TotalAmount: MustParseMoney("50.00"), RefundAmount: MustParseMoney("20.00"), BizNo: "AS002"}
+	transport.drop("alipay.trade.refund", 1)
+	record, err := manager.Refund(ctx, apply)
+	if err == nil || record.Status != RefundRecordPending {
+		t.Fatalf("unexpected record: %+v %v", record, err)
+	}
+	// 退款未确认时同样占用可退金额
+	if _, err = manager.Refund(ctx, RefundApply{OutTradeNo: apply.OutTradeNo, TotalAmount: apply.TotalAmount, RefundAmount: MustParseMoney("30.01")}); err != ErrRefundExceeded {
+		t.Errorf("unexpected error: %v", err)
+	}
+	record, err = manager.Refund(ctx, apply)
+	if err != nil || record.Status != RefundRecordSuccess || record.OutRequestNo != "20261019224002-1" {
+		t.Errorf("unexpected record: %+v %v", record, err)
+	}
+	if refunds := gateway.Refunds(apply.OutTradeNo); len(refunds) != 1 {
+		t.Errorf("refund should not be duplicated: %d", len(refunds))
+	}
+
+	// 重试次数内恢复
+	manager.Retries = 2
+	transport.drop("alipay.trade.refund", 2)
+	record, err = manager.Refund(ctx, RefundApply{OutTradeNo: apply.OutTradeNo, TotalAmount: apply.TotalAmount, RefundAmount: MustParseMoney("10.00")})
+	if err != nil || record.Status != RefundRecordSuccess {
+		t.Errorf("unexpected record: %+v %v", record, err)
+	}
+	if _, err = manager.Retry(ctx, apply.OutTradeNo, record.OutRequestNo); err != ErrRefundNotPending {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestRefundManager_Failed(t *testing.T) {
+	ctx := context.Background()
+	manager, _ := newRefundManager(t)
+	paidTrade(t, "20261019224003", MustParseMoney("10.00"))
+	gateway.InjectError("alipay.trade.refund", alipaytest.ErrTradeStatusError, 1)
+	apply := RefundApply{OutTradeNo: "20261019224003", TotalAmount: MustParseMoney("10.00"), RefundAmount: MustParseMoney("10.00")}
+	record, err := manager.Refund(ctx, apply)
+	if err == nil || record.Status != RefundRecordFailed || record.SubCode != "ACQ.TRADE_STATUS_ERROR" {
+		t.Errorf("unexpected record: %+v %v", record, err)
+	}
+	// 失败的退款不占用可退金额
+	record, err = manager.Refund(ctx, apply)
+	if err != nil || record.Status != RefundRecordSuccess || record.OutRequestNo != "20261019224003-2" {
+		t.Errorf("unexpected record: %+v %v", record, err)
+	}
+}
+
+func TestRefundManager_HandleNotify(t *testing.T) {
+	ctx := context.Background()
+	manager, transport := newRefundManager(t)
+	manager.Retries = 0
+	paidTrade(t, "20261019224004", MustParseMoney("30.00"))
+	transport.drop("alipay.trade.refund", 1)
+	record, _ := manager.Refund(ctx, RefundApply{OutTradeNo: "20261019224004", TotalAmount: MustParseMoney("30.00"), RefundAmount: MustParseMoney("12.00")})
+	if record.Status != RefundRecordPending {
+		t.Fatalf("unexpected record: %+v", record)
+	}
+	notifyReq := &NotifyReq{OutTradeNo: "20261019224004", OutBizNo: record.OutRequestNo, RefundFee: MustParseMoney("12.00"), GmtRefund: "2026-10-19 22:40:00.000", TradeStatus: TradeSuccess}
+	record, err := manager.HandleNotify(ctx, notifyReq)
+	if err != nil || record.Status != RefundRecordSuccess || record.GmtRefund != notifyReq.GmtRefund {
+		t.Errorf("unexpected record: %+v %v", record, err)
+	}
+	// 累计退款金额大于已确认的退款
+	notifyReq.RefundFee = MustParseMoney("20.00")
+	if _, err = manager.HandleNotify(ctx, notifyReq); err == nil {
+		t.Error("refund_fee mismatch should fail")
+	}
+	notifyReq.OutBizNo = "20261019224004-9"
+	if _, err = manager.HandleNotify(ctx, notifyReq); err != ErrRefundNotFound {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if record, err = manager.HandleNotify(ctx, &NotifyReq{OutTradeNo: "20261019224004", TradeStatus: TradeSuccess}); record != nil || err != nil {
+		t.Error("payment notification should be ignored")
+	}
+}
@@ -0,0 +1,146 @@
+package alipay_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/try-labs/xpay"
+	"github.com/try-labs/xpay/alipaytest"
+)
+
+/**
+ * @author: Sam
+ * @since:
+ * @date: 2026/10/20 02:10
+ * @desc:
+ */
+
+func TestTradeOrderSettleReq_DoValidate(t *testing.T) {
+	req := TradeOrderSettleReq{
+		OutRequestNo: "ST20261020001",
+		TradeNo:      "2026102022001446880000000001",
+		RoyaltyParameters: []*OpenApiRoyaltyDetailInfoPojo{
+			{TransIn: "2088000000000001", Amount: MustParseMoney("3.00")},
+			{TransIn: "2088000000000002", Amount: MustParseMoney("2.00")},
+		},
+	}
+	settleable := MustParseMoney("5.00")
+	req.SettleableAmount = &settleable
+	if err := req.DoValidate(); err != nil {
+		t.Fatal(err)
+	}
+	settleable = MustParseMoney("4.99")
+	req.RoyaltyParameters = append(req.RoyaltyParameters, &OpenApiRoyaltyDetailInfoPojo{TransIn: "2088000000000003"})
+	errs, ok := req.DoValidate().(ValidationErrors)
+	if !ok || len(errs) != 2 || !errs.HasField("royalty_parameters[2].amount") || !errs.HasField("royalty_parameters") {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	for _, err := range errs {
+		if err.Field == "royalty_parameters" && (err.Code != ValidationCodeExceed || err.Param != "4.99") {
+			t.Errorf("unexpected error: %+v", err)
+		}
+	}
+	// 可分账金额为0时任何分账都超过可分账金额
+	settleable = 0
+	req.RoyaltyParameters = req.RoyaltyParameters[:2]
+	if errs, ok := req.DoValidate().(ValidationErrors); !ok || len(errs) != 1 || errs[0].Code != ValidationCodeExceed || errs[0].Param != "0.00" {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+	// 未指定可分账金额时不校验金额之和，由 TradeOrderSettle 查询后校验
+	req.SettleableAmount = nil
+	if err := req.DoValidate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestClient_TradeOrderSettle(t *testing.T) {
+	g := alipaytest.NewGateway()
+	defer g.Close()
+	royaltyClient, err := g.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	payRes, err := royaltyClient.TradePay(ctx, TradePayReq{OutTradeNo: "R20261020001", TotalAmount: MustParseMoney("100.00"), Subject: "分账订单", Scene: "bar_code", AuthCode: "285516572327851289"})
+	if err != nil || payRes.Fail() {
+		t.Fatalf("%v %v", payRes, err)
+	}
+	tradeNo := payRes.TradeNo
+
+	receivers := []*RoyaltyEntity{
+		{Type: "userId", Account: "2088000000000001", Memo: "平台服务费"},
+		{Type: "loginName", Account: "partner@example.com", Name: "测试商户"},
+	}
+	bindRes, err := royaltyClient.TradeRoyaltyRelationBind(ctx, TradeRoyaltyRelationBindReq{ReceiverList: receivers, OutRequestNo: "BR20261020001"})
+	if err != nil || bindRes.ResultCode != "SUCCESS" {
+		t.Fatalf("%v %v", bindRes, err)
+	}
+	queryRes, err := royaltyClient.TradeRoyaltyRelationBatchQuery(ctx, TradeRoyaltyRelationBatchQueryReq{PageNum: 1, PageSize: 1, OutRequestNo: "BQ20261020001"})
+	if err != nil || queryRes.TotalRecordNum != 2 || queryRes.TotalPageNum != 2 || len(queryRes.ReceiverList) != 1 || queryRes.ReceiverList[0].Account != "2088000000000001" {
+		t.Fatalf("%v %v", queryRes, err)
+	}
+	rateRes, err := royaltyClient.TradeRoyaltyRateQuery(ctx, TradeRoyaltyRateQueryReq{OutRequestNo: "RQ20261020001"})
+	if err != nil || rateRes.MaxRatio != alipaytest.RoyaltyMaxRatio {
+		t.Fatalf("%v %v", rateRes, err)
+	}
+
+	// 退款后可分账金额减少，以查询结果作为客户端校验的上限
+	if _, err = royaltyClient.TradeRefund(ctx, TradeRefundReq{TradeNo: tradeNo, RefundAmount: MustParseMoney("10.00"), OutRequestNo: "RF20261020001"}); err != nil {
+		t.Fatal(err)
+	}
+	onSettleRes, err := royaltyClient.TradeOrderOnSettleQuery(ctx, TradeOrderOnSettleQueryReq{TradeNo: tradeNo})
+	if err != nil || onSettleRes.UnsettledAmount != MustParseMoney("90.00") {
+		t.Fatalf("%v %v", onSettleRes, err)
+	}
+	settleReq := TradeOrderSettleReq{
+		OutRequestNo: "ST20261020001",
+		TradeNo:      tradeNo,
+		RoyaltyParameters: []*OpenApiRoyaltyDetailInfoPojo{
+			{TransIn: "2088000000000001", Amount: MustParseMoney("20.00")},
+			{TransIn: "partner@example.com", TransInType: "loginName", Amount: MustParseMoney("10.00")},
+		},
+		SettleableAmount: &onSettleRes.UnsettledAmount,
+	}
+	settleRes, err := royaltyClient.TradeOrderSettle(ctx, settleReq)
+	if err != nil || settleRes.Fail() || len(settleRes.SettleNo) == 0 {
+		t.Fatalf("%v %v", settleRes, err)
+	}
+	// 重复的结算请求号返回原分账单号
+	if again, err := royaltyClient.TradeOrderSettle(ctx, settleReq); err != nil || again.SettleNo != settleRes.SettleNo {
+		t.Errorf("settle should be idempotent: %v %v", again, err)
+	}
+	if trade, _ := g.Trade("R20261020001"); trade.SettledAmount != MustParseMoney("30.00") {
+		t.Errorf("unexpected trade: %+v", trade)
+	}
+	settleQueryRes, err := royaltyClient.TradeOrderSettleQuery(ctx, TradeOrderSettleQueryReq{SettleNo: settleRes.SettleNo})
+	if err != nil || len(settleQueryRes.RoyaltyDetailList) != 2 || settleQueryRes.RoyaltyDetailList[1].TransInType != "loginName" || settleQueryRes.RoyaltyDetailList[0].State != "SUCCESS" {
+		t.Fatalf("%v %v", settleQueryRes, err)
+	}
+	if byRequest, err := royaltyClient.TradeOrderSettleQuery(ctx, TradeOrderSettleQueryReq{OutRequestNo: "ST20261020001", TradeNo: tradeNo}); err != nil || byRequest.OutRequestNo != "ST20261020001" {
+		t.Errorf("%v %v", byRequest, err)
+	}
+
+	// 超过剩余可分账金额，指定的可分账金额过大时由支付宝拒绝
+	overstated := MustParseMoney("90.00")
+	settleReq.OutRequestNo, settleReq.SettleableAmount = "ST20261020002", &overstated
+	settleReq.RoyaltyParameters = []*OpenApiRoyaltyDetailInfoPojo{{TransIn: "2088000000000001", Amount: MustParseMoney("60.01")}}
+	if res, _ := royaltyClient.TradeOrderSettle(ctx, settleReq); res.SubCode != alipaytest.ErrAllocAmountExceed.SubCode {
+		t.Errorf("unexpected result: %v", res)
+	}
+	// 未指定可分账金额时查询剩余的60.00后在请求前校验
+	settles := g.Calls("alipay.trade.order.settle")
+	_, err = royaltyClient.TradeOrderSettle(ctx, TradeOrderSettleReq{OutRequestNo: "ST20261020003", TradeNo: tradeNo, RoyaltyParameters: settleReq.RoyaltyParameters})
+	if errs, ok := err.(ValidationErrors); !ok || errs[0].Code != ValidationCodeExceed || errs[0].Param != "60.00" || g.Calls("alipay.trade.order.settle") != settles {
+		t.Errorf("request exceeding settleable amount should fail validation: %v", err)
+	}
+	settleReq.SettleableAmount = nil
+
+	// 解绑后不能再分账给该接收方
+	if _, err = royaltyClient.TradeRoyaltyRelationUnbind(ctx, TradeRoyaltyRelationUnbindReq{ReceiverList: receivers[:1], OutRequestNo: "UB20261020001"}); err != nil {
+		t.Fatal(err)
+	}
+	settleReq.RoyaltyParameters[0].Amount = MustParseMoney("1.00")
+	if res, _ := royaltyClient.TradeOrderSettle(ctx, settleReq); res.SubCode != alipaytest.ErrRoyaltyReceiverNotBind.SubCode {
+		t.Errorf("unexpected result: %v", res)
+	}
+}
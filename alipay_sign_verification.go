@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/google/go-querystring/query"
+	"net/url"
 	"os"
 	"sort"
 	"strings"
@@ -84,6 +85,37 @@ func (r responseBuff) IsHtmlError() bool {
 	return strings.Contains(str, `<html>`)
 }
 
+// RequestSignContent 请求参数的待签名字符串：去掉sign和空值，按key排序后以&拼接
+func RequestSignContent(values url.Values) string {
+	valueList := make([]string, 0, len(values))
+	for key := range values {
+		var value = strings.TrimSpace(values.Get(key))
+		if key != ExcludeKeySign && len(value) > 0 {
+			valueList = append(valueList, key+"="+value)
+		}
+	}
+	sort.Strings(valueList)
+	return strings.Join(valueList, "&")
+}
+
+// NotifySignContent 通知参数的待验签字符串：去掉sign和sign_type，按key排序后以&拼接
+func NotifySignContent(values url.Values) string {
+	keyValueList := make([]string, 0, len(values))
+	for key, value := range values {
+		if key == ExcludeKeySign || key == ExcludeKeySignType || len(value) != 1 {
+			continue
+		}
+		keyValueList = append(keyValueList, key+"="+value[0])
+	}
+	sort.Strings(keyValueList)
+	return strings.Join(keyValueList, "&")
+}
+
+// ResponseSignContent 同步响应的待验签字符串，即xxx_response对应的原始内容
+func ResponseSignContent(buff []byte) (string, error) {
+	return responseBuff(buff).GetWaitSignData()
+}
+
 type Signature struct {
 	// 应用id
 	appId string
@@ -116,16 +148,7 @@ func (r *Signature) Sign() (string, error) {
 	if err != nil {
 		return "", err
 	}
-	valueList := make([]string, 0, len(values))
-	for key := range values {
-		var value = strings.TrimSpace(values.Get(key))
-		if len(value) > 0 {
-			valueList = append(valueList, key+"="+value)
-		}
-	}
-	sort.Strings(valueList)
-	var src = strings.Join(valueList, "&")
-	sign, err := RSASignWithKey([]byte(src), r.appPrivateKey, crypto.SHA256)
+	sign, err := RSASignWithKey([]byte(RequestSignContent(values)), r.appPrivateKey, crypto.SHA256)
 	if err != nil {
 		return "", err
 	}
@@ -250,6 +273,12 @@ func (r *CertSignStrategy) LoadRootCertFile(filename string) error {
 	if err != nil {
 		return err
 	}
+	r.alipayRootCertSn = GetRootCertSN(buff)
+	return nil
+}
+
+// GetRootCertSN 支付宝根证书SN，根证书文件中包含多个证书，只取RSA签名的证书SN以_拼接
+func GetRootCertSN(buff []byte) string {
 	var certStrList = strings.Split(string(buff), CertificateEnd)
 	certSNSlice := make([]string, 0, len(certStrList))
 	for _, certStr := range certStrList {
@@ -259,8 +288,7 @@ func (r *CertSignStrategy) LoadRootCertFile(filename string) error {
 			certSNSlice = append(certSNSlice, GetCertSN(cert))
 		}
 	}
-	r.alipayRootCertSn = strings.Join(certSNSlice, "_")
-	return nil
+	return strings.Join(certSNSlice, "_")
 }
 
 // LoadPublicCertFile 加载支付宝公钥证书
@@ -1,15 +1,17 @@
-package alipay
+package alipay_test
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"log"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
-	"sort"
-	"strings"
 	"testing"
 	"time"
+
+	. "github.com/try-labs/xpay"
+	"github.com/try-labs/xpay/alipaytest"
 )
 
 /**
@@ -19,90 +21,77 @@ import (
  * @desc:
  */
 
+var gateway *alipaytest.Gateway
 var client *Client
 
-func init() {
+// TestMain 全部请求发送到本地的模拟网关，不依赖真实的支付宝账号和证书
+func TestMain(m *testing.M) {
 	var err error
-	signStrategy := NewCertSignStrategy(OtherAppId, OtherPrivateKey, "appPublicCert.crt", "alipayRootCert.crt", "alipayPublicCert.crt")
-	client, err = NewClient(signStrategy, SetClientOptIsProd(true))
-
-	//signStrategy := NewNormalRSA2SignStrategy(AppId,PrivateKey, PublicKey, AlipayPublicKey)
-	//client, err = NewAlipayClient(signStrategy)
+	gateway = alipaytest.NewGateway()
+	client, err = gateway.Client()
 	if err != nil {
 		fmt.Println("初始化失败, 错误信息为", err, client)
 		os.Exit(-1)
 	}
+	code := m.Run()
+	gateway.Close()
+	os.Exit(code)
 }
 
 func TestClient_AsyncNotify(t *testing.T) {
-	notifyParamMap := make(map[string]string, 0)
-	notifyParamMap["app_id"] = "2016091800539057"
-	notifyParamMap["auth_app_id"] = "2016091800539057"
-	notifyParamMap["buyer_id"] = "2088102176305546"
-	notifyParamMap["buyer_pay_amount"] = "1.69"
-	notifyParamMap["charset"] = "utf-8"
-	notifyParamMap["fund_bill_list"] = "[{\"amount\":\"1.69\",\"fundChannel\":\"ALIPAYACCOUNT\"}]"
-	notifyParamMap["gmt_create"] = "2023-03-15 11:24:05"
-	notifyParamMap["gmt_payment"] = "2023-03-15 11:27:57"
-	notifyParamMap["invoice_amount"] = "1.69"
-	notifyParamMap["notify_id"] = "2023031500222112759005540527043634"
-	notifyParamMap["notify_time"] = "2023-03-15 11:28:00"
-	notifyParamMap["notify_type"] = "trade_status_sync"
-	notifyParamMap["out_trade_no"] = "123456789022"
-	notifyParamMap["point_amount"] = "0.00"
-	notifyParamMap["receipt_amount"] = "1.69"
-	notifyParamMap["seller_id"] = "2088102176166347"
-	notifyParamMap["sign"] = "F3nlxkcKoCtginCmxscHhDRxTEpxP8jh+TeQCEP87UCC/in+g9JQ9zRVlxRq/819OYykVG+wY2D0vwNpiiiujZaOuGiH2Yl/VKgnG3CSyCZfbsoa02nydc4pV87ZbU9xwYF8CX3RODbTRNWC589uY1OXi1L4YzkPT1AoZVNu+LtZhrVlWEYGOW0Isjd95ZEG9rkdDk4LfcAAW9A6lY9IExFF0S7nRm6UoGRzxI/ViHw3g/gyMBTBb/MAzST3J4PmtB3o6YVkJDbOuY5b9wFi9e2r22xccYqdLbCNYN24Qdhlu+MCkSH5mDiDGgc0jMgOEs5BVzULIIYE+biAs05QXw=="
-	notifyParamMap["sign_type"] = "RSA2"
-	notifyParamMap["subject"] = "测试title"
-	notifyParamMap["total_amount"] = "1.69"
-	notifyParamMap["trade_no"] = "2023031522001405540505760265"
-	notifyParamMap["trade_status"] = "TRADE_SUCCESS"
-	notifyParamMap["version"] = "1.0"
-	var err error
-	var buff []byte
-	if buff, err = json.Marshal(notifyParamMap); err != nil {
-		panic(err)
-	}
-	var notifyParam = new(NotifyReq)
-	if err = json.Unmarshal(buff, notifyParam); err != nil {
-		panic(err)
-	}
-	var keyValueList = make([]string, 0, len(notifyParamMap))
-	for key, value := range notifyParamMap {
-		// 删除sign、sign_type
-		if key == "sign" || key == "sign_type" {
-			continue
+	notified := make(chan *NotifyReq, 1)
+	merchant := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		notifyReq, err := client.AsyncNotify(r)
+		if err != nil {
+			t.Error(err)
+			return
 		}
-		keyValueList = append(keyValueList, key+"="+value)
+		notified <- notifyReq
+		io.WriteString(w, "success")
+	}))
+	defer merchant.Close()
+	req := NewTradePagePayReq("20230315170140", MustParseMoney("88.88"), "测试商品")
+	req.NotifyUrl = merchant.URL
+	payURL, err := client.TradePagePay(*req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, err := http.Get(payURL.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+	if err = gateway.PayTrade(req.OutTradeNo, ""); err != nil {
+		t.Fatal(err)
+	}
+	gateway.WaitNotifications()
+	notifyReq := <-notified
+	if notifyReq.TradeStatus != TradeSuccess || notifyReq.OutTradeNo != req.OutTradeNo {
+		t.Errorf("unexpected notify: %s", notifyReq)
 	}
-	sort.Strings(keyValueList)
-	src := strings.Join(keyValueList, "&")
-	err = client.VerifySign(AsyncVerificationScene, notifyParam.Sign, []byte(src), notifyParam.AlipayCertSn)
-	log.Println("校验参数err", err)
 }
 
 func TestClient_TradePagePay(t *testing.T) {
-	goods := NewGoodsDetail("1111", "iphone3", 1, 100).SetShowURL("https://ms.bdimg.com/pacific/0/pic/-1225338224_-1800436947.jpg")
+	goods := NewGoodsDetail("1111", "iphone3", 1, NewMoneyFromYuan(100)).SetShowURL("https://ms.bdimg.com/pacific/0/pic/-1225338224_-1800436947.jpg")
 	goodsDetail := make([]*GoodsDetail, 0, 1)
 	goodsDetail = append(goodsDetail, goods)
-	req := NewTradePagePayReq("210122262212", "100.20", "测试title", WithGoodsDetail(goodsDetail))
+	req := NewTradePagePayReq("210122262212", MustParseMoney("100.20"), "测试title", WithGoodsDetail(goodsDetail))
 	req.NotifyUrl = "http://106.14.196.12:8081/order/asyncCallBack"
 	req.ReturnUrl = "http://106.14.196.12:8081/syncCallBack"
 	result, err := client.TradePagePay(*req)
 	if err != nil {
-		fmt.Println(err)
+		t.Fatal(err)
 	}
-	fmt.Println(result)
+	t.Log(result)
 }
 
 func TestClient_TradeWapPay(t *testing.T) {
-	goods := NewGoodsDetail("1111", "iphone3", 1, 100).SetShowURL("https://ms.bdimg.com/pacific/0/pic/-1225338224_-1800436947.jpg")
+	goods := NewGoodsDetail("1111", "iphone3", 1, NewMoneyFromYuan(100)).SetShowURL("https://ms.bdimg.com/pacific/0/pic/-1225338224_-1800436947.jpg")
 	goodsDetail := make([]*GoodsDetail, 0, 1)
 	goodsDetail = append(goodsDetail, goods)
 	req := TradeWapPayReq{}
 	req.OutTradeNo = "1211010101221122"
-	req.TotalAmount = "1001.00"
+	req.TotalAmount = MustParseMoney("1001.00")
 	req.Subject = "测试产品"
 	req.GoodsDetail = goodsDetail
 	req.NotifyUrl = "http://106.14.196.12:8081/asyncCallBack"
@@ -110,27 +99,27 @@ func TestClient_TradeWapPay(t *testing.T) {
 	req.ProductCode = QuickWapWay
 	result, err := client.TradeWapPay(req)
 	if err != nil {
-		fmt.Println(err)
+		t.Fatal(err)
 	}
-	fmt.Println(result)
+	t.Log(result)
 }
 
 func TestClient_TradeAppPay(t *testing.T) {
-	goods := NewGoodsDetail("1111", "iphone3", 1, 100).SetShowURL("https://ms.bdimg.com/pacific/0/pic/-1225338224_-1800436947.jpg")
+	goods := NewGoodsDetail("1111", "iphone3", 1, NewMoneyFromYuan(100)).SetShowURL("https://ms.bdimg.com/pacific/0/pic/-1225338224_-1800436947.jpg")
 	goodsDetail := make([]*GoodsDetail, 0, 1)
 	goodsDetail = append(goodsDetail, goods)
 	req := TradeAppPayReq{}
 	req.OutTradeNo = "221010101221122"
-	req.TotalAmount = "1001.00"
+	req.TotalAmount = MustParseMoney("1001.00")
 	req.Subject = "测试产品"
 	req.GoodsDetail = goodsDetail
 	req.NotifyUrl = "http://106.14.196.12:8081/asyncCallBack"
 	req.ReturnUrl = "http://106.14.196.12:8081/syncCallBack"
 	result, err := client.TradeAppPay(req)
 	if err != nil {
-		fmt.Println(err)
+		t.Fatal(err)
 	}
-	fmt.Println(result)
+	t.Log(result)
 }
 func TestClient_TradeQuery(t *testing.T) {
 	req := TradeQueryReq{}
@@ -140,9 +129,9 @@ func TestClient_TradeQuery(t *testing.T) {
 	defer cancelFunc()
 	result, err := client.TradeQuery(ctx, req)
 	if err != nil {
-		fmt.Println(err)
+		t.Fatal(err)
 	}
-	fmt.Println(result)
+	t.Log(result)
 
 }
 func TestClient_TradeClose(t *testing.T) {
@@ -153,9 +142,9 @@ func TestClient_TradeClose(t *testing.T) {
 	defer cancelFunc()
 	result, err := client.TradeClose(ctx, req)
 	if err != nil {
-		fmt.Println(err)
+		t.Fatal(err)
 	}
-	fmt.Println(result)
+	t.Log(result)
 
 }
 
@@ -163,14 +152,14 @@ func TestClient_TradeRefund(t *testing.T) {
 	req := TradeRefundReq{}
 	req.OutTradeNo = "20230321171318"
 	req.OutRequestNo = "202303211713181"
-	req.RefundAmount = "0.10"
+	req.RefundAmount = MustParseMoney("0.10")
 	ctx, cancelFunc := context.WithTimeout(context.Background(), 5*time.Duration(time.Second))
 	defer cancelFunc()
 	result, err := client.TradeRefund(ctx, req)
 	if err != nil {
-		fmt.Println(err)
+		t.Fatal(err)
 	}
-	fmt.Println(result)
+	t.Log(result)
 
 }
 
@@ -182,9 +171,9 @@ func TestClient_TradeFastPayRefundQuery(t *testing.T) {
 	defer cancelFunc()
 	result, err := client.TradeFastPayRefundQuery(ctx, req)
 	if err != nil {
-		fmt.Println(err)
+		t.Fatal(err)
 	}
-	fmt.Println(result)
+	t.Log(result)
 }
 
 func TestClient_DataServiceBillDownloadUrlQuery(t *testing.T) {
@@ -195,27 +184,27 @@ func TestClient_DataServiceBillDownloadUrlQuery(t *testing.T) {
 	defer cancelFunc()
 	result, err := client.DataServiceBillDownloadUrlQuery(ctx, req)
 	if err != nil {
-		fmt.Println(err)
+		t.Fatal(err)
 	}
-	fmt.Println(result)
+	t.Log(result)
 }
 
 func TestClient_TradePreCreate(t *testing.T) {
-	goods := NewGoodsDetail("1111", "iphone3", 1, 100).SetShowURL("https://ms.bdimg.com/pacific/0/pic/-1225338224_-1800436947.jpg")
+	goods := NewGoodsDetail("1111", "iphone3", 1, NewMoneyFromYuan(100)).SetShowURL("https://ms.bdimg.com/pacific/0/pic/-1225338224_-1800436947.jpg")
 	goodsDetail := make([]*GoodsDetail, 0, 1)
 	goodsDetail = append(goodsDetail, goods)
 	req := TradePreCreateReq{}
 	req.OutTradeNo = "2310101012211222"
-	req.TotalAmount = "1001.00"
+	req.TotalAmount = MustParseMoney("1001.00")
 	req.Subject = "测试产品"
 	req.GoodsDetail = goodsDetail
 	ctx, cancelFunc := context.WithTimeout(context.Background(), 5*time.Duration(time.Second))
 	defer cancelFunc()
 	result, err := client.TradePreCreate(ctx, req)
 	if err != nil {
-		fmt.Println(err)
+		t.Fatal(err)
 	}
-	fmt.Println(result)
+	t.Log(result)
 }
 
 func TestClient_TradeCancel(t *testing.T) {
@@ -225,9 +214,9 @@ func TestClient_TradeCancel(t *testing.T) {
 	defer cancelFunc()
 	result, err := client.TradeCancel(ctx, req)
 	if err != nil {
-		fmt.Println(err)
+		t.Fatal(err)
 	}
-	fmt.Println(result)
+	t.Log(result)
 }
 func TestClient_DataBillBalanceQuery(t *testing.T) {
 	ctx, cancelFunc := context.WithTimeout(context.Background(), 5*time.Duration(time.Second))
@@ -235,9 +224,9 @@ func TestClient_DataBillBalanceQuery(t *testing.T) {
 	req := DataBillBalanceQueryReq{}
 	result, err := client.DataBillBalanceQuery(ctx, req)
 	if err != nil {
-		fmt.Println(err)
+		t.Fatal(err)
 	}
-	fmt.Println(result)
+	t.Log(result)
 }
 
 func TestClient_DataBillBailQuery(t *testing.T) {
@@ -250,15 +239,15 @@ func TestClient_DataBillBailQuery(t *testing.T) {
 	}
 	result, err := client.DataBillBailQuery(ctx, req)
 	if err != nil {
-		fmt.Println(err)
+		t.Fatal(err)
 	}
-	fmt.Println(result)
+	t.Log(result)
 }
 
 func TestClient_TradeCreate(t *testing.T) {
 	req := TradeCreateReq{}
 	req.OutTradeNo = "231010101221122"
-	req.TotalAmount = "101.00"
+	req.TotalAmount = MustParseMoney("101.00")
 	req.Subject = "测试产品"
 	req.ProductCode = FaceToFacePayment
 	req.BuyerId = "2088102146225135"
@@ -267,18 +256,18 @@ func TestClient_TradeCreate(t *testing.T) {
 	defer cancelFunc()
 	result, err := client.TradeCreate(ctx, req)
 	if err != nil {
-		fmt.Println(err)
+		t.Fatal(err)
 	}
-	fmt.Println(result)
+	t.Log(result)
 }
 
 func TestClient_TradePay(t *testing.T) {
-	goods := NewGoodsDetail("1111", "iphone3", 1, 100).SetShowURL("https://ms.bdimg.com/pacific/0/pic/-1225338224_-1800436947.jpg")
+	goods := NewGoodsDetail("1111", "iphone3", 1, NewMoneyFromYuan(100)).SetShowURL("https://ms.bdimg.com/pacific/0/pic/-1225338224_-1800436947.jpg")
 	goodsDetail := make([]*GoodsDetail, 0, 1)
 	goodsDetail = append(goodsDetail, goods)
 	req := &TradePayReq{}
 	req.OutTradeNo = "20230322111111123"
-	req.TotalAmount = "56058987.00"
+	req.TotalAmount = MustParseMoney("56058987.00")
 	req.Subject = "100个iPhone14 pro max "
 	req.Scene = "bar_code"
 	req.AuthCode = "285516572327851289"
@@ -287,9 +276,9 @@ func TestClient_TradePay(t *testing.T) {
 	defer cancelFunc()
 	result, err := client.TradePay(ctx, *req)
 	if err != nil {
-		fmt.Println(err)
+		t.Fatal(err)
 	}
-	fmt.Println(result)
+	t.Log(result)
 }
 
 func TestClient_TradeOrderInfoSync(t *testing.T) {
@@ -301,9 +290,9 @@ func TestClient_TradeOrderInfoSync(t *testing.T) {
 	defer cancelFunc()
 	result, err := client.TradeOrderInfoSync(ctx, *req)
 	if err != nil {
-		fmt.Println(err)
+		t.Fatal(err)
 	}
-	fmt.Println(result)
+	t.Log(result)
 }
 
 func TestClient_FundAccountQuery(t *testing.T) {
@@ -314,9 +303,9 @@ func TestClient_FundAccountQuery(t *testing.T) {
 	defer cancelFunc()
 	result, err := client.FundAccountQuery(ctx, *req)
 	if err != nil {
-		fmt.Println(err)
+		t.Fatal(err)
 	}
-	fmt.Println(result)
+	t.Log(result)
 }
 
 func TestClient_SystemOauthToken(t *testing.T) {
@@ -325,9 +314,9 @@ func TestClient_SystemOauthToken(t *testing.T) {
 	defer cancelFunc()
 	result, err := client.SystemOauthToken(ctx, req)
 	if err != nil {
-		fmt.Println(err)
+		t.Fatal(err)
 	}
-	fmt.Println(result)
+	t.Log(result)
 }
 
 func TestClient_UserInfoAuth(t *testing.T) {
@@ -337,9 +326,9 @@ func TestClient_UserInfoAuth(t *testing.T) {
 	req.ReturnUrl = "http://106.14.196.12:8081/"
 	result, err := client.UserInfoAuth(*req)
 	if err != nil {
-		fmt.Println(err)
+		t.Fatal(err)
 	}
-	fmt.Println(result)
+	t.Log(result)
 }
 
 func TestClient_UserInfoShare(t *testing.T) {
@@ -348,9 +337,9 @@ func TestClient_UserInfoShare(t *testing.T) {
 	defer cancelFunc()
 	result, err := client.UserInfoShare(ctx, req)
 	if err != nil {
-		fmt.Println(err)
+		t.Fatal(err)
 	}
-	fmt.Println(result)
+	t.Log(result)
 }
 
 func TestClient_OpenAuthTokenApp(t *testing.T) {
@@ -359,9 +348,9 @@ func TestClient_OpenAuthTokenApp(t *testing.T) {
 	defer cancelFunc()
 	result, err := client.OpenAuthTokenApp(ctx, req)
 	if err != nil {
-		fmt.Println(err)
+		t.Fatal(err)
 	}
-	fmt.Println(result)
+	t.Log(result)
 }
 
 func TestClient_UserCertifyOpenInitialize(t *testing.T) {
@@ -383,9 +372,9 @@ func TestClient_UserCertifyOpenInitialize(t *testing.T) {
 	defer cancelFunc()
 	result, err := client.UserCertifyOpenInitialize(ctx, req)
 	if err != nil {
-		fmt.Println(err)
+		t.Fatal(err)
 	}
-	fmt.Println(result)
+	t.Log(result)
 }
 
 func TestClient_UserCertifyOpenQuery(t *testing.T) {
@@ -395,9 +384,9 @@ func TestClient_UserCertifyOpenQuery(t *testing.T) {
 	defer cancelFunc()
 	result, err := client.UserCertifyOpenQuery(ctx, req)
 	if err != nil {
-		fmt.Println(err)
+		t.Fatal(err)
 	}
-	fmt.Println(result)
+	t.Log(result)
 }
 
 func TestClient_UserCertifyOpenCertify(t *testing.T) {
@@ -408,9 +397,9 @@ func TestClient_UserCertifyOpenCertify(t *testing.T) {
 	defer cancelFunc()
 	result, err := client.UserCertifyOpenCertify(ctx, req)
 	if err != nil {
-		fmt.Println(err)
+		t.Fatal(err)
 	}
-	fmt.Println(result)
+	t.Log(result)
 }
 
 func TestClient_FundTransToAccountTransfer(t *testing.T) {
@@ -418,14 +407,14 @@ func TestClient_FundTransToAccountTransfer(t *testing.T) {
 	req.OutBizNo = "OC201809253000000393900404029253"
 	req.PayeeType = "ALIPAY_LOGONID"
 	req.PayeeAccount = "13951604344"
-	req.Amount = "100.00"
+	req.Amount = MustParseMoney("100.00")
 	ctx, cancelFunc := context.WithTimeout(context.Background(), 5*time.Duration(time.Second))
 	defer cancelFunc()
 	result, err := client.FundTransToAccountTransfer(ctx, req)
 	if err != nil {
-		fmt.Println(err)
+		t.Fatal(err)
 	}
-	fmt.Println(result)
+	t.Log(result)
 }
 
 func TestClient_FundTransOrderQuery(t *testing.T) {
@@ -435,14 +424,14 @@ func TestClient_FundTransOrderQuery(t *testing.T) {
 	defer cancelFunc()
 	result, err := client.FundTransOrderQuery(ctx, req)
 	if err != nil {
-		fmt.Println(err)
+		t.Fatal(err)
 	}
-	fmt.Println(result)
+	t.Log(result)
 }
 
 func TestClient_FundTransUniTransfer(t *testing.T) {
 	req := FundTransUniTransferReq{}
-	req.TransAmount = "10.20"
+	req.TransAmount = MustParseMoney("10.20")
 	req.ProductCode = TransAccountNoPwd
 	req.OutBizNo = "OC201809253000000393900404029253"
 	req.BizScene = "DIRECT_TRANSFER"
@@ -457,9 +446,9 @@ func TestClient_FundTransUniTransfer(t *testing.T) {
 	defer cancelFunc()
 	result, err := client.FundTransUniTransfer(ctx, req)
 	if err != nil {
-		fmt.Println(err)
+		t.Fatal(err)
 	}
-	fmt.Println(result)
+	t.Log(result)
 }
 
 func TestClient_FundTransCommonQuery(t *testing.T) {
@@ -471,26 +460,26 @@ func TestClient_FundTransCommonQuery(t *testing.T) {
 	defer cancelFunc()
 	result, err := client.FundTransCommonQuery(ctx, req)
 	if err != nil {
-		fmt.Println(err)
+		t.Fatal(err)
 	}
-	fmt.Println(result)
+	t.Log(result)
 }
 
 func TestClient_CommerceCityFacilitatorVoucherGenerate(t *testing.T) {
 	req := CommerceCityFacilitatorVoucherGenerateReq{}
 	req.CityCode = "440300"
 	req.TradeNo = "OC201809253000000393900404029253"
-	req.TradeFee = "10.00"
+	req.TradeFee = MustParseMoney("10.00")
 	req.TicketNum = "2"
 	req.TicketType = "oneway"
-	req.TicketPrice = "5.00"
+	req.TicketPrice = MustParseMoney("5.00")
 	ctx, cancelFunc := context.WithTimeout(context.Background(), 5*time.Duration(time.Second))
 	defer cancelFunc()
 	result, err := client.CommerceCityFacilitatorVoucherGenerate(ctx, req)
 	if err != nil {
-		fmt.Println(err)
+		t.Fatal(err)
 	}
-	fmt.Println(result)
+	t.Log(result)
 }
 func TestClient_CommerceCityFacilitatorVoucherRefund(t *testing.T) {
 	req := CommerceCityFacilitatorVoucherRefundReq{}
@@ -501,9 +490,9 @@ func TestClient_CommerceCityFacilitatorVoucherRefund(t *testing.T) {
 	defer cancelFunc()
 	result, err := client.CommerceCityFacilitatorVoucherRefund(ctx, req)
 	if err != nil {
-		fmt.Println(err)
+		t.Fatal(err)
 	}
-	fmt.Println(result)
+	t.Log(result)
 }
 
 func TestClient_CommerceCityFacilitatorStationQuery(t *testing.T) {
@@ -514,9 +503,9 @@ func TestClient_CommerceCityFacilitatorStationQuery(t *testing.T) {
 	defer cancelFunc()
 	result, err := client.CommerceCityFacilitatorStationQuery(ctx, req)
 	if err != nil {
-		fmt.Println(err)
+		t.Fatal(err)
 	}
-	fmt.Println(result)
+	t.Log(result)
 }
 
 func TestClient_CommerceCityFacilitatorVoucherBatchQuery(t *testing.T) {
@@ -528,9 +517,46 @@ func TestClient_CommerceCityFacilitatorVoucherBatchQuery(t *testing.T) {
 	defer cancelFunc()
 	result, err := client.CommerceCityFacilitatorVoucherBatchQuery(ctx, req)
 	if err != nil {
-		fmt.Println(err)
+		t.Fatal(err)
+	}
+	t.Log(result)
+}
+
+func TestClient_Execute(t *testing.T) {
+	ctx, cancelFunc := context.WithTimeout(context.Background(), 5*time.Duration(time.Second))
+	defer cancelFunc()
+	createReq, err := NewGenericReq("alipay.trade.precreate", map[string]string{"out_trade_no": "2310101012211333", "total_amount": "9.90", "subject": "测试产品"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	createRes, err := client.Execute(ctx, *createReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !createRes.Success() {
+		t.Fatalf("unexpected response: %s", createRes.Content)
+	}
+	queryReq, err := NewGenericReq("alipay.trade.query", `{"out_trade_no":"2310101012211333"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	queryRes, err := client.Execute(ctx, *queryReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := new(TradeQueryResContent)
+	if err = queryRes.Unmarshal(content); err != nil {
+		t.Fatal(err)
+	}
+	if content.TradeStatus != TradeWaitBuyerPay || content.TotalAmount != MustParseMoney("9.90") {
+		t.Errorf("unexpected response: %s", queryRes.Content)
+	}
+	if _, err = NewGenericReq("alipay.trade.query", "{"); err != ErrInvalidBizContent {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err = client.Execute(ctx, GenericReq{}); err == nil {
+		t.Error("method is required")
 	}
-	fmt.Println(result)
 }
 
 func TestName(t *testing.T) {
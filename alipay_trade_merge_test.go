@@ -0,0 +1,176 @@
+package alipay_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	. "github.com/try-labs/xpay"
+	"github.com/try-labs/xpay/alipaytest"
+)
+
+/**
+ * @author: Sam
+ * @since:
+ * @date: 2026/10/20 05:10
+ * @desc:
+ */
+
+func newMergeOrderDetail(outTradeNo, amount string) *MergeOrderDetail {
+	return &MergeOrderDetail{
+		AppId:       alipaytest.DefaultAppId,
+		OutTradeNo:  outTradeNo,
+		ProductCode: QuickWapWay,
+		TotalAmount: MustParseMoney(amount),
+		Subject:     "商品" + outTradeNo,
+	}
+}
+
+func TestTradeMergePreCreateReq_DoValidate(t *testing.T) {
+	req := TradeMergePreCreateReq{
+		OrderDetails: []*MergeOrderDetail{newMergeOrderDetail("M001", "10.00"), newMergeOrderDetail("M002", "20.00")},
+		TotalAmount:  MustParseMoney("30.00"),
+	}
+	if err := req.DoValidate(); err != nil {
+		t.Fatal(err)
+	}
+
+	duplicated := newMergeOrderDetail("M001", "5.00")
+	duplicated.ProductCode = QuickMsecurityPay
+	req.OrderDetails = append(req.OrderDetails, duplicated, newMergeOrderDetail("M003", "0"))
+	errs, ok := req.DoValidate().(ValidationErrors)
+	if !ok {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	for field, code := range map[string]string{
+		"order_details[2].out_trade_no": ValidationCodeUnique,
+		"order_details[2].product_code": ValidationCodeMismatch,
+		"order_details[3].total_amount": ValidationCodeRequired,
+		"order_details":                 ValidationCodeMismatch,
+	} {
+		if !errs.HasField(field) {
+			t.Errorf("expect error on %s, got %v", field, errs)
+			continue
+		}
+		for _, err := range errs {
+			if err.Field == field && err.Code != code {
+				t.Errorf("%s: code = %s, want %s", field, err.Code, code)
+			}
+		}
+	}
+
+	req = TradeMergePreCreateReq{}
+	for i := 0; i <= MergeMaxOrders; i++ {
+		req.OrderDetails = append(req.OrderDetails, newMergeOrderDetail(fmt.Sprintf("M1%02d", i), "1.00"))
+	}
+	errs, _ = req.DoValidate().(ValidationErrors)
+	if len(errs) != 1 || errs[0].Field != "order_details" || errs[0].Code != ValidationCodeExceed {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+}
+
+func TestClient_TradeMergePay(t *testing.T) {
+	g := alipaytest.NewGateway()
+	defer g.Close()
+	mergeClient, err := g.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	notified := make(chan *TradeMergeNotifyReq, 1)
+	merchant := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		notifyReq, err := mergeClient.TradeMergeNotify(r)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		notified <- notifyReq
+		io.WriteString(w, "success")
+	}))
+	defer merchant.Close()
+	ctx := context.Background()
+
+	paid := newMergeOrderDetail("M20261020000", "1.00")
+	if _, err = mergeClient.TradeCreate(ctx, TradeCreateReq{OutTradeNo: paid.OutTradeNo, TotalAmount: paid.TotalAmount, Subject: paid.Subject, BuyerId: alipaytest.DefaultBuyerId}); err != nil {
+		t.Fatal(err)
+	}
+	if err = g.PayTrade(paid.OutTradeNo, ""); err != nil {
+		t.Fatal(err)
+	}
+	first, second := newMergeOrderDetail("M20261020001", "30.00"), newMergeOrderDetail("M20261020002", "20.50")
+	first.PassbackParams = "seller%3D1"
+	res, err := mergeClient.TradeMergePreCreate(ctx, TradeMergePreCreateReq{
+		OutMergeNo:   "MG20261020001",
+		OrderDetails: []*MergeOrderDetail{first, second, paid},
+		NotifyUrl:    merchant.URL,
+	})
+	if err != nil || res.Fail() || len(res.PreOrderNo) == 0 {
+		t.Fatalf("%v %v", res, err)
+	}
+	if failed := res.Failed(); len(failed) != 1 || failed[0].OutTradeNo != paid.OutTradeNo || failed[0].ResultCode != alipaytest.ErrTradeHasSuccess.SubCode {
+		t.Fatalf("unexpected failed orders: %v", res)
+	}
+
+	wapURL, err := mergeClient.TradeWapMergePay(TradeWapMergePayReq{PreOrderNo: res.PreOrderNo, ReturnUrl: "https://shop.example.com/return"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wapURL.Query().Get("method") != "alipay.trade.wap.merge.pay" || wapURL.Query().Get("return_url") != "https://shop.example.com/return" {
+		t.Errorf("unexpected wap url: %s", wapURL)
+	}
+	page, err := http.Get(wapURL.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	page.Body.Close()
+	if page.StatusCode != http.StatusOK {
+		t.Errorf("cashier status = %d", page.StatusCode)
+	}
+
+	appRes, err := mergeClient.TradeAppMergePay(TradeAppMergePayReq{PreOrderNo: res.PreOrderNo})
+	if err != nil {
+		t.Fatal(err)
+	}
+	values, _ := url.ParseQuery(appRes.OrderString)
+	if values.Get("method") != "alipay.trade.app.merge.pay" || len(values.Get("sign")) == 0 {
+		t.Errorf("unexpected order string: %s", appRes)
+	}
+	merge, err := g.SubmitMergeOrderString(appRes.OrderString)
+	if err != nil || merge.TotalAmount != MustParseMoney("50.50") || len(merge.OutTradeNos) != 2 {
+		t.Fatalf("%+v %v", merge, err)
+	}
+
+	if err = g.PayMerge(res.PreOrderNo, ""); err != nil {
+		t.Fatal(err)
+	}
+	notifyReq := <-notified
+	if notifyReq.PreOrderNo != res.PreOrderNo || notifyReq.OutMergeNo != "MG20261020001" || notifyReq.TotalAmount != MustParseMoney("50.50") {
+		t.Errorf("unexpected notify: %v", notifyReq)
+	}
+	trades := notifyReq.Trades()
+	if len(trades) != 2 {
+		t.Fatalf("unexpected trades: %v", trades)
+	}
+	for i, detail := range []*MergeOrderDetail{first, second} {
+		trade := trades[i]
+		if trade.NotifyType != "trade_status_sync" || trade.OutTradeNo != detail.OutTradeNo || trade.TotalAmount != detail.TotalAmount ||
+			trade.TradeStatus != TradeSuccess || trade.BuyerId != alipaytest.DefaultBuyerId || len(trade.GmtPayment) == 0 ||
+			trade.NotifyId != notifyReq.NotifyId || len(trade.Sign) > 0 || len(trade.SignType) > 0 {
+			t.Errorf("unexpected trade notify: %v", trade)
+		}
+	}
+	if trades[0].PassbackParams != first.PassbackParams {
+		t.Errorf("passback_params = %s", trades[0].PassbackParams)
+	}
+	queryRes, err := mergeClient.TradeQuery(ctx, TradeQueryReq{OutTradeNo: second.OutTradeNo})
+	if err != nil || queryRes.TradeStatus != TradeSuccess {
+		t.Errorf("%v %v", queryRes, err)
+	}
+	g.WaitNotifications()
+	if notifications := g.Notifications(); len(notifications) != 1 {
+		t.Errorf("notifications = %d, want 1", len(notifications))
+	}
+}
@@ -1,9 +1,6 @@
 package alipay
 
-import (
-	"encoding/json"
-	"fmt"
-)
+import "encoding/json"
 
 /**
  * @author: Sam
@@ -15,8 +12,8 @@ import (
 var _ IAliPayRequest = &UserCertifyOpenInitializeReq{}
 
 type UserCertifyOpenInitializeReq struct {
-	OuterOrderNo string `json:"outer_order_no"` // 必选	32 商户请求的唯一标识，商户要保证其唯一性，值为32位长度的字母数字组合。建议：前面几位字符是商户自定义的简称，中间可以使用一段时间，后段可以使用一个随机或递增序列
-	BizCode      string `json:"biz_code"`       // 必选	32 认证场景码。入参支持的认证场景码和商户签约的认证场景相关，取值如下:
+	OuterOrderNo string `json:"outer_order_no" validate:"required,max=32"`                                    // 必选	32 商户请求的唯一标识，商户要保证其唯一性，值为32位长度的字母数字组合。建议：前面几位字符是商户自定义的简称，中间可以使用一段时间，后段可以使用一个随机或递增序列
+	BizCode      string `json:"biz_code" validate:"required,enum=FACE|CERT_PHOTO|CERT_PHOTO_FACE|SMART_FACE"` // 必选	32 认证场景码。入参支持的认证场景码和商户签约的认证场景相关，取值如下:
 	//FACE：多因子人脸认证
 	//CERT_PHOTO：多因子证照认证
 	//CERT_PHOTO_FACE ：多因子证照和人脸认证
@@ -27,15 +24,7 @@ type UserCertifyOpenInitializeReq struct {
 }
 
 func (r *UserCertifyOpenInitializeReq) DoValidate() error {
-	outerOrderNoLen := len(r.OuterOrderNo)
-	if outerOrderNoLen == 0 || outerOrderNoLen > 32 {
-		return fmt.Errorf("商户请求的唯一标识outer_order_no参数长度为%d，不符合长度1-32个字符的要求", outerOrderNoLen)
-	}
-	bizCodeLen := len(r.BizCode)
-	if bizCodeLen == 0 || bizCodeLen > 32 {
-		return fmt.Errorf("认证场景码biz_code参数长度为%d，不符合长度1-32个字符的要求", bizCodeLen)
-	}
-	return nil
+	return ValidateStruct(r)
 }
 
 func (r *UserCertifyOpenInitializeReq) RequestApi() string {
@@ -43,8 +32,8 @@ func (r *UserCertifyOpenInitializeReq) RequestApi() string {
 }
 
 type OpenCertifyIdentityParam struct {
-	IdentityType string `json:"identity_type"`       // 必选	30 1.若本人验证，使用CERT_INFO； 2.若代他人验证，使用AGENT_CERT_INFO； 枚举值 证件信息: CERT_INFO 代理人证件信息: AGENT_CERT_INFO
-	CertType     string `json:"cert_type,omitempty"` // 可选	100
+	IdentityType string `json:"identity_type" validate:"required,enum=CERT_INFO|AGENT_CERT_INFO"`                                                                                           // 必选	30 1.若本人验证，使用CERT_INFO； 2.若代他人验证，使用AGENT_CERT_INFO； 枚举值 证件信息: CERT_INFO 代理人证件信息: AGENT_CERT_INFO
+	CertType     string `json:"cert_type,omitempty" validate:"required,enum=IDENTITY_CARD|HOME_VISIT_PERMIT_HK_MC|HOME_VISIT_PERMIT_TAIWAN|RESIDENCE_PERMIT_HK_MC|RESIDENCE_PERMIT_TAIWAN"` // 可选	100
 	/*cert_type：
 	1、若为身份证，填IDENTITY_CARD；
 	2、若为港澳居民来往内地通行证，填HOME_VISIT_PERMIT_HK_MC；
@@ -59,13 +48,13 @@ type OpenCertifyIdentityParam struct {
 	台湾居民居住证: RESIDENCE_PERMIT_TAIWAN
 	注意事项: 在identity_type为CERT_INFO或者AGENT_CERT_INFO时，该字段必填
 	*/
-	CertName string `json:"cert_name,omitempty"` // 可选	50 填入真实姓名 注意事项 在identity_type为CERT_INFO或者AGENT_CERT_INFO时，该字段必填
-	CertNo   string `json:"cert_no,omitempty"`   // 可选	30  填入姓名相匹配的证件号码 注意事项 在identity_type为CERT_INFO或者AGENT_CERT_INFO时，该字段必填
+	CertName string `json:"cert_name,omitempty" validate:"required,max=50"` // 可选	50 填入真实姓名 注意事项 在identity_type为CERT_INFO或者AGENT_CERT_INFO时，该字段必填
+	CertNo   string `json:"cert_no,omitempty" validate:"required,max=30"`   // 可选	30  填入姓名相匹配的证件号码 注意事项 在identity_type为CERT_INFO或者AGENT_CERT_INFO时，该字段必填
 }
 
 type OpenCertifyMerchantConfig struct {
-	FaceReserveStrategy string `json:"face_reserve_strategy,omitempty"` //	可选	32 不传默认为reserve 枚举值 保存活体人脸: reserve 不保存活体人脸: never
-	ReturnUrl           string `json:"return_url,omitempty"`            //	必选	4096 认证成功后需要跳转的地址，一般为商户业务页面；若无跳转地址可填空字符"";
+	FaceReserveStrategy string `json:"face_reserve_strategy,omitempty" validate:"enum=reserve|never"` //	可选	32 不传默认为reserve 枚举值 保存活体人脸: reserve 不保存活体人脸: never
+	ReturnUrl           string `json:"return_url,omitempty" validate:"max=4096"`                      //	必选	4096 认证成功后需要跳转的地址，一般为商户业务页面；若无跳转地址可填空字符"";
 
 }
 
@@ -88,16 +77,12 @@ func (r *UserCertifyOpenInitializeRes) String() string {
 var _ IAliPayRequest = &UserCertifyOpenQueryReq{}
 
 type UserCertifyOpenQueryReq struct {
-	CertifyId string `json:"certify_id"` // 必选	32 本次申请操作的唯一标识，通过alipay.user.certify.open.initialize(身份认证初始化服务)接口同步响应获取。
+	CertifyId string `json:"certify_id" validate:"required,max=32"` // 必选	32 本次申请操作的唯一标识，通过alipay.user.certify.open.initialize(身份认证初始化服务)接口同步响应获取。
 	baseAliPayRequest
 }
 
 func (r *UserCertifyOpenQueryReq) DoValidate() error {
-	certifyIdLen := len(r.CertifyId)
-	if certifyIdLen == 0 || certifyIdLen > 32 {
-		return fmt.Errorf("本次申请操作的唯一标识certify_id参数长度为%d，不符合长度1-32个字符的要求", certifyIdLen)
-	}
-	return nil
+	return ValidateStruct(r)
 }
 
 func (r *UserCertifyOpenQueryReq) RequestApi() string {
@@ -126,17 +111,13 @@ func (r *UserCertifyOpenQueryRes) String() string {
 var _ IAliPayRequest = &UserCertifyOpenCertifyReq{}
 
 type UserCertifyOpenCertifyReq struct {
-	ReturnUrl string `json:"return_url,omitempty" url:"return_url,omitempty"` // 可选	256 HTTP/HTTPS开头字符串
-	CertifyId string `json:"certify_id"`                                      //	必选	32 本次申请操作的唯一标识，由开放认证初始化接口调用后生成，后续的操作都需要用到
+	ReturnUrl string `json:"return_url,omitempty" url:"return_url,omitempty" validate:"max=256"` // 可选	256 HTTP/HTTPS开头字符串
+	CertifyId string `json:"certify_id" validate:"required,max=32"`                              //	必选	32 本次申请操作的唯一标识，由开放认证初始化接口调用后生成，后续的操作都需要用到
 	IAliPayRequest
 }
 
 func (r *UserCertifyOpenCertifyReq) DoValidate() error {
-	certifyIdLen := len(r.CertifyId)
-	if certifyIdLen == 0 || certifyIdLen > 32 {
-		return fmt.Errorf("本次申请操作的唯一标识certify_id参数长度为%d，不符合长度1-32个字符的要求", certifyIdLen)
-	}
-	return nil
+	return ValidateStruct(r)
 }
 
 func (r *UserCertifyOpenCertifyReq) RequestApi() string {
@@ -144,3 +125,135 @@ func (r *UserCertifyOpenCertifyReq) RequestApi() string {
 }
 
 ////////////////////////////////////////////////
+
+var _ IAliPayRequest = &UserAgreementPageSignReq{}
+
+type UserAgreementPageSignReq struct {
+	PersonalProductCode string                     `json:"personal_product_code" validate:"required,max=64"`  // 必选	64 个人签约产品码，商户和支付宝签约时确定。周期扣款固定为 CYCLE_PAY_AUTH_P
+	ProductCode         string                     `json:"product_code,omitempty" validate:"max=64"`          // 可选	64 销售产品码，商户签约的支付宝合同所对应的产品码。周期扣款为 GENERAL_WITHHOLDING
+	SignScene           string                     `json:"sign_scene,omitempty" validate:"max=64"`            // 可选	64 协议签约场景，商户和支付宝签约时确定，如 INDUSTRY|DIGITAL_MEDIA。 查询和解约时需要传入相同的值
+	ExternalAgreementNo string                     `json:"external_agreement_no,omitempty" validate:"max=32"` // 可选	32 商户签约号，代扣协议中标示用户的唯一签约号（确保在商户系统中唯一）。
+	ExternalLogonId     string                     `json:"external_logon_id,omitempty" validate:"max=100"`    // 可选	100 用户在商户网站的登录账号，用于在签约页面展示
+	SignValidityPeriod  string                     `json:"sign_validity_period,omitempty" validate:"max=8"`   // 可选	8 当前用户签约请求的协议有效周期。 整形数字加上时间单位的协议有效期，从发起签约请求的时间开始算起。 目前支持的时间单位： 1. d：天 2. m：月 如果未传入，默认为长期有效。 2m
+	AccessParams        *AgreementAccessParams     `json:"access_params" validate:"required"`                 // 必选	 请按当前接入的方式进行填充，且输入值必须为文档中的参数取值范围。
+	PeriodRuleParams    *AgreementPeriodRuleParams `json:"period_rule_params,omitempty"`                      // 可选	 周期管控规则参数，周期扣款产品必传
+	ThirdPartyType      string                     `json:"third_party_type,omitempty" validate:"max=32"`      // 可选	32 签约第三方主体类型。对于三方协议，表示当前用户和哪一类的第三方主体进行签约。 默认为PARTNER
+	// 自己添加
+	ReturnUrl string `json:"-" url:"-"` // 可选	256 签约成功后跳转的地址
+	NotifyUrl string `json:"-" url:"-"` // 可选	256 签约成功后异步通知的地址
+	baseAliPayRequest
+}
+
+func (r *UserAgreementPageSignReq) DoValidate() error {
+	return ValidateStruct(r)
+}
+
+func (r *UserAgreementPageSignReq) RequestApi() string {
+	return "alipay.user.agreement.page.sign"
+}
+
+type AgreementAccessParams struct {
+	Channel string `json:"channel" validate:"required,enum=ALIPAYAPP|QRCODE|QRCODEORSMS"` // 必选	32 目前支持以下值： 1. ALIPAYAPP （钱包h5页面签约） 2. QRCODE(扫码签约) 3. QRCODEORSMS(扫码签约或者短信签约)
+}
+
+type AgreementPeriodRuleParams struct {
+	PeriodType    string `json:"period_type" validate:"required,enum=DAY|MONTH"`          // 必选	20 周期类型，DAY（扣款周期按天计）、MONTH（扣款周期按自然月计）
+	Period        int    `json:"period" validate:"required,range=1~9999"`                 // 必选	20 周期数，与period_type组合使用确定扣款周期，period_type为DAY时不小于7
+	ExecuteTime   string `json:"execute_time" validate:"required,len=10"`                 // 必选	20 首次扣款日期，格式为yyyy-MM-dd，之后按周期递推
+	SingleAmount  Money  `json:"single_amount" validate:"required,amount=0.01~100000000"` // 必选	20 单次扣款最大金额，单位为元
+	TotalAmount   Money  `json:"total_amount,omitempty" validate:"amount=0.01~100000000"` // 可选	20 周期内允许扣款的总金额，单位为元
+	TotalPayments int    `json:"total_payments,omitempty" validate:"range=0~9999"`        // 可选	20 总扣款次数
+}
+
+////////////////////////////////////////////////
+
+var _ IAliPayRequest = &UserAgreementQueryReq{}
+
+type UserAgreementQueryReq struct {
+	PersonalProductCode string `json:"personal_product_code,omitempty" validate:"max=64"`                 // 可选	64 个人签约产品码，未传入协议号时必填
+	AlipayUserId        string `json:"alipay_user_id,omitempty" validate:"anyof=agreement,max=16"`        // 可选	16 用户的支付宝账号对应的支付宝唯一用户号
+	AlipayLogonId       string `json:"alipay_logon_id,omitempty" validate:"anyof=agreement,max=100"`      // 可选	100 用户的支付宝登录账号
+	SignScene           string `json:"sign_scene,omitempty" validate:"max=64"`                            // 可选	64 签约协议场景，与签约时一致，未传入协议号时必填
+	ExternalAgreementNo string `json:"external_agreement_no,omitempty" validate:"anyof=agreement,max=32"` // 可选	32 商户签约号
+	ThirdPartyType      string `json:"third_party_type,omitempty" validate:"max=32"`                      // 可选	32 签约第三方主体类型，默认为PARTNER
+	AgreementNo         string `json:"agreement_no,omitempty" validate:"anyof=agreement,max=64"`          // 可选	64 支付宝协议号，传入时其他条件可以为空
+	baseAliPayRequest
+}
+
+func (r *UserAgreementQueryReq) DoValidate() error {
+	return ValidateStruct(r)
+}
+
+func (r *UserAgreementQueryReq) RequestApi() string {
+	return "alipay.user.agreement.query"
+}
+
+type UserAgreementQueryRes struct {
+	UserAgreementQueryResContent `json:"alipay_user_agreement_query_response"`
+	SignCertSn
+}
+
+type UserAgreementQueryResContent struct {
+	CommonRes
+	AgreementNo         string          `json:"agreement_no"`                    // 必选	64 用户签约成功后的协议号
+	ExternalAgreementNo string          `json:"external_agreement_no,omitempty"` // 可选	32 商户签约号
+	PersonalProductCode string          `json:"personal_product_code"`           // 必选	64 协议产品码
+	SignScene           string          `json:"sign_scene"`                      // 必选	64 签约协议的场景
+	Status              AgreementStatus `json:"status"`                          // 必选	16 协议当前状态 1. TEMP：暂存，协议未生效过； 2. NORMAL：正常； 3. STOP：暂停
+	PrincipalId         string          `json:"principal_id"`                    // 必选	16 签约主体标识，即用户的支付宝用户号
+	AlipayLogonId       string          `json:"alipay_logon_id,omitempty"`       // 可选	100 返回脱敏的支付宝账号
+	ExternalLogonId     string          `json:"external_logon_id,omitempty"`     // 可选	100 用户在商户网站的登录账号
+	ThirdPartyType      string          `json:"third_party_type,omitempty"`      // 可选	32 签约第三方主体类型
+	SignTime            string          `json:"sign_time"`                       // 必选	19 协议签约时间
+	ValidTime           string          `json:"valid_time"`                      // 必选	19 协议生效时间
+	InvalidTime         string          `json:"invalid_time"`                    // 必选	19 协议失效时间，长期有效的协议为 2115-02-01 00:00:00
+	SingleQuota         Money           `json:"single_quota,omitempty"`          // 可选	10 单笔代扣额度
+	LastDeductTime      string          `json:"last_deduct_time,omitempty"`      // 可选	19 周期扣款产品，上次扣款时间
+	NextDeductTime      string          `json:"next_deduct_time,omitempty"`      // 可选	19 周期扣款产品，预计下次扣款时间
+}
+
+func (r *UserAgreementQueryRes) String() string {
+	buff, _ := json.Marshal(r)
+	return string(buff)
+}
+
+////////////////////////////////////////////////
+
+var _ IAliPayRequest = &UserAgreementUnsignReq{}
+
+type UserAgreementUnsignReq struct {
+	AlipayUserId        string `json:"alipay_user_id,omitempty" validate:"anyof=agreement,max=16"`        // 可选	16 用户的支付宝账号对应的支付宝唯一用户号
+	AlipayLogonId       string `json:"alipay_logon_id,omitempty" validate:"anyof=agreement,max=100"`      // 可选	100 用户的支付宝登录账号
+	PersonalProductCode string `json:"personal_product_code,omitempty" validate:"max=64"`                 // 可选	64 个人签约产品码，未传入协议号时必填
+	SignScene           string `json:"sign_scene,omitempty" validate:"max=64"`                            // 可选	64 签约协议场景，未传入协议号时必填
+	ExternalAgreementNo string `json:"external_agreement_no,omitempty" validate:"anyof=agreement,max=32"` // 可选	32 商户签约号
+	ThirdPartyType      string `json:"third_party_type,omitempty" validate:"max=32"`                      // 可选	32 签约第三方主体类型，默认为PARTNER
+	AgreementNo         string `json:"agreement_no,omitempty" validate:"anyof=agreement,max=64"`          // 可选	64 支付宝协议号，传入时其他条件可以为空
+	ExtendParams        string `json:"extend_params,omitempty" validate:"max=2048"`                       // 可选	2048 扩展参数
+	OperateType         string `json:"operate_type,omitempty" validate:"enum=confirm|invalid"`            // 可选	32 注意：仅异步解约需传入，其余情况无需传递本参数。 confirm（解约确认），invalid（解约作废）
+	// 自己添加
+	NotifyUrl string `json:"-" url:"-"` // 可选	256 解约成功后异步通知的地址
+	baseAliPayRequest
+}
+
+func (r *UserAgreementUnsignReq) DoValidate() error {
+	return ValidateStruct(r)
+}
+
+func (r *UserAgreementUnsignReq) RequestApi() string {
+	return "alipay.user.agreement.unsign"
+}
+
+type UserAgreementUnsignRes struct {
+	UserAgreementUnsignResContent `json:"alipay_user_agreement_unsign_response"`
+	SignCertSn
+}
+
+type UserAgreementUnsignResContent struct {
+	CommonRes
+}
+
+func (r *UserAgreementUnsignRes) String() string {
+	buff, _ := json.Marshal(r)
+	return string(buff)
+}
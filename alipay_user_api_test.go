@@ -0,0 +1,120 @@
+package alipay_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/try-labs/xpay"
+	"github.com/try-labs/xpay/alipaytest"
+)
+
+/**
+ * @author: Sam
+ * @since:
+ * @date: 2026/10/20 00:10
+ * @desc:
+ */
+
+func TestClient_UserAgreement(t *testing.T) {
+	notified := make(chan *UserAgreementNotifyReq, 1)
+	merchant := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		notifyReq, err := client.AgreementNotify(r)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		notified <- notifyReq
+		io.WriteString(w, "success")
+	}))
+	defer merchant.Close()
+	ctx := context.Background()
+	signURL, err := client.UserAgreementPageSign(UserAgreementPageSignReq{
+		PersonalProductCode: CyclePayAuthP,
+		ProductCode:         GeneralWithholding,
+		SignScene:           "INDUSTRY|DIGITAL_MEDIA",
+		ExternalAgreementNo: "AG20261020001",
+		AccessParams:        &AgreementAccessParams{Channel: "ALIPAYAPP"},
+		PeriodRuleParams:    &AgreementPeriodRuleParams{PeriodType: "MONTH", Period: 1, ExecuteTime: "2026-10-20", SingleAmount: MustParseMoney("30.00")},
+		NotifyUrl:           merchant.URL,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signURL.Query().Get("method") != "alipay.user.agreement.page.sign" || signURL.Query().Get("notify_url") != merchant.URL {
+		t.Fatalf("unexpected url: %s", signURL)
+	}
+	response, err := http.Get(signURL.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, _ := io.ReadAll(response.Body)
+	response.Body.Close()
+	if response.StatusCode != http.StatusOK || !strings.Contains(string(page), "AG20261020001") {
+		t.Fatalf("unexpected page: %d %s", response.StatusCode, page)
+	}
+	if err = gateway.SignAgreement("AG20261020001", ""); err != nil {
+		t.Fatal(err)
+	}
+	signed := <-notified
+	if signed.Unsigned() || signed.Status != AgreementNormal || signed.ExternalAgreementNo != "AG20261020001" || len(signed.AgreementNo) == 0 {
+		t.Fatalf("unexpected notification: %+v", signed)
+	}
+
+	queryRes, err := client.UserAgreementQuery(ctx, UserAgreementQueryReq{PersonalProductCode: CyclePayAuthP, SignScene: "INDUSTRY|DIGITAL_MEDIA", ExternalAgreementNo: "AG20261020001"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !queryRes.Success() || queryRes.AgreementNo != signed.AgreementNo || queryRes.Status != AgreementNormal || queryRes.SingleQuota != MustParseMoney("30.00") {
+		t.Errorf("unexpected query result: %+v", queryRes)
+	}
+
+	payReq := TradePayReq{OutTradeNo: "20261020001001", TotalAmount: MustParseMoney("25.00"), Subject: "会员月卡", ProductCode: GeneralWithholding, AgreementParams: &AgreementParams{AgreementNo: signed.AgreementNo}}
+	payRes, err := client.TradePay(ctx, payReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !payRes.Success() || payRes.BuyerUserId != alipaytest.DefaultBuyerId {
+		t.Errorf("unexpected pay result: %+v", payRes)
+	}
+	// 按协议扣款时不传scene
+	if requests := gateway.Requests(); strings.Contains(string(requests[len(requests)-1].BizContent), `"scene"`) {
+		t.Errorf("unexpected biz_content: %s", requests[len(requests)-1].BizContent)
+	}
+	payReq.OutTradeNo, payReq.TotalAmount = "20261020001002", MustParseMoney("30.01")
+	if payRes, err = client.TradePay(ctx, payReq); err != nil || payRes.SubCode != alipaytest.ErrAgreementAmountExceed.SubCode {
+		t.Errorf("unexpected pay result: %+v %v", payRes, err)
+	}
+
+	unsignRes, err := client.UserAgreementUnsign(ctx, UserAgreementUnsignReq{AgreementNo: signed.AgreementNo})
+	if err != nil || !unsignRes.Success() {
+		t.Fatalf("unexpected unsign result: %+v %v", unsignRes, err)
+	}
+	if unsigned := <-notified; !unsigned.Unsigned() || unsigned.Status != AgreementUnsign || len(unsigned.UnsignTime) == 0 {
+		t.Errorf("unexpected notification: %+v", unsigned)
+	}
+	if queryRes, err = client.UserAgreementQuery(ctx, UserAgreementQueryReq{AgreementNo: signed.AgreementNo}); err != nil || queryRes.SubCode != alipaytest.ErrUserAgreementNotExist.SubCode {
+		t.Errorf("unexpected query result: %+v %v", queryRes, err)
+	}
+	payReq.OutTradeNo, payReq.TotalAmount = "20261020001003", MustParseMoney("25.00")
+	if payRes, err = client.TradePay(ctx, payReq); err != nil || payRes.SubCode != alipaytest.ErrAgreementNotExist.SubCode {
+		t.Errorf("unexpected pay result: %+v %v", payRes, err)
+	}
+}
+
+func TestTradePayReq_AgreementParams(t *testing.T) {
+	req := TradePayReq{OutTradeNo: "20261020001004", TotalAmount: MustParseMoney("1.00"), Subject: "会员月卡"}
+	if errs, ok := req.DoValidate().(ValidationErrors); !ok || !errs.HasField("auth_code") {
+		t.Errorf("auth_code should be required: %v", errs)
+	}
+	req.AgreementParams = &AgreementParams{}
+	if errs, ok := req.DoValidate().(ValidationErrors); !ok || errs.HasField("auth_code") || !errs.HasField("agreement_params.agreement_no") {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+	if _, err := (&UserAgreementQueryReq{PersonalProductCode: CyclePayAuthP}).DoValidate().(ValidationErrors); !err {
+		t.Error("agreement query should require agreement_no or user")
+	}
+}
@@ -0,0 +1,367 @@
+package alipay
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+/**
+ * @author: Sam
+ * @since: 1.0.0
+ * @date: 2026/10/19 11:20
+ * @desc: 基于结构体标签的参数校验
+ *
+ * 标签格式为 validate:"rule1,rule2=param"，支持的规则如下：
+ *   required                  必填
+ *   max=N / min=N / len=N     字符串按字符数（而非字节数）计算长度，切片按元素个数计算
+ *   enum=A|B|C                取值必须为其中之一，为空时不校验
+ *   amount=0.01~100000000     金额取值范围，为0时不校验
+ *   range=1~100               整数取值范围
+ *   anyof=group               同一分组的字段至少有一个不为空
+ *   required_if=Field value   字段Field的值为value时必填
+ *   required_with=Field       字段Field不为空时必填
+ *   required_without=A B      字段A、B都为空时必填，多个字段以空格分隔
+ * 结构体、结构体指针以及结构体切片类型的字段会递归校验。
+ */
+
+// 校验失败的错误码
+const (
+	ValidationCodeRequired    = "required"
+	ValidationCodeMaxLength   = "max_length"
+	ValidationCodeMinLength   = "min_length"
+	ValidationCodeLength      = "length"
+	ValidationCodeEnum        = "enum"
+	ValidationCodeAmountRange = "amount_range"
+	ValidationCodeRange       = "range"
+	ValidationCodeAnyOf       = "any_of"
+	ValidationCodeUnique      = "unique"   // 列表中的值重复
+	ValidationCodeMismatch    = "mismatch" // 汇总值与明细不一致
+	ValidationCodeExceed      = "exceed"   // 明细之和超过上限
+)
+
+const validateTagName = "validate"
+
+// FieldError 单个字段的校验错误
+type FieldError struct {
+	Field   string `json:"field"`           // 字段名，取json标签，嵌套字段形如 goods_detail[0].goods_id
+	Code    string `json:"code"`            // 错误码
+	Param   string `json:"param,omitempty"` // 规则参数，如最大长度、枚举值等
+	Message string `json:"message"`         // 错误描述
+}
+
+func (r *FieldError) Error() string {
+	return r.Message
+}
+
+// ValidationErrors 校验错误集合，包含全部未通过校验的字段
+type ValidationErrors []*FieldError
+
+func (r ValidationErrors) Error() string {
+	messages := make([]string, 0, len(r))
+	for _, fieldErr := range r {
+		messages = append(messages, fieldErr.Message)
+	}
+	return "xpay: " + strings.Join(messages, "; ")
+}
+
+// HasField 指定字段是否校验失败
+func (r ValidationErrors) HasField(field string) bool {
+	for _, fieldErr := range r {
+		if fieldErr.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateStruct 按照validate标签校验结构体，返回 ValidationErrors 或 nil
+func ValidateStruct(object interface{}) error {
+	value := reflect.ValueOf(object)
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+	var errs ValidationErrors
+	validateStruct(value, "", &errs)
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+type fieldRule struct {
+	name  string
+	param string
+}
+
+type fieldMeta struct {
+	index  int
+	name   string
+	rules  []fieldRule
+	nested bool
+}
+
+type structMeta struct {
+	fields []*fieldMeta
+	// 字段名 => 字段下标，用于 required_if 等规则引用
+	fieldIndex map[string]int
+	// anyof分组 => 分组内的字段
+	groups     map[string][]*fieldMeta
+	groupOrder []string
+}
+
+var structMetaCache sync.Map
+
+var moneyType = reflect.TypeOf(Money(0))
+
+func getStructMeta(t reflect.Type) *structMeta {
+	if cached, ok := structMetaCache.Load(t); ok {
+		return cached.(*structMeta)
+	}
+	meta := &structMeta{fieldIndex: make(map[string]int), groups: make(map[string][]*fieldMeta)}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		tag := field.Tag.Get(validateTagName)
+		if tag == "-" {
+			continue
+		}
+		meta.fieldIndex[field.Name] = i
+		item := &fieldMeta{index: i, name: jsonFieldName(field), nested: isNestedType(field.Type)}
+		if len(tag) > 0 {
+			for _, rule := range strings.Split(tag, ",") {
+				ruleName, param := rule, ""
+				if index := strings.IndexByte(rule, '='); index >= 0 {
+					ruleName, param = rule[:index], rule[index+1:]
+				}
+				if ruleName == "anyof" {
+					if _, ok := meta.groups[param]; !ok {
+						meta.groupOrder = append(meta.groupOrder, param)
+					}
+					meta.groups[param] = append(meta.groups[param], item)
+					continue
+				}
+				item.rules = append(item.rules, fieldRule{name: ruleName, param: param})
+			}
+		}
+		if len(item.rules) > 0 || item.nested {
+			meta.fields = append(meta.fields, item)
+		}
+	}
+	structMetaCache.Store(t, meta)
+	return meta
+}
+
+func jsonFieldName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if len(name) == 0 || name == "-" {
+		return field.Name
+	}
+	return name
+}
+
+func isNestedType(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return isNestedType(t.Elem())
+	case reflect.Struct:
+		return true
+	}
+	return false
+}
+
+func validateStruct(value reflect.Value, prefix string, errs *ValidationErrors) {
+	meta := getStructMeta(value.Type())
+	for _, item := range meta.fields {
+		fieldValue := value.Field(item.index)
+		path := prefix + item.name
+		for _, rule := range item.rules {
+			if fieldErr := checkRule(value, meta, fieldValue, path, rule); fieldErr != nil {
+				*errs = append(*errs, fieldErr)
+				// 必填校验失败时，不再进行该字段其他规则的校验
+				if fieldErr.Code == ValidationCodeRequired {
+					break
+				}
+			}
+		}
+		if item.nested {
+			validateNested(fieldValue, path, errs)
+		}
+	}
+	for _, group := range meta.groupOrder {
+		members := meta.groups[group]
+		names := make([]string, 0, len(members))
+		satisfied := false
+		for _, member := range members {
+			names = append(names, prefix+member.name)
+			if !value.Field(member.index).IsZero() {
+				satisfied = true
+			}
+		}
+		if !satisfied {
+			*errs = append(*errs, &FieldError{
+				Field:   strings.Join(names, "|"),
+				Code:    ValidationCodeAnyOf,
+				Param:   group,
+				Message: fmt.Sprintf("参数%s不能同时为空", strings.Join(names, "、")),
+			})
+		}
+	}
+}
+
+func validateNested(value reflect.Value, path string, errs *ValidationErrors) {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !value.IsNil() {
+			validateNested(value.Elem(), path, errs)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			validateNested(value.Index(i), path+"["+strconv.Itoa(i)+"]", errs)
+		}
+	case reflect.Struct:
+		validateStruct(value, path+".", errs)
+	}
+}
+
+func checkRule(parent reflect.Value, meta *structMeta, value reflect.Value, path string, rule fieldRule) *FieldError {
+	switch rule.name {
+	case "required":
+		if value.IsZero() {
+			return requiredError(path, "")
+		}
+	case "required_if":
+		args := strings.SplitN(rule.param, " ", 2)
+		other, ok := lookupField(parent, meta, args[0])
+		if ok && len(args) == 2 && fmt.Sprint(other.Interface()) == args[1] && value.IsZero() {
+			return requiredError(path, rule.param)
+		}
+	case "required_with":
+		other, ok := lookupField(parent, meta, rule.param)
+		if ok && !other.IsZero() && value.IsZero() {
+			return requiredError(path, rule.param)
+		}
+	case "required_without":
+		if !value.IsZero() {
+			return nil
+		}
+		for _, name := range strings.Fields(rule.param) {
+			if other, ok := lookupField(parent, meta, name); !ok || !other.IsZero() {
+				return nil
+			}
+		}
+		return requiredError(path, rule.param)
+	case "max", "min", "len":
+		if value.Type() == moneyType {
+			return nil
+		}
+		limit, _ := strconv.Atoi(rule.param)
+		length, ok := valueLength(value)
+		if !ok || length == 0 {
+			return nil
+		}
+		if rule.name == "max" && length > limit {
+			return &FieldError{Field: path, Code: ValidationCodeMaxLength, Param: rule.param,
+				Message: fmt.Sprintf("参数%s长度为%d，超过最大长度%d", path, length, limit)}
+		}
+		if rule.name == "min" && length < limit {
+			return &FieldError{Field: path, Code: ValidationCodeMinLength, Param: rule.param,
+				Message: fmt.Sprintf("参数%s长度为%d，小于最小长度%d", path, length, limit)}
+		}
+		if rule.name == "len" && length != limit {
+			return &FieldError{Field: path, Code: ValidationCodeLength, Param: rule.param,
+				Message: fmt.Sprintf("参数%s长度为%d，不符合长度%d的要求", path, length, limit)}
+		}
+	case "enum":
+		if value.IsZero() {
+			return nil
+		}
+		current := fmt.Sprint(value.Interface())
+		for _, item := range strings.Split(rule.param, "|") {
+			if item == current {
+				return nil
+			}
+		}
+		return &FieldError{Field: path, Code: ValidationCodeEnum, Param: rule.param,
+			Message: fmt.Sprintf("参数%s的值%s不合法，可选值为%s", path, current, strings.Replace(rule.param, "|", "、", -1))}
+	case "amount":
+		// Money 为0时视为未设置，*Money 不为nil时即使为0也需要校验
+		var amount Money
+		switch current := value.Interface().(type) {
+		case Money:
+			if current.IsZero() {
+				return nil
+			}
+			amount = current
+		case *Money:
+			if current == nil {
+				return nil
+			}
+			amount = *current
+		default:
+			return nil
+		}
+		bounds := strings.SplitN(rule.param, "~", 2)
+		if len(bounds) != 2 {
+			return nil
+		}
+		min, max := MustParseMoney(bounds[0]), MustParseMoney(bounds[1])
+		if !amount.InRange(min, max) {
+			return &FieldError{Field: path, Code: ValidationCodeAmountRange, Param: rule.param,
+				Message: fmt.Sprintf("参数%s金额为%s，不在取值范围[%s,%s]内", path, amount, min, max)}
+		}
+	case "range":
+		bounds := strings.SplitN(rule.param, "~", 2)
+		if len(bounds) != 2 || !isIntKind(value.Kind()) {
+			return nil
+		}
+		min, _ := strconv.ParseInt(bounds[0], 10, 64)
+		max, _ := strconv.ParseInt(bounds[1], 10, 64)
+		if current := value.Int(); current < min || current > max {
+			return &FieldError{Field: path, Code: ValidationCodeRange, Param: rule.param,
+				Message: fmt.Sprintf("参数%s的值为%d，不在取值范围[%d,%d]内", path, current, min, max)}
+		}
+	}
+	return nil
+}
+
+func isIntKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+func requiredError(path, param string) *FieldError {
+	return &FieldError{Field: path, Code: ValidationCodeRequired, Param: param, Message: fmt.Sprintf("参数%s不能为空", path)}
+}
+
+func lookupField(parent reflect.Value, meta *structMeta, name string) (reflect.Value, bool) {
+	index, ok := meta.fieldIndex[name]
+	if !ok {
+		return reflect.Value{}, false
+	}
+	return parent.Field(index), true
+}
+
+func valueLength(value reflect.Value) (int, bool) {
+	switch value.Kind() {
+	case reflect.String:
+		return utf8.RuneCountInString(value.String()), true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return value.Len(), true
+	}
+	return 0, false
+}
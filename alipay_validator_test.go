@@ -0,0 +1,133 @@
+package alipay
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+/**
+ * @author: Sam
+ * @since:
+ * @date: 2026/10/19 11:50
+ * @desc:
+ */
+
+func validationErrors(t *testing.T, err error) ValidationErrors {
+	t.Helper()
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("want ValidationErrors, got %v", err)
+	}
+	return errs
+}
+
+func TestValidateStruct_RuneLength(t *testing.T) {
+	// 256个汉字按字节计算为768，按字符计算刚好满足长度要求
+	req := NewTradePagePayReq("20150320010101001", MustParseMoney("88.88"), strings.Repeat("测", 256))
+	if err := req.DoValidate(); err != nil {
+		t.Fatal(err)
+	}
+	req.Subject += "试"
+	errs := validationErrors(t, req.DoValidate())
+	if len(errs) != 1 || errs[0].Field != "subject" || errs[0].Code != ValidationCodeMaxLength {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+}
+
+func TestValidateStruct_Aggregate(t *testing.T) {
+	req := &TradePagePayReq{ProductCode: "QUICK_WAP_WAY", QrPayMode: "4"}
+	errs := validationErrors(t, req.DoValidate())
+	for _, field := range []string{"out_trade_no", "total_amount", "subject", "product_code", "qrcode_width"} {
+		if !errs.HasField(field) {
+			t.Errorf("field %s should be invalid: %v", field, errs)
+		}
+	}
+}
+
+func TestValidateStruct_Nested(t *testing.T) {
+	goods := NewGoodsDetail(strings.Repeat("1", 33), "iphone", 0, MustParseMoney("9.99"))
+	req := NewTradePagePayReq("20150320010101001", MustParseMoney("88.88"), "iphone", WithGoodsDetail([]*GoodsDetail{goods}))
+	errs := validationErrors(t, req.DoValidate())
+	want := map[string]string{
+		"goods_detail[0].goods_id": ValidationCodeMaxLength,
+		"goods_detail[0].quantity": ValidationCodeRange,
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	for _, fieldErr := range errs {
+		if want[fieldErr.Field] != fieldErr.Code {
+			t.Errorf("unexpected error %s: %s", fieldErr.Field, fieldErr.Code)
+		}
+	}
+}
+
+func TestValidateStruct_AnyOf(t *testing.T) {
+	req := &FundTransCommonQueryReq{}
+	errs := validationErrors(t, req.DoValidate())
+	if len(errs) != 1 || errs[0].Code != ValidationCodeAnyOf {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+	req = &FundTransCommonQueryReq{OrderId: "20190801110070000006380000250621"}
+	if err := req.DoValidate(); err != nil {
+		t.Error(err)
+	}
+	// 传递了out_biz_no时product_code和biz_scene必传
+	req = &FundTransCommonQueryReq{OutBizNo: "201806300001"}
+	errs = validationErrors(t, req.DoValidate())
+	if !errs.HasField("product_code") || !errs.HasField("biz_scene") {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+}
+
+func TestValidateStruct_RequiredIf(t *testing.T) {
+	req := &OauthTokenReq{GrantType: GrantRefreshToken}
+	errs := validationErrors(t, req.DoValidate())
+	if len(errs) != 1 || errs[0].Field != "refresh_token" || errs[0].Code != ValidationCodeRequired {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+	req = &OauthTokenReq{GrantType: "password"}
+	errs = validationErrors(t, req.DoValidate())
+	if len(errs) != 1 || errs[0].Code != ValidationCodeEnum {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+	req = &OauthTokenReq{GrantType: GrantAuthorizationCode, Code: "4b203fe6c11548bcabd8da5bb087a83b"}
+	if err := req.DoValidate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestValidateStruct_Amount(t *testing.T) {
+	req := &FundTransUniTransferReq{
+		OutBizNo:    "201806300001",
+		TransAmount: MustParseMoney("0.09"),
+		ProductCode: "TRANS_ACCOUNT_NO_PWD",
+		BizScene:    "DIRECT_TRANSFER",
+		OrderTitle:  "201905代发",
+		PayeeInfo:   Participant{Identity: "2088123412341234", IdentityType: "ALIPAY_USER_ID"},
+	}
+	errs := validationErrors(t, req.DoValidate())
+	if len(errs) != 1 || errs[0].Field != "trans_amount" || errs[0].Code != ValidationCodeAmountRange {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+	req.TransAmount = MustParseMoney("0.10")
+	req.PayeeInfo.IdentityType = "ALIPAY_LOGON_ID"
+	errs = validationErrors(t, req.DoValidate())
+	if len(errs) != 1 || errs[0].Field != "payee_info.name" {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+}
+
+func TestValidateStruct_AmountPointer(t *testing.T) {
+	zero, negative := Money(0), MustParseMoney("-1.00")
+	req := &TradeCreateReq{OutTradeNo: "20261020001", TotalAmount: MustParseMoney("1.00"), Subject: "测试", BuyerId: "2088102177846880", UndiscountableAmount: &zero}
+	if err := req.DoValidate(); err != nil {
+		t.Errorf("0.00 should be valid: %v", err)
+	}
+	req.DiscountableAmount = &negative
+	errs := validationErrors(t, req.DoValidate())
+	if len(errs) != 1 || errs[0].Field != "discountable_amount" || errs[0].Code != ValidationCodeAmountRange {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+}
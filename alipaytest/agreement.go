@@ -0,0 +1,238 @@
+package alipaytest
+
+import (
+	"html/template"
+	"time"
+
+	alipay "github.com/try-labs/xpay"
+)
+
+/**
+ * @author: Sam
+ * @since: 1.0.0
+ * @date: 2026/10/20 00:10
+ * @desc: 代扣协议的内存状态及相关接口
+ *
+ * 访问 alipay.user.agreement.page.sign 的签约页面后创建暂存(TEMP)的协议，通过 SignAgreement 模拟用户确认签约，
+ * 签约、解约后向签约时的 notify_url 发送 dut_user_sign、dut_user_unsign 通知。
+ */
+
+// Agreement 网关中的代扣协议
+type Agreement struct {
+	AgreementNo         string                 // 支付宝协议号
+	ExternalAgreementNo string                 // 商户签约号
+	PersonalProductCode string                 // 个人签约产品码
+	SignScene           string                 // 签约场景
+	ExternalLogonId     string                 // 用户在商户网站的登录账号
+	Status              alipay.AgreementStatus // 协议状态
+	AlipayUserId        string                 // 签约用户
+	SingleAmount        alipay.Money           // 单次扣款最大金额，为0时不限制
+	NotifyUrl           string                 // 异步通知地址
+	SignTime            time.Time              // 签约时间
+	UnsignTime          time.Time              // 解约时间
+}
+
+// agreementInvalidTime 长期有效协议的失效时间
+const agreementInvalidTime = "2115-02-01 00:00:00"
+
+// agreementContent 签约、查询、解约接口共用的业务参数
+type agreementContent struct {
+	AgreementNo         string `json:"agreement_no"`
+	ExternalAgreementNo string `json:"external_agreement_no"`
+	PersonalProductCode string `json:"personal_product_code"`
+	SignScene           string `json:"sign_scene"`
+	AlipayUserId        string `json:"alipay_user_id"`
+	ExternalLogonId     string `json:"external_logon_id"`
+	PeriodRuleParams    *struct {
+		SingleAmount alipay.Money `json:"single_amount"`
+	} `json:"period_rule_params"`
+}
+
+// Agreement 按商户签约号获取协议的副本
+func (g *Gateway) Agreement(externalAgreementNo string) (Agreement, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	agreement, ok := g.agreements[externalAgreementNo]
+	if !ok {
+		return Agreement{}, false
+	}
+	return *agreement, true
+}
+
+// SignAgreement 模拟用户在签约页面确认签约，alipayUserId为空时使用 DefaultBuyerId
+func (g *Gateway) SignAgreement(externalAgreementNo, alipayUserId string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	agreement, ok := g.agreements[externalAgreementNo]
+	if !ok {
+		return ErrUserAgreementNotExist
+	}
+	if agreement.Status != alipay.AgreementTemp {
+		return ErrAgreementStatusNotNormal
+	}
+	if len(alipayUserId) == 0 {
+		alipayUserId = DefaultBuyerId
+	}
+	agreement.AgreementNo = g.nextSeq("6200")
+	agreement.AlipayUserId = alipayUserId
+	agreement.Status = alipay.AgreementNormal
+	agreement.SignTime = g.Now()
+	g.notifyAgreement(agreement)
+	return nil
+}
+
+// findAgreement 按协议号、商户签约号或者签约用户查找协议
+func (g *Gateway) findAgreement(content *agreementContent) (*Agreement, error) {
+	for _, agreement := range g.agreements {
+		if agreement.Status == alipay.AgreementTemp || agreement.Status == alipay.AgreementUnsign {
+			continue
+		}
+		switch {
+		case len(content.AgreementNo) > 0:
+			if agreement.AgreementNo == content.AgreementNo {
+				return agreement, nil
+			}
+		case len(content.ExternalAgreementNo) > 0:
+			if agreement.ExternalAgreementNo == content.ExternalAgreementNo {
+				return agreement, nil
+			}
+		case agreement.AlipayUserId == content.AlipayUserId && agreement.PersonalProductCode == content.PersonalProductCode && agreement.SignScene == content.SignScene:
+			return agreement, nil
+		}
+	}
+	return nil, ErrUserAgreementNotExist
+}
+
+// notifyAgreement 签约或者解约后发送通知
+func (g *Gateway) notifyAgreement(agreement *Agreement) {
+	notifyURL := agreement.NotifyUrl
+	if len(notifyURL) == 0 {
+		notifyURL = g.notifyURL
+	}
+	if len(notifyURL) == 0 {
+		return
+	}
+	notifyType := alipay.NotifyTypeAgreementSign
+	if agreement.Status == alipay.AgreementUnsign {
+		notifyType = alipay.NotifyTypeAgreementUnsign
+	}
+	values := g.NotifyValues(notifyType)
+	fields := map[string]string{
+		"agreement_no":          agreement.AgreementNo,
+		"external_agreement_no": agreement.ExternalAgreementNo,
+		"personal_product_code": agreement.PersonalProductCode,
+		"sign_scene":            agreement.SignScene,
+		"status":                string(agreement.Status),
+		"alipay_user_id":        agreement.AlipayUserId,
+		"alipay_logon_id":       DefaultBuyerLogonId,
+		"external_logon_id":     agreement.ExternalLogonId,
+		"partner_id":            DefaultSellerId,
+		"sign_time":             g.formatTime(agreement.SignTime),
+		"valid_time":            g.formatTime(agreement.SignTime),
+		"invalid_time":          agreementInvalidTime,
+		"unsign_time":           g.formatTime(agreement.UnsignTime),
+	}
+	for key, value := range fields {
+		if len(value) > 0 {
+			values.Set(key, value)
+		}
+	}
+	g.Notify(notifyURL, values)
+}
+
+// agreementPay 按代扣协议扣款，扣款金额不能超过签约时的单次扣款最大金额
+func (g *Gateway) agreementPay(trade *Trade, agreementNo string) error {
+	agreement, err := g.findAgreement(&agreementContent{AgreementNo: agreementNo})
+	if err != nil {
+		return ErrAgreementNotExist
+	}
+	if agreement.Status != alipay.AgreementNormal {
+		return ErrAgreementStatusNotNormal
+	}
+	if agreement.SingleAmount.IsPositive() && trade.TotalAmount.Cmp(agreement.SingleAmount) > 0 {
+		return ErrAgreementAmountExceed
+	}
+	trade.BuyerId = agreement.AlipayUserId
+	g.payTrade(trade)
+	return nil
+}
+
+func handleUserAgreementPageSign(g *Gateway, req *Request) (interface{}, error) {
+	content := new(agreementContent)
+	if err := req.Bind(content); err != nil {
+		return nil, ErrInvalidParameter
+	}
+	if len(content.PersonalProductCode) == 0 || len(content.ExternalAgreementNo) == 0 {
+		return nil, ErrInvalidParameter
+	}
+	agreement, ok := g.agreements[content.ExternalAgreementNo]
+	if !ok {
+		agreement = &Agreement{
+			ExternalAgreementNo: content.ExternalAgreementNo,
+			PersonalProductCode: content.PersonalProductCode,
+			SignScene:           content.SignScene,
+			ExternalLogonId:     content.ExternalLogonId,
+			Status:              alipay.AgreementTemp,
+			NotifyUrl:           req.NotifyUrl,
+		}
+		if content.PeriodRuleParams != nil {
+			agreement.SingleAmount = content.PeriodRuleParams.SingleAmount
+		}
+		g.agreements[agreement.ExternalAgreementNo] = agreement
+	}
+	return *agreement, nil
+}
+
+func handleUserAgreementQuery(g *Gateway, req *Request) (interface{}, error) {
+	content := new(agreementContent)
+	if err := req.Bind(content); err != nil {
+		return nil, ErrInvalidParameter
+	}
+	agreement, err := g.findAgreement(content)
+	if err != nil {
+		return nil, err
+	}
+	return alipay.UserAgreementQueryResContent{
+		CommonRes:           success,
+		AgreementNo:         agreement.AgreementNo,
+		ExternalAgreementNo: agreement.ExternalAgreementNo,
+		PersonalProductCode: agreement.PersonalProductCode,
+		SignScene:           agreement.SignScene,
+		Status:              agreement.Status,
+		PrincipalId:         agreement.AlipayUserId,
+		ExternalLogonId:     agreement.ExternalLogonId,
+		SignTime:            g.formatTime(agreement.SignTime),
+		ValidTime:           g.formatTime(agreement.SignTime),
+		InvalidTime:         agreementInvalidTime,
+		SingleQuota:         agreement.SingleAmount,
+	}, nil
+}
+
+func handleUserAgreementUnsign(g *Gateway, req *Request) (interface{}, error) {
+	content := new(agreementContent)
+	if err := req.Bind(content); err != nil {
+		return nil, ErrInvalidParameter
+	}
+	agreement, err := g.findAgreement(content)
+	if err != nil {
+		return nil, err
+	}
+	agreement.Status = alipay.AgreementUnsign
+	agreement.UnsignTime = g.Now()
+	if len(req.NotifyUrl) > 0 {
+		agreement.NotifyUrl = req.NotifyUrl
+	}
+	g.notifyAgreement(agreement)
+	return alipay.UserAgreementUnsignResContent{CommonRes: success}, nil
+}
+
+var agreementTemplate = template.Must(template.New("agreement").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>支付宝签约</title></head>
+<body>
+<p>商户签约号：{{.ExternalAgreementNo}}</p>
+<p>签约产品码：{{.PersonalProductCode}}</p>
+<p>协议状态：{{.Status}}</p>
+</body>
+</html>
+`))
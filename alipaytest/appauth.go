@@ -0,0 +1,149 @@
+package alipaytest
+
+import (
+	"strconv"
+	"time"
+
+	alipay "github.com/try-labs/xpay"
+)
+
+/**
+ * @author: Sam
+ * @since: 1.0.0
+ * @date: 2026/10/20 03:20
+ * @desc: 第三方应用授权
+ *
+ * IssueAppAuthCode 模拟商户在授权页面完成授权，返回的授权码只能换取一次令牌。
+ * 刷新令牌后原来的 app_auth_token 和 app_refresh_token 立即失效，
+ * 请求中携带失效或者不存在的 app_auth_token 时返回 ErrInvalidAppAuthToken。
+ */
+
+const (
+	// AppAuthExpiresIn 应用授权令牌的有效期，单位秒
+	AppAuthExpiresIn = 31536000
+	// AppAuthReExpiresIn 刷新令牌的有效期，单位秒
+	AppAuthReExpiresIn = 32140800
+)
+
+// AppAuth 网关中的应用授权
+type AppAuth struct {
+	UserId          string // 授权商户的user_id
+	AuthAppId       string // 授权商户的appid
+	AppAuthToken    string // 应用授权令牌
+	AppRefreshToken string // 刷新令牌
+	AuthStart       string // 授权生效时间
+	AuthEnd         string // 授权失效时间
+	Status          string // valid 或者 invalid
+}
+
+// IssueAppAuthCode 模拟商户完成授权，返回回调地址中的app_auth_code
+func (g *Gateway) IssueAppAuthCode(userId, authAppId string) string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	code := "P" + g.nextSeq("5000")
+	g.appAuthCodes[code] = &AppAuth{UserId: userId, AuthAppId: authAppId}
+	return code
+}
+
+// AppAuth 按应用授权令牌获取授权的副本
+func (g *Gateway) AppAuth(appAuthToken string) (AppAuth, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	auth, ok := g.appAuths[appAuthToken]
+	if !ok {
+		return AppAuth{}, false
+	}
+	return *auth, true
+}
+
+// checkAppAuthToken 请求中携带了应用授权令牌时校验令牌是否有效
+func (g *Gateway) checkAppAuthToken(req *Request) error {
+	if len(req.AppAuthToken) == 0 {
+		return nil
+	}
+	if auth, ok := g.appAuths[req.AppAuthToken]; !ok || auth.Status != "valid" {
+		return ErrInvalidAppAuthToken
+	}
+	return nil
+}
+
+// issueAppAuthToken 生成新的令牌，原令牌失效
+func (g *Gateway) issueAppAuthToken(origin *AppAuth) *AppAuth {
+	if len(origin.AppAuthToken) > 0 {
+		origin.Status = "invalid"
+	}
+	now := g.Now()
+	auth := &AppAuth{
+		UserId:          origin.UserId,
+		AuthAppId:       origin.AuthAppId,
+		AppAuthToken:    "202610BB" + g.nextSeq("5001"),
+		AppRefreshToken: "202610BR" + g.nextSeq("5002"),
+		AuthStart:       g.formatTime(now),
+		AuthEnd:         g.formatTime(now.Add(AppAuthExpiresIn * time.Second)),
+		Status:          "valid",
+	}
+	g.appAuths[auth.AppAuthToken] = auth
+	return auth
+}
+
+func handleOpenAuthTokenApp(g *Gateway, req *Request) (interface{}, error) {
+	content := new(alipay.OpenAuthTokenAppReq)
+	if err := req.Bind(content); err != nil {
+		return nil, ErrInvalidParameter
+	}
+	var auth *AppAuth
+	switch content.GrantType {
+	case alipay.GrantAuthorizationCode:
+		origin, ok := g.appAuthCodes[content.Code]
+		if !ok {
+			return nil, ErrInvalidAppAuthCode
+		}
+		delete(g.appAuthCodes, content.Code)
+		auth = g.issueAppAuthToken(origin)
+	case alipay.GrantRefreshToken:
+		var origin *AppAuth
+		for _, item := range g.appAuths {
+			if item.AppRefreshToken == content.RefreshToken && item.Status == "valid" {
+				origin = item
+				break
+			}
+		}
+		if origin == nil {
+			return nil, ErrInvalidAppRefreshToken
+		}
+		auth = g.issueAppAuthToken(origin)
+	default:
+		return nil, ErrInvalidParameter
+	}
+	return alipay.OpenAuthTokenAppResContent{
+		CommonRes:       success,
+		UserId:          auth.UserId,
+		AuthAppId:       auth.AuthAppId,
+		AppAuthToken:    auth.AppAuthToken,
+		AppRefreshToken: auth.AppRefreshToken,
+		ExpiresIn:       strconv.Itoa(AppAuthExpiresIn),
+		ReExpiresIn:     strconv.Itoa(AppAuthReExpiresIn),
+	}, nil
+}
+
+func handleOpenAuthTokenAppQuery(g *Gateway, req *Request) (interface{}, error) {
+	content := new(alipay.OpenAuthTokenAppQueryReq)
+	if err := req.Bind(content); err != nil {
+		return nil, ErrInvalidParameter
+	}
+	auth, ok := g.appAuths[content.AppAuthToken]
+	if !ok {
+		return nil, ErrInvalidAppAuthToken
+	}
+	return alipay.OpenAuthTokenAppQueryResContent{
+		CommonRes:   success,
+		UserId:      auth.UserId,
+		AuthAppId:   auth.AuthAppId,
+		ExpiresIn:   AppAuthExpiresIn,
+		AuthMethods: []string{"alipay.trade.precreate", "alipay.trade.query", "alipay.trade.refund"},
+		AuthStart:   auth.AuthStart,
+		AuthEnd:     auth.AuthEnd,
+		Status:      auth.Status,
+		IsByAppAuth: true,
+	}, nil
+}
@@ -0,0 +1,301 @@
+package alipaytest
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	alipay "github.com/try-labs/xpay"
+)
+
+/**
+ * @author: Sam
+ * @since: 1.0.0
+ * @date: 2026/10/19 23:40
+ * @desc: 批量转账的内存状态及相关接口
+ *
+ * 批次创建后为 WAIT_PAY，通过 PayBatch 模拟付款方确认支付，逐笔转账后发送 alipay.fund.batch.order.changed 消息通知。
+ */
+
+// Batch 网关中的转账批次
+type Batch struct {
+	OutBatchNo     string         // 商户批次号
+	BatchTransId   string         // 支付宝批次单号
+	ProductCode    string         // 销售产品码
+	BizScene       string         // 业务场景
+	OrderTitle     string         // 批次标题
+	Status         string         // 批次状态
+	TotalAmount    alipay.Money   // 批次总金额
+	PassbackParams string         // 回传参数
+	Details        []*BatchDetail // 转账明细
+	GmtCreate      time.Time      // 创建时间
+	GmtFinish      time.Time      // 完成时间
+}
+
+// BatchDetail 批次中的一笔转账明细
+type BatchDetail struct {
+	DetailId    string             // 支付宝明细单号
+	OutBizNo    string             // 商户明细订单号
+	TransAmount alipay.Money       // 明细金额
+	PayeeInfo   alipay.Participant // 收款方
+	Remark      string             // 明细备注
+	Status      string             // 明细状态
+	ErrorCode   string             // 失败的错误码
+	FailReason  string             // 失败的原因
+}
+
+const (
+	batchWaitPay        = "WAIT_PAY"
+	batchPartialSuccess = "PARTIAL_SUCCESS"
+	batchDisuse         = "DISUSE"
+)
+
+// defaultBatchPageSize 明细查询未指定每页笔数时的默认值
+const defaultBatchPageSize = 20
+
+// Batch 按商户批次号获取批次的副本
+func (g *Gateway) Batch(outBatchNo string) (Batch, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	batch, ok := g.batches[outBatchNo]
+	if !ok {
+		return Batch{}, false
+	}
+	copied := *batch
+	copied.Details = make([]*BatchDetail, 0, len(batch.Details))
+	for _, detail := range batch.Details {
+		detail := *detail
+		copied.Details = append(copied.Details, &detail)
+	}
+	return copied, true
+}
+
+// PayBatch 模拟付款方确认支付批次，逐笔扣除余额，余额不足或者通过 SetTransferStatus 预设为 FAIL 的明细转账失败，
+// 完成后向 WithNotifyURL 设置的地址发送消息通知
+func (g *Gateway) PayBatch(outBatchNo string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	batch, ok := g.batches[outBatchNo]
+	if !ok {
+		return ErrBatchNotExist
+	}
+	if batch.Status != batchWaitPay {
+		return ErrBatchStatusError
+	}
+	var success, fail int
+	for _, detail := range batch.Details {
+		result, preset := g.transferResults[detail.OutBizNo]
+		switch {
+		case preset && result.status == transferFail:
+			delete(g.transferResults, detail.OutBizNo)
+			detail.Status, detail.ErrorCode, detail.FailReason = transferFail, result.errorCode, result.failReason
+		case g.balance.Cmp(detail.TransAmount) < 0:
+			detail.Status, detail.ErrorCode, detail.FailReason = transferFail, ErrPayerBalanceNotEnough.SubCode, ErrPayerBalanceNotEnough.SubMsg
+		default:
+			g.balance = g.balance.Sub(detail.TransAmount)
+			detail.Status = transferSuccess
+		}
+		if detail.Status == transferSuccess {
+			success++
+		} else {
+			fail++
+		}
+	}
+	switch {
+	case fail == 0:
+		batch.Status = transferSuccess
+	case success == 0:
+		batch.Status = transferFail
+	default:
+		batch.Status = batchPartialSuccess
+	}
+	batch.GmtFinish = g.Now()
+	g.notifyBatch(batch)
+	return nil
+}
+
+// batchSummary 批次的成功、失败金额及笔数
+func batchSummary(batch *Batch) (successAmount, failAmount alipay.Money, successCount, failCount int) {
+	for _, detail := range batch.Details {
+		switch detail.Status {
+		case transferSuccess:
+			successAmount, successCount = successAmount.Add(detail.TransAmount), successCount+1
+		case transferFail:
+			failAmount, failCount = failAmount.Add(detail.TransAmount), failCount+1
+		}
+	}
+	return
+}
+
+// notifyBatch 批次完成或者关闭时发送 alipay.fund.batch.order.changed 消息通知
+func (g *Gateway) notifyBatch(batch *Batch) {
+	if len(g.notifyURL) == 0 {
+		return
+	}
+	successAmount, failAmount, successCount, failCount := batchSummary(batch)
+	bizContent, _ := json.Marshal(alipay.FundBatchOrderChanged{
+		BatchTransId:     batch.BatchTransId,
+		OutBatchNo:       batch.OutBatchNo,
+		ProductCode:      batch.ProductCode,
+		BizScene:         batch.BizScene,
+		BatchStatus:      batch.Status,
+		TotalAmount:      batch.TotalAmount,
+		SuccessAmount:    successAmount,
+		FailAmount:       failAmount,
+		TotalItemCount:   len(batch.Details),
+		SuccessItemCount: successCount,
+		FailItemCount:    failCount,
+		PassbackParams:   batch.PassbackParams,
+		GmtFinish:        g.formatTime(batch.GmtFinish),
+	})
+	values := g.NotifyValues("")
+	for _, key := range []string{"notify_time", "notify_type", "auth_app_id"} {
+		values.Del(key)
+	}
+	values.Set("msg_method", alipay.FundBatchOrderChangedMethod)
+	values.Set("utc_timestamp", strconv.FormatInt(g.Now().UnixNano()/int64(time.Millisecond), 10))
+	values.Set("version", "1.1")
+	values.Set("biz_content", string(bizContent))
+	g.Notify(g.notifyURL, values)
+}
+
+func (g *Gateway) findBatch(batchTransId, outBatchNo string) (*Batch, error) {
+	if len(batchTransId) == 0 {
+		if batch, ok := g.batches[outBatchNo]; ok {
+			return batch, nil
+		}
+		return nil, ErrBatchNotExist
+	}
+	for _, batch := range g.batches {
+		if batch.BatchTransId == batchTransId {
+			return batch, nil
+		}
+	}
+	return nil, ErrBatchNotExist
+}
+
+func handleFundBatchUniTransfer(g *Gateway, req *Request) (interface{}, error) {
+	content := new(alipay.FundBatchUniTransferReq)
+	if err := req.Bind(content); err != nil {
+		return nil, ErrInvalidParameter
+	}
+	batch, ok := g.batches[content.OutBatchNo]
+	if !ok {
+		batch = &Batch{
+			OutBatchNo:     content.OutBatchNo,
+			BatchTransId:   g.nextSeq("2000"),
+			ProductCode:    content.ProductCode,
+			BizScene:       content.BizScene,
+			OrderTitle:     content.OrderTitle,
+			Status:         batchWaitPay,
+			TotalAmount:    content.TotalTransAmount,
+			PassbackParams: content.PassbackParams,
+			GmtCreate:      g.Now(),
+		}
+		for _, order := range content.TransOrderList {
+			batch.Details = append(batch.Details, &BatchDetail{
+				DetailId:    g.nextSeq("2100"),
+				OutBizNo:    order.OutBizNo,
+				TransAmount: order.TransAmount,
+				PayeeInfo:   order.PayeeInfo,
+				Remark:      order.Remark,
+				Status:      "INIT",
+			})
+		}
+		g.batches[batch.OutBatchNo] = batch
+	}
+	return alipay.FundBatchUniTransferResContent{
+		CommonRes:    success,
+		OutBatchNo:   batch.OutBatchNo,
+		BatchTransId: batch.BatchTransId,
+		Status:       batch.Status,
+	}, nil
+}
+
+func handleFundBatchDetailQuery(g *Gateway, req *Request) (interface{}, error) {
+	content := new(alipay.FundBatchDetailQueryReq)
+	if err := req.Bind(content); err != nil {
+		return nil, ErrInvalidParameter
+	}
+	batch, err := g.findBatch(content.BatchTransId, content.OutBatchNo)
+	if err != nil {
+		return nil, err
+	}
+	details := make([]*alipay.BatchTransDetail, 0, len(batch.Details))
+	for _, detail := range batch.Details {
+		if len(content.DetailStatus) > 0 && detail.Status != content.DetailStatus {
+			continue
+		}
+		if len(content.OutBizNo) > 0 && detail.OutBizNo != content.OutBizNo {
+			continue
+		}
+		details = append(details, &alipay.BatchTransDetail{
+			DetailId:    detail.DetailId,
+			OutBizNo:    detail.OutBizNo,
+			TransAmount: detail.TransAmount,
+			Status:      detail.Status,
+			PayeeInfo:   detail.PayeeInfo,
+			ErrorCode:   detail.ErrorCode,
+			FailReason:  detail.FailReason,
+			Remark:      detail.Remark,
+			GmtCreate:   g.formatTime(batch.GmtCreate),
+			GmtFinish:   g.formatTime(batch.GmtFinish),
+		})
+	}
+	pageNum, pageSize := content.PageNum, content.PageSize
+	if pageNum < 1 {
+		pageNum = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultBatchPageSize
+	}
+	totalPageCount := (len(details) + pageSize - 1) / pageSize
+	start, end := (pageNum-1)*pageSize, pageNum*pageSize
+	if start > len(details) {
+		start = len(details)
+	}
+	if end > len(details) {
+		end = len(details)
+	}
+	successAmount, failAmount, successCount, failCount := batchSummary(batch)
+	return alipay.FundBatchDetailQueryResContent{
+		CommonRes:        success,
+		BatchTransId:     batch.BatchTransId,
+		OutBatchNo:       batch.OutBatchNo,
+		ProductCode:      batch.ProductCode,
+		BizScene:         batch.BizScene,
+		BatchStatus:      batch.Status,
+		TotalAmount:      batch.TotalAmount,
+		SuccessAmount:    successAmount,
+		FailAmount:       failAmount,
+		TotalItemCount:   len(batch.Details),
+		SuccessItemCount: successCount,
+		FailItemCount:    failCount,
+		GmtCreate:        g.formatTime(batch.GmtCreate),
+		GmtFinish:        g.formatTime(batch.GmtFinish),
+		PageNum:          pageNum,
+		PageSize:         pageSize,
+		TotalPageCount:   totalPageCount,
+		AccDetailList:    details[start:end],
+	}, nil
+}
+
+func handleFundBatchClose(g *Gateway, req *Request) (interface{}, error) {
+	content := new(alipay.FundBatchCloseReq)
+	if err := req.Bind(content); err != nil {
+		return nil, ErrInvalidParameter
+	}
+	batch, err := g.findBatch(content.BatchTransId, "")
+	if err != nil {
+		return nil, err
+	}
+	if batch.Status != batchWaitPay && batch.Status != batchDisuse {
+		return nil, ErrBatchStatusError
+	}
+	if batch.Status == batchWaitPay {
+		batch.Status = batchDisuse
+		batch.GmtFinish = g.Now()
+		g.notifyBatch(batch)
+	}
+	return alipay.FundBatchCloseResContent{CommonRes: success, BatchTransId: batch.BatchTransId, Status: batch.Status}, nil
+}
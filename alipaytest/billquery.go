@@ -0,0 +1,238 @@
+package alipaytest
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	alipay "github.com/try-labs/xpay"
+)
+
+/**
+ * @author: Sam
+ * @since: 1.0.0
+ * @date: 2026/10/20 01:40
+ * @desc: 账务明细及交易、转账账单查询
+ *
+ * 账务明细由网关中已支付的交易、退款和转账生成，卖出交易为已支付的交易，买入交易始终为空。
+ * 起止时间间隔超过31天或者结束时间不晚于起始时间时返回参数无效，与支付宝一致。
+ */
+
+// billQueryContent 账单查询接口共用的业务参数
+type billQueryContent struct {
+	StartTime       string `json:"start_time"`
+	EndTime         string `json:"end_time"`
+	AlipayOrderNo   string `json:"alipay_order_no"`
+	MerchantOrderNo string `json:"merchant_order_no"`
+	PageNo          string `json:"page_no"`
+	PageSize        string `json:"page_size"`
+}
+
+// billQuery 解析后的查询条件
+type billQuery struct {
+	*billQueryContent
+	start, end       time.Time
+	pageNo, pageSize int
+}
+
+// billEntry 按时间排序的账单明细
+type billEntry struct {
+	at              time.Time
+	seq             string
+	alipayOrderNo   string
+	merchantOrderNo string
+	item            interface{}
+}
+
+func (g *Gateway) parseBillQuery(req *Request) (*billQuery, error) {
+	query := &billQuery{billQueryContent: new(billQueryContent), pageNo: 1, pageSize: 2000}
+	if err := req.Bind(query.billQueryContent); err != nil {
+		return nil, ErrInvalidParameter
+	}
+	var err error
+	if query.start, err = time.ParseInLocation(time.DateTime, query.StartTime, g.location); err != nil {
+		return nil, ErrInvalidParameter
+	}
+	if query.end, err = time.ParseInLocation(time.DateTime, query.EndTime, g.location); err != nil {
+		return nil, ErrInvalidParameter
+	}
+	if !query.end.After(query.start) || query.end.Sub(query.start) > alipay.BillQueryMaxRange {
+		return nil, ErrInvalidParameter
+	}
+	if pageNo, err := strconv.Atoi(query.PageNo); err == nil && pageNo > 0 {
+		query.pageNo = pageNo
+	}
+	if pageSize, err := strconv.Atoi(query.PageSize); err == nil && pageSize > 0 {
+		query.pageSize = pageSize
+	}
+	return query, nil
+}
+
+// page 过滤并排序后返回当前页的明细及总条数
+func (q *billQuery) page(entries []*billEntry) ([]interface{}, int) {
+	matched := make([]*billEntry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.at.Before(q.start) || !entry.at.Before(q.end) {
+			continue
+		}
+		if len(q.AlipayOrderNo) > 0 && entry.alipayOrderNo != q.AlipayOrderNo || len(q.MerchantOrderNo) > 0 && entry.merchantOrderNo != q.MerchantOrderNo {
+			continue
+		}
+		matched = append(matched, entry)
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		if matched[i].at.Equal(matched[j].at) {
+			return matched[i].seq < matched[j].seq
+		}
+		return matched[i].at.Before(matched[j].at)
+	})
+	start, end := (q.pageNo-1)*q.pageSize, q.pageNo*q.pageSize
+	if start > len(matched) {
+		start = len(matched)
+	}
+	if end > len(matched) {
+		end = len(matched)
+	}
+	items := make([]interface{}, 0, end-start)
+	for _, entry := range matched[start:end] {
+		items = append(items, entry.item)
+	}
+	return items, len(matched)
+}
+
+// accountLogs 由交易、退款和转账生成的账务明细
+func (g *Gateway) accountLogs() []*billEntry {
+	var entries []*billEntry
+	for _, trade := range g.trades {
+		if trade.GmtPayment.IsZero() {
+			continue
+		}
+		entries = append(entries, &billEntry{at: trade.GmtPayment, seq: trade.TradeNo, alipayOrderNo: trade.TradeNo, merchantOrderNo: trade.OutTradeNo, item: &alipay.AccountLogItem{
+			TransDt:         g.formatTime(trade.GmtPayment),
+			AccountLogId:    trade.TradeNo + "01",
+			AlipayOrderNo:   trade.TradeNo,
+			MerchantOrderNo: trade.OutTradeNo,
+			TransAmount:     trade.TotalAmount,
+			Type:            "在线支付",
+			OtherAccount:    trade.BuyerLogonId,
+			TransMemo:       trade.Subject,
+			Direction:       "收入",
+			BizDesc:         "交易",
+		}})
+	}
+	for _, refund := range g.refunds {
+		entries = append(entries, &billEntry{at: refund.GmtRefund, seq: refund.TradeNo + refund.OutRequestNo, alipayOrderNo: refund.TradeNo, merchantOrderNo: refund.OutTradeNo, item: &alipay.AccountLogItem{
+			TransDt:             g.formatTime(refund.GmtRefund),
+			AccountLogId:        refund.TradeNo + refund.OutRequestNo,
+			AlipayOrderNo:       refund.TradeNo,
+			MerchantOrderNo:     refund.OutTradeNo,
+			TransAmount:         alipay.Money(0).Sub(refund.RefundAmount),
+			Type:                "交易退款",
+			TransMemo:           refund.RefundReason,
+			Direction:           "支出",
+			BizDesc:             "退款",
+			MerchantOutRefundNo: refund.OutRequestNo,
+		}})
+	}
+	for _, transfer := range g.transfers {
+		if transfer.Status != transferSuccess {
+			continue
+		}
+		entries = append(entries, &billEntry{at: transfer.TransDate, seq: transfer.OrderId, alipayOrderNo: transfer.OrderId, merchantOrderNo: transfer.OutBizNo, item: &alipay.AccountLogItem{
+			TransDt:         g.formatTime(transfer.TransDate),
+			AccountLogId:    transfer.PayFundOrderId,
+			AlipayOrderNo:   transfer.OrderId,
+			MerchantOrderNo: transfer.OutBizNo,
+			TransAmount:     alipay.Money(0).Sub(transfer.TransAmount),
+			Type:            "转账",
+			OtherAccount:    transfer.PayeeIdentity,
+			TransMemo:       transfer.OrderTitle,
+			Direction:       "支出",
+			BizDesc:         "单笔转账",
+		}})
+	}
+	return entries
+}
+
+func handleDataBillAccountLogQuery(g *Gateway, req *Request) (interface{}, error) {
+	query, err := g.parseBillQuery(req)
+	if err != nil {
+		return nil, err
+	}
+	items, total := query.page(g.accountLogs())
+	res := alipay.DataBillAccountLogQueryResContent{CommonRes: success, PageNo: strconv.Itoa(query.pageNo), PageSize: strconv.Itoa(query.pageSize), TotalSize: strconv.Itoa(total)}
+	for _, item := range items {
+		res.DetailList = append(res.DetailList, item.(*alipay.AccountLogItem))
+	}
+	return res, nil
+}
+
+func handleDataBillSellQuery(g *Gateway, req *Request) (interface{}, error) {
+	query, err := g.parseBillQuery(req)
+	if err != nil {
+		return nil, err
+	}
+	var entries []*billEntry
+	for _, trade := range g.trades {
+		if trade.GmtPayment.IsZero() {
+			continue
+		}
+		status := "成功"
+		if trade.Status == alipay.TradeClosed {
+			status = "关闭"
+		}
+		entries = append(entries, &billEntry{at: trade.GmtCreate, seq: trade.TradeNo, alipayOrderNo: trade.TradeNo, merchantOrderNo: trade.OutTradeNo, item: &alipay.TradeBillItem{
+			GmtCreate:         g.formatTime(trade.GmtCreate),
+			GmtPay:            g.formatTime(trade.GmtPayment),
+			AlipayOrderNo:     trade.TradeNo,
+			MerchantOrderNo:   trade.OutTradeNo,
+			TotalAmount:       trade.TotalAmount,
+			OrderStatus:       status,
+			OrderType:         "即时到账交易",
+			GoodsTitle:        trade.Subject,
+			OtherAccount:      trade.BuyerLogonId,
+			TradeRefundAmount: trade.RefundAmount,
+		}})
+	}
+	items, total := query.page(entries)
+	res := alipay.DataBillSellQueryResContent{CommonRes: success, PageNo: strconv.Itoa(query.pageNo), PageSize: strconv.Itoa(query.pageSize), TotalSize: strconv.Itoa(total)}
+	for _, item := range items {
+		res.DetailList = append(res.DetailList, item.(*alipay.TradeBillItem))
+	}
+	return res, nil
+}
+
+func handleDataBillBuyQuery(g *Gateway, req *Request) (interface{}, error) {
+	query, err := g.parseBillQuery(req)
+	if err != nil {
+		return nil, err
+	}
+	return alipay.DataBillBuyQueryResContent{CommonRes: success, PageNo: strconv.Itoa(query.pageNo), PageSize: strconv.Itoa(query.pageSize), TotalSize: "0"}, nil
+}
+
+func handleDataBillTransferQuery(g *Gateway, req *Request) (interface{}, error) {
+	query, err := g.parseBillQuery(req)
+	if err != nil {
+		return nil, err
+	}
+	var entries []*billEntry
+	for _, transfer := range g.transfers {
+		entries = append(entries, &billEntry{at: transfer.TransDate, seq: transfer.OrderId, alipayOrderNo: transfer.OrderId, merchantOrderNo: transfer.OutBizNo, item: &alipay.TransferBillItem{
+			TransDt:         g.formatTime(transfer.TransDate),
+			AlipayOrderNo:   transfer.OrderId,
+			MerchantOrderNo: transfer.OutBizNo,
+			AccountLogId:    transfer.PayFundOrderId,
+			Type:            "TRANSFER_OUT",
+			Amount:          transfer.TransAmount,
+			Status:          transfer.Status,
+			OtherAccount:    transfer.PayeeIdentity,
+			TransMemo:       transfer.OrderTitle,
+		}})
+	}
+	items, total := query.page(entries)
+	res := alipay.DataBillTransferQueryResContent{CommonRes: success, PageNo: strconv.Itoa(query.pageNo), PageSize: strconv.Itoa(query.pageSize), TotalSize: strconv.Itoa(total)}
+	for _, item := range items {
+		res.DetailList = append(res.DetailList, item.(*alipay.TransferBillItem))
+	}
+	return res, nil
+}
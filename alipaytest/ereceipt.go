@@ -0,0 +1,125 @@
+package alipaytest
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	alipay "github.com/try-labs/xpay"
+)
+
+/**
+ * @author: Sam
+ * @since: 1.0.0
+ * @date: 2026/10/20 01:10
+ * @desc: 电子回单的内存状态及相关接口
+ *
+ * 申请后的回单为 INIT，查询次数超过 SetEreceiptPending 设置的次数后生成，交易回单的支付宝交易号不存在时生成失败。
+ */
+
+// Ereceipt 网关中的电子回单申请
+type Ereceipt struct {
+	FileId       string // 文件申请号
+	Type         string // 申请的类型
+	Key          string // 账务流水号或者支付宝交易号
+	Status       string // INIT、SUCCESS、FAIL
+	ErrorMessage string // 生成失败的原因
+	Queries      int    // 查询次数
+}
+
+// Ereceipt 按文件申请号获取回单申请的副本
+func (g *Gateway) Ereceipt(fileId string) (Ereceipt, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	ereceipt, ok := g.ereceipts[fileId]
+	if !ok {
+		return Ereceipt{}, false
+	}
+	return *ereceipt, true
+}
+
+// SetEreceiptPending 设置回单生成前返回 INIT 的查询次数，默认1次
+func (g *Gateway) SetEreceiptPending(queries int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.ereceiptPending = queries
+}
+
+// FailEreceipt 预设该key的回单生成失败，对之后的申请生效
+func (g *Gateway) FailEreceipt(key, errorMessage string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.ereceiptErrors[key] = errorMessage
+}
+
+// EreceiptPDF 回单下载地址返回的PDF内容
+func EreceiptPDF(ereceipt Ereceipt) []byte {
+	return []byte(fmt.Sprintf("%%PDF-1.4\n%% %s %s %s\n%%%%EOF\n", ereceipt.FileId, ereceipt.Type, ereceipt.Key))
+}
+
+func (g *Gateway) ereceiptURL(fileId string) string {
+	return g.URL + ereceiptPath + "?" + url.Values{"file_id": {fileId}}.Encode()
+}
+
+func (g *Gateway) serveEreceipt(w http.ResponseWriter, request *http.Request) {
+	g.mu.Lock()
+	ereceipt, ok := g.ereceipts[request.URL.Query().Get("file_id")]
+	if ok && ereceipt.Status != alipay.EreceiptStatusSuccess {
+		ok = false
+	}
+	var data []byte
+	if ok {
+		data = EreceiptPDF(*ereceipt)
+	}
+	g.mu.Unlock()
+	if !ok {
+		http.NotFound(w, request)
+		return
+	}
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Write(data)
+}
+
+func handleDataBillEreceiptApply(g *Gateway, req *Request) (interface{}, error) {
+	content := new(alipay.DataBillEreceiptApplyReq)
+	if err := req.Bind(content); err != nil {
+		return nil, ErrInvalidParameter
+	}
+	if content.Type != alipay.EreceiptTypeFundDetail && content.Type != alipay.EreceiptTypeTrade || len(content.Key) == 0 {
+		return nil, ErrInvalidParameter
+	}
+	ereceipt := &Ereceipt{FileId: g.nextSeq("3000"), Type: content.Type, Key: content.Key, Status: alipay.EreceiptStatusInit}
+	if message, ok := g.ereceiptErrors[content.Key]; ok {
+		ereceipt.ErrorMessage = message
+	} else if _, ok = g.tradeNos[content.Key]; content.Type == alipay.EreceiptTypeTrade && !ok {
+		ereceipt.ErrorMessage = ErrTradeNotExist.SubMsg
+	}
+	g.ereceipts[ereceipt.FileId] = ereceipt
+	return alipay.DataBillEreceiptApplyResContent{CommonRes: success, FileId: ereceipt.FileId}, nil
+}
+
+func handleDataBillEreceiptQuery(g *Gateway, req *Request) (interface{}, error) {
+	content := new(alipay.DataBillEreceiptQueryReq)
+	if err := req.Bind(content); err != nil {
+		return nil, ErrInvalidParameter
+	}
+	ereceipt, ok := g.ereceipts[content.FileId]
+	if !ok {
+		return nil, ErrEreceiptNotExist
+	}
+	ereceipt.Queries++
+	if ereceipt.Status == alipay.EreceiptStatusInit && ereceipt.Queries > g.ereceiptPending {
+		ereceipt.Status = alipay.EreceiptStatusSuccess
+		if len(ereceipt.ErrorMessage) > 0 {
+			ereceipt.Status = alipay.EreceiptStatusFail
+		}
+	}
+	res := alipay.DataBillEreceiptQueryResContent{CommonRes: success, Status: ereceipt.Status}
+	switch ereceipt.Status {
+	case alipay.EreceiptStatusSuccess:
+		res.DownloadUrl = g.ereceiptURL(ereceipt.FileId)
+	case alipay.EreceiptStatusFail:
+		res.ErrorMessage = ereceipt.ErrorMessage
+	}
+	return res, nil
+}
@@ -0,0 +1,111 @@
+package alipaytest
+
+import (
+	"fmt"
+
+	alipay "github.com/try-labs/xpay"
+)
+
+/**
+ * @author: Sam
+ * @since: 1.0.0
+ * @date: 2026/10/19 16:05
+ * @desc: 网关返回的错误码，可通过 Gateway.InjectError 注入到指定接口
+ */
+
+// Error 网关返回的错误
+type Error struct {
+	Code    string
+	Msg     string
+	SubCode string
+	SubMsg  string
+	// 公共参数错误放在error_response节点返回
+	errorResponse bool
+}
+
+// NewError 创建业务错误，code为40004
+func NewError(subCode, subMsg string) *Error {
+	return &Error{Code: "40004", Msg: "Business Failed", SubCode: subCode, SubMsg: subMsg}
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("alipaytest: %s %s, %s %s", e.Code, e.Msg, e.SubCode, e.SubMsg)
+}
+
+func (e *Error) commonRes() alipay.CommonRes {
+	return alipay.CommonRes{Code: e.Code, Msg: e.Msg, SubCode: e.SubCode, SubMsg: e.SubMsg}
+}
+
+// 公共错误
+var (
+	ErrSystemError             = &Error{Code: "20000", Msg: "Service Currently Unavailable", SubCode: "isp.unknow-error", SubMsg: "系统繁忙"}
+	ErrMissingMethod           = &Error{Code: "40001", Msg: "Missing Required Arguments", SubCode: "isv.missing-method", SubMsg: "缺少方法名参数", errorResponse: true}
+	ErrInvalidAppId            = &Error{Code: "40002", Msg: "Invalid Arguments", SubCode: "isv.invalid-app-id", SubMsg: "无效的AppID参数", errorResponse: true}
+	ErrInvalidSignType         = &Error{Code: "40002", Msg: "Invalid Arguments", SubCode: "isv.invalid-signature-type", SubMsg: "无效的签名类型", errorResponse: true}
+	ErrInvalidSignature        = &Error{Code: "40002", Msg: "Invalid Arguments", SubCode: "isv.invalid-signature", SubMsg: "验签出错", errorResponse: true}
+	ErrInvalidAppCertSn        = &Error{Code: "40002", Msg: "Invalid Arguments", SubCode: "isv.invalid-app-cert-sn", SubMsg: "应用公钥证书序列号不匹配", errorResponse: true}
+	ErrInvalidAlipayRootCertSn = &Error{Code: "40002", Msg: "Invalid Arguments", SubCode: "isv.invalid-alipay-root-cert-sn", SubMsg: "支付宝根证书序列号不匹配", errorResponse: true}
+	ErrInvalidMethod           = &Error{Code: "40002", Msg: "Invalid Arguments", SubCode: "isv.invalid-method", SubMsg: "不存在的方法名", errorResponse: true}
+	ErrInvalidParameter        = &Error{Code: "40002", Msg: "Invalid Arguments", SubCode: "isv.invalid-parameter", SubMsg: "参数无效"}
+	ErrInvalidAppAuthToken     = &Error{Code: "20001", Msg: "Insufficient Token Permissions", SubCode: "aop.invalid-app-auth-token", SubMsg: "无效的应用授权令牌", errorResponse: true}
+	ErrInvalidAuthToken        = &Error{Code: "20001", Msg: "Insufficient Token Permissions", SubCode: "aop.invalid-auth-token", SubMsg: "无效的访问令牌"}
+	ErrAuthTokenTimeout        = &Error{Code: "20001", Msg: "Insufficient Token Permissions", SubCode: "aop.auth-token-time-out", SubMsg: "访问令牌已过期"}
+)
+
+// 交易相关错误
+var (
+	ErrTradeNotExist         = NewError("ACQ.TRADE_NOT_EXIST", "交易不存在")
+	ErrTradeHasSuccess       = NewError("ACQ.TRADE_HAS_SUCCESS", "交易已被支付")
+	ErrTradeHasClose         = NewError("ACQ.TRADE_HAS_CLOSE", "交易已经关闭")
+	ErrTradeStatusError      = NewError("ACQ.TRADE_STATUS_ERROR", "交易状态不合法")
+	ErrContextInconsistent   = NewError("ACQ.CONTEXT_INCONSISTENT", "交易信息被篡改")
+	ErrRefundAmountExceed    = NewError("ACQ.REFUND_AMT_NOT_EQUAL_TOTAL", "退款金额超限")
+	ErrPaymentFail           = NewError("ACQ.PAYMENT_FAIL", "支付失败")
+	ErrBuyerBalanceNotEnough = NewError("ACQ.BUYER_BALANCE_NOT_ENOUGH", "买家余额不足")
+	ErrBillNotExist          = NewError("isp.bill_not_exist", "账单不存在")
+	ErrEreceiptNotExist      = NewError("FILE_NOT_EXIST", "回单申请不存在")
+	ErrPreOrderNotExist      = NewError("ACQ.PRE_ORDER_NOT_EXIST", "预下单号不存在或者已过期")
+	ErrTradeHasMerged        = NewError("ACQ.TRADE_HAS_MERGED", "交易已加入其他合并支付")
+)
+
+// 分账相关错误
+var (
+	ErrRoyaltyReceiverNotBind = NewError("ACQ.ROYALTY_RECEIVER_NOT_BIND", "分账接收方未绑定分账关系")
+	ErrAllocAmountExceed      = NewError("ACQ.ALLOC_AMOUNT_VALIDATE_ERROR", "分账金额超过最大可分账金额")
+	ErrSettleFinished         = NewError("ACQ.TRADE_SETTLE_FINISHED", "交易分账已完结")
+	ErrSettleNotExist         = NewError("ACQ.SETTLE_NOT_EXIST", "分账请求不存在")
+)
+
+// 应用授权及用户授权相关错误
+var (
+	ErrInvalidAppAuthCode     = NewError("isv.code-invalid", "授权码code无效")
+	ErrInvalidAppRefreshToken = NewError("isv.refresh-token-invalid", "刷新令牌refresh_token无效")
+	ErrInvalidRefreshToken    = NewError("isv.refresh-token-time-out", "刷新令牌refresh_token过期或者无效")
+)
+
+// 资金授权相关错误
+var (
+	ErrAuthOrderNotExist     = NewError("AUTH_ORDER_NOT_EXIST", "资金授权订单不存在")
+	ErrAuthOrderStatusError  = NewError("AUTH_ORDER_STATUS_ERROR", "资金授权订单状态不合法")
+	ErrAuthOperationNotExist = NewError("AUTH_OPERATION_NOT_EXIST", "资金操作流水不存在")
+	ErrUnfreezeAmountExceed  = NewError("UNFREEZE_AMOUNT_LARGER_THAN_REST_AMOUNT", "解冻金额大于剩余冻结金额")
+	ErrAuthPayAmountExceed   = NewError("ACQ.PAY_AMOUNT_LARGER_THAN_REST_AMOUNT", "支付金额大于剩余冻结金额")
+)
+
+// 代扣协议相关错误
+var (
+	ErrUserAgreementNotExist    = NewError("USER_AGREEMENT_NOT_EXIST", "用户协议不存在")
+	ErrAgreementNotExist        = NewError("ACQ.AGREEMENT_NOT_EXIST", "用户协议不存在")
+	ErrAgreementStatusNotNormal = NewError("ACQ.AGREEMENT_STATUS_NOT_NORMAL", "用户协议状态非NORMAL")
+	ErrAgreementAmountExceed    = NewError("ACQ.AGREEMENT_AMOUNT_EXCEED", "扣款金额超过协议约定的单次扣款金额")
+)
+
+// 资金相关错误
+var (
+	ErrPayerBalanceNotEnough = NewError("PAYER_BALANCE_NOT_ENOUGH", "付款方余额不足")
+	ErrTransSystemError      = NewError("SYSTEM_ERROR", "系统繁忙")
+	ErrPayeeNotExist         = NewError("PAYEE_NOT_EXIST", "收款账号不存在")
+	ErrOrderNotExist         = NewError("ORDER_NOT_EXIST", "转账订单不存在")
+	ErrBatchNotExist         = NewError("BATCH_NOT_EXIST", "批次不存在")
+	ErrBatchStatusError      = NewError("BATCH_STATUS_ERROR", "批次状态不支持该操作")
+)
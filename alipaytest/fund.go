@@ -0,0 +1,236 @@
+package alipaytest
+
+import (
+	"time"
+
+	alipay "github.com/try-labs/xpay"
+)
+
+/**
+ * @author: Sam
+ * @since: 1.0.0
+ * @date: 2026/10/19 16:40
+ * @desc: 转账的内存状态及资金相关接口
+ */
+
+// Transfer 网关中的转账单
+type Transfer struct {
+	Method            string       // 转账接口
+	OutBizNo          string       // 商户转账单号
+	OrderId           string       // 支付宝转账单号
+	PayFundOrderId    string       // 支付宝支付资金流水号
+	TransAmount       alipay.Money // 转账金额
+	Status            string       // 转账状态
+	PayeeIdentity     string       // 收款方标识
+	PayeeIdentityType string       // 收款方标识类型
+	PayeeName         string       // 收款方姓名
+	OrderTitle        string       // 转账标题
+	TransDate         time.Time    // 转账时间
+	ErrorCode         string       // 转账失败或者退票的错误码
+	FailReason        string       // 转账失败或者退票的原因
+}
+
+const (
+	transferSuccess = "SUCCESS"
+	transferFail    = "FAIL"
+	transferRefund  = "REFUND"
+)
+
+// transferResult 通过 SetTransferStatus 预设的转账状态
+type transferResult struct {
+	status     string
+	errorCode  string
+	failReason string
+}
+
+// Transfer 按商户转账单号获取转账单的副本
+func (g *Gateway) Transfer(outBizNo string) (Transfer, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	transfer, ok := g.transfers[outBizNo]
+	if !ok {
+		return Transfer{}, false
+	}
+	return *transfer, true
+}
+
+// SetTransferStatus 设置转账单的状态，用于模拟处理中、转账失败和退票。
+// 转账单尚未创建时在创建后生效，FAIL 的转账单在创建时直接返回失败；转为 FAIL 或者 REFUND 时退回扣除的余额
+func (g *Gateway) SetTransferStatus(outBizNo, status, errorCode, failReason string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	result := &transferResult{status: status, errorCode: errorCode, failReason: failReason}
+	transfer, ok := g.transfers[outBizNo]
+	if !ok {
+		g.transferResults[outBizNo] = result
+		return
+	}
+	g.applyTransferResult(transfer, result)
+}
+
+func (g *Gateway) applyTransferResult(transfer *Transfer, result *transferResult) {
+	deducted := transfer.Status != transferFail && transfer.Status != transferRefund
+	transfer.Status, transfer.ErrorCode, transfer.FailReason = result.status, result.errorCode, result.failReason
+	if deducted && (result.status == transferFail || result.status == transferRefund) {
+		g.balance = g.balance.Add(transfer.TransAmount)
+	}
+}
+
+// Balance 账户当前余额
+func (g *Gateway) Balance() alipay.Money {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.balance
+}
+
+// SetBalance 设置账户余额
+func (g *Gateway) SetBalance(balance alipay.Money) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.balance = balance
+}
+
+// createTransfer 同一商户转账单号重复请求时返回原转账单，不会重复扣款
+func (g *Gateway) createTransfer(transfer *Transfer) (*Transfer, error) {
+	if exist, ok := g.transfers[transfer.OutBizNo]; ok {
+		return exist, nil
+	}
+	if !transfer.TransAmount.IsPositive() {
+		return nil, ErrInvalidParameter
+	}
+	if g.balance.Cmp(transfer.TransAmount) < 0 {
+		return nil, ErrPayerBalanceNotEnough
+	}
+	g.balance = g.balance.Sub(transfer.TransAmount)
+	transfer.OrderId = g.nextSeq("1100")
+	transfer.PayFundOrderId = g.nextSeq("0001")
+	transfer.Status = transferSuccess
+	transfer.TransDate = g.Now()
+	g.transfers[transfer.OutBizNo] = transfer
+	if result, ok := g.transferResults[transfer.OutBizNo]; ok {
+		delete(g.transferResults, transfer.OutBizNo)
+		g.applyTransferResult(transfer, result)
+	}
+	return transfer, nil
+}
+
+func (g *Gateway) findTransfer(outBizNo, orderId, payFundOrderId string) (*Transfer, error) {
+	for _, transfer := range g.transfers {
+		switch {
+		case len(payFundOrderId) > 0:
+			if transfer.PayFundOrderId == payFundOrderId {
+				return transfer, nil
+			}
+		case len(orderId) > 0:
+			if transfer.OrderId == orderId {
+				return transfer, nil
+			}
+		case transfer.OutBizNo == outBizNo:
+			return transfer, nil
+		}
+	}
+	return nil, ErrOrderNotExist
+}
+
+func handleFundTransUniTransfer(g *Gateway, req *Request) (interface{}, error) {
+	content := new(alipay.FundTransUniTransferReq)
+	if err := req.Bind(content); err != nil {
+		return nil, ErrInvalidParameter
+	}
+	transfer, err := g.createTransfer(&Transfer{
+		Method:            req.Method,
+		OutBizNo:          content.OutBizNo,
+		TransAmount:       content.TransAmount,
+		PayeeIdentity:     content.PayeeInfo.Identity,
+		PayeeIdentityType: content.PayeeInfo.IdentityType,
+		PayeeName:         content.PayeeInfo.Name,
+		OrderTitle:        content.OrderTitle,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if transfer.Status == transferFail {
+		return nil, NewError(transfer.ErrorCode, transfer.FailReason)
+	}
+	return alipay.FundTransUniTransferResContent{
+		CommonRes:      success,
+		OutBizNo:       transfer.OutBizNo,
+		OrderId:        transfer.OrderId,
+		PayFundOrderId: transfer.PayFundOrderId,
+		Status:         transfer.Status,
+		TransDate:      g.formatTime(transfer.TransDate),
+	}, nil
+}
+
+func handleFundTransToAccountTransfer(g *Gateway, req *Request) (interface{}, error) {
+	content := new(alipay.FundTransToAccountTransferReq)
+	if err := req.Bind(content); err != nil {
+		return nil, ErrInvalidParameter
+	}
+	transfer, err := g.createTransfer(&Transfer{
+		Method:            req.Method,
+		OutBizNo:          content.OutBizNo,
+		TransAmount:       content.Amount,
+		PayeeIdentity:     content.PayeeAccount,
+		PayeeIdentityType: content.PayeeType,
+		PayeeName:         content.PayeeRealName,
+		OrderTitle:        content.Remark,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return alipay.FundTransToAccountTransferResContent{
+		CommonRes: success,
+		OutBizNo:  transfer.OutBizNo,
+		OrderId:   transfer.OrderId,
+		PayDate:   g.formatTime(transfer.TransDate),
+	}, nil
+}
+
+func handleFundTransOrderQuery(g *Gateway, req *Request) (interface{}, error) {
+	content := new(alipay.FundTransOrderQueryReq)
+	if err := req.Bind(content); err != nil {
+		return nil, ErrInvalidParameter
+	}
+	transfer, err := g.findTransfer(content.OutBizNo, content.OrderId, "")
+	if err != nil {
+		return nil, err
+	}
+	return alipay.FundTransOrderQueryResContent{
+		CommonRes: success,
+		OrderId:   transfer.OrderId,
+		Status:    transfer.Status,
+		PayDate:   g.formatTime(transfer.TransDate),
+		OutBizNo:  transfer.OutBizNo,
+	}, nil
+}
+
+func handleFundTransCommonQuery(g *Gateway, req *Request) (interface{}, error) {
+	content := new(alipay.FundTransCommonQueryReq)
+	if err := req.Bind(content); err != nil {
+		return nil, ErrInvalidParameter
+	}
+	transfer, err := g.findTransfer(content.OutBizNo, content.OrderId, content.PayFundOrderId)
+	if err != nil {
+		return nil, err
+	}
+	return alipay.FundTransCommonQueryResContent{
+		CommonRes:      success,
+		OrderId:        transfer.OrderId,
+		PayFundOrderId: transfer.PayFundOrderId,
+		OutBizNo:       transfer.OutBizNo,
+		TransAmount:    transfer.TransAmount,
+		Status:         transfer.Status,
+		PayDate:        g.formatTime(transfer.TransDate),
+		ErrorCode:      transfer.ErrorCode,
+		FailReason:     transfer.FailReason,
+	}, nil
+}
+
+func handleFundAccountQuery(g *Gateway, req *Request) (interface{}, error) {
+	return alipay.FundAccountQueryResContent{CommonRes: success, AvailableAmount: g.balance}, nil
+}
+
+func handleDataBillBalanceQuery(g *Gateway, req *Request) (interface{}, error) {
+	return alipay.DataBillBalanceQueryResContent{CommonRes: success, TotalAmount: g.balance, AvailableAmount: g.balance}, nil
+}
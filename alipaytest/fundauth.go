@@ -0,0 +1,418 @@
+package alipaytest
+
+import (
+	"net/url"
+	"time"
+
+	alipay "github.com/try-labs/xpay"
+)
+
+/**
+ * @author: Sam
+ * @since: 1.0.0
+ * @date: 2026/10/20 02:40
+ * @desc: 资金预授权的内存状态及相关接口
+ *
+ * 当面冻结直接成功；app冻结和发码冻结创建 INIT 的授权订单，通过 ConfirmFundAuth 模拟用户确认授权。
+ * 冻结、解冻成功后向请求时的 notify_url 发送 fund_auth_freeze、fund_auth_unfreeze 通知。
+ * alipay.trade.pay 传入 auth_no 时从剩余冻结金额中转支付，auth_confirm_mode 为 COMPLETE 时解冻剩余金额。
+ */
+
+// FundAuth 网关中的资金授权订单
+type FundAuth struct {
+	AuthNo              string               // 支付宝资金授权订单号
+	OutOrderNo          string               // 商户授权资金订单号
+	OrderTitle          string               // 订单标题
+	Status              string               // 授权订单状态，INIT、AUTHORIZED、FINISH、CLOSED
+	TotalFreezeAmount   alipay.Money         // 累计冻结金额
+	TotalUnfreezeAmount alipay.Money         // 累计解冻金额
+	TotalPayAmount      alipay.Money         // 累计转支付金额
+	RestAmount          alipay.Money         // 剩余冻结金额
+	PayerUserId         string               // 付款方支付宝用户号
+	ExtraParam          string               // 业务扩展参数
+	NotifyUrl           string               // 异步通知地址
+	Operations          []*FundAuthOperation // 资金操作流水，按操作顺序排列
+}
+
+// FundAuthOperation 资金授权操作流水
+type FundAuthOperation struct {
+	OperationId   string       // 支付宝资金操作流水号
+	OutRequestNo  string       // 商户请求流水号，转支付时为商户订单号
+	OperationType string       // FREEZE、UNFREEZE、PAY
+	Amount        alipay.Money // 操作金额
+	Status        string       // INIT、SUCCESS、CLOSED
+	Remark        string       // 附言
+	GmtCreate     time.Time    // 创建时间
+	GmtTrans      time.Time    // 成功时间
+}
+
+// fundAuthContent 资金授权接口共用的业务参数
+type fundAuthContent struct {
+	AuthNo        string       `json:"auth_no"`
+	OutOrderNo    string       `json:"out_order_no"`
+	OperationId   string       `json:"operation_id"`
+	OutRequestNo  string       `json:"out_request_no"`
+	OperationType string       `json:"operation_type"`
+	OrderTitle    string       `json:"order_title"`
+	Amount        alipay.Money `json:"amount"`
+	AuthCode      string       `json:"auth_code"`
+	Remark        string       `json:"remark"`
+	ExtraParam    string       `json:"extra_param"`
+}
+
+// FundAuth 按商户授权资金订单号获取授权订单的副本
+func (g *Gateway) FundAuth(outOrderNo string) (FundAuth, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	auth, ok := g.fundAuths[outOrderNo]
+	if !ok {
+		return FundAuth{}, false
+	}
+	copied := *auth
+	copied.Operations = make([]*FundAuthOperation, 0, len(auth.Operations))
+	for _, operation := range auth.Operations {
+		item := *operation
+		copied.Operations = append(copied.Operations, &item)
+	}
+	return copied, true
+}
+
+// ConfirmFundAuth 模拟用户确认app冻结或者扫码冻结，payerUserId为空时使用 DefaultBuyerId
+func (g *Gateway) ConfirmFundAuth(outOrderNo, payerUserId string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	auth, ok := g.fundAuths[outOrderNo]
+	if !ok {
+		return ErrAuthOrderNotExist
+	}
+	if auth.Status != alipay.FundAuthOrderInit {
+		return ErrAuthOrderStatusError
+	}
+	if len(payerUserId) == 0 {
+		payerUserId = DefaultBuyerId
+	}
+	auth.PayerUserId = payerUserId
+	g.freezeSuccess(auth, auth.Operations[0])
+	return nil
+}
+
+// SubmitFundAuthOrderString 模拟支付宝客户端提交 alipay.fund.auth.order.app.freeze 的订单串，校验签名并创建待授权的订单
+func (g *Gateway) SubmitFundAuthOrderString(orderString string) (FundAuth, error) {
+	values, err := url.ParseQuery(orderString)
+	if err != nil {
+		return FundAuth{}, err
+	}
+	req, err := g.parseRequest(values)
+	if err != nil {
+		return FundAuth{}, err
+	}
+	content := new(fundAuthContent)
+	if err = req.Bind(content); err != nil {
+		return FundAuth{}, ErrInvalidParameter
+	}
+	if _, err = g.dispatch(req); err != nil {
+		return FundAuth{}, err
+	}
+	auth, _ := g.FundAuth(content.OutOrderNo)
+	return auth, nil
+}
+
+// createFundAuth 创建授权订单及冻结操作，商户授权资金订单号已存在时返回原订单
+func (g *Gateway) createFundAuth(req *Request, content *fundAuthContent) (*FundAuth, error) {
+	if len(content.OutOrderNo) == 0 || len(content.OutRequestNo) == 0 || !content.Amount.IsPositive() {
+		return nil, ErrInvalidParameter
+	}
+	if auth, ok := g.fundAuths[content.OutOrderNo]; ok {
+		if auth.Operations[0].OutRequestNo != content.OutRequestNo || auth.Operations[0].Amount != content.Amount {
+			return nil, ErrContextInconsistent
+		}
+		return auth, nil
+	}
+	auth := &FundAuth{
+		AuthNo:     g.nextSeq("2001"),
+		OutOrderNo: content.OutOrderNo,
+		OrderTitle: content.OrderTitle,
+		Status:     alipay.FundAuthOrderInit,
+		ExtraParam: content.ExtraParam,
+		NotifyUrl:  req.NotifyUrl,
+	}
+	auth.Operations = append(auth.Operations, &FundAuthOperation{
+		OperationId:   g.nextSeq("0020"),
+		OutRequestNo:  content.OutRequestNo,
+		OperationType: alipay.FundAuthOperationFreeze,
+		Amount:        content.Amount,
+		Status:        alipay.FundAuthOperationInit,
+		GmtCreate:     g.Now(),
+	})
+	g.fundAuths[auth.OutOrderNo] = auth
+	g.fundAuthNos[auth.AuthNo] = auth
+	return auth, nil
+}
+
+func (g *Gateway) freezeSuccess(auth *FundAuth, operation *FundAuthOperation) {
+	operation.Status = alipay.FundAuthOperationSuccess
+	operation.GmtTrans = g.Now()
+	auth.Status = alipay.FundAuthOrderAuthorized
+	auth.TotalFreezeAmount = auth.TotalFreezeAmount.Add(operation.Amount)
+	auth.RestAmount = auth.RestAmount.Add(operation.Amount)
+	g.notifyFundAuth(auth, operation)
+}
+
+// unfreeze 解冻剩余金额中的amount，剩余金额为0时授权订单完成
+func (g *Gateway) unfreeze(auth *FundAuth, outRequestNo, remark string, amount alipay.Money) *FundAuthOperation {
+	now := g.Now()
+	operation := &FundAuthOperation{
+		OperationId:   g.nextSeq("0020"),
+		OutRequestNo:  outRequestNo,
+		OperationType: alipay.FundAuthOperationUnfreeze,
+		Amount:        amount,
+		Status:        alipay.FundAuthOperationSuccess,
+		Remark:        remark,
+		GmtCreate:     now,
+		GmtTrans:      now,
+	}
+	auth.Operations = append(auth.Operations, operation)
+	auth.TotalUnfreezeAmount = auth.TotalUnfreezeAmount.Add(amount)
+	auth.RestAmount = auth.RestAmount.Sub(amount)
+	if auth.RestAmount.IsZero() {
+		auth.Status = alipay.FundAuthOrderFinish
+	}
+	g.notifyFundAuth(auth, operation)
+	return operation
+}
+
+// fundAuthPay 预授权转支付，支付金额不能超过剩余冻结金额
+func (g *Gateway) fundAuthPay(trade *Trade, authNo, confirmMode string) error {
+	auth, ok := g.fundAuthNos[authNo]
+	if !ok {
+		return ErrAuthOrderNotExist
+	}
+	if auth.Status != alipay.FundAuthOrderAuthorized {
+		return ErrAuthOrderStatusError
+	}
+	if trade.TotalAmount.Cmp(auth.RestAmount) > 0 {
+		return ErrAuthPayAmountExceed
+	}
+	now := g.Now()
+	auth.Operations = append(auth.Operations, &FundAuthOperation{
+		OperationId:   g.nextSeq("0020"),
+		OutRequestNo:  trade.OutTradeNo,
+		OperationType: alipay.FundAuthOperationPay,
+		Amount:        trade.TotalAmount,
+		Status:        alipay.FundAuthOperationSuccess,
+		GmtCreate:     now,
+		GmtTrans:      now,
+	})
+	auth.TotalPayAmount = auth.TotalPayAmount.Add(trade.TotalAmount)
+	auth.RestAmount = auth.RestAmount.Sub(trade.TotalAmount)
+	switch {
+	case auth.RestAmount.IsZero():
+		auth.Status = alipay.FundAuthOrderFinish
+	case confirmMode == alipay.AuthConfirmModeComplete:
+		g.unfreeze(auth, trade.OutTradeNo+"_complete", "转支付完成解冻剩余金额", auth.RestAmount)
+	}
+	trade.BuyerId = auth.PayerUserId
+	g.payTrade(trade)
+	return nil
+}
+
+// notifyFundAuth 冻结或者解冻成功后发送通知
+func (g *Gateway) notifyFundAuth(auth *FundAuth, operation *FundAuthOperation) {
+	notifyURL := auth.NotifyUrl
+	if len(notifyURL) == 0 {
+		notifyURL = g.notifyURL
+	}
+	if len(notifyURL) == 0 {
+		return
+	}
+	notifyType := alipay.NotifyTypeFundAuthFreeze
+	if operation.OperationType == alipay.FundAuthOperationUnfreeze {
+		notifyType = alipay.NotifyTypeFundAuthUnfreeze
+	}
+	values := g.NotifyValues(notifyType)
+	fields := map[string]string{
+		"auth_no":               auth.AuthNo,
+		"out_order_no":          auth.OutOrderNo,
+		"operation_id":          operation.OperationId,
+		"out_request_no":        operation.OutRequestNo,
+		"operation_type":        operation.OperationType,
+		"amount":                operation.Amount.String(),
+		"status":                operation.Status,
+		"gmt_create":            g.formatTime(operation.GmtCreate),
+		"gmt_trans":             g.formatTime(operation.GmtTrans),
+		"payer_user_id":         auth.PayerUserId,
+		"payer_logon_id":        DefaultBuyerLogonId,
+		"payee_user_id":         DefaultSellerId,
+		"total_freeze_amount":   auth.TotalFreezeAmount.String(),
+		"total_unfreeze_amount": auth.TotalUnfreezeAmount.String(),
+		"total_pay_amount":      auth.TotalPayAmount.String(),
+		"rest_amount":           auth.RestAmount.String(),
+		"fund_amount":           operation.Amount.String(),
+	}
+	for key, value := range fields {
+		if len(value) > 0 {
+			values.Set(key, value)
+		}
+	}
+	g.Notify(notifyURL, values)
+}
+
+// handleFundAuthOrderAppFreeze 创建待用户确认的授权订单
+func handleFundAuthOrderAppFreeze(g *Gateway, req *Request) (interface{}, error) {
+	content := new(fundAuthContent)
+	if err := req.Bind(content); err != nil {
+		return nil, ErrInvalidParameter
+	}
+	if _, err := g.createFundAuth(req, content); err != nil {
+		return nil, err
+	}
+	return success, nil
+}
+
+// handleFundAuthOrderFreeze 当面冻结，同一商户授权资金订单号重复请求时返回原冻结结果
+func handleFundAuthOrderFreeze(g *Gateway, req *Request) (interface{}, error) {
+	content := new(fundAuthContent)
+	if err := req.Bind(content); err != nil || len(content.AuthCode) == 0 {
+		return nil, ErrInvalidParameter
+	}
+	auth, err := g.createFundAuth(req, content)
+	if err != nil {
+		return nil, err
+	}
+	operation := auth.Operations[0]
+	if operation.Status == alipay.FundAuthOperationInit {
+		auth.PayerUserId = DefaultBuyerId
+		g.freezeSuccess(auth, operation)
+	}
+	return alipay.FundAuthOrderFreezeResContent{
+		CommonRes:    success,
+		AuthNo:       auth.AuthNo,
+		OutOrderNo:   auth.OutOrderNo,
+		OperationId:  operation.OperationId,
+		OutRequestNo: operation.OutRequestNo,
+		Amount:       operation.Amount,
+		Status:       operation.Status,
+		PayerUserId:  auth.PayerUserId,
+		PayerLogonId: DefaultBuyerLogonId,
+		GmtTrans:     g.formatTime(operation.GmtTrans),
+		FundAmount:   operation.Amount,
+	}, nil
+}
+
+// handleFundAuthOrderVoucherCreate 创建待用户扫码确认的授权订单
+func handleFundAuthOrderVoucherCreate(g *Gateway, req *Request) (interface{}, error) {
+	content := new(fundAuthContent)
+	if err := req.Bind(content); err != nil {
+		return nil, ErrInvalidParameter
+	}
+	auth, err := g.createFundAuth(req, content)
+	if err != nil {
+		return nil, err
+	}
+	return alipay.FundAuthOrderVoucherCreateResContent{
+		CommonRes:    success,
+		OutOrderNo:   auth.OutOrderNo,
+		OutRequestNo: auth.Operations[0].OutRequestNo,
+		CodeType:     "qrCode",
+		CodeValue:    "https://qr.alipay.com/bax" + auth.AuthNo[len(auth.AuthNo)-16:],
+	}, nil
+}
+
+// handleFundAuthOrderUnfreeze 同一请求流水号重复请求时返回原解冻结果，不会重复解冻
+func handleFundAuthOrderUnfreeze(g *Gateway, req *Request) (interface{}, error) {
+	content := new(fundAuthContent)
+	if err := req.Bind(content); err != nil || !content.Amount.IsPositive() {
+		return nil, ErrInvalidParameter
+	}
+	auth, ok := g.fundAuthNos[content.AuthNo]
+	if !ok {
+		return nil, ErrAuthOrderNotExist
+	}
+	operation := findFundAuthOperation(auth, "", content.OutRequestNo, alipay.FundAuthOperationUnfreeze)
+	if operation == nil {
+		if auth.Status != alipay.FundAuthOrderAuthorized {
+			return nil, ErrAuthOrderStatusError
+		}
+		if content.Amount.Cmp(auth.RestAmount) > 0 {
+			return nil, ErrUnfreezeAmountExceed
+		}
+		if len(req.NotifyUrl) > 0 {
+			auth.NotifyUrl = req.NotifyUrl
+		}
+		operation = g.unfreeze(auth, content.OutRequestNo, content.Remark, content.Amount)
+	}
+	return alipay.FundAuthOrderUnfreezeResContent{
+		CommonRes:    success,
+		AuthNo:       auth.AuthNo,
+		OutOrderNo:   auth.OutOrderNo,
+		OperationId:  operation.OperationId,
+		OutRequestNo: operation.OutRequestNo,
+		Amount:       operation.Amount,
+		Status:       operation.Status,
+		GmtTrans:     g.formatTime(operation.GmtTrans),
+		FundAmount:   operation.Amount,
+	}, nil
+}
+
+// findFundAuthOperation 按支付宝操作流水号或者商户请求流水号查找操作
+func findFundAuthOperation(auth *FundAuth, operationId, outRequestNo, operationType string) *FundAuthOperation {
+	for _, operation := range auth.Operations {
+		if len(operationId) > 0 {
+			if operation.OperationId == operationId {
+				return operation
+			}
+			continue
+		}
+		if operation.OutRequestNo == outRequestNo && operation.OperationType == operationType {
+			return operation
+		}
+	}
+	return nil
+}
+
+func handleFundAuthOperationDetailQuery(g *Gateway, req *Request) (interface{}, error) {
+	content := new(fundAuthContent)
+	if err := req.Bind(content); err != nil {
+		return nil, ErrInvalidParameter
+	}
+	auth, ok := g.fundAuthNos[content.AuthNo]
+	if len(content.AuthNo) == 0 {
+		auth, ok = g.fundAuths[content.OutOrderNo]
+	}
+	if !ok {
+		return nil, ErrAuthOrderNotExist
+	}
+	operationType := content.OperationType
+	if len(operationType) == 0 {
+		operationType = alipay.FundAuthOperationFreeze
+	}
+	operation := findFundAuthOperation(auth, content.OperationId, content.OutRequestNo, operationType)
+	if operation == nil {
+		return nil, ErrAuthOperationNotExist
+	}
+	return alipay.FundAuthOperationDetailQueryResContent{
+		CommonRes:             success,
+		AuthNo:                auth.AuthNo,
+		OutOrderNo:            auth.OutOrderNo,
+		OrderStatus:           auth.Status,
+		TotalFreezeAmount:     auth.TotalFreezeAmount,
+		RestAmount:            auth.RestAmount,
+		TotalPayAmount:        auth.TotalPayAmount,
+		OrderTitle:            auth.OrderTitle,
+		PayerLogonId:          DefaultBuyerLogonId,
+		PayerUserId:           auth.PayerUserId,
+		ExtraParam:            auth.ExtraParam,
+		OperationId:           operation.OperationId,
+		OutRequestNo:          operation.OutRequestNo,
+		Amount:                operation.Amount,
+		OperationType:         operation.OperationType,
+		Status:                operation.Status,
+		Remark:                operation.Remark,
+		GmtCreate:             g.formatTime(operation.GmtCreate),
+		GmtTrans:              g.formatTime(operation.GmtTrans),
+		FundAmount:            operation.Amount,
+		TotalFreezeFundAmount: auth.TotalFreezeAmount,
+		TotalPayFundAmount:    auth.TotalPayAmount,
+		RestFundAmount:        auth.RestAmount,
+	}, nil
+}
@@ -0,0 +1,581 @@
+package alipaytest
+
+import (
+	"crypto"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	alipay "github.com/try-labs/xpay"
+	"github.com/try-labs/xpay/testkeys"
+)
+
+/**
+ * @author: Sam
+ * @since: 1.0.0
+ * @date: 2026/10/19 16:00
+ * @desc: 基于httptest的本地模拟支付宝网关，用于离线集成测试
+ *
+ * 网关会校验客户端的请求签名，使用 testkeys 生成的支付宝密钥对响应签名，并在内存中维护交易、退款和转账状态，
+ * 交易状态变化时向notify_url发送签名的异步通知。支持公钥和证书两种模式。
+ */
+
+const (
+	// DefaultAppId 网关默认的应用ID
+	DefaultAppId = "2021000000000001"
+	// DefaultSellerId 卖家支付宝用户ID
+	DefaultSellerId = "2088102177649450"
+	// DefaultBuyerId 买家支付宝用户ID
+	DefaultBuyerId = "2088102177846880"
+	// DefaultBuyerLogonId 买家支付宝账号
+	DefaultBuyerLogonId = "tes***@sandbox.com"
+
+	gatewayPath  = "/gateway.do"
+	billPath     = "/bill/download"
+	ereceiptPath = "/ereceipt/download"
+)
+
+// HandlerFunc 处理某个接口的请求，返回响应内容或错误
+// 返回 *Error 时以业务错误响应，其他错误以系统错误响应。处理函数执行时已持有网关的锁
+type HandlerFunc func(g *Gateway, req *Request) (interface{}, error)
+
+// Hook 请求校验通过后、处理前调用，返回非nil错误时直接以该错误响应
+type Hook func(req *Request) error
+
+// Request 网关收到的请求
+type Request struct {
+	Method       string     // 接口名称
+	AppId        string     // 应用ID
+	NotifyUrl    string     // 异步通知地址
+	ReturnUrl    string     // 同步跳转地址
+	AppAuthToken string     // 应用授权令牌
+	AuthToken    string     // 用户授权令牌
+	Params       url.Values // 全部公共参数
+	BizContent   []byte     // 业务参数
+}
+
+// Bind 将业务参数解析到v
+func (r *Request) Bind(v interface{}) error {
+	if len(r.BizContent) == 0 {
+		return nil
+	}
+	return json.Unmarshal(r.BizContent, v)
+}
+
+type Option func(g *Gateway)
+
+// WithAppId 设置网关接受的应用ID
+func WithAppId(appId string) Option {
+	return func(g *Gateway) {
+		g.appId = appId
+	}
+}
+
+// WithKeys 设置双方的密钥和证书，默认使用 testkeys.Shared
+func WithKeys(keys *testkeys.Set) Option {
+	return func(g *Gateway) {
+		g.keys = keys
+	}
+}
+
+// WithCertMode 使用证书模式，校验请求的app_cert_sn和alipay_root_cert_sn，响应中返回alipay_cert_sn
+func WithCertMode() Option {
+	return func(g *Gateway) {
+		g.certMode = true
+	}
+}
+
+// WithNotifyURL 设置默认的异步通知地址，请求中指定了notify_url时以请求为准
+func WithNotifyURL(notifyURL string) Option {
+	return func(g *Gateway) {
+		g.notifyURL = notifyURL
+	}
+}
+
+// WithEncryptKey 设置AES密钥，默认为 DefaultEncryptKey
+func WithEncryptKey(encryptKey string) Option {
+	return func(g *Gateway) {
+		g.encryptKey = encryptKey
+	}
+}
+
+// WithLocation 设置网关时间所在的时区
+func WithLocation(location *time.Location) Option {
+	return func(g *Gateway) {
+		g.location = location
+	}
+}
+
+// WithNow 设置网关获取当前时间的函数，便于测试订单超时等场景
+func WithNow(now func() time.Time) Option {
+	return func(g *Gateway) {
+		g.now = now
+	}
+}
+
+// WithBalance 设置账户初始余额，默认为100000.00
+func WithBalance(balance alipay.Money) Option {
+	return func(g *Gateway) {
+		g.balance = balance
+	}
+}
+
+type injectedError struct {
+	err   *Error
+	times int
+}
+
+// Gateway 模拟支付宝网关
+type Gateway struct {
+	*httptest.Server
+
+	appId     string
+	keys      *testkeys.Set
+	certMode  bool
+	certDir   string
+	notifyURL string
+	location  *time.Location
+	now       func() time.Time
+	// encryptKey 加密小程序敏感数据的AES密钥
+	encryptKey string
+
+	mu        sync.Mutex
+	handlers  map[string]HandlerFunc
+	hooks     []Hook
+	injected  map[string][]*injectedError
+	calls     map[string]int
+	requests  []*Request
+	seq       int64
+	balance   alipay.Money
+	passwords map[string]bool
+	bills     map[string][]byte
+	trades    map[string]*Trade
+	tradeNos  map[string]*Trade
+	refunds   map[string]*Refund
+	transfers map[string]*Transfer
+	// transferResults 转账单创建后生效的状态
+	transferResults map[string]*transferResult
+	batches         map[string]*Batch
+	agreements      map[string]*Agreement
+	ereceipts       map[string]*Ereceipt
+	// ereceiptErrors 按申请的key预设的回单生成失败原因
+	ereceiptErrors  map[string]string
+	ereceiptPending int
+	// royaltyReceivers 已绑定的分账接收方，按绑定顺序排列
+	royaltyReceivers []*alipay.RoyaltyEntity
+	settles          map[string]*Settle
+	fundAuths        map[string]*FundAuth
+	fundAuthNos      map[string]*FundAuth
+	// appAuthCodes 未使用的应用授权码
+	appAuthCodes map[string]*AppAuth
+	appAuths     map[string]*AppAuth
+	// oauthCodes 未使用的用户授权码对应的用户ID
+	oauthCodes  map[string]string
+	oauthTokens map[string]*OauthToken
+	merges      map[string]*Merge
+
+	notifier
+}
+
+// NewGateway 创建并启动模拟网关，使用完毕后需调用 Close
+func NewGateway(opts ...Option) *Gateway {
+	g := &Gateway{
+		appId:     DefaultAppId,
+		location:  time.Local,
+		now:       time.Now,
+		handlers:  make(map[string]HandlerFunc),
+		injected:  make(map[string][]*injectedError),
+		calls:     make(map[string]int),
+		balance:   alipay.NewMoneyFromYuan(100000),
+		passwords: make(map[string]bool),
+		bills:     make(map[string][]byte),
+		trades:    make(map[string]*Trade),
+		tradeNos:  make(map[string]*Trade),
+		refunds:   make(map[string]*Refund),
+		transfers: make(map[string]*Transfer),
+
+		transferResults: make(map[string]*transferResult),
+		batches:         make(map[string]*Batch),
+		agreements:      make(map[string]*Agreement),
+		ereceipts:       make(map[string]*Ereceipt),
+		ereceiptErrors:  make(map[string]string),
+		ereceiptPending: 1,
+		settles:         make(map[string]*Settle),
+		fundAuths:       make(map[string]*FundAuth),
+		fundAuthNos:     make(map[string]*FundAuth),
+		encryptKey:      DefaultEncryptKey,
+		appAuthCodes:    make(map[string]*AppAuth),
+		appAuths:        make(map[string]*AppAuth),
+		oauthCodes:      make(map[string]string),
+		oauthTokens:     make(map[string]*OauthToken),
+		merges:          make(map[string]*Merge),
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	if g.keys == nil {
+		g.keys = testkeys.Shared()
+	}
+	if g.certMode {
+		var err error
+		if g.certDir, err = os.MkdirTemp("", "alipaytest"); err != nil {
+			panic(err)
+		}
+	}
+	for method, handler := range defaultHandlers {
+		g.handlers[method] = handler
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(gatewayPath, g.serveGateway)
+	mux.HandleFunc(billPath, g.serveBill)
+	mux.HandleFunc(ereceiptPath, g.serveEreceipt)
+	g.Server = httptest.NewServer(mux)
+	return g
+}
+
+// Close 等待未完成的异步通知后关闭网关
+func (g *Gateway) Close() {
+	g.WaitNotifications()
+	g.Server.Close()
+	if len(g.certDir) > 0 {
+		os.RemoveAll(g.certDir)
+	}
+}
+
+// GatewayURL 网关地址，作为客户端的serverUrl
+func (g *Gateway) GatewayURL() string {
+	return g.URL + gatewayPath
+}
+
+// AppId 网关接受的应用ID
+func (g *Gateway) AppId() string {
+	return g.appId
+}
+
+// Keys 网关使用的密钥和证书
+func (g *Gateway) Keys() *testkeys.Set {
+	return g.keys
+}
+
+// SignStrategy 商户侧的签名策略，与网关的模式一致
+func (g *Gateway) SignStrategy() (alipay.SignVerifier, error) {
+	if g.certMode {
+		return g.keys.CertStrategy(g.appId, g.certDir)
+	}
+	return g.keys.NormalStrategy(g.appId), nil
+}
+
+// Client 创建连接到本网关的客户端，optsFunc 在默认配置之后生效
+func (g *Gateway) Client(optsFunc ...alipay.ClientOptFunc) (*alipay.Client, error) {
+	signVerifier, err := g.SignStrategy()
+	if err != nil {
+		return nil, err
+	}
+	defaults := []alipay.ClientOptFunc{
+		alipay.SetServerUrl(g.GatewayURL()),
+		alipay.SetClientOptHttpClient(g.Server.Client()),
+		alipay.SetClientOptLocation(g.location),
+		alipay.SetClientOptEncryptKey(g.encryptKey),
+	}
+	return alipay.NewClient(signVerifier, append(defaults, optsFunc...)...)
+}
+
+// Handle 注册或替换某个接口的处理函数
+func (g *Gateway) Handle(method string, handler HandlerFunc) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.handlers[method] = handler
+}
+
+// Use 添加请求钩子，可用于检查请求参数或按条件返回错误
+func (g *Gateway) Use(hook Hook) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.hooks = append(g.hooks, hook)
+}
+
+// InjectError 指定接口接下来的times次请求返回err，times小于等于0时一直生效
+func (g *Gateway) InjectError(method string, err *Error, times int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.injected[method] = append(g.injected[method], &injectedError{err: err, times: times})
+}
+
+// ClearErrors 清除全部注入的错误
+func (g *Gateway) ClearErrors() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.injected = make(map[string][]*injectedError)
+}
+
+// Calls 某个接口被调用的次数，包含返回错误的调用
+func (g *Gateway) Calls(method string) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.calls[method]
+}
+
+// Requests 已通过验签的全部请求
+func (g *Gateway) Requests() []*Request {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]*Request(nil), g.requests...)
+}
+
+// Now 网关当前时间
+func (g *Gateway) Now() time.Time {
+	return g.now().In(g.location)
+}
+
+func (g *Gateway) formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.In(g.location).Format(time.DateTime)
+}
+
+// nextSeq 生成支付宝侧的单号
+func (g *Gateway) nextSeq(middle string) string {
+	g.seq++
+	return g.Now().Format("20060102") + middle + fmt.Sprintf("%016d", g.seq)
+}
+
+// requestValues 表单提交时参数在body中，charset放在地址上，不能合并
+func requestValues(request *http.Request) (url.Values, error) {
+	if err := request.ParseForm(); err != nil {
+		return nil, err
+	}
+	if len(request.PostForm) > 0 {
+		return request.PostForm, nil
+	}
+	return request.URL.Query(), nil
+}
+
+func (g *Gateway) serveGateway(w http.ResponseWriter, request *http.Request) {
+	values, err := requestValues(request)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req, err := g.parseRequest(values)
+	if err != nil {
+		g.writeResponse(w, "", err)
+		return
+	}
+	if page, ok := pageMethods[req.Method]; ok {
+		g.servePage(w, req, page)
+		return
+	}
+	content, err := g.dispatch(req)
+	if err != nil {
+		g.writeResponse(w, req.Method, err)
+		return
+	}
+	g.writeResponse(w, req.Method, content)
+}
+
+// parseRequest 校验公共参数和签名
+func (g *Gateway) parseRequest(values url.Values) (*Request, error) {
+	req := &Request{
+		Method:       values.Get("method"),
+		AppId:        values.Get("app_id"),
+		NotifyUrl:    values.Get("notify_url"),
+		ReturnUrl:    values.Get("return_url"),
+		AppAuthToken: values.Get("app_auth_token"),
+		AuthToken:    values.Get("auth_token"),
+		Params:       values,
+		BizContent:   []byte(values.Get("biz_content")),
+	}
+	if len(req.Method) == 0 {
+		return nil, ErrMissingMethod
+	}
+	if req.AppId != g.appId {
+		return nil, ErrInvalidAppId
+	}
+	if values.Get("sign_type") != alipay.SignTypeRSA2 {
+		return nil, ErrInvalidSignType
+	}
+	if g.certMode {
+		if values.Get("app_cert_sn") != g.keys.AppCert.SN() {
+			return nil, ErrInvalidAppCertSn
+		}
+		if values.Get("alipay_root_cert_sn") != g.keys.Chain.RootCertSN() {
+			return nil, ErrInvalidAlipayRootCertSn
+		}
+	}
+	if err := g.verifyRequest(values); err != nil {
+		return nil, ErrInvalidSignature
+	}
+	return req, nil
+}
+
+// verifyRequest 待签名内容为除sign外全部非空参数按key排序后拼接
+func (g *Gateway) verifyRequest(values url.Values) error {
+	sign, err := base64.StdEncoding.DecodeString(values.Get("sign"))
+	if err != nil {
+		return err
+	}
+	list := make([]string, 0, len(values))
+	for key := range values {
+		value := strings.TrimSpace(values.Get(key))
+		if key == alipay.ExcludeKeySign || len(value) == 0 {
+			continue
+		}
+		list = append(list, key+"="+value)
+	}
+	sort.Strings(list)
+	return alipay.RSAVerifyWithKey([]byte(strings.Join(list, "&")), sign, g.keys.App.PublicKey(), crypto.SHA256)
+}
+
+func (g *Gateway) dispatch(req *Request) (interface{}, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.calls[req.Method]++
+	g.requests = append(g.requests, req)
+	for _, hook := range g.hooks {
+		if err := hook(req); err != nil {
+			return nil, err
+		}
+	}
+	if err := g.takeInjected(req.Method); err != nil {
+		return nil, err
+	}
+	if err := g.checkAppAuthToken(req); err != nil {
+		return nil, err
+	}
+	if len(req.AuthToken) > 0 {
+		if _, err := g.checkAuthToken(req.AuthToken); err != nil {
+			return nil, err
+		}
+	}
+	handler, ok := g.handlers[req.Method]
+	if !ok {
+		return nil, ErrInvalidMethod
+	}
+	return handler(g, req)
+}
+
+func (g *Gateway) takeInjected(method string) *Error {
+	list := g.injected[method]
+	if len(list) == 0 {
+		return nil
+	}
+	item := list[0]
+	if item.times > 0 {
+		item.times--
+		if item.times == 0 {
+			g.injected[method] = list[1:]
+		}
+	}
+	return item.err
+}
+
+// responseKey alipay.trade.query 对应 alipay_trade_query_response
+func responseKey(method string) string {
+	if len(method) == 0 {
+		return "error_response"
+	}
+	return strings.Replace(method, ".", "_", -1) + "_response"
+}
+
+// writeResponse 响应内容与签名拼接在一起，签名内容为响应节点的原始json，证书模式下在签名前返回alipay_cert_sn
+func (g *Gateway) writeResponse(w http.ResponseWriter, method string, content interface{}) {
+	if err, ok := content.(error); ok {
+		bizErr, ok := err.(*Error)
+		if !ok {
+			bizErr = ErrSystemError
+		}
+		if bizErr.errorResponse {
+			method = ""
+		}
+		content = bizErr.commonRes()
+	}
+	buff, err := json.Marshal(content)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	sign, err := g.sign(buff)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html;charset=utf-8")
+	if g.certMode {
+		fmt.Fprintf(w, `{"%s":%s,"alipay_cert_sn":"%s","sign":"%s"}`, responseKey(method), buff, g.keys.AlipayCert.SN(), sign)
+		return
+	}
+	fmt.Fprintf(w, `{"%s":%s,"sign":"%s"}`, responseKey(method), buff, sign)
+}
+
+func (g *Gateway) sign(content []byte) (string, error) {
+	sign, err := alipay.RSASignWithKey(content, g.keys.Alipay.PrivateKey, crypto.SHA256)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(sign), nil
+}
+
+// pageMethods 通过浏览器跳转访问的接口及对应的页面，网关返回收银台或者签约页面
+var pageMethods = map[string]*template.Template{
+	"alipay.trade.page.pay":           cashierTemplate,
+	"alipay.trade.wap.pay":            cashierTemplate,
+	"alipay.trade.wap.merge.pay":      mergeCashierTemplate,
+	"alipay.user.agreement.page.sign": agreementTemplate,
+}
+
+var cashierTemplate = template.Must(template.New("cashier").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>支付宝收银台</title></head>
+<body>
+<p>商户订单号：{{.OutTradeNo}}</p>
+<p>支付宝交易号：{{.TradeNo}}</p>
+<p>订单金额：{{.TotalAmount}}</p>
+<p>交易状态：{{.Status}}</p>
+</body>
+</html>
+`))
+
+// servePage 模拟收银台或者签约页面，创建待支付的交易或者暂存的协议，可通过 PayTrade、SignAgreement 模拟用户操作
+func (g *Gateway) servePage(w http.ResponseWriter, req *Request, page *template.Template) {
+	content, err := g.dispatch(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html;charset=utf-8")
+	page.Execute(w, content)
+}
+
+// SetBill 设置账单下载接口返回的文件内容
+func (g *Gateway) SetBill(billType, billDate string, data []byte) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.bills[billType+"_"+billDate] = data
+}
+
+func (g *Gateway) billURL(billType, billDate string) string {
+	query := url.Values{"bill_type": {billType}, "bill_date": {billDate}}
+	return g.URL + billPath + "?" + query.Encode()
+}
+
+func (g *Gateway) serveBill(w http.ResponseWriter, request *http.Request) {
+	g.mu.Lock()
+	data, ok := g.bills[request.URL.Query().Get("bill_type")+"_"+request.URL.Query().Get("bill_date")]
+	g.mu.Unlock()
+	if !ok {
+		http.NotFound(w, request)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Write(data)
+}
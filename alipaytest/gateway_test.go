@@ -0,0 +1,323 @@
+package alipaytest
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	alipay "github.com/try-labs/xpay"
+)
+
+/**
+ * @author: Sam
+ * @since:
+ * @date: 2026/10/19 17:20
+ * @desc:
+ */
+
+func newTestGateway(t *testing.T, opts ...Option) (*Gateway, *alipay.Client) {
+	t.Helper()
+	g := NewGateway(opts...)
+	t.Cleanup(g.Close)
+	client, err := g.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g, client
+}
+
+func TestGateway_PagePayNotify(t *testing.T) {
+	var client *alipay.Client
+	notified := make(chan *alipay.NotifyReq, 1)
+	merchant := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		notifyReq, err := client.AsyncNotify(r)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		notified <- notifyReq
+		io.WriteString(w, "success")
+	}))
+	defer merchant.Close()
+	g, client := newTestGateway(t)
+
+	req := alipay.NewTradePagePayReq("20261019000001", alipay.MustParseMoney("100.20"), "测试商品")
+	req.NotifyUrl = merchant.URL
+	payURL, err := client.TradePagePay(*req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, err := http.Get(payURL.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+	trade, ok := g.Trade("20261019000001")
+	if !ok || trade.Status != alipay.TradeWaitBuyerPay {
+		t.Fatalf("unexpected trade: %+v", trade)
+	}
+	if err = g.PayTrade(trade.OutTradeNo, ""); err != nil {
+		t.Fatal(err)
+	}
+	g.WaitNotifications()
+	notifyReq := <-notified
+	if notifyReq.TradeStatus != alipay.TradeSuccess || notifyReq.TotalAmount != alipay.MustParseMoney("100.20") || notifyReq.TradeNo != trade.TradeNo {
+		t.Errorf("unexpected notify: %s", notifyReq)
+	}
+	if notifications := g.Notifications(); len(notifications) != 1 || !notifications[0].Acknowledged() {
+		t.Errorf("notification should be acknowledged: %+v", notifications)
+	}
+
+	res, err := client.TradeQuery(context.Background(), alipay.TradeQueryReq{OutTradeNo: trade.OutTradeNo})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Success() || res.TradeStatus != alipay.TradeSuccess || res.BuyerUserId != DefaultBuyerId {
+		t.Errorf("unexpected query result: %s", res)
+	}
+}
+
+func TestGateway_TradePayRefund(t *testing.T) {
+	g, client := newTestGateway(t)
+	ctx := context.Background()
+	payReq := alipay.TradePayReq{OutTradeNo: "20261019000002", TotalAmount: alipay.MustParseMoney("10.00"), Subject: "测试商品", Scene: "bar_code", AuthCode: "283726519381047283"}
+	payRes, err := client.TradePay(ctx, payReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !payRes.Success() || len(payRes.TradeNo) == 0 {
+		t.Fatalf("unexpected pay result: %+v", payRes)
+	}
+
+	refundReq := alipay.TradeRefundReq{OutTradeNo: payReq.OutTradeNo, OutRequestNo: "R1", RefundAmount: alipay.MustParseMoney("4.00")}
+	for i := 0; i < 2; i++ {
+		refundRes, err := client.TradeRefund(ctx, refundReq)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !refundRes.Success() || refundRes.RefundFee != alipay.MustParseMoney("4.00") {
+			t.Errorf("unexpected refund result: %+v", refundRes)
+		}
+	}
+	refundReq.OutRequestNo, refundReq.RefundAmount = "R2", alipay.MustParseMoney("6.01")
+	refundRes, err := client.TradeRefund(ctx, refundReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refundRes.SubCode != ErrRefundAmountExceed.SubCode {
+		t.Errorf("refund should exceed: %+v", refundRes)
+	}
+
+	queryRes, err := client.TradeFastPayRefundQuery(ctx, alipay.TradeFastPayRefundQueryReq{OutTradeNo: payReq.OutTradeNo, OutRequestNo: "R1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if queryRes.RefundStatus != "REFUND_SUCCESS" || queryRes.RefundAmount != alipay.MustParseMoney("4.00") {
+		t.Errorf("unexpected refund query result: %+v", queryRes)
+	}
+	if refunds := g.Refunds(payReq.OutTradeNo); len(refunds) != 1 {
+		t.Errorf("unexpected refunds: %+v", refunds)
+	}
+}
+
+func TestGateway_RequirePassword(t *testing.T) {
+	g, client := newTestGateway(t)
+	ctx := context.Background()
+	g.RequirePassword("283726519381047284")
+	payReq := alipay.TradePayReq{OutTradeNo: "20261019000003", TotalAmount: alipay.MustParseMoney("1.00"), Subject: "测试商品", Scene: "bar_code", AuthCode: "283726519381047284"}
+	payRes, err := client.TradePay(ctx, payReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payRes.Code != "10003" {
+		t.Fatalf("unexpected pay result: %+v", payRes)
+	}
+	if err = g.PayTrade(payReq.OutTradeNo, ""); err != nil {
+		t.Fatal(err)
+	}
+	queryRes, err := client.TradeQuery(ctx, alipay.TradeQueryReq{TradeNo: payRes.TradeNo})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if queryRes.TradeStatus != alipay.TradeSuccess {
+		t.Errorf("unexpected trade status: %s", queryRes.TradeStatus)
+	}
+}
+
+func TestGateway_InjectError(t *testing.T) {
+	g, client := newTestGateway(t)
+	ctx := context.Background()
+	g.InjectError("alipay.trade.query", ErrSystemError, 1)
+	req := alipay.TradeQueryReq{OutTradeNo: "20261019000004"}
+	res, err := client.TradeQuery(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Code != ErrSystemError.Code {
+		t.Errorf("injected error expected: %+v", res)
+	}
+	if res, err = client.TradeQuery(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if res.SubCode != ErrTradeNotExist.SubCode {
+		t.Errorf("unexpected result: %+v", res)
+	}
+	if g.Calls("alipay.trade.query") != 2 {
+		t.Errorf("unexpected calls: %d", g.Calls("alipay.trade.query"))
+	}
+}
+
+func TestGateway_InvalidSignature(t *testing.T) {
+	g, client := newTestGateway(t)
+	payURL, err := client.TradePagePay(*alipay.NewTradePagePayReq("20261019000005", alipay.MustParseMoney("1.00"), "测试商品"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := payURL.Query()
+	values.Set("biz_content", strings.Replace(values.Get("biz_content"), `"1.00"`, `"0.01"`, 1))
+	response, err := http.PostForm(g.GatewayURL(), values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer response.Body.Close()
+	res := new(alipay.ErrorResponse)
+	if err = json.NewDecoder(response.Body).Decode(res); err != nil {
+		t.Fatal(err)
+	}
+	if res.SubCode != ErrInvalidSignature.SubCode {
+		t.Errorf("unexpected response: %+v", res)
+	}
+	if _, ok := g.Trade("20261019000005"); ok {
+		t.Error("trade should not be created")
+	}
+}
+
+func TestGateway_Transfer(t *testing.T) {
+	g, client := newTestGateway(t, WithBalance(alipay.MustParseMoney("100.00")))
+	ctx := context.Background()
+	req := alipay.FundTransUniTransferReq{
+		OutBizNo:    "T20261019000001",
+		TransAmount: alipay.MustParseMoney("30.00"),
+		ProductCode: alipay.TransAccountNoPwd,
+		BizScene:    "DIRECT_TRANSFER",
+		OrderTitle:  "测试转账",
+		PayeeInfo:   alipay.Participant{Identity: DefaultBuyerId, IdentityType: "ALIPAY_USER_ID"},
+	}
+	for i := 0; i < 2; i++ {
+		res, err := client.FundTransUniTransfer(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !res.Success() || res.Status != "SUCCESS" {
+			t.Errorf("unexpected transfer result: %+v", res)
+		}
+	}
+	if balance := g.Balance(); balance != alipay.MustParseMoney("70.00") {
+		t.Errorf("unexpected balance: %s", balance)
+	}
+	queryRes, err := client.FundTransCommonQuery(ctx, alipay.FundTransCommonQueryReq{OutBizNo: req.OutBizNo, ProductCode: req.ProductCode, BizScene: req.BizScene})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if queryRes.Status != "SUCCESS" || queryRes.TransAmount != req.TransAmount {
+		t.Errorf("unexpected query result: %+v", queryRes)
+	}
+	req.OutBizNo, req.TransAmount = "T20261019000002", alipay.MustParseMoney("70.01")
+	res, err := client.FundTransUniTransfer(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.SubCode != ErrPayerBalanceNotEnough.SubCode {
+		t.Errorf("balance should not be enough: %+v", res)
+	}
+
+	// 退票后退回余额
+	g.SetTransferStatus("T20261019000001", "REFUND", "PAYEE_ACCOUNT_STATUS_ERROR", "收款账户状态异常")
+	queryRes, err = client.FundTransCommonQuery(ctx, alipay.FundTransCommonQueryReq{OutBizNo: "T20261019000001", ProductCode: req.ProductCode, BizScene: req.BizScene})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if queryRes.Status != "REFUND" || queryRes.FailReason != "收款账户状态异常" || g.Balance() != alipay.MustParseMoney("100.00") {
+		t.Errorf("unexpected query result: %+v", queryRes)
+	}
+	g.SetTransferStatus("T20261019000003", "FAIL", ErrPayeeNotExist.SubCode, ErrPayeeNotExist.SubMsg)
+	req.OutBizNo, req.TransAmount = "T20261019000003", alipay.MustParseMoney("10.00")
+	if res, err = client.FundTransUniTransfer(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+	if res.SubCode != ErrPayeeNotExist.SubCode || g.Balance() != alipay.MustParseMoney("100.00") {
+		t.Errorf("transfer should fail: %+v", res)
+	}
+}
+
+func TestGateway_SubmitOrderString(t *testing.T) {
+	g, client := newTestGateway(t)
+	result, err := client.TradeAppPay(alipay.TradeAppPayReq{OutTradeNo: "20261019000006", TotalAmount: alipay.MustParseMoney("9.99"), Subject: "测试商品"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	trade, err := g.SubmitOrderString(result.OrderString)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trade.Status != alipay.TradeWaitBuyerPay || trade.Method != "alipay.trade.app.pay" {
+		t.Errorf("unexpected trade: %+v", trade)
+	}
+	values, _ := url.ParseQuery(result.OrderString)
+	values.Set("app_id", "2021000000000002")
+	if _, err = g.SubmitOrderString(values.Encode()); err != ErrInvalidAppId {
+		t.Errorf("invalid app id expected, got %v", err)
+	}
+}
+
+func TestGateway_CertMode(t *testing.T) {
+	var client *alipay.Client
+	notified := make(chan *alipay.NotifyReq, 1)
+	merchant := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		notifyReq, err := client.AsyncNotify(r)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		notified <- notifyReq
+		io.WriteString(w, "success")
+	}))
+	defer merchant.Close()
+	g, client := newTestGateway(t, WithCertMode(), WithNotifyURL(merchant.URL))
+	ctx := context.Background()
+	res, err := client.TradePreCreate(ctx, alipay.TradePreCreateReq{OutTradeNo: "20261019000007", TotalAmount: alipay.MustParseMoney("5.00"), Subject: "测试商品"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Success() || res.AlipayCertSn != g.Keys().AlipayCert.SN() {
+		t.Fatalf("unexpected precreate result: %+v", res)
+	}
+	if err = g.PayTrade(res.OutTradeNo, ""); err != nil {
+		t.Fatal(err)
+	}
+	g.WaitNotifications()
+	if notifyReq := <-notified; notifyReq.AlipayCertSn != g.Keys().AlipayCert.SN() {
+		t.Errorf("unexpected notify: %s", notifyReq)
+	}
+
+	// 公钥模式的客户端不会携带证书序列号
+	normal, err := alipay.NewClient(g.Keys().NormalStrategy(g.AppId()), alipay.SetServerUrl(g.GatewayURL()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	queryRes, err := normal.TradeQuery(ctx, alipay.TradeQueryReq{OutTradeNo: res.OutTradeNo})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if queryRes.Success() {
+		t.Errorf("unexpected query result: %+v", queryRes)
+	}
+	if g.Calls("alipay.trade.query") != 0 {
+		t.Error("request without cert sn should be rejected")
+	}
+}
@@ -0,0 +1,84 @@
+package alipaytest
+
+import (
+	alipay "github.com/try-labs/xpay"
+)
+
+/**
+ * @author: Sam
+ * @since: 1.0.0
+ * @date: 2026/10/19 16:50
+ * @desc: 网关默认支持的接口，未涉及状态的接口返回固定的成功响应
+ */
+
+var defaultHandlers = map[string]HandlerFunc{
+	"alipay.trade.page.pay":                              handlePageCreate,
+	"alipay.trade.wap.pay":                               handlePageCreate,
+	"alipay.trade.app.pay":                               handlePageCreate,
+	"alipay.trade.create":                                handleTradeCreate,
+	"alipay.trade.precreate":                             handleTradePreCreate,
+	"alipay.trade.merge.precreate":                       handleTradeMergePreCreate,
+	"alipay.trade.wap.merge.pay":                         handleTradeMergePay,
+	"alipay.trade.app.merge.pay":                         handleTradeMergePay,
+	"alipay.trade.pay":                                   handleTradePay,
+	"alipay.trade.query":                                 handleTradeQuery,
+	"alipay.trade.close":                                 handleTradeClose,
+	"alipay.trade.cancel":                                handleTradeCancel,
+	"alipay.trade.refund":                                handleTradeRefund,
+	"alipay.trade.fastpay.refund.query":                  handleTradeFastPayRefundQuery,
+	"alipay.trade.orderinfo.sync":                        handleTradeOrderInfoSync,
+	"alipay.trade.royalty.relation.bind":                 handleTradeRoyaltyRelationBind,
+	"alipay.trade.royalty.relation.unbind":               handleTradeRoyaltyRelationUnbind,
+	"alipay.trade.royalty.relation.batchquery":           handleTradeRoyaltyRelationBatchQuery,
+	"alipay.trade.royalty.rate.query":                    handleTradeRoyaltyRateQuery,
+	"alipay.trade.order.settle":                          handleTradeOrderSettle,
+	"alipay.trade.order.settle.query":                    handleTradeOrderSettleQuery,
+	"alipay.trade.order.onsettle.query":                  handleTradeOrderOnSettleQuery,
+	"alipay.data.dataservice.bill.downloadurl.query":     handleBillDownloadUrlQuery,
+	"alipay.data.bill.balance.query":                     handleDataBillBalanceQuery,
+	"alipay.data.bill.bail.query":                        handleSuccess,
+	"alipay.data.bill.ereceipt.apply":                    handleDataBillEreceiptApply,
+	"alipay.data.bill.ereceipt.query":                    handleDataBillEreceiptQuery,
+	"alipay.data.bill.accountlog.query":                  handleDataBillAccountLogQuery,
+	"alipay.data.bill.sell.query":                        handleDataBillSellQuery,
+	"alipay.data.bill.buy.query":                         handleDataBillBuyQuery,
+	"alipay.data.bill.transfer.query":                    handleDataBillTransferQuery,
+	"alipay.fund.account.query":                          handleFundAccountQuery,
+	"alipay.fund.trans.uni.transfer":                     handleFundTransUniTransfer,
+	"alipay.fund.trans.toaccount.transfer":               handleFundTransToAccountTransfer,
+	"alipay.fund.trans.order.query":                      handleFundTransOrderQuery,
+	"alipay.fund.trans.common.query":                     handleFundTransCommonQuery,
+	"alipay.fund.batch.uni.transfer":                     handleFundBatchUniTransfer,
+	"alipay.fund.batch.detail.query":                     handleFundBatchDetailQuery,
+	"alipay.fund.batch.close":                            handleFundBatchClose,
+	"alipay.fund.auth.order.app.freeze":                  handleFundAuthOrderAppFreeze,
+	"alipay.fund.auth.order.freeze":                      handleFundAuthOrderFreeze,
+	"alipay.fund.auth.order.voucher.create":              handleFundAuthOrderVoucherCreate,
+	"alipay.fund.auth.order.unfreeze":                    handleFundAuthOrderUnfreeze,
+	"alipay.fund.auth.operation.detail.query":            handleFundAuthOperationDetailQuery,
+	"alipay.system.oauth.token":                          handleSystemOauthToken,
+	"alipay.open.auth.token.app":                         handleOpenAuthTokenApp,
+	"alipay.open.auth.token.app.query":                   handleOpenAuthTokenAppQuery,
+	"alipay.user.info.share":                             handleUserInfoShare,
+	"alipay.user.certify.open.initialize":                handleUserCertifyOpenInitialize,
+	"alipay.user.certify.open.query":                     handleUserCertifyOpenQuery,
+	"alipay.user.agreement.page.sign":                    handleUserAgreementPageSign,
+	"alipay.user.agreement.query":                        handleUserAgreementQuery,
+	"alipay.user.agreement.unsign":                       handleUserAgreementUnsign,
+	"alipay.commerce.cityfacilitator.voucher.generate":   handleSuccess,
+	"alipay.commerce.cityfacilitator.voucher.refund":     handleSuccess,
+	"alipay.commerce.cityfacilitator.station.query":      handleSuccess,
+	"alipay.commerce.cityfacilitator.voucher.batchquery": handleSuccess,
+}
+
+func handleSuccess(g *Gateway, req *Request) (interface{}, error) {
+	return success, nil
+}
+
+func handleUserCertifyOpenInitialize(g *Gateway, req *Request) (interface{}, error) {
+	return alipay.UserCertifyOpenInitializeResContent{CommonRes: success, CertifyId: "OC" + g.nextSeq("3000")}, nil
+}
+
+func handleUserCertifyOpenQuery(g *Gateway, req *Request) (interface{}, error) {
+	return alipay.UserCertifyOpenQueryResContent{CommonRes: success, Passed: "T"}, nil
+}
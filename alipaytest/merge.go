@@ -0,0 +1,226 @@
+package alipaytest
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/url"
+	"time"
+
+	alipay "github.com/try-labs/xpay"
+)
+
+/**
+ * @author: Sam
+ * @since: 1.0.0
+ * @date: 2026/10/20 05:10
+ * @desc: 合并支付
+ *
+ * 预创建时为每个子订单创建待支付的交易，alipay.trade.wap.merge.pay 返回合并收银台页面，
+ * alipay.trade.app.merge.pay 的订单串通过 SubmitMergeOrderString 提交，之后通过 PayMerge 模拟买家一次付款。
+ * 子订单的付款结果以一条 trade_merge_status_sync 通知发送到预创建时的notify_url，退款等后续通知仍按子订单发送。
+ */
+
+// MergeExpiresIn 预下单号的有效期
+const MergeExpiresIn = 2 * time.Hour
+
+// Merge 网关中的合并支付
+type Merge struct {
+	PreOrderNo  string             // 预下单号
+	OutMergeNo  string             // 合并单号
+	OutTradeNos []string           // 子订单的商户订单号
+	TotalAmount alipay.Money       // 子订单金额之和
+	Status      alipay.TradeStatus // 合并支付状态
+	NotifyUrl   string             // 异步通知地址
+	GmtCreate   time.Time          // 预创建时间
+}
+
+// Merge 按预下单号获取合并支付的副本
+func (g *Gateway) Merge(preOrderNo string) (Merge, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	merge, ok := g.merges[preOrderNo]
+	if !ok {
+		return Merge{}, false
+	}
+	copied := *merge
+	copied.OutTradeNos = append([]string(nil), merge.OutTradeNos...)
+	return copied, true
+}
+
+// PayMerge 模拟买家一次付款全部子订单，buyerId为空时使用 DefaultBuyerId
+func (g *Gateway) PayMerge(preOrderNo, buyerId string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	merge, err := g.findMerge(preOrderNo)
+	if err != nil {
+		return err
+	}
+	trades := make([]*Trade, 0, len(merge.OutTradeNos))
+	for _, outTradeNo := range merge.OutTradeNos {
+		trade := g.trades[outTradeNo]
+		if trade.Status != alipay.TradeWaitBuyerPay {
+			return ErrTradeStatusError
+		}
+		trades = append(trades, trade)
+	}
+	for _, trade := range trades {
+		if len(buyerId) > 0 {
+			trade.BuyerId = buyerId
+		}
+		g.payTrade(trade)
+	}
+	merge.Status = alipay.TradeSuccess
+	g.notifyMerge(merge, trades)
+	return nil
+}
+
+// SubmitMergeOrderString 模拟支付宝客户端提交 alipay.trade.app.merge.pay 的订单串，校验签名并返回合并支付
+func (g *Gateway) SubmitMergeOrderString(orderString string) (Merge, error) {
+	values, err := url.ParseQuery(orderString)
+	if err != nil {
+		return Merge{}, err
+	}
+	req, err := g.parseRequest(values)
+	if err != nil {
+		return Merge{}, err
+	}
+	content, err := g.dispatch(req)
+	if err != nil {
+		return Merge{}, err
+	}
+	return content.(Merge), nil
+}
+
+// findMerge 查找未过期的合并支付
+func (g *Gateway) findMerge(preOrderNo string) (*Merge, error) {
+	merge, ok := g.merges[preOrderNo]
+	if !ok || (merge.Status == alipay.TradeWaitBuyerPay && !g.Now().Before(merge.GmtCreate.Add(MergeExpiresIn))) {
+		return nil, ErrPreOrderNotExist
+	}
+	return merge, nil
+}
+
+// notifyMerge 发送合并支付通知，子订单信息以json数组放在order_details中
+func (g *Gateway) notifyMerge(merge *Merge, trades []*Trade) {
+	notifyURL := merge.NotifyUrl
+	if len(notifyURL) == 0 {
+		notifyURL = g.notifyURL
+	}
+	if len(notifyURL) == 0 {
+		return
+	}
+	details := make([]*alipay.MergeOrderNotifyDetail, 0, len(trades))
+	for _, trade := range trades {
+		details = append(details, &alipay.MergeOrderNotifyDetail{
+			AppId:          g.appId,
+			OutTradeNo:     trade.OutTradeNo,
+			TradeNo:        trade.TradeNo,
+			SellerId:       DefaultSellerId,
+			TradeStatus:    trade.Status,
+			TotalAmount:    trade.TotalAmount,
+			ReceiptAmount:  trade.TotalAmount.Sub(trade.RefundAmount),
+			Subject:        trade.Subject,
+			Body:           trade.Body,
+			PassbackParams: trade.PassbackParams,
+			GmtCreate:      g.formatTime(trade.GmtCreate),
+			GmtPayment:     g.formatTime(trade.GmtPayment),
+		})
+	}
+	orderDetails, _ := json.Marshal(details)
+	values := g.NotifyValues(alipay.NotifyTypeTradeMergeStatusSync)
+	fields := map[string]string{
+		"pre_order_no":   merge.PreOrderNo,
+		"out_merge_no":   merge.OutMergeNo,
+		"buyer_id":       trades[0].BuyerId,
+		"buyer_logon_id": trades[0].BuyerLogonId,
+		"trade_status":   string(merge.Status),
+		"total_amount":   merge.TotalAmount.String(),
+		"gmt_payment":    g.formatTime(trades[0].GmtPayment),
+		"order_details":  string(orderDetails),
+	}
+	for key, value := range fields {
+		if len(value) > 0 {
+			values.Set(key, value)
+		}
+	}
+	g.Notify(notifyURL, values)
+}
+
+func handleTradeMergePreCreate(g *Gateway, req *Request) (interface{}, error) {
+	content := new(alipay.TradeMergePreCreateReq)
+	if err := req.Bind(content); err != nil || len(content.OrderDetails) == 0 {
+		return nil, ErrInvalidParameter
+	}
+	merge := &Merge{
+		PreOrderNo: g.nextSeq("2018"),
+		OutMergeNo: content.OutMergeNo,
+		Status:     alipay.TradeWaitBuyerPay,
+		NotifyUrl:  req.NotifyUrl,
+		GmtCreate:  g.Now(),
+	}
+	results := make([]*alipay.MergeOrderDetailResult, 0, len(content.OrderDetails))
+	for _, detail := range content.OrderDetails {
+		result := &alipay.MergeOrderDetailResult{AppId: detail.AppId, OutTradeNo: detail.OutTradeNo}
+		results = append(results, result)
+		trade, err := g.createTrade(req, &tradeContent{
+			OutTradeNo:     detail.OutTradeNo,
+			TotalAmount:    detail.TotalAmount,
+			Subject:        detail.Subject,
+			Body:           detail.Body,
+			PassbackParams: detail.PassbackParams,
+		})
+		if err != nil {
+			if bizErr, ok := err.(*Error); ok {
+				result.ResultCode = bizErr.SubCode
+			}
+			continue
+		}
+		if len(trade.PreOrderNo) > 0 && trade.PreOrderNo != merge.PreOrderNo {
+			result.ResultCode = ErrTradeHasMerged.SubCode
+			continue
+		}
+		trade.PreOrderNo = merge.PreOrderNo
+		// 子订单的后续通知发送到合并支付的通知地址
+		trade.NotifyUrl = req.NotifyUrl
+		result.Success = true
+		merge.OutTradeNos = append(merge.OutTradeNos, trade.OutTradeNo)
+		merge.TotalAmount = merge.TotalAmount.Add(trade.TotalAmount)
+	}
+	res := alipay.TradeMergePreCreateResContent{CommonRes: success, OutMergeNo: content.OutMergeNo, OrderDetailResults: results}
+	// 全部子订单失败时不返回预下单号
+	if len(merge.OutTradeNos) > 0 {
+		g.merges[merge.PreOrderNo] = merge
+		res.PreOrderNo = merge.PreOrderNo
+	}
+	return res, nil
+}
+
+// handleTradeMergePay alipay.trade.wap.merge.pay、alipay.trade.app.merge.pay 返回待支付的合并支付
+func handleTradeMergePay(g *Gateway, req *Request) (interface{}, error) {
+	content := new(alipay.TradeAppMergePayReq)
+	if err := req.Bind(content); err != nil {
+		return nil, ErrInvalidParameter
+	}
+	merge, err := g.findMerge(content.PreOrderNo)
+	if err != nil {
+		return nil, err
+	}
+	if merge.Status != alipay.TradeWaitBuyerPay {
+		return nil, ErrTradeHasSuccess
+	}
+	copied := *merge
+	copied.OutTradeNos = append([]string(nil), merge.OutTradeNos...)
+	return copied, nil
+}
+
+var mergeCashierTemplate = template.Must(template.New("merge_cashier").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>支付宝收银台</title></head>
+<body>
+<p>预下单号：{{.PreOrderNo}}</p>
+<p>子订单：{{range .OutTradeNos}}{{.}} {{end}}</p>
+<p>订单金额：{{.TotalAmount}}</p>
+<p>交易状态：{{.Status}}</p>
+</body>
+</html>
+`))
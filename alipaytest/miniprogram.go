@@ -0,0 +1,46 @@
+package alipaytest
+
+import (
+	"encoding/json"
+
+	alipay "github.com/try-labs/xpay"
+)
+
+/**
+ * @author: Sam
+ * @since: 1.0.0
+ * @date: 2026/10/20 04:40
+ * @desc: 小程序敏感数据，模拟 my.getPhoneNumber 等接口返回的加密数据
+ */
+
+// DefaultEncryptKey 网关默认的AES密钥，Client 创建的客户端使用相同的密钥
+const DefaultEncryptKey = "eHBheS1hZXMta2V5LTAxNg=="
+
+// EncryptMiniProgramData 使用AES密钥加密content并以支付宝私钥签名，content为结构体时编码为json
+func (g *Gateway) EncryptMiniProgramData(content interface{}) (*alipay.MiniProgramEncryptedData, error) {
+	buff, ok := content.([]byte)
+	if !ok {
+		var err error
+		if buff, err = json.Marshal(content); err != nil {
+			return nil, err
+		}
+	}
+	key, err := alipay.ParseAESKey(g.encryptKey)
+	if err != nil {
+		return nil, err
+	}
+	response, err := alipay.AESEncrypt(buff, key)
+	if err != nil {
+		return nil, err
+	}
+	sign, err := g.sign([]byte(`"` + response + `"`))
+	if err != nil {
+		return nil, err
+	}
+	return &alipay.MiniProgramEncryptedData{Response: response, Sign: sign, SignType: alipay.SignTypeRSA2, EncryptType: "AES", Charset: "UTF-8"}, nil
+}
+
+// EncryptPhoneNumber 模拟 my.getPhoneNumber 返回的加密手机号
+func (g *Gateway) EncryptPhoneNumber(mobile string) (*alipay.MiniProgramEncryptedData, error) {
+	return g.EncryptMiniProgramData(alipay.MiniProgramData{CommonRes: success, Mobile: mobile})
+}
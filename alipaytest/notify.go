@@ -0,0 +1,164 @@
+package alipaytest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	alipay "github.com/try-labs/xpay"
+)
+
+/**
+ * @author: Sam
+ * @since: 1.0.0
+ * @date: 2026/10/19 17:00
+ * @desc: 异步通知，签名内容为除sign、sign_type外的全部参数按key排序后拼接
+ */
+
+// Notification 已发送的异步通知
+type Notification struct {
+	URL        string     // 通知地址
+	Values     url.Values // 通知参数
+	StatusCode int        // 商户响应的状态码
+	Body       string     // 商户响应的内容
+	Err        error      // 发送失败的错误
+}
+
+// Acknowledged 商户是否已确认收到通知，即响应了success
+func (n *Notification) Acknowledged() bool {
+	return n.Err == nil && n.StatusCode == http.StatusOK && strings.TrimSpace(n.Body) == "success"
+}
+
+type notifier struct {
+	notifyMu      sync.Mutex
+	notifyWG      sync.WaitGroup
+	notifications []*Notification
+}
+
+// WaitNotifications 等待已触发的异步通知全部发送完成
+func (n *notifier) WaitNotifications() {
+	n.notifyWG.Wait()
+}
+
+// Notifications 已发送完成的异步通知
+func (n *notifier) Notifications() []*Notification {
+	n.notifyMu.Lock()
+	defer n.notifyMu.Unlock()
+	return append([]*Notification(nil), n.notifications...)
+}
+
+// SignValues 使用支付宝私钥对通知参数签名，设置sign和sign_type
+func (g *Gateway) SignValues(values url.Values) error {
+	list := make([]string, 0, len(values))
+	for key := range values {
+		if key == alipay.ExcludeKeySign || key == alipay.ExcludeKeySignType {
+			continue
+		}
+		list = append(list, key+"="+values.Get(key))
+	}
+	sort.Strings(list)
+	sign, err := g.sign([]byte(strings.Join(list, "&")))
+	if err != nil {
+		return err
+	}
+	values.Set(alipay.ExcludeKeySignType, alipay.SignTypeRSA2)
+	values.Set(alipay.ExcludeKeySign, sign)
+	return nil
+}
+
+// Notify 签名后以表单形式异步发送通知，可用于发送网关未内置的通知类型
+func (g *Gateway) Notify(notifyURL string, values url.Values) error {
+	if err := g.SignValues(values); err != nil {
+		return err
+	}
+	g.notifyWG.Add(1)
+	go func() {
+		defer g.notifyWG.Done()
+		notification := &Notification{URL: notifyURL, Values: values}
+		client := &http.Client{Timeout: 5 * time.Second}
+		response, err := client.PostForm(notifyURL, values)
+		if err == nil {
+			var body []byte
+			body, err = io.ReadAll(response.Body)
+			response.Body.Close()
+			notification.StatusCode = response.StatusCode
+			notification.Body = string(body)
+		}
+		notification.Err = err
+		g.notifyMu.Lock()
+		g.notifications = append(g.notifications, notification)
+		g.notifyMu.Unlock()
+	}()
+	return nil
+}
+
+// NotifyValues 通知的公共参数
+func (g *Gateway) NotifyValues(notifyType string) url.Values {
+	notifyId := make([]byte, 16)
+	rand.Read(notifyId)
+	values := url.Values{
+		"notify_time": {g.formatTime(g.Now())},
+		"notify_type": {notifyType},
+		"notify_id":   {hex.EncodeToString(notifyId)},
+		"charset":     {alipay.CharsetUTF8},
+		"version":     {alipay.ApiVersion},
+		"app_id":      {g.appId},
+		"auth_app_id": {g.appId},
+	}
+	if g.certMode {
+		values.Set("alipay_cert_sn", g.keys.AlipayCert.SN())
+	}
+	return values
+}
+
+// notifyTrade 交易状态变化时发送trade_status_sync通知，退款通知包含out_biz_no、refund_fee和gmt_refund
+func (g *Gateway) notifyTrade(trade *Trade, refund *Refund) {
+	notifyURL := trade.NotifyUrl
+	if len(notifyURL) == 0 {
+		notifyURL = g.notifyURL
+	}
+	if len(notifyURL) == 0 {
+		return
+	}
+	values := g.NotifyValues("trade_status_sync")
+	fields := map[string]string{
+		"trade_no":        trade.TradeNo,
+		"out_trade_no":    trade.OutTradeNo,
+		"buyer_id":        trade.BuyerId,
+		"buyer_logon_id":  trade.BuyerLogonId,
+		"seller_id":       DefaultSellerId,
+		"trade_status":    string(trade.Status),
+		"total_amount":    trade.TotalAmount.String(),
+		"subject":         trade.Subject,
+		"body":            trade.Body,
+		"passback_params": trade.PassbackParams,
+		"gmt_create":      g.formatTime(trade.GmtCreate),
+		"gmt_payment":     g.formatTime(trade.GmtPayment),
+		"gmt_close":       g.formatTime(trade.GmtClose),
+	}
+	if !trade.GmtPayment.IsZero() {
+		fields["receipt_amount"] = trade.TotalAmount.Sub(trade.RefundAmount).String()
+		fields["buyer_pay_amount"] = trade.TotalAmount.String()
+		fields["invoice_amount"] = trade.TotalAmount.String()
+		fundBill, _ := json.Marshal([]map[string]string{{"amount": trade.TotalAmount.String(), "fundChannel": "ALIPAYACCOUNT"}})
+		fields["fund_bill_list"] = string(fundBill)
+	}
+	if refund != nil {
+		fields["out_biz_no"] = refund.OutRequestNo
+		fields["refund_fee"] = trade.RefundAmount.String()
+		fields["gmt_refund"] = refund.GmtRefund.In(g.location).Format("2006-01-02 15:04:05.000")
+	}
+	for key, value := range fields {
+		if len(value) > 0 {
+			values.Set(key, value)
+		}
+	}
+	g.Notify(notifyURL, values)
+}
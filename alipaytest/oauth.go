@@ -0,0 +1,135 @@
+package alipaytest
+
+import (
+	"strconv"
+	"time"
+
+	alipay "github.com/try-labs/xpay"
+)
+
+/**
+ * @author: Sam
+ * @since: 1.0.0
+ * @date: 2026/10/20 04:00
+ * @desc: 用户授权令牌
+ *
+ * IssueOauthCode 模拟用户完成授权，返回的授权码换取的令牌属于指定用户，只能使用一次；
+ * 未通过 IssueOauthCode 生成的授权码换取的令牌属于 DefaultBuyerId。
+ * 刷新后原令牌立即失效，访问令牌超过 OauthExpiresIn 后失效，失效的令牌调用用户授权类接口时返回错误。
+ */
+
+const (
+	// OauthExpiresIn 访问令牌的有效期，单位秒
+	OauthExpiresIn = 1296000
+	// OauthReExpiresIn 刷新令牌的有效期，单位秒
+	OauthReExpiresIn = 2592000
+)
+
+// OauthToken 网关中的用户授权令牌
+type OauthToken struct {
+	UserId       string    // 支付宝用户ID
+	AccessToken  string    // 访问令牌
+	RefreshToken string    // 刷新令牌
+	AuthStart    time.Time // 授权开始时间
+	Status       string    // valid 或者 invalid，刷新后原令牌为invalid
+}
+
+// IssueOauthCode 模拟用户完成授权，返回授权回调中的auth_code
+func (g *Gateway) IssueOauthCode(userId string) string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	code := "auth" + g.nextSeq("6000")
+	g.oauthCodes[code] = userId
+	return code
+}
+
+// OauthToken 按访问令牌获取用户授权令牌的副本
+func (g *Gateway) OauthToken(accessToken string) (OauthToken, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	token, ok := g.oauthTokens[accessToken]
+	if !ok {
+		return OauthToken{}, false
+	}
+	return *token, true
+}
+
+// checkAuthToken 校验用户授权令牌是否有效
+func (g *Gateway) checkAuthToken(accessToken string) (*OauthToken, error) {
+	token, ok := g.oauthTokens[accessToken]
+	if !ok || token.Status != "valid" {
+		return nil, ErrInvalidAuthToken
+	}
+	if !g.Now().Before(token.AuthStart.Add(OauthExpiresIn * time.Second)) {
+		return nil, ErrAuthTokenTimeout
+	}
+	return token, nil
+}
+
+func (g *Gateway) issueOauthToken(userId string) *OauthToken {
+	token := &OauthToken{
+		UserId:       userId,
+		AccessToken:  "authusrB" + g.nextSeq("0001"),
+		RefreshToken: "authusrR" + g.nextSeq("0001"),
+		AuthStart:    g.Now(),
+		Status:       "valid",
+	}
+	g.oauthTokens[token.AccessToken] = token
+	return token
+}
+
+func handleSystemOauthToken(g *Gateway, req *Request) (interface{}, error) {
+	content := new(alipay.OauthTokenReq)
+	if err := req.Bind(content); err != nil {
+		return nil, ErrInvalidParameter
+	}
+	var token *OauthToken
+	switch content.GrantType {
+	case alipay.GrantRefreshToken:
+		var origin *OauthToken
+		for _, item := range g.oauthTokens {
+			if item.RefreshToken == content.RefreshToken && item.Status == "valid" {
+				origin = item
+				break
+			}
+		}
+		if origin == nil || !g.Now().Before(origin.AuthStart.Add(OauthReExpiresIn*time.Second)) {
+			return nil, ErrInvalidRefreshToken
+		}
+		origin.Status = "invalid"
+		token = g.issueOauthToken(origin.UserId)
+	default:
+		userId, ok := g.oauthCodes[content.Code]
+		if ok {
+			delete(g.oauthCodes, content.Code)
+		} else {
+			userId = DefaultBuyerId
+		}
+		token = g.issueOauthToken(userId)
+	}
+	return alipay.OauthTokenResContent{
+		CommonRes:    success,
+		UserId:       token.UserId,
+		AccessToken:  token.AccessToken,
+		ExpiresIn:    strconv.Itoa(OauthExpiresIn),
+		RefreshToken: token.RefreshToken,
+		ReExpiresIn:  strconv.Itoa(OauthReExpiresIn),
+		AuthStart:    g.formatTime(token.AuthStart),
+	}, nil
+}
+
+func handleUserInfoShare(g *Gateway, req *Request) (interface{}, error) {
+	content := new(alipay.UserInfoShareReq)
+	if err := req.Bind(content); err != nil {
+		return nil, ErrInvalidParameter
+	}
+	accessToken := content.AuthToken
+	if len(accessToken) == 0 {
+		accessToken = req.AuthToken
+	}
+	token, err := g.checkAuthToken(accessToken)
+	if err != nil {
+		return nil, err
+	}
+	return alipay.UserInfoShareResContent{CommonRes: success, UserId: token.UserId, NickName: "沙箱买家"}, nil
+}
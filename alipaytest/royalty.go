@@ -0,0 +1,221 @@
+package alipaytest
+
+import (
+	alipay "github.com/try-labs/xpay"
+)
+
+/**
+ * @author: Sam
+ * @since: 1.0.0
+ * @date: 2026/10/20 02:10
+ * @desc: 分账关系及交易结算
+ *
+ * 分账的收入方需要先绑定分账关系，交易的可分账金额为订单金额减去累计退款金额和累计分账金额，
+ * 分账金额之和超过可分账金额时返回 ErrAllocAmountExceed。同一交易重复的结算请求号直接返回原分账单号。
+ */
+
+// RoyaltyMaxRatio 分账比例查询返回的最大分账比例
+const RoyaltyMaxRatio = 30
+
+// Settle 网关中的分账请求
+type Settle struct {
+	SettleNo     string                        // 支付宝分账单号
+	TradeNo      string                        // 支付宝交易号
+	OutRequestNo string                        // 结算请求流水号
+	Details      []*alipay.RoyaltyDetailResult // 分账明细
+	OperationDt  string                        // 分账受理时间
+}
+
+// Settle 按分账单号获取分账请求的副本
+func (g *Gateway) Settle(settleNo string) (Settle, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	settle, ok := g.settles[settleNo]
+	if !ok {
+		return Settle{}, false
+	}
+	return *settle, true
+}
+
+// royaltyReceiverIndex 已绑定的分账接收方的下标，未绑定时返回-1
+func (g *Gateway) royaltyReceiverIndex(accountType, account string) int {
+	for i, receiver := range g.royaltyReceivers {
+		if receiver.Type == accountType && receiver.Account == account {
+			return i
+		}
+	}
+	return -1
+}
+
+func (g *Gateway) findSettle(settleNo, tradeNo, outRequestNo string) (*Settle, bool) {
+	if len(settleNo) > 0 {
+		settle, ok := g.settles[settleNo]
+		return settle, ok
+	}
+	for _, settle := range g.settles {
+		if settle.TradeNo == tradeNo && settle.OutRequestNo == outRequestNo {
+			return settle, true
+		}
+	}
+	return nil, false
+}
+
+// unsettledAmount 交易剩余的可分账金额
+func unsettledAmount(trade *Trade) alipay.Money {
+	if trade.SettleFinished {
+		return 0
+	}
+	return trade.TotalAmount.Sub(trade.RefundAmount).Sub(trade.SettledAmount)
+}
+
+func handleTradeRoyaltyRelationBind(g *Gateway, req *Request) (interface{}, error) {
+	content := new(alipay.TradeRoyaltyRelationBindReq)
+	if err := req.Bind(content); err != nil || len(content.ReceiverList) == 0 {
+		return nil, ErrInvalidParameter
+	}
+	for _, receiver := range content.ReceiverList {
+		if g.royaltyReceiverIndex(receiver.Type, receiver.Account) < 0 {
+			g.royaltyReceivers = append(g.royaltyReceivers, receiver)
+		}
+	}
+	return alipay.TradeRoyaltyRelationBindResContent{CommonRes: success, ResultCode: "SUCCESS"}, nil
+}
+
+func handleTradeRoyaltyRelationUnbind(g *Gateway, req *Request) (interface{}, error) {
+	content := new(alipay.TradeRoyaltyRelationUnbindReq)
+	if err := req.Bind(content); err != nil || len(content.ReceiverList) == 0 {
+		return nil, ErrInvalidParameter
+	}
+	for _, receiver := range content.ReceiverList {
+		if i := g.royaltyReceiverIndex(receiver.Type, receiver.Account); i >= 0 {
+			g.royaltyReceivers = append(g.royaltyReceivers[:i], g.royaltyReceivers[i+1:]...)
+		}
+	}
+	return alipay.TradeRoyaltyRelationUnbindResContent{CommonRes: success, ResultCode: "SUCCESS"}, nil
+}
+
+func handleTradeRoyaltyRelationBatchQuery(g *Gateway, req *Request) (interface{}, error) {
+	content := new(alipay.TradeRoyaltyRelationBatchQueryReq)
+	if err := req.Bind(content); err != nil {
+		return nil, ErrInvalidParameter
+	}
+	pageNum, pageSize := content.PageNum, content.PageSize
+	if pageNum <= 0 {
+		pageNum = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 10
+	}
+	total := len(g.royaltyReceivers)
+	start, end := (pageNum-1)*pageSize, pageNum*pageSize
+	if start > total {
+		start = total
+	}
+	if end > total {
+		end = total
+	}
+	return alipay.TradeRoyaltyRelationBatchQueryResContent{
+		CommonRes:       success,
+		ResultCode:      "SUCCESS",
+		ReceiverList:    g.royaltyReceivers[start:end],
+		TotalPageNum:    (total + pageSize - 1) / pageSize,
+		TotalRecordNum:  total,
+		CurrentPageNum:  pageNum,
+		CurrentPageSize: end - start,
+	}, nil
+}
+
+func handleTradeRoyaltyRateQuery(g *Gateway, req *Request) (interface{}, error) {
+	return alipay.TradeRoyaltyRateQueryResContent{CommonRes: success, UserId: DefaultSellerId, MaxRatio: RoyaltyMaxRatio}, nil
+}
+
+func handleTradeOrderSettle(g *Gateway, req *Request) (interface{}, error) {
+	content := new(alipay.TradeOrderSettleReq)
+	if err := req.Bind(content); err != nil || len(content.RoyaltyParameters) == 0 {
+		return nil, ErrInvalidParameter
+	}
+	trade, err := g.findTrade(req)
+	if err != nil {
+		return nil, err
+	}
+	if settle, ok := g.findSettle("", trade.TradeNo, content.OutRequestNo); ok {
+		return alipay.TradeOrderSettleResContent{CommonRes: success, TradeNo: trade.TradeNo, SettleNo: settle.SettleNo}, nil
+	}
+	if trade.Status != alipay.TradeSuccess && trade.Status != alipay.TradeFinished {
+		return nil, ErrTradeStatusError
+	}
+	if trade.SettleFinished {
+		return nil, ErrSettleFinished
+	}
+	var sum alipay.Money
+	for _, parameter := range content.RoyaltyParameters {
+		transInType := parameter.TransInType
+		if len(transInType) == 0 {
+			transInType = "userId"
+		}
+		if g.royaltyReceiverIndex(transInType, parameter.TransIn) < 0 {
+			return nil, ErrRoyaltyReceiverNotBind
+		}
+		if !parameter.Amount.IsPositive() {
+			return nil, ErrInvalidParameter
+		}
+		sum = sum.Add(parameter.Amount)
+	}
+	if sum.Cmp(unsettledAmount(trade)) > 0 {
+		return nil, ErrAllocAmountExceed
+	}
+	now := g.formatTime(g.Now())
+	settle := &Settle{SettleNo: g.nextSeq("2000"), TradeNo: trade.TradeNo, OutRequestNo: content.OutRequestNo, OperationDt: now}
+	for _, parameter := range content.RoyaltyParameters {
+		operationType := parameter.RoyaltyType
+		if len(operationType) == 0 {
+			operationType = "transfer"
+		}
+		transInType := parameter.TransInType
+		if len(transInType) == 0 {
+			transInType = "userId"
+		}
+		settle.Details = append(settle.Details, &alipay.RoyaltyDetailResult{
+			OperationType: operationType,
+			ExecuteDt:     now,
+			TransOut:      DefaultSellerId,
+			TransOutType:  "userId",
+			TransIn:       parameter.TransIn,
+			TransInType:   transInType,
+			Amount:        parameter.Amount,
+			State:         "SUCCESS",
+			DetailId:      g.nextSeq("2001"),
+		})
+	}
+	g.settles[settle.SettleNo] = settle
+	trade.SettledAmount = trade.SettledAmount.Add(sum)
+	if content.ExtendParams != nil && content.ExtendParams.RoyaltyFinish == "true" {
+		trade.SettleFinished = true
+	}
+	return alipay.TradeOrderSettleResContent{CommonRes: success, TradeNo: trade.TradeNo, SettleNo: settle.SettleNo}, nil
+}
+
+func handleTradeOrderSettleQuery(g *Gateway, req *Request) (interface{}, error) {
+	content := new(alipay.TradeOrderSettleQueryReq)
+	if err := req.Bind(content); err != nil {
+		return nil, ErrInvalidParameter
+	}
+	settle, ok := g.findSettle(content.SettleNo, content.TradeNo, content.OutRequestNo)
+	if !ok {
+		return nil, ErrSettleNotExist
+	}
+	return alipay.TradeOrderSettleQueryResContent{
+		CommonRes:         success,
+		OutRequestNo:      settle.OutRequestNo,
+		OperationDt:       settle.OperationDt,
+		RoyaltyDetailList: settle.Details,
+	}, nil
+}
+
+func handleTradeOrderOnSettleQuery(g *Gateway, req *Request) (interface{}, error) {
+	trade, err := g.findTrade(req)
+	if err != nil {
+		return nil, err
+	}
+	return alipay.TradeOrderOnSettleQueryResContent{CommonRes: success, UnsettledAmount: unsettledAmount(trade)}, nil
+}
@@ -0,0 +1,248 @@
+package alipaytest
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	alipay "github.com/try-labs/xpay"
+	"github.com/try-labs/xpay/subscription"
+)
+
+/**
+ * @author: Sam
+ * @since: 1.0.0
+ * @date: 2026/10/20 05:40
+ * @desc: 内存中的存储，供测试中的 RefundManager、PayoutEngine 等使用
+ */
+
+var _ alipay.RefundStore = &RefundStore{}
+
+// RefundStore 实现 alipay.RefundStore，按商户订单号保存退款记录
+type RefundStore struct {
+	mu      sync.Mutex
+	refunds map[string][]alipay.RefundRecord
+}
+
+func NewRefundStore() *RefundStore {
+	return &RefundStore{refunds: make(map[string][]alipay.RefundRecord)}
+}
+
+func (r *RefundStore) Refunds(ctx context.Context, outTradeNo string) ([]*alipay.RefundRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	list := make([]*alipay.RefundRecord, 0, len(r.refunds[outTradeNo]))
+	for _, record := range r.refunds[outTradeNo] {
+		record := record
+		list = append(list, &record)
+	}
+	return list, nil
+}
+
+func (r *RefundStore) Save(ctx context.Context, record *alipay.RefundRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	list := r.refunds[record.OutTradeNo]
+	for i := range list {
+		if list[i].OutRequestNo == record.OutRequestNo {
+			list[i] = *record
+			return nil
+		}
+	}
+	r.refunds[record.OutTradeNo] = append(list, *record)
+	return nil
+}
+
+var _ alipay.PayoutStore = &PayoutStore{}
+
+// PayoutStore 实现 alipay.PayoutStore，按批次号和商户付款单号保存付款记录
+type PayoutStore struct {
+	mu      sync.Mutex
+	payouts map[string]alipay.PayoutRecord
+}
+
+func NewPayoutStore() *PayoutStore {
+	return &PayoutStore{payouts: make(map[string]alipay.PayoutRecord)}
+}
+
+func (r *PayoutStore) Payout(ctx context.Context, batchNo, bizNo string) (*alipay.PayoutRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	record, ok := r.payouts[batchNo+"\x00"+bizNo]
+	if !ok {
+		return nil, nil
+	}
+	return &record, nil
+}
+
+func (r *PayoutStore) Save(ctx context.Context, record *alipay.PayoutRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.payouts[record.BatchNo+"\x00"+record.Payee.BizNo] = *record
+	return nil
+}
+
+var _ alipay.AppAuthStore = &AppAuthStore{}
+
+// AppAuthStore 实现 alipay.AppAuthStore，按商户的user_id保存应用授权令牌
+type AppAuthStore struct {
+	mu    sync.Mutex
+	auths map[string]alipay.AppAuth
+}
+
+func NewAppAuthStore() *AppAuthStore {
+	return &AppAuthStore{auths: make(map[string]alipay.AppAuth)}
+}
+
+func (r *AppAuthStore) AppAuth(ctx context.Context, userId string) (*alipay.AppAuth, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	auth, ok := r.auths[userId]
+	if !ok {
+		return nil, nil
+	}
+	return &auth, nil
+}
+
+func (r *AppAuthStore) Expiring(ctx context.Context, before time.Time) ([]*alipay.AppAuth, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var auths []*alipay.AppAuth
+	for _, auth := range r.auths {
+		if !auth.ExpiresAt.IsZero() && auth.ExpiresAt.Before(before) {
+			auth := auth
+			auths = append(auths, &auth)
+		}
+	}
+	return auths, nil
+}
+
+func (r *AppAuthStore) Save(ctx context.Context, auth *alipay.AppAuth) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.auths[auth.UserId] = *auth
+	return nil
+}
+
+func (r *AppAuthStore) Delete(ctx context.Context, userId string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.auths, userId)
+	return nil
+}
+
+var _ alipay.OauthTokenStore = &OauthTokenStore{}
+
+// OauthTokenStore 实现 alipay.OauthTokenStore，按用户的user_id保存用户授权令牌
+type OauthTokenStore struct {
+	mu     sync.Mutex
+	tokens map[string]alipay.OauthToken
+}
+
+func NewOauthTokenStore() *OauthTokenStore {
+	return &OauthTokenStore{tokens: make(map[string]alipay.OauthToken)}
+}
+
+func (r *OauthTokenStore) OauthToken(ctx context.Context, userId string) (*alipay.OauthToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	token, ok := r.tokens[userId]
+	if !ok {
+		return nil, nil
+	}
+	return &token, nil
+}
+
+func (r *OauthTokenStore) Save(ctx context.Context, token *alipay.OauthToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tokens[token.UserId] = *token
+	return nil
+}
+
+func (r *OauthTokenStore) Delete(ctx context.Context, userId string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.tokens, userId)
+	return nil
+}
+
+var _ subscription.Store = &SubscriptionStore{}
+
+// SubscriptionStore 实现 subscription.Store，按商户订阅号保存订阅，扣款记录按追加的顺序保存
+type SubscriptionStore struct {
+	mu            sync.Mutex
+	subscriptions map[string]subscription.Subscription
+	attempts      []subscription.Attempt
+}
+
+func NewSubscriptionStore() *SubscriptionStore {
+	return &SubscriptionStore{subscriptions: make(map[string]subscription.Subscription)}
+}
+
+func (r *SubscriptionStore) Subscription(ctx context.Context, id string) (*subscription.Subscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sub, ok := r.subscriptions[id]
+	if !ok {
+		return nil, nil
+	}
+	return &sub, nil
+}
+
+func (r *SubscriptionStore) AgreementSubscriptions(ctx context.Context, agreementNo string) ([]*subscription.Subscription, error) {
+	return r.filter(func(sub *subscription.Subscription) bool {
+		return sub.AgreementNo == agreementNo
+	}), nil
+}
+
+func (r *SubscriptionStore) Due(ctx context.Context, now time.Time) ([]*subscription.Subscription, error) {
+	subs := r.filter(func(sub *subscription.Subscription) bool {
+		return sub.Status.Billable() && !sub.DueAt.After(now)
+	})
+	sort.Slice(subs, func(i, j int) bool {
+		return subs[i].DueAt.Before(subs[j].DueAt)
+	})
+	return subs, nil
+}
+
+func (r *SubscriptionStore) filter(match func(sub *subscription.Subscription) bool) []*subscription.Subscription {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var subs []*subscription.Subscription
+	for _, sub := range r.subscriptions {
+		sub := sub
+		if match(&sub) {
+			subs = append(subs, &sub)
+		}
+	}
+	return subs
+}
+
+func (r *SubscriptionStore) Save(ctx context.Context, sub *subscription.Subscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subscriptions[sub.Id] = *sub
+	return nil
+}
+
+func (r *SubscriptionStore) AddAttempt(ctx context.Context, attempt *subscription.Attempt) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.attempts = append(r.attempts, *attempt)
+	return nil
+}
+
+// Attempts 订阅的扣款记录，按追加的顺序
+func (r *SubscriptionStore) Attempts(subscriptionId string) []subscription.Attempt {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var attempts []subscription.Attempt
+	for _, attempt := range r.attempts {
+		if attempt.SubscriptionId == subscriptionId {
+			attempts = append(attempts, attempt)
+		}
+	}
+	return attempts
+}
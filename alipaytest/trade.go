@@ -0,0 +1,485 @@
+package alipaytest
+
+import (
+	"net/url"
+	"time"
+
+	alipay "github.com/try-labs/xpay"
+)
+
+/**
+ * @author: Sam
+ * @since: 1.0.0
+ * @date: 2026/10/19 16:20
+ * @desc: 交易和退款的内存状态及相关接口
+ */
+
+// Trade 网关中的交易
+type Trade struct {
+	TradeNo        string             // 支付宝交易号
+	OutTradeNo     string             // 商户订单号
+	Method         string             // 下单接口
+	Subject        string             // 订单标题
+	Body           string             // 订单描述
+	TotalAmount    alipay.Money       // 订单金额
+	RefundAmount   alipay.Money       // 累计退款金额
+	SettledAmount  alipay.Money       // 累计分账金额
+	SettleFinished bool               // 分账已完结，剩余金额不能再分账
+	Status         alipay.TradeStatus // 交易状态
+	BuyerId        string             // 买家支付宝用户ID
+	BuyerLogonId   string             // 买家支付宝账号
+	PassbackParams string             // 公用回传参数
+	NotifyUrl      string             // 异步通知地址
+	QrCode         string             // 预下单二维码
+	PreOrderNo     string             // 合并支付的预下单号
+	GmtCreate      time.Time          // 创建时间
+	GmtPayment     time.Time          // 付款时间
+	GmtClose       time.Time          // 关闭时间
+}
+
+// Refund 网关中的退款
+type Refund struct {
+	TradeNo      string       // 支付宝交易号
+	OutTradeNo   string       // 商户订单号
+	OutRequestNo string       // 退款请求号
+	RefundReason string       // 退款原因
+	RefundAmount alipay.Money // 退款金额
+	GmtRefund    time.Time    // 退款时间
+}
+
+// tradeContent 各下单接口共用的业务参数
+type tradeContent struct {
+	OutTradeNo     string       `json:"out_trade_no"`
+	TotalAmount    alipay.Money `json:"total_amount"`
+	Subject        string       `json:"subject"`
+	Body           string       `json:"body"`
+	BuyerId        string       `json:"buyer_id"`
+	AuthCode       string       `json:"auth_code"`
+	PassbackParams string       `json:"passback_params"`
+	// AgreementParams 按代扣协议扣款
+	AgreementParams *struct {
+		AgreementNo string `json:"agreement_no"`
+	} `json:"agreement_params"`
+	// AuthNo、AuthConfirmMode 预授权转支付
+	AuthNo          string `json:"auth_no"`
+	AuthConfirmMode string `json:"auth_confirm_mode"`
+}
+
+// tradeKey 查询类接口的交易号，trade_no 优先
+type tradeKey struct {
+	OutTradeNo string `json:"out_trade_no"`
+	TradeNo    string `json:"trade_no"`
+}
+
+var success = alipay.CommonRes{Code: "10000", Msg: "Success"}
+
+// Trade 按商户订单号获取交易的副本
+func (g *Gateway) Trade(outTradeNo string) (Trade, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	trade, ok := g.trades[outTradeNo]
+	if !ok {
+		return Trade{}, false
+	}
+	return *trade, true
+}
+
+// Refunds 获取交易的全部退款
+func (g *Gateway) Refunds(outTradeNo string) []Refund {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	list := make([]Refund, 0)
+	for _, refund := range g.refunds {
+		if refund.OutTradeNo == outTradeNo {
+			list = append(list, *refund)
+		}
+	}
+	return list
+}
+
+// RequirePassword 条码支付使用该付款码时需要用户输入密码，alipay.trade.pay 返回10003，之后通过 PayTrade 完成付款
+func (g *Gateway) RequirePassword(authCode string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.passwords[authCode] = true
+}
+
+// PayTrade 模拟买家完成付款，buyerId为空时使用 DefaultBuyerId
+func (g *Gateway) PayTrade(outTradeNo, buyerId string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	trade, ok := g.trades[outTradeNo]
+	if !ok {
+		return ErrTradeNotExist
+	}
+	if trade.Status != alipay.TradeWaitBuyerPay {
+		return ErrTradeStatusError
+	}
+	if len(buyerId) > 0 {
+		trade.BuyerId = buyerId
+	}
+	g.payTrade(trade)
+	return nil
+}
+
+// CloseTrade 模拟未付款的交易超时关闭
+func (g *Gateway) CloseTrade(outTradeNo string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	trade, ok := g.trades[outTradeNo]
+	if !ok {
+		return ErrTradeNotExist
+	}
+	if trade.Status != alipay.TradeWaitBuyerPay {
+		return ErrTradeStatusError
+	}
+	g.closeTrade(trade)
+	g.notifyTrade(trade, nil)
+	return nil
+}
+
+// SubmitOrderString 模拟支付宝客户端提交 alipay.trade.app.pay 的订单串，校验签名并创建待支付的交易
+func (g *Gateway) SubmitOrderString(orderString string) (Trade, error) {
+	values, err := url.ParseQuery(orderString)
+	if err != nil {
+		return Trade{}, err
+	}
+	req, err := g.parseRequest(values)
+	if err != nil {
+		return Trade{}, err
+	}
+	content, err := g.dispatch(req)
+	if err != nil {
+		return Trade{}, err
+	}
+	return content.(Trade), nil
+}
+
+func (g *Gateway) createTrade(req *Request, content *tradeContent) (*Trade, error) {
+	if len(content.OutTradeNo) == 0 || !content.TotalAmount.IsPositive() {
+		return nil, ErrInvalidParameter
+	}
+	if trade, ok := g.trades[content.OutTradeNo]; ok {
+		switch trade.Status {
+		case alipay.TradeSuccess, alipay.TradeFinished:
+			return nil, ErrTradeHasSuccess
+		case alipay.TradeClosed:
+			return nil, ErrTradeHasClose
+		}
+		if trade.TotalAmount != content.TotalAmount {
+			return nil, ErrContextInconsistent
+		}
+		return trade, nil
+	}
+	trade := &Trade{
+		TradeNo:        g.nextSeq("2200"),
+		OutTradeNo:     content.OutTradeNo,
+		Method:         req.Method,
+		Subject:        content.Subject,
+		Body:           content.Body,
+		TotalAmount:    content.TotalAmount,
+		Status:         alipay.TradeWaitBuyerPay,
+		BuyerId:        content.BuyerId,
+		PassbackParams: content.PassbackParams,
+		NotifyUrl:      req.NotifyUrl,
+		GmtCreate:      g.Now(),
+	}
+	g.trades[trade.OutTradeNo] = trade
+	g.tradeNos[trade.TradeNo] = trade
+	return trade, nil
+}
+
+func (g *Gateway) payTrade(trade *Trade) {
+	if len(trade.BuyerId) == 0 {
+		trade.BuyerId = DefaultBuyerId
+	}
+	trade.BuyerLogonId = DefaultBuyerLogonId
+	trade.Status = alipay.TradeSuccess
+	trade.GmtPayment = g.Now()
+	// 合并支付的子订单由 notifyMerge 统一通知
+	if len(trade.PreOrderNo) == 0 {
+		g.notifyTrade(trade, nil)
+	}
+}
+
+func (g *Gateway) closeTrade(trade *Trade) {
+	trade.Status = alipay.TradeClosed
+	trade.GmtClose = g.Now()
+}
+
+func (g *Gateway) findTrade(req *Request) (*Trade, error) {
+	key := new(tradeKey)
+	if err := req.Bind(key); err != nil {
+		return nil, ErrInvalidParameter
+	}
+	if len(key.TradeNo) > 0 {
+		if trade, ok := g.tradeNos[key.TradeNo]; ok {
+			return trade, nil
+		}
+		return nil, ErrTradeNotExist
+	}
+	if trade, ok := g.trades[key.OutTradeNo]; ok {
+		return trade, nil
+	}
+	return nil, ErrTradeNotExist
+}
+
+func refundKey(tradeNo, outRequestNo string) string {
+	return tradeNo + "_" + outRequestNo
+}
+
+// handlePageCreate alipay.trade.page.pay、alipay.trade.wap.pay、alipay.trade.app.pay 创建待支付的交易
+func handlePageCreate(g *Gateway, req *Request) (interface{}, error) {
+	content := new(tradeContent)
+	if err := req.Bind(content); err != nil {
+		return nil, ErrInvalidParameter
+	}
+	trade, err := g.createTrade(req, content)
+	if err != nil {
+		return nil, err
+	}
+	return *trade, nil
+}
+
+func handleTradeCreate(g *Gateway, req *Request) (interface{}, error) {
+	content := new(tradeContent)
+	if err := req.Bind(content); err != nil {
+		return nil, ErrInvalidParameter
+	}
+	if len(content.BuyerId) == 0 {
+		return nil, ErrInvalidParameter
+	}
+	trade, err := g.createTrade(req, content)
+	if err != nil {
+		return nil, err
+	}
+	return alipay.TradeCreateResContent{CommonRes: success, OutTradeNo: trade.OutTradeNo, TradeNo: trade.TradeNo}, nil
+}
+
+func handleTradePreCreate(g *Gateway, req *Request) (interface{}, error) {
+	content := new(tradeContent)
+	if err := req.Bind(content); err != nil {
+		return nil, ErrInvalidParameter
+	}
+	trade, err := g.createTrade(req, content)
+	if err != nil {
+		return nil, err
+	}
+	if len(trade.QrCode) == 0 {
+		trade.QrCode = "https://qr.alipay.com/bax" + trade.TradeNo[len(trade.TradeNo)-16:]
+	}
+	return alipay.TradePreCreateResContent{CommonRes: success, OutTradeNo: trade.OutTradeNo, QrCode: trade.QrCode}, nil
+}
+
+// handleTradePay 条码支付，默认直接支付成功，需要输入密码的付款码返回10003
+func handleTradePay(g *Gateway, req *Request) (interface{}, error) {
+	content := new(tradeContent)
+	if err := req.Bind(content); err != nil {
+		return nil, ErrInvalidParameter
+	}
+	if len(content.AuthCode) == 0 && content.AgreementParams == nil && len(content.AuthNo) == 0 {
+		return nil, ErrInvalidParameter
+	}
+	trade, err := g.createTrade(req, content)
+	if err != nil {
+		return nil, err
+	}
+	res := alipay.TradePayResContent{
+		CommonRes:   success,
+		TradeNo:     trade.TradeNo,
+		OutTradeNo:  trade.OutTradeNo,
+		TotalAmount: trade.TotalAmount,
+	}
+	switch {
+	case content.AgreementParams != nil:
+		if err = g.agreementPay(trade, content.AgreementParams.AgreementNo); err != nil {
+			return nil, err
+		}
+	case len(content.AuthNo) > 0:
+		if err = g.fundAuthPay(trade, content.AuthNo, content.AuthConfirmMode); err != nil {
+			return nil, err
+		}
+	case g.passwords[content.AuthCode]:
+		res.CommonRes = alipay.CommonRes{Code: "10003", Msg: "order success pay inprocess"}
+		return res, nil
+	default:
+		g.payTrade(trade)
+	}
+	res.BuyerLogonId = trade.BuyerLogonId
+	res.BuyerUserId = trade.BuyerId
+	res.ReceiptAmount = trade.TotalAmount
+	res.BuyerPayAmount = trade.TotalAmount
+	res.GmtPayment = g.formatTime(trade.GmtPayment)
+	res.FundBillList = []*alipay.FundBill{{FundChannel: "ALIPAYACCOUNT", Amount: trade.TotalAmount}}
+	return res, nil
+}
+
+func handleTradeQuery(g *Gateway, req *Request) (interface{}, error) {
+	trade, err := g.findTrade(req)
+	if err != nil {
+		return nil, err
+	}
+	res := alipay.TradeQueryResContent{
+		CommonRes:   success,
+		OutTradeNo:  trade.OutTradeNo,
+		TradeNo:     trade.TradeNo,
+		TradeStatus: trade.Status,
+		TotalAmount: trade.TotalAmount,
+		Subject:     trade.Subject,
+		Body:        trade.Body,
+		BuyerUserId: trade.BuyerId,
+	}
+	if !trade.GmtPayment.IsZero() {
+		res.BuyerPayAmount = trade.TotalAmount
+		res.ReceiptAmount = trade.TotalAmount.Sub(trade.RefundAmount)
+		res.SendPayDate = g.formatTime(trade.GmtPayment)
+	}
+	return res, nil
+}
+
+func handleTradeClose(g *Gateway, req *Request) (interface{}, error) {
+	trade, err := g.findTrade(req)
+	if err != nil {
+		return nil, err
+	}
+	if trade.Status != alipay.TradeWaitBuyerPay {
+		return nil, ErrTradeStatusError
+	}
+	g.closeTrade(trade)
+	return alipay.TradeCloseResContent{CommonRes: success, OutTradeNo: trade.OutTradeNo, TradeNo: trade.TradeNo}, nil
+}
+
+// handleTradeCancel 未付款的交易关闭，已付款的交易全额退款，交易不存在时创建已关闭的交易防止后续付款
+func handleTradeCancel(g *Gateway, req *Request) (interface{}, error) {
+	key := new(tradeKey)
+	if err := req.Bind(key); err != nil {
+		return nil, ErrInvalidParameter
+	}
+	trade, err := g.findTrade(req)
+	if err == ErrTradeNotExist && len(key.OutTradeNo) > 0 {
+		trade = &Trade{TradeNo: g.nextSeq("2200"), OutTradeNo: key.OutTradeNo, Method: req.Method, GmtCreate: g.Now()}
+		g.closeTrade(trade)
+		g.trades[trade.OutTradeNo] = trade
+		g.tradeNos[trade.TradeNo] = trade
+	} else if err != nil {
+		return nil, err
+	}
+	res := alipay.TradeCancelResContent{CommonRes: success, TradeNo: trade.TradeNo, OutTradeNo: trade.OutTradeNo, RetryFlag: "N", Action: "close"}
+	switch trade.Status {
+	case alipay.TradeWaitBuyerPay:
+		g.closeTrade(trade)
+	case alipay.TradeSuccess:
+		refund := &Refund{
+			TradeNo:      trade.TradeNo,
+			OutTradeNo:   trade.OutTradeNo,
+			OutRequestNo: trade.OutTradeNo,
+			RefundAmount: trade.TotalAmount.Sub(trade.RefundAmount),
+			GmtRefund:    g.Now(),
+		}
+		g.refunds[refundKey(trade.TradeNo, refund.OutRequestNo)] = refund
+		trade.RefundAmount = trade.TotalAmount
+		g.closeTrade(trade)
+		g.notifyTrade(trade, refund)
+		res.Action = "refund"
+	case alipay.TradeFinished:
+		return nil, ErrTradeStatusError
+	}
+	return res, nil
+}
+
+// handleTradeRefund 同一退款请求号重复请求时返回原退款结果，不会重复退款
+func handleTradeRefund(g *Gateway, req *Request) (interface{}, error) {
+	content := new(alipay.TradeRefundReq)
+	if err := req.Bind(content); err != nil {
+		return nil, ErrInvalidParameter
+	}
+	trade, err := g.findTrade(req)
+	if err != nil {
+		return nil, err
+	}
+	outRequestNo := content.OutRequestNo
+	if len(outRequestNo) == 0 {
+		outRequestNo = trade.OutTradeNo
+	}
+	res := alipay.TradeRefundResContent{
+		CommonRes:    success,
+		TradeNo:      trade.TradeNo,
+		OutTradeNo:   trade.OutTradeNo,
+		BuyerLogonId: trade.BuyerLogonId,
+		BuyerUserId:  trade.BuyerId,
+		FundChange:   "N",
+	}
+	if _, ok := g.refunds[refundKey(trade.TradeNo, outRequestNo)]; ok {
+		res.RefundFee = trade.RefundAmount
+		return res, nil
+	}
+	if trade.Status != alipay.TradeSuccess {
+		return nil, ErrTradeStatusError
+	}
+	if !content.RefundAmount.IsPositive() {
+		return nil, ErrInvalidParameter
+	}
+	if trade.RefundAmount.Add(content.RefundAmount).Cmp(trade.TotalAmount) > 0 {
+		return nil, ErrRefundAmountExceed
+	}
+	refund := &Refund{
+		TradeNo:      trade.TradeNo,
+		OutTradeNo:   trade.OutTradeNo,
+		OutRequestNo: outRequestNo,
+		RefundReason: content.RefundReason,
+		RefundAmount: content.RefundAmount,
+		GmtRefund:    g.Now(),
+	}
+	g.refunds[refundKey(trade.TradeNo, outRequestNo)] = refund
+	trade.RefundAmount = trade.RefundAmount.Add(refund.RefundAmount)
+	if trade.RefundAmount == trade.TotalAmount {
+		g.closeTrade(trade)
+	}
+	g.notifyTrade(trade, refund)
+	res.FundChange = "Y"
+	res.RefundFee = trade.RefundAmount
+	return res, nil
+}
+
+// handleTradeFastPayRefundQuery 退款不存在时与支付宝一致，返回成功但不返回refund_status
+func handleTradeFastPayRefundQuery(g *Gateway, req *Request) (interface{}, error) {
+	content := new(alipay.TradeFastPayRefundQueryReq)
+	if err := req.Bind(content); err != nil {
+		return nil, ErrInvalidParameter
+	}
+	trade, err := g.findTrade(req)
+	if err != nil {
+		return nil, err
+	}
+	res := alipay.TradeFastPayRefundQueryResContent{CommonRes: success, TradeNo: trade.TradeNo, OutTradeNo: trade.OutTradeNo}
+	refund, ok := g.refunds[refundKey(trade.TradeNo, content.OutRequestNo)]
+	if !ok {
+		return res, nil
+	}
+	res.OutRequestNo = refund.OutRequestNo
+	res.RefundReason = refund.RefundReason
+	res.TotalAmount = trade.TotalAmount
+	res.RefundAmount = refund.RefundAmount
+	res.RefundStatus = "REFUND_SUCCESS"
+	res.GMTRefundPay = g.formatTime(refund.GmtRefund)
+	return res, nil
+}
+
+func handleTradeOrderInfoSync(g *Gateway, req *Request) (interface{}, error) {
+	trade, err := g.findTrade(req)
+	if err != nil {
+		return nil, err
+	}
+	return alipay.TradeOrderInfoSyncResContent{CommonRes: success, TradeNo: trade.TradeNo, OutTradeNo: trade.OutTradeNo, BuyerUserId: trade.BuyerId}, nil
+}
+
+func handleBillDownloadUrlQuery(g *Gateway, req *Request) (interface{}, error) {
+	content := new(alipay.DataServiceBillDownloadUrlQueryReq)
+	if err := req.Bind(content); err != nil {
+		return nil, ErrInvalidParameter
+	}
+	if _, ok := g.bills[content.BillType+"_"+content.BillDate]; !ok {
+		return nil, ErrBillNotExist
+	}
+	return alipay.DataServiceBillDownloadUrlQueryResContent{CommonRes: success, BillDownloadUrl: g.billURL(content.BillType, content.BillDate)}, nil
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	alipay "github.com/try-labs/xpay"
+)
+
+/**
+ * @author: Sam
+ * @since: 1.0.0
+ * @date: 2026/10/19 19:40
+ * @desc: 根据配置文件调用任意接口
+ */
+
+// config 配置文件，配置了app_public_cert时使用证书模式，否则使用公钥模式
+type config struct {
+	AppId            string `json:"app_id"`
+	PrivateKey       string `json:"private_key"`        // 应用私钥，支持@文件路径
+	AppPublicKey     string `json:"app_public_key"`     // 应用公钥，为空时由应用私钥计算
+	AlipayPublicKey  string `json:"alipay_public_key"`  // 支付宝公钥，支持@文件路径
+	AppPublicCert    string `json:"app_public_cert"`    // 应用公钥证书文件
+	AlipayRootCert   string `json:"alipay_root_cert"`   // 支付宝根证书文件
+	AlipayPublicCert string `json:"alipay_public_cert"` // 支付宝公钥证书文件
+	ServerUrl        string `json:"server_url"`         // 网关地址，为空时使用沙箱环境
+	IsProd           bool   `json:"is_prod"`            // 是否生产环境
+	AppAuthToken     string `json:"app_auth_token"`
+	NotifyUrl        string `json:"notify_url"`
+}
+
+func (c *cli) loadConfig(filename string) (*config, error) {
+	buff, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	conf := new(config)
+	if err = json.Unmarshal(buff, conf); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	if len(conf.AppId) == 0 || len(conf.PrivateKey) == 0 {
+		return nil, errors.New("config: app_id and private_key are required")
+	}
+	return conf, nil
+}
+
+// signVerifier 签名策略的构造函数在密钥有误时会panic，这里提前解析并转换为错误
+func (c *cli) signVerifier(conf *config) (alipay.SignVerifier, error) {
+	buff, err := c.readValue(conf.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	privateKey, _, err := parsePrivateKey(string(buff))
+	if err != nil {
+		return nil, fmt.Errorf("private_key: %w", err)
+	}
+	if len(conf.AppPublicCert) > 0 {
+		for _, filename := range []string{conf.AppPublicCert, conf.AlipayRootCert, conf.AlipayPublicCert} {
+			if _, err = os.Stat(filename); err != nil {
+				return nil, err
+			}
+		}
+		if _, _, err = loadCert(conf.AlipayPublicCert); err != nil {
+			return nil, fmt.Errorf("alipay_public_cert: %w", err)
+		}
+		return alipay.NewCertSignStrategy(conf.AppId, string(buff), conf.AppPublicCert, conf.AlipayRootCert, conf.AlipayPublicCert), nil
+	}
+	appPublicKey := conf.AppPublicKey
+	if len(appPublicKey) == 0 {
+		der, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		appPublicKey = base64.StdEncoding.EncodeToString(der)
+	} else if _, err = parsePublicKey(appPublicKey); err != nil {
+		return nil, fmt.Errorf("app_public_key: %w", err)
+	}
+	alipayPublicKey, err := c.readValue(conf.AlipayPublicKey)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = parsePublicKey(string(alipayPublicKey)); err != nil {
+		return nil, fmt.Errorf("alipay_public_key: %w", err)
+	}
+	return alipay.NewNormalRSA2SignStrategy(conf.AppId, string(buff), appPublicKey, string(alipayPublicKey)), nil
+}
+
+func (c *cli) exec(args []string) error {
+	flagSet := c.flagSet("exec")
+	configFile := flagSet.String("config", "xpay.json", "配置文件")
+	method := flagSet.String("method", "", "接口名称，如 alipay.trade.query")
+	bizContent := flagSet.String("biz-content", "{}", "业务参数json，@文件路径，或 - 从标准输入读取")
+	timeout := flagSet.Duration("timeout", 10*time.Second, "请求超时时间")
+	if err := flagSet.Parse(args); err != nil {
+		return err
+	}
+	if len(*method) == 0 {
+		flagSet.Usage()
+		return errUsage
+	}
+	conf, err := c.loadConfig(*configFile)
+	if err != nil {
+		return err
+	}
+	signVerifier, err := c.signVerifier(conf)
+	if err != nil {
+		return err
+	}
+	optsFunc := []alipay.ClientOptFunc{alipay.SetClientOptIsProd(conf.IsProd)}
+	if len(conf.ServerUrl) > 0 {
+		optsFunc = append(optsFunc, alipay.SetServerUrl(conf.ServerUrl))
+	}
+	client, err := alipay.NewClient(signVerifier, optsFunc...)
+	if err != nil {
+		return err
+	}
+	buff, err := c.readValue(*bizContent)
+	if err != nil {
+		return err
+	}
+	req, err := alipay.NewGenericReq(*method, json.RawMessage(bytes.TrimSpace(buff)))
+	if err != nil {
+		return err
+	}
+	ctx, cancelFunc := context.WithTimeout(context.Background(), *timeout)
+	defer cancelFunc()
+	res, err := client.Execute(ctx, *req, alipay.WithNotifyUrl(conf.NotifyUrl), alipay.WithAppAuthToken(conf.AppAuthToken))
+	if err != nil {
+		return err
+	}
+	var indent bytes.Buffer
+	if err = json.Indent(&indent, res.Content, "", "  "); err != nil {
+		return err
+	}
+	c.printf("%s\n", indent.Bytes())
+	if res.Fail() {
+		return fmt.Errorf("xpay: %s %s %s %s", res.Code, res.Msg, res.SubCode, res.SubMsg)
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	alipay "github.com/try-labs/xpay"
+)
+
+/**
+ * @author: Sam
+ * @since: 1.0.0
+ * @date: 2026/10/19 19:20
+ * @desc: 密钥和证书
+ */
+
+// parsePrivateKey 支持PKCS1、PKCS8格式，带或不带PEM头尾均可
+func parsePrivateKey(raw string) (*rsa.PrivateKey, string, error) {
+	if key, err := alipay.ParsePKCS8PrivateKey(alipay.FormatPKCS8PrivateKey(raw)); err == nil {
+		return key, "PKCS8", nil
+	}
+	key, err := alipay.ParsePKCS1PrivateKey(alipay.FormatPKCS1PrivateKey(raw))
+	if err != nil {
+		return nil, "", alipay.ErrLoadPrivateKey
+	}
+	return key, "PKCS1", nil
+}
+
+func parsePublicKey(raw string) (*rsa.PublicKey, error) {
+	return alipay.ParsePublicKey(alipay.FormatPublicKey(raw))
+}
+
+func (c *cli) key(args []string) error {
+	flagSet := c.flagSet("key")
+	in := flagSet.String("in", "-", "密钥内容，@文件路径，或 - 从标准输入读取")
+	keyType := flagSet.String("type", "", "密钥类型 private|public，为空时自动识别")
+	public := flagSet.Bool("public", false, "私钥时同时输出对应的公钥")
+	raw := flagSet.Bool("raw", false, "输出不带PEM头尾的单行格式")
+	if err := flagSet.Parse(args); err != nil {
+		return err
+	}
+	buff, err := c.readValue(*in)
+	if err != nil {
+		return err
+	}
+	// 私钥统一输出为PKCS8格式，与支付宝开放平台密钥工具生成的格式一致
+	output := func(title, blockType string, der []byte) {
+		if *raw {
+			c.printf("%s: %s\n", title, base64.StdEncoding.EncodeToString(der))
+			return
+		}
+		c.printf("%s:\n%s", title, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}))
+	}
+	if *keyType != "public" {
+		privateKey, format, err := parsePrivateKey(string(buff))
+		if err == nil {
+			der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+			if err != nil {
+				return err
+			}
+			c.printf("format: %s %d bits\n", format, privateKey.N.BitLen())
+			output("private key (PKCS8)", alipay.PrivateKeyType, der)
+			if *public {
+				if der, err = x509.MarshalPKIXPublicKey(&privateKey.PublicKey); err != nil {
+					return err
+				}
+				output("public key", alipay.PublicKeyType, der)
+			}
+			return nil
+		}
+		if *keyType == "private" {
+			return err
+		}
+	}
+	publicKey, err := parsePublicKey(string(buff))
+	if err != nil {
+		return err
+	}
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return err
+	}
+	c.printf("format: PKIX %d bits\n", publicKey.N.BitLen())
+	output("public key", alipay.PublicKeyType, der)
+	return nil
+}
+
+// loadCert 读取公钥证书，返回证书及SN
+func loadCert(filename string) (*x509.Certificate, string, error) {
+	buff, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, "", err
+	}
+	cert, err := alipay.ParseCertificate(buff)
+	if err != nil {
+		return nil, "", err
+	}
+	return cert, alipay.GetCertSN(cert), nil
+}
+
+func (c *cli) certSN(args []string) error {
+	flagSet := c.flagSet("certsn")
+	appCert := flagSet.String("app-cert", "", "应用公钥证书文件 appCertPublicKey.crt")
+	rootCert := flagSet.String("root-cert", "", "支付宝根证书文件 alipayRootCert.crt")
+	alipayCert := flagSet.String("alipay-cert", "", "支付宝公钥证书文件 alipayCertPublicKey_RSA2.crt")
+	if err := flagSet.Parse(args); err != nil {
+		return err
+	}
+	if len(*appCert)+len(*rootCert)+len(*alipayCert) == 0 {
+		flagSet.Usage()
+		return errUsage
+	}
+	if len(*appCert) > 0 {
+		_, sn, err := loadCert(*appCert)
+		if err != nil {
+			return fmt.Errorf("app cert: %w", err)
+		}
+		c.printf("app_cert_sn=%s\n", sn)
+	}
+	if len(*rootCert) > 0 {
+		buff, err := os.ReadFile(*rootCert)
+		if err != nil {
+			return err
+		}
+		sn := alipay.GetRootCertSN(buff)
+		if len(sn) == 0 {
+			return errors.New("root cert: no RSA certificate found")
+		}
+		c.printf("alipay_root_cert_sn=%s\n", sn)
+	}
+	if len(*alipayCert) > 0 {
+		_, sn, err := loadCert(*alipayCert)
+		if err != nil {
+			return fmt.Errorf("alipay cert: %w", err)
+		}
+		c.printf("alipay_cert_sn=%s\n", sn)
+	}
+	return nil
+}
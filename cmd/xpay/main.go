@@ -0,0 +1,100 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+/**
+ * @author: Sam
+ * @since: 1.0.0
+ * @date: 2026/10/19 19:20
+ * @desc: xpay 命令行工具，用于排查密钥、证书和签名问题
+ *
+ * 参数值支持三种形式：直接传入内容、@文件路径、- 从标准输入读取
+ */
+
+const usage = `xpay 命令行工具
+
+用法:
+  xpay <command> [flags]
+
+命令:
+  key     规范化应用私钥或公钥，输出PEM格式
+  certsn  计算 app_cert_sn、alipay_root_cert_sn 和 alipay_cert_sn
+  sign    输出请求参数的待签名字符串，指定私钥时输出签名
+  verify  校验异步通知或同步响应的签名
+  exec    根据配置文件调用任意接口
+
+使用 xpay <command> -h 查看命令的参数
+`
+
+var errUsage = errors.New("xpay: invalid usage")
+
+type command struct {
+	name string
+	run  func(c *cli, args []string) error
+}
+
+var commands = []command{
+	{"key", (*cli).key},
+	{"certsn", (*cli).certSN},
+	{"sign", (*cli).sign},
+	{"verify", (*cli).verify},
+	{"exec", (*cli).exec},
+}
+
+type cli struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func main() {
+	c := &cli{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr}
+	if err := c.run(os.Args[1:]); err != nil {
+		if !errors.Is(err, flag.ErrHelp) && !errors.Is(err, errUsage) {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		os.Exit(1)
+	}
+}
+
+func (c *cli) run(args []string) error {
+	if len(args) == 0 {
+		fmt.Fprint(c.stderr, usage)
+		return errUsage
+	}
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			return cmd.run(c, args[1:])
+		}
+	}
+	fmt.Fprint(c.stderr, usage)
+	return errUsage
+}
+
+func (c *cli) flagSet(name string) *flag.FlagSet {
+	flagSet := flag.NewFlagSet("xpay "+name, flag.ContinueOnError)
+	flagSet.SetOutput(c.stderr)
+	return flagSet
+}
+
+// readValue 读取参数值，@开头为文件路径，- 为标准输入
+func (c *cli) readValue(value string) ([]byte, error) {
+	switch {
+	case value == "-":
+		return io.ReadAll(c.stdin)
+	case strings.HasPrefix(value, "@"):
+		return os.ReadFile(value[1:])
+	}
+	return []byte(value), nil
+}
+
+func (c *cli) printf(format string, a ...interface{}) {
+	fmt.Fprintf(c.stdout, format, a...)
+}
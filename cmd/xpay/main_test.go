@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	alipay "github.com/try-labs/xpay"
+	"github.com/try-labs/xpay/alipaytest"
+	"github.com/try-labs/xpay/testkeys"
+)
+
+/**
+ * @author: Sam
+ * @since:
+ * @date: 2026/10/19 19:50
+ * @desc:
+ */
+
+func runCli(t *testing.T, stdin string, args ...string) (string, error) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	c := &cli{stdin: strings.NewReader(stdin), stdout: &stdout, stderr: &stderr}
+	err := c.run(args)
+	return stdout.String(), err
+}
+
+func TestCli_Key(t *testing.T) {
+	keys := testkeys.Shared()
+	output, err := runCli(t, keys.App.PKCS1Base64(), "key", "-public")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(output, "format: PKCS1 2048 bits") || !strings.Contains(output, string(keys.App.PKCS8PEM())) {
+		t.Errorf("unexpected output: %s", output)
+	}
+	if !strings.Contains(output, string(keys.App.PublicKeyPEM())) {
+		t.Errorf("public key missing: %s", output)
+	}
+	output, err = runCli(t, "", "key", "-raw", "-in", keys.Alipay.PublicKeyBase64())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output != "format: PKIX 2048 bits\npublic key: "+keys.Alipay.PublicKeyBase64()+"\n" {
+		t.Errorf("unexpected output: %s", output)
+	}
+	if _, err = runCli(t, "invalid", "key"); err == nil {
+		t.Error("invalid key should fail")
+	}
+}
+
+func TestCli_CertSN(t *testing.T) {
+	keys := testkeys.Shared()
+	files, err := keys.WriteCertFiles(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	output, err := runCli(t, "", "certsn", "-app-cert", files.AppCert, "-root-cert", files.RootCert, "-alipay-cert", files.AlipayCert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "app_cert_sn=" + keys.AppCert.SN() + "\nalipay_root_cert_sn=" + keys.Chain.RootCertSN() + "\nalipay_cert_sn=" + keys.AlipayCert.SN() + "\n"
+	if output != expected {
+		t.Errorf("unexpected output: %s", output)
+	}
+}
+
+func TestCli_Sign(t *testing.T) {
+	keys := testkeys.Shared()
+	merchant := keys.NormalStrategy(alipaytest.DefaultAppId)
+	param := &alipay.CommonReqParam{
+		Method:     "alipay.trade.query",
+		Format:     alipay.FormatJson,
+		Charset:    alipay.CharsetUTF8,
+		SignType:   alipay.SignTypeRSA2,
+		Timestamp:  "2026-10-19 19:50:00",
+		Version:    alipay.ApiVersion,
+		BizContent: `{"out_trade_no":"20261019000001"}`,
+	}
+	merchant.SetSignContent(param)
+	encode, err := merchant.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	output, err := runCli(t, encode, "sign", "-private-key", keys.App.PKCS8Base64())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `app_id=` + alipaytest.DefaultAppId + `&biz_content={"out_trade_no":"20261019000001"}&charset=utf-8&format=JSON&method=alipay.trade.query&sign_type=RSA2&timestamp=2026-10-19 19:50:00&version=1.0`
+	if !strings.Contains(output, "sign content:\n"+expected+"\n") || !strings.Contains(output, "captured sign: matches") {
+		t.Errorf("unexpected output: %s", output)
+	}
+	if _, err = runCli(t, encode, "sign", "-private-key", keys.Alipay.PKCS8Base64()); err != errSignMismatch {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestCli_VerifyNotify(t *testing.T) {
+	g := alipaytest.NewGateway()
+	defer g.Close()
+	values := g.NotifyValues("trade_status_sync")
+	values.Set("out_trade_no", "20261019000001")
+	values.Set("trade_status", string(alipay.TradeSuccess))
+	if err := g.SignValues(values); err != nil {
+		t.Fatal(err)
+	}
+	publicKey := g.Keys().Alipay.PublicKeyBase64()
+	output, err := runCli(t, values.Encode(), "verify", "-notify", "-", "-alipay-public-key", publicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(output, "verify: ok") {
+		t.Errorf("unexpected output: %s", output)
+	}
+	values.Set("trade_status", string(alipay.TradeClosed))
+	if _, err = runCli(t, values.Encode(), "verify", "-notify", "-", "-alipay-public-key", publicKey); err == nil {
+		t.Error("tampered notify should fail")
+	}
+}
+
+func TestCli_VerifyResponse(t *testing.T) {
+	keys := testkeys.Shared()
+	files, err := keys.WriteCertFiles(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := `{"code":"10000","msg":"Success","out_trade_no":"20261019000001"}`
+	sign, err := alipay.RSASignWithKey([]byte(content), keys.Alipay.PrivateKey, crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response := `{"alipay_trade_query_response":` + content + `,"alipay_cert_sn":"` + keys.AlipayCert.SN() + `","sign":"` + base64.StdEncoding.EncodeToString(sign) + `"}`
+	output, err := runCli(t, response, "verify", "-response", "-", "-alipay-cert", files.AlipayCert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output != "sign content:\n"+content+"\nverify: ok\n" {
+		t.Errorf("unexpected output: %s", output)
+	}
+	if _, err = runCli(t, response, "verify", "-response", "-", "-alipay-public-key", keys.App.PublicKeyBase64()); err == nil {
+		t.Error("wrong key should fail")
+	}
+}
+
+func TestCli_Exec(t *testing.T) {
+	g := alipaytest.NewGateway()
+	defer g.Close()
+	conf, err := json.Marshal(config{
+		AppId:           g.AppId(),
+		PrivateKey:      g.Keys().App.PKCS1Base64(),
+		AlipayPublicKey: g.Keys().Alipay.PublicKeyBase64(),
+		ServerUrl:       g.GatewayURL(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	configFile := filepath.Join(t.TempDir(), "xpay.json")
+	if err = os.WriteFile(configFile, conf, 0600); err != nil {
+		t.Fatal(err)
+	}
+	bizContent := `{"out_trade_no":"20261019000001","total_amount":"1.00","subject":"测试商品"}`
+	output, err := runCli(t, bizContent, "exec", "-config", configFile, "-method", "alipay.trade.precreate", "-biz-content", "-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(output, `"qr_code": "https://qr.alipay.com/`) {
+		t.Errorf("unexpected output: %s", output)
+	}
+	_, err = runCli(t, "", "exec", "-config", configFile, "-method", "alipay.trade.query", "-biz-content", `{"out_trade_no":"20261019000002"}`)
+	if err == nil || !strings.Contains(err.Error(), "ACQ.TRADE_NOT_EXIST") {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if g.Calls("alipay.trade.query") != 1 {
+		t.Error("request should reach the gateway")
+	}
+}
+
+func TestCli_Usage(t *testing.T) {
+	if _, err := runCli(t, "", "unknown"); err != errUsage {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := runCli(t, "", "verify", "-alipay-public-key", "x"); err != errUsage {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestParseParams(t *testing.T) {
+	values, err := parseParams([]byte(`{"biz_content":{"out_trade_no":"20261019000001"},"sign":"x"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if values.Get("biz_content") != `{"out_trade_no":"20261019000001"}` || values.Get("sign") != "x" {
+		t.Errorf("unexpected values: %v", values)
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	alipay "github.com/try-labs/xpay"
+)
+
+/**
+ * @author: Sam
+ * @since: 1.0.0
+ * @date: 2026/10/19 19:30
+ * @desc: 签名和验签
+ */
+
+var errSignMismatch = errors.New("xpay: sign does not match")
+
+// parseParams 解析表单编码或者json格式的参数
+func parseParams(buff []byte) (url.Values, error) {
+	buff = bytes.TrimSpace(buff)
+	if !bytes.HasPrefix(buff, []byte("{")) {
+		return url.ParseQuery(string(buff))
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(buff, &fields); err != nil {
+		return nil, err
+	}
+	values := make(url.Values, len(fields))
+	for key, value := range fields {
+		if str, ok := value.(string); ok {
+			values.Set(key, str)
+			continue
+		}
+		// biz_content 等字段可能被记录为json对象
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		values.Set(key, string(raw))
+	}
+	return values, nil
+}
+
+// loadVerifyKey 从支付宝公钥或支付宝公钥证书中读取公钥，证书模式同时返回证书SN
+func (c *cli) loadVerifyKey(publicKey, certFile string) (*rsa.PublicKey, string, error) {
+	if len(certFile) > 0 {
+		cert, sn, err := loadCert(certFile)
+		if err != nil {
+			return nil, "", err
+		}
+		key, ok := cert.PublicKey.(*rsa.PublicKey)
+		if !ok {
+			return nil, "", alipay.ErrTrans
+		}
+		return key, sn, nil
+	}
+	if len(publicKey) == 0 {
+		return nil, "", errors.New("xpay: -alipay-public-key or -alipay-cert is required")
+	}
+	buff, err := c.readValue(publicKey)
+	if err != nil {
+		return nil, "", err
+	}
+	key, err := parsePublicKey(string(buff))
+	return key, "", err
+}
+
+func verifyContent(content, sign string, key *rsa.PublicKey) error {
+	signBytes, err := base64.StdEncoding.DecodeString(sign)
+	if err != nil {
+		return fmt.Errorf("xpay: decode sign: %w", err)
+	}
+	return alipay.RSAVerifyWithKey([]byte(content), signBytes, key, crypto.SHA256)
+}
+
+func (c *cli) sign(args []string) error {
+	flagSet := c.flagSet("sign")
+	params := flagSet.String("params", "-", "抓取的请求参数，表单编码或json格式，@文件路径，或 - 从标准输入读取")
+	privateKey := flagSet.String("private-key", "", "应用私钥，指定时输出签名并与请求中的sign比较")
+	if err := flagSet.Parse(args); err != nil {
+		return err
+	}
+	buff, err := c.readValue(*params)
+	if err != nil {
+		return err
+	}
+	values, err := parseParams(buff)
+	if err != nil {
+		return err
+	}
+	content := alipay.RequestSignContent(values)
+	c.printf("sign content:\n%s\n", content)
+	if len(*privateKey) == 0 {
+		return nil
+	}
+	if buff, err = c.readValue(*privateKey); err != nil {
+		return err
+	}
+	key, _, err := parsePrivateKey(string(buff))
+	if err != nil {
+		return err
+	}
+	signBytes, err := alipay.RSASignWithKey([]byte(content), key, crypto.SHA256)
+	if err != nil {
+		return err
+	}
+	sign := base64.StdEncoding.EncodeToString(signBytes)
+	c.printf("sign: %s\n", sign)
+	// RSA2签名是确定的，相同的内容和私钥得到相同的签名
+	if captured := values.Get(alipay.ExcludeKeySign); len(captured) > 0 {
+		if captured != sign {
+			c.printf("captured sign: %s\n", captured)
+			return errSignMismatch
+		}
+		c.printf("captured sign: matches\n")
+	}
+	return nil
+}
+
+func (c *cli) verify(args []string) error {
+	flagSet := c.flagSet("verify")
+	notify := flagSet.String("notify", "", "异步通知的请求体，表单编码或json格式，@文件路径，或 - 从标准输入读取")
+	response := flagSet.String("response", "", "网关的同步响应，@文件路径，或 - 从标准输入读取")
+	publicKey := flagSet.String("alipay-public-key", "", "支付宝公钥")
+	alipayCert := flagSet.String("alipay-cert", "", "支付宝公钥证书文件")
+	if err := flagSet.Parse(args); err != nil {
+		return err
+	}
+	if (len(*notify) == 0) == (len(*response) == 0) {
+		flagSet.Usage()
+		return errUsage
+	}
+	key, certSN, err := c.loadVerifyKey(*publicKey, *alipayCert)
+	if err != nil {
+		return err
+	}
+	var content, sign, alipayCertSN string
+	if len(*notify) > 0 {
+		buff, err := c.readValue(*notify)
+		if err != nil {
+			return err
+		}
+		values, err := parseParams(buff)
+		if err != nil {
+			return err
+		}
+		content = alipay.NotifySignContent(values)
+		sign = values.Get(alipay.ExcludeKeySign)
+		alipayCertSN = values.Get("alipay_cert_sn")
+	} else {
+		buff, err := c.readValue(*response)
+		if err != nil {
+			return err
+		}
+		buff = bytes.TrimSpace(buff)
+		if content, err = alipay.ResponseSignContent(buff); err != nil {
+			return err
+		}
+		signCertSn := new(alipay.SignCertSn)
+		if err = json.Unmarshal(buff, signCertSn); err != nil {
+			return err
+		}
+		sign, alipayCertSN = signCertSn.Sign, signCertSn.AlipayCertSn
+	}
+	c.printf("sign content:\n%s\n", content)
+	if len(sign) == 0 {
+		return errors.New("xpay: sign is empty")
+	}
+	// 证书模式下客户端按alipay_cert_sn查找支付宝公钥，不一致时即使签名正确也会验签失败
+	if len(certSN) > 0 && len(alipayCertSN) > 0 && !strings.EqualFold(certSN, alipayCertSN) {
+		c.printf("alipay_cert_sn: %s, cert sn: %s\n", alipayCertSN, certSN)
+	}
+	if err = verifyContent(content, sign, key); err != nil {
+		return fmt.Errorf("%w: %v", errSignMismatch, err)
+	}
+	c.printf("verify: ok\n")
+	return nil
+}
@@ -2,4 +2,8 @@ module github.com/try-labs/xpay
 
 go 1.16
 
-require github.com/google/go-querystring v1.1.0
+require (
+	github.com/google/go-querystring v1.1.0
+	golang.org/x/text v0.14.0
+	rsc.io/qr v0.2.0
+)
@@ -0,0 +1,310 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	alipay "github.com/try-labs/xpay"
+)
+
+/**
+ * @author: Sam
+ * @since: 1.0.0
+ * @date: 2026/10/19 21:00
+ * @desc: 支付宝业务账单与商户订单对账
+ *
+ * 账单明细按商户订单号聚合后与商户侧对账周期内的订单逐一比较，账单中剩余的订单再按订单号单独查询商户侧，
+ * 仍然不存在的即为商户侧缺失。聚合后每笔交易只保留订单号、金额和退款请求号，不保留账单明细。
+ */
+
+// BillRows 账单明细，*alipay.TradeBillReader 实现了该接口
+type BillRows interface {
+	Next() bool
+	Record() *alipay.TradeBillRecord
+	Err() error
+}
+
+// billSummarizer 账单合计，账单明细实现该接口时对账结果会与账单合计核对
+type billSummarizer interface {
+	Summary() (*alipay.TradeBillSummary, error)
+}
+
+const (
+	billBizTypeTrade  = "交易"
+	billBizTypeRefund = "退款"
+)
+
+// billTimeLayout 账单头部的时间格式 2023年03月15日 00:00:00
+const billTimeLayout = "2006年01月02日 15:04:05"
+
+type Option func(*Reconciler)
+
+// WithResolver 使用交易查询和退款查询自动核实商户侧已支付但账单缺失、退款不一致的订单，这两类差异通常是跨对账周期导致的
+func WithResolver(client *alipay.Client) Option {
+	return func(r *Reconciler) {
+		r.client = client
+	}
+}
+
+// WithPeriod 对账周期，默认取账单头部的起始日期和终止日期
+func WithPeriod(start, end time.Time) Option {
+	return func(r *Reconciler) {
+		r.start, r.end = start, end
+	}
+}
+
+// WithLocation 解析账单时间使用的时区，默认为东八区
+func WithLocation(location *time.Location) Option {
+	return func(r *Reconciler) {
+		r.location = location
+	}
+}
+
+// Reconciler 对账
+type Reconciler struct {
+	source   OrderSource
+	client   *alipay.Client
+	location *time.Location
+	start    time.Time
+	end      time.Time
+}
+
+func NewReconciler(source OrderSource, opts ...Option) *Reconciler {
+	r := &Reconciler{source: source, location: time.FixedZone("CST", 8*3600)}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// billTrade 账单中一笔交易的聚合结果
+type billTrade struct {
+	tradeNo     string
+	paid        bool
+	totalAmount alipay.Money
+	refunds     map[string]alipay.Money // 退款请求号 => 退款金额
+}
+
+func (t *billTrade) refundAmount() alipay.Money {
+	var amount alipay.Money
+	for _, refund := range t.refunds {
+		amount = amount.Add(refund)
+	}
+	return amount
+}
+
+// Reconcile 读取全部账单明细后与商户订单比较
+func (r *Reconciler) Reconcile(ctx context.Context, rows BillRows) (*Report, error) {
+	report := &Report{Totals: Totals{ByCategory: make(map[Category]int)}}
+	start, end := r.start, r.end
+	trades := make(map[string]*billTrade)
+	for rows.Next() {
+		record := rows.Record()
+		trade := trades[record.OutTradeNo]
+		if trade == nil {
+			trade = &billTrade{tradeNo: record.TradeNo, refunds: make(map[string]alipay.Money)}
+			trades[record.OutTradeNo] = trade
+		}
+		switch record.BizType {
+		case billBizTypeTrade:
+			trade.paid = true
+			trade.totalAmount = record.TotalAmount
+			report.Totals.BillTradeCount++
+			report.Totals.BillTradeAmount = report.Totals.BillTradeAmount.Add(record.ReceiptAmount)
+		case billBizTypeRefund:
+			// 退款行的商家实收为负数
+			trade.refunds[record.OutRequestNo] = trade.refunds[record.OutRequestNo].Sub(record.ReceiptAmount)
+			report.Totals.BillRefundCount++
+			report.Totals.BillRefundAmount = report.Totals.BillRefundAmount.Add(record.ReceiptAmount)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if summarizer, ok := rows.(billSummarizer); ok {
+		summary, err := summarizer.Summary()
+		if err != nil {
+			return nil, err
+		}
+		report.Summary = summary
+		report.SummaryMatched = report.Totals.tieOut(summary)
+		start, end = r.period(summary.BillInfo)
+	}
+
+	iterator, err := r.source.Orders(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer iterator.Close()
+	for iterator.Next() {
+		order := iterator.Order()
+		report.Totals.MerchantOrderCount++
+		trade := trades[order.OutTradeNo]
+		delete(trades, order.OutTradeNo)
+		r.compare(report, order, trade, start, end)
+	}
+	if err = iterator.Err(); err != nil {
+		return nil, err
+	}
+	// 账单中存在但不在商户对账周期内的订单
+	for outTradeNo, trade := range trades {
+		order, err := r.source.Order(ctx, outTradeNo)
+		if err != nil {
+			return nil, err
+		}
+		if order == nil {
+			report.add(&Discrepancy{Category: MissingOnMerchant, OutTradeNo: outTradeNo, TradeNo: trade.tradeNo, AlipayAmount: trade.totalAmount, AlipayRefund: trade.refundAmount()})
+			continue
+		}
+		r.compare(report, order, trade, start, end)
+	}
+	if r.client != nil {
+		for _, discrepancy := range report.Discrepancies {
+			if err = r.resolve(ctx, discrepancy); err != nil {
+				return nil, err
+			}
+		}
+	}
+	report.finish()
+	return report, nil
+}
+
+// period 本次对账的周期，未指定对账周期时使用账单的起始日期和终止日期
+func (r *Reconciler) period(info alipay.BillInfo) (time.Time, time.Time) {
+	if !r.start.IsZero() || !r.end.IsZero() {
+		return r.start, r.end
+	}
+	start, startErr := time.ParseInLocation(billTimeLayout, info.StartTime, r.location)
+	end, endErr := time.ParseInLocation(billTimeLayout, info.EndTime, r.location)
+	if startErr != nil || endErr != nil {
+		return r.start, r.end
+	}
+	return start, end
+}
+
+// compare 比较一笔订单，trade为nil表示账单中没有该订单
+func (r *Reconciler) compare(report *Report, order *Order, trade *billTrade, start, end time.Time) {
+	if trade == nil {
+		trade = &billTrade{refunds: map[string]alipay.Money{}}
+	}
+	merchantRefunds := make(map[string]alipay.Money)
+	var merchantRefund alipay.Money
+	for _, refund := range order.Refunds {
+		if inPeriod(refund.RefundedAt, start, end) {
+			merchantRefunds[refund.OutRequestNo] = merchantRefunds[refund.OutRequestNo].Add(refund.Amount)
+			merchantRefund = merchantRefund.Add(refund.Amount)
+		}
+	}
+	discrepancy := &Discrepancy{
+		OutTradeNo:      order.OutTradeNo,
+		TradeNo:         order.TradeNo,
+		AlipayAmount:    trade.totalAmount,
+		MerchantAmount:  order.TotalAmount,
+		MerchantStatus:  order.Status,
+		AlipayRefund:    trade.refundAmount(),
+		MerchantRefund:  merchantRefund,
+		merchantRefunds: merchantRefunds,
+	}
+	if len(discrepancy.TradeNo) == 0 {
+		discrepancy.TradeNo = trade.tradeNo
+	}
+	matched := true
+	paid := order.Status == OrderPaid || (order.Status == OrderClosed && len(order.Refunds) > 0)
+	switch {
+	case trade.paid && !paid:
+		matched = false
+		report.add(discrepancy.with(StatusMismatch, fmt.Sprintf("alipay paid, merchant %s", order.Status)))
+	case trade.paid && trade.totalAmount != order.TotalAmount:
+		matched = false
+		report.add(discrepancy.with(AmountMismatch, fmt.Sprintf("alipay %s, merchant %s", trade.totalAmount, order.TotalAmount)))
+	case !trade.paid && paid && inPeriod(order.PaidAt, start, end):
+		matched = false
+		report.add(discrepancy.with(MissingOnAlipay, "merchant paid, not in bill"))
+	}
+	if detail, merchantOnly, conflict := refundDiff(trade.refunds, merchantRefunds); len(detail) > 0 {
+		matched = false
+		refundDiscrepancy := discrepancy.with(RefundMismatch, detail)
+		refundDiscrepancy.merchantOnlyRefunds, refundDiscrepancy.refundConflict = merchantOnly, conflict
+		report.add(refundDiscrepancy)
+	}
+	if matched && (trade.paid || len(trade.refunds) > 0) {
+		report.Totals.MatchedCount++
+	}
+}
+
+// refundDiff 按退款请求号比较退款，返回差异描述以及仅存在于商户侧的退款请求号，账单中退款请求号为空时只比较退款总额
+func refundDiff(alipayRefunds, merchantRefunds map[string]alipay.Money) (string, []string, bool) {
+	if amount, ok := alipayRefunds[""]; ok && len(alipayRefunds) == 1 {
+		var merchantAmount alipay.Money
+		for _, refund := range merchantRefunds {
+			merchantAmount = merchantAmount.Add(refund)
+		}
+		if amount != merchantAmount {
+			return fmt.Sprintf("alipay refund %s, merchant refund %s", amount, merchantAmount), nil, true
+		}
+		return "", nil, false
+	}
+	var diffs, merchantOnly []string
+	conflict := false
+	for outRequestNo, amount := range alipayRefunds {
+		merchantAmount, ok := merchantRefunds[outRequestNo]
+		switch {
+		case !ok:
+			conflict = true
+			diffs = append(diffs, fmt.Sprintf("%s: alipay %s, merchant missing", outRequestNo, amount))
+		case merchantAmount != amount:
+			conflict = true
+			diffs = append(diffs, fmt.Sprintf("%s: alipay %s, merchant %s", outRequestNo, amount, merchantAmount))
+		}
+	}
+	for outRequestNo, amount := range merchantRefunds {
+		if _, ok := alipayRefunds[outRequestNo]; !ok {
+			merchantOnly = append(merchantOnly, outRequestNo)
+			diffs = append(diffs, fmt.Sprintf("%s: alipay missing, merchant %s", outRequestNo, amount))
+		}
+	}
+	sort.Strings(diffs)
+	sort.Strings(merchantOnly)
+	return strings.Join(diffs, "; "), merchantOnly, conflict
+}
+
+// resolve 查询支付宝确认差异是否由跨对账周期导致
+func (r *Reconciler) resolve(ctx context.Context, discrepancy *Discrepancy) error {
+	switch discrepancy.Category {
+	case MissingOnAlipay:
+		res, err := r.client.TradeQuery(ctx, alipay.TradeQueryReq{OutTradeNo: discrepancy.OutTradeNo})
+		if err != nil {
+			return err
+		}
+		if res.Fail() {
+			discrepancy.Resolution = fmt.Sprintf("trade query: %s %s", res.SubCode, res.SubMsg)
+			return nil
+		}
+		discrepancy.Resolution = fmt.Sprintf("trade query: %s %s send_pay_date %s", res.TradeStatus, res.TotalAmount, res.SendPayDate)
+		paid := res.TradeStatus == alipay.TradeSuccess || res.TradeStatus == alipay.TradeFinished || (res.TradeStatus == alipay.TradeClosed && len(res.SendPayDate) > 0)
+		discrepancy.Resolved = paid && res.TotalAmount == discrepancy.MerchantAmount
+	case RefundMismatch:
+		// 只有商户侧多出的退款可以通过查询核实，账单中多出或者金额不一致的退款无法核实
+		resolved := !discrepancy.refundConflict
+		var resolutions []string
+		for _, outRequestNo := range discrepancy.merchantOnlyRefunds {
+			res, err := r.client.TradeFastPayRefundQuery(ctx, alipay.TradeFastPayRefundQueryReq{OutTradeNo: discrepancy.OutTradeNo, OutRequestNo: outRequestNo})
+			if err != nil {
+				return err
+			}
+			if res.Fail() || res.RefundStatus != alipay.RefundSuccess || res.RefundAmount != discrepancy.merchantRefunds[outRequestNo] {
+				resolved = false
+			}
+			resolutions = append(resolutions, fmt.Sprintf("%s: %s %s", outRequestNo, res.RefundStatus, res.RefundAmount))
+		}
+		if len(resolutions) == 0 {
+			return nil
+		}
+		discrepancy.Resolved = resolved
+		discrepancy.Resolution = "refund query: " + strings.Join(resolutions, "; ")
+	}
+	return nil
+}
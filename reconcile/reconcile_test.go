@@ -0,0 +1,270 @@
+package reconcile_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	alipay "github.com/try-labs/xpay"
+	"github.com/try-labs/xpay/alipaytest"
+	. "github.com/try-labs/xpay/reconcile"
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+/**
+ * @author: Sam
+ * @since:
+ * @date: 2026/10/19 21:00
+ * @desc:
+ */
+
+const billHeader = `#支付宝业务明细查询
+#账号：[20881021774994500156]
+#起始日期：[2026年10月18日 00:00:00]   终止日期：[2026年10月19日 00:00:00]
+#-----------------------------------------业务明细列表----------------------------------------
+支付宝交易号,商户订单号,业务类型,商品名称,创建时间,完成时间,门店编号,门店名称,操作员,终端号,对方账户,订单金额（元）,商家实收（元）,支付宝红包（元）,集分宝（元）,支付宝优惠（元）,商家优惠（元）,券核销金额（元）,券名称,商家红包消费金额（元）,卡消费金额（元）,退款批次号/请求号,服务费（元）,分润（元）,备注
+`
+
+const billFooter = `#-----------------------------------------业务明细列表结束------------------------------------
+#交易合计：%d笔，商家实收：￥%s元，商家优惠：￥0.00元
+#退款合计：%d笔，商家实收：￥%s元，商家优惠：￥0.00元
+#导出时间：[2026年10月19日 09:21:01]
+`
+
+var cst = time.FixedZone("CST", 8*3600)
+
+func at(day, hour int) time.Time {
+	return time.Date(2026, 10, day, hour, 0, 0, 0, cst)
+}
+
+func billRow(outTradeNo, bizType, totalAmount, receiptAmount, outRequestNo string) string {
+	return fmt.Sprintf("20261018220014468814%s	,%s	,%s	,测试商品	,2026-10-18 10:00:00,2026-10-18 10:00:05,,,,,tes***@sandbox.com	,%s,%s,0.00,0.00,0.00,0.00,0.00,,0.00,0.00,%s	,0.00,0.00,\n",
+		outTradeNo[len(outTradeNo)-8:], outTradeNo, bizType, totalAmount, receiptAmount, outRequestNo)
+}
+
+func billReader(t *testing.T, rows []string, tradeCount int, tradeAmount string, refundCount int, refundAmount string) *alipay.TradeBillReader {
+	t.Helper()
+	return dayBillReader(t, 18, rows, tradeCount, tradeAmount, refundCount, refundAmount)
+}
+
+// dayBillReader 10月day日的账单
+func dayBillReader(t *testing.T, day int, rows []string, tradeCount int, tradeAmount string, refundCount int, refundAmount string) *alipay.TradeBillReader {
+	t.Helper()
+	header := strings.Replace(billHeader, "[2026年10月18日 00:00:00]   终止日期：[2026年10月19日 00:00:00]",
+		fmt.Sprintf("[2026年10月%02d日 00:00:00]   终止日期：[2026年10月%02d日 00:00:00]", day, day+1), 1)
+	content := header + strings.Join(rows, "") + fmt.Sprintf(billFooter, tradeCount, tradeAmount, refundCount, refundAmount)
+	buff, err := simplifiedchinese.GBK.NewEncoder().Bytes([]byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return alipay.NewTradeBillReader(io.NopCloser(bytes.NewReader(buff)))
+}
+
+func money(s string) alipay.Money {
+	return alipay.MustParseMoney(s)
+}
+
+// scenario 每类差异各一笔，另有一致、仅退款在对账周期内以及支付时间在对账周期外的订单
+func scenario(t *testing.T) (*alipay.TradeBillReader, SliceSource) {
+	rows := []string{
+		billRow("20261018000001", "交易", "88.88", "88.88", ""),
+		billRow("20261018000001", "退款", "88.88", "-8.88", "20261018000001-1"),
+		billRow("20261018000002", "交易", "10.00", "10.00", ""),
+		billRow("20261018000003", "交易", "20.00", "20.00", ""),
+		billRow("20261018000004", "交易", "5.00", "5.00", ""),
+		billRow("20261018000006", "交易", "50.00", "50.00", ""),
+		billRow("20261017000007", "退款", "10.00", "-3.00", "20261017000007-1"),
+		billRow("20261017000008", "交易", "7.00", "7.00", ""),
+	}
+	source := SliceSource{
+		{OutTradeNo: "20261018000001", TotalAmount: money("88.88"), Status: OrderPaid, PaidAt: at(18, 10), Refunds: []Refund{{OutRequestNo: "20261018000001-1", Amount: money("8.88"), RefundedAt: at(18, 12)}}},
+		{OutTradeNo: "20261018000002", TotalAmount: money("10.00"), Status: OrderUnpaid},
+		{OutTradeNo: "20261018000003", TotalAmount: money("25.00"), Status: OrderPaid, PaidAt: at(18, 10)},
+		{OutTradeNo: "20261018000005", TotalAmount: money("30.00"), Status: OrderPaid, PaidAt: at(18, 23)},
+		{OutTradeNo: "20261018000006", TotalAmount: money("50.00"), Status: OrderPaid, PaidAt: at(18, 10), Refunds: []Refund{{OutRequestNo: "20261018000006-1", Amount: money("10.00"), RefundedAt: at(18, 23)}}},
+		{OutTradeNo: "20261017000007", TotalAmount: money("10.00"), Status: OrderPaid, PaidAt: at(17, 10), Refunds: []Refund{{OutRequestNo: "20261017000007-1", Amount: money("3.00"), RefundedAt: at(18, 10)}}},
+		{OutTradeNo: "20261017000008", TotalAmount: money("7.00"), Status: OrderPaid, PaidAt: at(17, 23)},
+	}
+	return billReader(t, rows, 6, "180.88", 2, "-11.88"), source
+}
+
+func categories(report *Report) map[string]Category {
+	result := make(map[string]Category)
+	for _, discrepancy := range report.Discrepancies {
+		result[discrepancy.OutTradeNo] = discrepancy.Category
+	}
+	return result
+}
+
+func TestReconciler_Reconcile(t *testing.T) {
+	rows, source := scenario(t)
+	report, err := NewReconciler(source).Reconcile(context.Background(), rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]Category{
+		"20261018000002": StatusMismatch,
+		"20261018000003": AmountMismatch,
+		"20261018000004": MissingOnMerchant,
+		"20261018000005": MissingOnAlipay,
+		"20261018000006": RefundMismatch,
+	}
+	if actual := categories(report); fmt.Sprint(actual) != fmt.Sprint(expected) {
+		t.Errorf("unexpected discrepancies: %v", actual)
+	}
+	totals := report.Totals
+	if !report.SummaryMatched || totals.BillTradeCount != 6 || totals.BillTradeAmount != money("180.88") || totals.BillRefundAmount != money("-11.88") {
+		t.Errorf("unexpected totals: %+v", totals)
+	}
+	// 20261017000008 支付时间在对账周期外，不计入商户侧订单数
+	if totals.MerchantOrderCount != 6 || totals.MatchedCount != 3 || totals.DiscrepancyCount != 5 || totals.ByCategory[AmountMismatch] != 1 {
+		t.Errorf("unexpected totals: %+v", totals)
+	}
+	if len(report.Unresolved()) != 5 {
+		t.Errorf("discrepancies should be unresolved without resolver")
+	}
+	refund := report.Discrepancies[3]
+	if refund.Category != RefundMismatch || refund.MerchantRefund != money("10.00") || !refund.AlipayRefund.IsZero() || refund.Detail != "20261018000006-1: alipay missing, merchant 10.00" {
+		t.Errorf("unexpected refund discrepancy: %+v", refund)
+	}
+}
+
+func TestReconciler_SummaryNotMatched(t *testing.T) {
+	rows := billReader(t, []string{billRow("20261018000001", "交易", "88.88", "88.88", "")}, 2, "100.00", 0, "0.00")
+	source := SliceSource{{OutTradeNo: "20261018000001", TotalAmount: money("88.88"), Status: OrderPaid}}
+	report, err := NewReconciler(source).Reconcile(context.Background(), rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.SummaryMatched || report.Summary.TradeCount != 2 || report.Totals.MatchedCount != 1 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+}
+
+// TestReconciler_ReusePeriod 同一个 Reconciler 依次对账多天的账单，每次使用各自账单的周期
+func TestReconciler_ReusePeriod(t *testing.T) {
+	source := SliceSource{
+		{OutTradeNo: "20261018000001", TotalAmount: money("88.88"), Status: OrderPaid, PaidAt: at(18, 10)},
+		{OutTradeNo: "20261019000001", TotalAmount: money("10.00"), Status: OrderPaid, PaidAt: at(19, 10), Refunds: []Refund{{OutRequestNo: "20261019000001-1", Amount: money("2.00"), RefundedAt: at(19, 12)}}},
+		{OutTradeNo: "20261019000002", TotalAmount: money("20.00"), Status: OrderPaid, PaidAt: at(19, 11)},
+	}
+	reconciler := NewReconciler(source)
+	report, err := reconciler.Reconcile(context.Background(), billReader(t, []string{billRow("20261018000001", "交易", "88.88", "88.88", "")}, 1, "88.88", 0, "0.00"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Totals.MerchantOrderCount != 1 || report.Totals.DiscrepancyCount != 0 {
+		t.Errorf("unexpected totals: %+v", report.Totals)
+	}
+	rows := []string{
+		billRow("20261019000001", "交易", "10.00", "10.00", ""),
+		billRow("20261019000001", "退款", "10.00", "-2.00", "20261019000001-1"),
+	}
+	if report, err = reconciler.Reconcile(context.Background(), dayBillReader(t, 19, rows, 1, "10.00", 1, "-2.00")); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]Category{"20261019000002": MissingOnAlipay}
+	if actual := categories(report); fmt.Sprint(actual) != fmt.Sprint(expected) || report.Totals.MerchantOrderCount != 2 || report.Totals.MatchedCount != 1 {
+		t.Errorf("unexpected report: %v %+v", actual, report.Totals)
+	}
+}
+
+func TestReconciler_Resolver(t *testing.T) {
+	gateway := alipaytest.NewGateway()
+	defer gateway.Close()
+	client, err := gateway.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	// 20261018000005 在对账周期结束后才出现在账单中，20261018000006 的退款在下一天的账单中
+	for _, order := range []alipay.TradePreCreateReq{
+		{OutTradeNo: "20261018000005", TotalAmount: money("30.00"), Subject: "测试商品"},
+		{OutTradeNo: "20261018000006", TotalAmount: money("50.00"), Subject: "测试商品"},
+	} {
+		if _, err = client.TradePreCreate(ctx, order); err != nil {
+			t.Fatal(err)
+		}
+		if err = gateway.PayTrade(order.OutTradeNo, "2088102177846880"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err = client.TradeRefund(ctx, alipay.TradeRefundReq{OutTradeNo: "20261018000006", RefundAmount: money("10.00"), OutRequestNo: "20261018000006-1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, source := scenario(t)
+	report, err := NewReconciler(source, WithResolver(client)).Reconcile(ctx, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, discrepancy := range report.Discrepancies {
+		switch discrepancy.Category {
+		case MissingOnAlipay, RefundMismatch:
+			if !discrepancy.Resolved || len(discrepancy.Resolution) == 0 {
+				t.Errorf("discrepancy should be resolved: %+v", discrepancy)
+			}
+		default:
+			if discrepancy.Resolved {
+				t.Errorf("discrepancy should not be resolved: %+v", discrepancy)
+			}
+		}
+	}
+	if len(report.Unresolved()) != 3 {
+		t.Errorf("unexpected unresolved: %d", len(report.Unresolved()))
+	}
+
+	// 支付宝不存在的交易和金额不一致的退款无法核实
+	rows = billReader(t, []string{billRow("20261018000006", "交易", "50.00", "50.00", "")}, 1, "50.00", 0, "0.00")
+	source = SliceSource{
+		{OutTradeNo: "20261018000009", TotalAmount: money("9.00"), Status: OrderPaid},
+		{OutTradeNo: "20261018000006", TotalAmount: money("50.00"), Status: OrderPaid, Refunds: []Refund{{OutRequestNo: "20261018000006-1", Amount: money("20.00")}}},
+	}
+	report, err = NewReconciler(source, WithResolver(client)).Reconcile(ctx, rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Unresolved()) != 2 {
+		t.Errorf("unexpected discrepancies: %+v %+v", report.Discrepancies[0], report.Discrepancies[1])
+	}
+}
+
+func TestReport_Write(t *testing.T) {
+	rows, source := scenario(t)
+	report, err := NewReconciler(source).Reconcile(context.Background(), rows)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buff bytes.Buffer
+	if err = report.WriteJSON(&buff); err != nil {
+		t.Fatal(err)
+	}
+	decoded := new(Report)
+	if err = json.Unmarshal(buff.Bytes(), decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Totals.DiscrepancyCount != 5 || decoded.Summary.TradeReceiptAmount != money("180.88") || decoded.Discrepancies[0].Category != AmountMismatch {
+		t.Errorf("unexpected json: %s", buff.String())
+	}
+
+	buff.Reset()
+	if err = report.WriteCSV(&buff); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buff).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 6 || records[0][0] != "category" {
+		t.Fatalf("unexpected csv: %v", records)
+	}
+	if strings.Join(records[1][:8], ",") != "AMOUNT_MISMATCH,20261018000003,2026101822001446881418000003,20.00,25.00,0.00,0.00,PAID" {
+		t.Errorf("unexpected csv record: %v", records[1])
+	}
+}
@@ -0,0 +1,141 @@
+package reconcile
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+
+	alipay "github.com/try-labs/xpay"
+)
+
+/**
+ * @author: Sam
+ * @since: 1.0.0
+ * @date: 2026/10/19 21:00
+ * @desc: 对账结果
+ */
+
+// Category 差异类型
+type Category string
+
+const (
+	MissingOnMerchant Category = "MISSING_ON_MERCHANT" // 账单中存在，商户侧没有该订单
+	MissingOnAlipay   Category = "MISSING_ON_ALIPAY"   // 商户侧已支付，账单中没有该订单
+	AmountMismatch    Category = "AMOUNT_MISMATCH"     // 订单金额不一致
+	StatusMismatch    Category = "STATUS_MISMATCH"     // 账单中已支付，商户侧未支付
+	RefundMismatch    Category = "REFUND_MISMATCH"     // 退款不一致
+)
+
+// Discrepancy 一笔差异
+type Discrepancy struct {
+	Category       Category     `json:"category"`
+	OutTradeNo     string       `json:"out_trade_no"`
+	TradeNo        string       `json:"trade_no,omitempty"`
+	AlipayAmount   alipay.Money `json:"alipay_amount"`
+	MerchantAmount alipay.Money `json:"merchant_amount"`
+	AlipayRefund   alipay.Money `json:"alipay_refund"`
+	MerchantRefund alipay.Money `json:"merchant_refund"`
+	MerchantStatus OrderStatus  `json:"merchant_status,omitempty"`
+	Detail         string       `json:"detail,omitempty"`
+	Resolved       bool         `json:"resolved"`             // 已通过查询核实，通常是跨对账周期导致的
+	Resolution     string       `json:"resolution,omitempty"` // 核实时的查询结果
+
+	merchantRefunds     map[string]alipay.Money // 商户侧对账周期内的退款，退款请求号 => 退款金额
+	merchantOnlyRefunds []string                // 仅存在于商户侧的退款请求号
+	refundConflict      bool                    // 存在账单中多出或者金额不一致的退款
+}
+
+func (r *Discrepancy) with(category Category, detail string) *Discrepancy {
+	discrepancy := *r
+	discrepancy.Category, discrepancy.Detail = category, detail
+	return &discrepancy
+}
+
+// Totals 对账合计
+type Totals struct {
+	BillTradeCount     int              `json:"bill_trade_count"`     // 账单交易笔数
+	BillTradeAmount    alipay.Money     `json:"bill_trade_amount"`    // 账单交易商家实收
+	BillRefundCount    int              `json:"bill_refund_count"`    // 账单退款笔数
+	BillRefundAmount   alipay.Money     `json:"bill_refund_amount"`   // 账单退款商家实收，为负数
+	MerchantOrderCount int              `json:"merchant_order_count"` // 商户侧对账周期内的订单数
+	MatchedCount       int              `json:"matched_count"`        // 一致的订单数
+	DiscrepancyCount   int              `json:"discrepancy_count"`    // 差异数
+	ByCategory         map[Category]int `json:"by_category"`          // 按差异类型统计
+}
+
+// tieOut 账单明细合计与账单尾部的合计是否一致
+func (r *Totals) tieOut(summary *alipay.TradeBillSummary) bool {
+	return r.BillTradeCount == summary.TradeCount &&
+		r.BillTradeAmount == summary.TradeReceiptAmount &&
+		r.BillRefundCount == summary.RefundCount &&
+		r.BillRefundAmount == summary.RefundReceiptAmount
+}
+
+// Report 对账报告
+type Report struct {
+	Totals         Totals                   `json:"totals"`
+	Summary        *alipay.TradeBillSummary `json:"summary,omitempty"`         // 账单尾部的合计，账单明细不提供合计时为nil
+	SummaryMatched bool                     `json:"summary_matched,omitempty"` // 账单明细合计与账单尾部的合计一致
+	Discrepancies  []*Discrepancy           `json:"discrepancies"`
+}
+
+func (r *Report) add(discrepancy *Discrepancy) {
+	r.Discrepancies = append(r.Discrepancies, discrepancy)
+	r.Totals.ByCategory[discrepancy.Category]++
+	r.Totals.DiscrepancyCount++
+}
+
+// finish 按差异类型和商户订单号排序，保证同一份数据输出的报告一致
+func (r *Report) finish() {
+	if r.Discrepancies == nil {
+		r.Discrepancies = []*Discrepancy{}
+	}
+	sort.SliceStable(r.Discrepancies, func(i, j int) bool {
+		a, b := r.Discrepancies[i], r.Discrepancies[j]
+		if a.Category != b.Category {
+			return a.Category < b.Category
+		}
+		return a.OutTradeNo < b.OutTradeNo
+	})
+}
+
+// Unresolved 未核实的差异
+func (r *Report) Unresolved() []*Discrepancy {
+	var list []*Discrepancy
+	for _, discrepancy := range r.Discrepancies {
+		if !discrepancy.Resolved {
+			list = append(list, discrepancy)
+		}
+	}
+	return list
+}
+
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+var csvHeader = []string{"category", "out_trade_no", "trade_no", "alipay_amount", "merchant_amount", "alipay_refund", "merchant_refund", "merchant_status", "resolved", "resolution", "detail"}
+
+// WriteCSV 输出差异明细，UTF-8编码
+func (r *Report) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, d := range r.Discrepancies {
+		record := []string{
+			string(d.Category), d.OutTradeNo, d.TradeNo,
+			d.AlipayAmount.String(), d.MerchantAmount.String(), d.AlipayRefund.String(), d.MerchantRefund.String(),
+			string(d.MerchantStatus), strconv.FormatBool(d.Resolved), d.Resolution, d.Detail,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
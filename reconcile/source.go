@@ -0,0 +1,121 @@
+package reconcile
+
+import (
+	"context"
+	"time"
+
+	alipay "github.com/try-labs/xpay"
+)
+
+/**
+ * @author: Sam
+ * @since: 1.0.0
+ * @date: 2026/10/19 21:00
+ * @desc: 商户侧的订单数据源
+ */
+
+type OrderStatus string
+
+const (
+	OrderUnpaid OrderStatus = "UNPAID" // 待支付
+	OrderPaid   OrderStatus = "PAID"   // 已支付，包括部分退款
+	OrderClosed OrderStatus = "CLOSED" // 已关闭，未支付关闭或者全额退款后关闭
+)
+
+// Order 商户侧的订单
+type Order struct {
+	OutTradeNo  string       // 商户订单号
+	TradeNo     string       // 支付宝交易号
+	TotalAmount alipay.Money // 订单金额
+	Status      OrderStatus  // 订单状态
+	PaidAt      time.Time    // 支付时间，为零值时不判断是否属于对账周期
+	Refunds     []Refund     // 退款记录
+}
+
+// Refund 商户侧的退款
+type Refund struct {
+	OutRequestNo string       // 退款请求号
+	Amount       alipay.Money // 退款金额
+	RefundedAt   time.Time    // 退款时间，为零值时不判断是否属于对账周期
+}
+
+// OrderIterator 订单迭代器
+type OrderIterator interface {
+	Next() bool
+	Order() *Order
+	Err() error
+	Close() error
+}
+
+// OrderSource 商户侧的订单数据源
+type OrderSource interface {
+	// Orders 对账周期内支付或者退款的订单
+	Orders(ctx context.Context, start, end time.Time) (OrderIterator, error)
+	// Order 按商户订单号查询，用于账单中存在但不在对账周期内的订单，不存在时返回nil
+	Order(ctx context.Context, outTradeNo string) (*Order, error)
+}
+
+// SliceSource 基于内存中订单列表的数据源
+type SliceSource []*Order
+
+func (r SliceSource) Orders(ctx context.Context, start, end time.Time) (OrderIterator, error) {
+	orders := make([]*Order, 0, len(r))
+	for _, order := range r {
+		if inPeriod(order.PaidAt, start, end) {
+			orders = append(orders, order)
+			continue
+		}
+		for _, refund := range order.Refunds {
+			if inPeriod(refund.RefundedAt, start, end) {
+				orders = append(orders, order)
+				break
+			}
+		}
+	}
+	return &sliceIterator{orders: orders, index: -1}, nil
+}
+
+func (r SliceSource) Order(ctx context.Context, outTradeNo string) (*Order, error) {
+	for _, order := range r {
+		if order.OutTradeNo == outTradeNo {
+			return order, nil
+		}
+	}
+	return nil, nil
+}
+
+type sliceIterator struct {
+	orders []*Order
+	index  int
+}
+
+func (r *sliceIterator) Next() bool {
+	r.index++
+	return r.index < len(r.orders)
+}
+
+func (r *sliceIterator) Order() *Order {
+	return r.orders[r.index]
+}
+
+func (r *sliceIterator) Err() error {
+	return nil
+}
+
+func (r *sliceIterator) Close() error {
+	return nil
+}
+
+// inPeriod 时间为零值或者对账周期未设置时视为属于对账周期
+func inPeriod(t, start, end time.Time) bool {
+	if t.IsZero() {
+		return true
+	}
+	if !start.IsZero() && t.Before(start) {
+		return false
+	}
+	if !end.IsZero() && !t.Before(end) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,275 @@
+package replay
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"sync"
+)
+
+/**
+ * @author: Sam
+ * @since: 1.0.0
+ * @date: 2026/10/19 18:30
+ * @desc: 录制和回放网关请求的http.RoundTripper，通过 SetClientOptHttpClient 接入客户端
+ *
+ * 录制模式下把每次请求的接口名、解码后的biz_content以及原始响应写入golden文件，敏感字段会被替换为 RedactedValue；
+ * 回放模式下按接口名和biz_content匹配golden文件返回响应。同一请求录制多次时按顺序回放，用完后重复返回最后一次的响应。
+ * 响应中的sign同样会被脱敏，回放时需要通过 Resigner 使用测试密钥重新签名，客户端验签才能通过。
+ */
+
+// RedactedValue 脱敏后的取值
+const RedactedValue = "REDACTED"
+
+var ErrNoRecording = errors.New("replay: no recording matched")
+
+// DefaultRedactKeys 默认脱敏的字段，公共参数、biz_content和响应中的同名字段都会被脱敏
+var DefaultRedactKeys = []string{
+	"sign", "app_auth_token", "auth_token", "auth_code", "access_token", "refresh_token",
+	"app_refresh_token", "cert_no", "cert_name", "payee_account", "identity",
+}
+
+type Mode int
+
+const (
+	// ModeReplay 回放
+	ModeReplay Mode = iota
+	// ModeRecord 录制
+	ModeRecord
+)
+
+// Exchange golden文件的内容
+type Exchange struct {
+	Method     string            `json:"method"`      // 接口名称
+	HttpMethod string            `json:"http_method"` // http请求方法
+	Params     map[string]string `json:"params"`      // 除biz_content外的公共参数
+	BizContent json.RawMessage   `json:"biz_content"` // 解码后的业务参数
+	StatusCode int               `json:"status_code"` // 响应状态码
+	Response   string            `json:"response"`    // 原始响应
+}
+
+// Transport 录制和回放网关请求
+type Transport struct {
+	// Mode 录制或回放
+	Mode Mode
+	// Dir golden文件所在目录
+	Dir string
+	// Base 录制时实际发送请求的RoundTripper，为nil时使用http.DefaultTransport
+	Base http.RoundTripper
+	// RedactKeys 需要脱敏的字段
+	RedactKeys []string
+	// Resigner 回放时对响应重新签名，为nil时原样返回
+	Resigner *Resigner
+
+	mu       sync.Mutex
+	counters map[string]int
+}
+
+// NewRecorder 创建录制模式的Transport
+func NewRecorder(dir string, base http.RoundTripper) *Transport {
+	return &Transport{Mode: ModeRecord, Dir: dir, Base: base, RedactKeys: DefaultRedactKeys}
+}
+
+// NewReplayer 创建回放模式的Transport
+func NewReplayer(dir string, resigner *Resigner) *Transport {
+	return &Transport{Mode: ModeReplay, Dir: dir, RedactKeys: DefaultRedactKeys, Resigner: resigner}
+}
+
+// Client 使用当前Transport的http.Client
+func (r *Transport) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+func (r *Transport) RoundTrip(request *http.Request) (*http.Response, error) {
+	var body []byte
+	var err error
+	if request.Body != nil {
+		if body, err = io.ReadAll(request.Body); err != nil {
+			return nil, err
+		}
+		request.Body.Close()
+		request.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		values = request.URL.Query()
+	}
+	exchange := &Exchange{Method: values.Get("method"), HttpMethod: request.Method, Params: make(map[string]string)}
+	for key := range values {
+		if key != "biz_content" {
+			exchange.Params[key] = r.redactParam(key, values.Get(key))
+		}
+	}
+	if exchange.BizContent, err = r.canonicalBizContent(values.Get("biz_content")); err != nil {
+		return nil, err
+	}
+	key := exchangeKey(exchange)
+	if r.Mode == ModeRecord {
+		return r.record(request, exchange, key)
+	}
+	return r.replay(request, exchange, key)
+}
+
+func (r *Transport) record(request *http.Request, exchange *Exchange, key string) (*http.Response, error) {
+	base := r.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	response, err := base.RoundTrip(request)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	response.Body = io.NopCloser(bytes.NewReader(body))
+	exchange.StatusCode = response.StatusCode
+	exchange.Response = r.redactResponse(string(body))
+	buff, err := json.MarshalIndent(exchange, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err = os.MkdirAll(r.Dir, 0755); err != nil {
+		return nil, err
+	}
+	if err = os.WriteFile(r.filename(key, r.next(key)), buff, 0644); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (r *Transport) replay(request *http.Request, exchange *Exchange, key string) (*http.Response, error) {
+	seq := r.next(key)
+	buff, err := os.ReadFile(r.filename(key, seq))
+	// 录制的响应用完后重复返回最后一次的响应
+	for os.IsNotExist(err) && seq > 1 {
+		seq--
+		buff, err = os.ReadFile(r.filename(key, seq))
+	}
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s %s", ErrNoRecording, exchange.Method, exchange.BizContent)
+	}
+	if err != nil {
+		return nil, err
+	}
+	recorded := new(Exchange)
+	if err = json.Unmarshal(buff, recorded); err != nil {
+		return nil, err
+	}
+	body := []byte(recorded.Response)
+	if r.Resigner != nil {
+		if body, err = r.Resigner.Resign(body); err != nil {
+			return nil, err
+		}
+	}
+	return &http.Response{
+		Status:        strconv.Itoa(recorded.StatusCode) + " " + http.StatusText(recorded.StatusCode),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"text/html;charset=utf-8"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       request,
+	}, nil
+}
+
+// next 同一请求在本次录制或回放中的序号，从1开始
+func (r *Transport) next(key string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.counters == nil {
+		r.counters = make(map[string]int)
+	}
+	r.counters[key]++
+	return r.counters[key]
+}
+
+func (r *Transport) filename(key string, seq int) string {
+	return filepath.Join(r.Dir, key+"_"+strconv.Itoa(seq)+".json")
+}
+
+// exchangeKey 接口名加上biz_content的摘要，如 alipay.trade.query_1a2b3c4d
+func exchangeKey(exchange *Exchange) string {
+	sum := sha1.Sum(append([]byte(exchange.Method+"\n"), exchange.BizContent...))
+	return exchange.Method + "_" + hex.EncodeToString(sum[:4])
+}
+
+func (r *Transport) isRedactKey(key string) bool {
+	for _, item := range r.RedactKeys {
+		if item == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Transport) redactParam(key, value string) string {
+	if len(value) > 0 && r.isRedactKey(key) {
+		return RedactedValue
+	}
+	return value
+}
+
+// canonicalBizContent 脱敏后按key排序重新编码，保证同样的业务参数得到同样的结果
+func (r *Transport) canonicalBizContent(bizContent string) (json.RawMessage, error) {
+	if len(bizContent) == 0 {
+		return json.RawMessage("null"), nil
+	}
+	var content interface{}
+	if err := json.Unmarshal([]byte(bizContent), &content); err != nil {
+		return nil, err
+	}
+	buff, err := json.Marshal(r.redactValue(content))
+	if err != nil {
+		return nil, err
+	}
+	return buff, nil
+}
+
+func (r *Transport) redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if _, ok := item.(string); ok && r.isRedactKey(key) {
+				v[key] = RedactedValue
+				continue
+			}
+			v[key] = r.redactValue(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = r.redactValue(item)
+		}
+	}
+	return value
+}
+
+var responseFieldPattern = regexp.MustCompile(`"([a-z_]+)":"((?:[^"\\]|\\.)*)"`)
+
+// redactResponse 直接替换原始响应中的字段值，不改变其余内容的格式
+func (r *Transport) redactResponse(body string) string {
+	return responseFieldPattern.ReplaceAllStringFunc(body, func(field string) string {
+		match := responseFieldPattern.FindStringSubmatch(field)
+		if r.isRedactKey(match[1]) {
+			return `"` + match[1] + `":"` + RedactedValue + `"`
+		}
+		return field
+	})
+}
@@ -0,0 +1,141 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	alipay "github.com/try-labs/xpay"
+	"github.com/try-labs/xpay/alipaytest"
+)
+
+/**
+ * @author: Sam
+ * @since:
+ * @date: 2026/10/19 18:50
+ * @desc:
+ */
+
+const authCode = "287951669891795468"
+
+// record 通过模拟网关录制一次条码支付以及支付前后的两次查询
+func record(t *testing.T, dir string, opts ...alipaytest.Option) *alipaytest.Gateway {
+	t.Helper()
+	g := alipaytest.NewGateway(opts...)
+	t.Cleanup(g.Close)
+	recorder := NewRecorder(dir, g.Server.Client().Transport)
+	client, err := g.Client(alipay.SetClientOptHttpClient(recorder.Client()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if _, err = client.TradePay(ctx, alipay.TradePayReq{OutTradeNo: "20261019000001", TotalAmount: alipay.MustParseMoney("8.80"), Subject: "测试商品", AuthCode: authCode, Scene: "bar_code"}); err != nil {
+		t.Fatal(err)
+	}
+	preCreate := alipay.TradePreCreateReq{OutTradeNo: "20261019000002", TotalAmount: alipay.MustParseMoney("1.00"), Subject: "测试商品", ProductCode: "FACE_TO_FACE_PAYMENT"}
+	if _, err = client.TradePreCreate(ctx, preCreate); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.TradeQuery(ctx, alipay.TradeQueryReq{OutTradeNo: "20261019000002"}); err != nil {
+		t.Fatal(err)
+	}
+	if err = g.PayTrade("20261019000002", ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.TradeQuery(ctx, alipay.TradeQueryReq{OutTradeNo: "20261019000002"}); err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+func TestTransport_RecordRedact(t *testing.T) {
+	dir := t.TempDir()
+	record(t, dir)
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 4 {
+		t.Fatalf("unexpected golden files: %v", files)
+	}
+	for _, file := range files {
+		buff, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		content := string(buff)
+		if strings.Contains(content, authCode) {
+			t.Errorf("auth_code should be redacted: %s", file)
+		}
+		if !strings.Contains(content, `"sign": "REDACTED"`) || !strings.Contains(content, `\"sign\":\"REDACTED\"`) {
+			t.Errorf("sign should be redacted: %s", content)
+		}
+	}
+}
+
+func TestTransport_Replay(t *testing.T) {
+	dir := t.TempDir()
+	g := record(t, dir)
+	calls := g.Calls("alipay.trade.query")
+	replayer := NewReplayer(dir, NewResigner(g.Keys().Alipay.PrivateKey))
+	client, err := g.Client(alipay.SetClientOptHttpClient(replayer.Client()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	payRes, err := client.TradePay(ctx, alipay.TradePayReq{OutTradeNo: "20261019000001", TotalAmount: alipay.MustParseMoney("8.80"), Subject: "测试商品", AuthCode: "280000000000000000", Scene: "bar_code"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !payRes.Success() || payRes.OutTradeNo != "20261019000001" {
+		t.Errorf("unexpected pay response: %+v", payRes)
+	}
+	// 同一请求按录制顺序回放，用完后重复最后一次的响应
+	for _, status := range []alipay.TradeStatus{alipay.TradeWaitBuyerPay, alipay.TradeSuccess, alipay.TradeSuccess} {
+		queryRes, err := client.TradeQuery(ctx, alipay.TradeQueryReq{OutTradeNo: "20261019000002"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if queryRes.TradeStatus != status {
+			t.Errorf("trade status %s, want %s", queryRes.TradeStatus, status)
+		}
+	}
+	if g.Calls("alipay.trade.query") != calls {
+		t.Error("replay should not reach the gateway")
+	}
+	if _, err = client.TradeQuery(ctx, alipay.TradeQueryReq{OutTradeNo: "20261019000003"}); !errors.Is(err, ErrNoRecording) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestTransport_ReplayWithoutResigner(t *testing.T) {
+	dir := t.TempDir()
+	g := record(t, dir)
+	client, err := g.Client(alipay.SetClientOptHttpClient(NewReplayer(dir, nil).Client()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.TradeQuery(context.Background(), alipay.TradeQueryReq{OutTradeNo: "20261019000002"}); err == nil {
+		t.Error("redacted sign should fail verification")
+	}
+}
+
+func TestTransport_ReplayCertMode(t *testing.T) {
+	dir := t.TempDir()
+	g := record(t, dir, alipaytest.WithCertMode())
+	resigner := NewResigner(g.Keys().Alipay.PrivateKey).WithAlipayCertSn(g.Keys().AlipayCert.SN())
+	client, err := g.Client(alipay.SetClientOptHttpClient(NewReplayer(dir, resigner).Client()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	queryRes, err := client.TradeQuery(context.Background(), alipay.TradeQueryReq{OutTradeNo: "20261019000002"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if queryRes.TradeStatus != alipay.TradeWaitBuyerPay {
+		t.Errorf("unexpected trade status: %s", queryRes.TradeStatus)
+	}
+}
@@ -0,0 +1,62 @@
+package replay
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"encoding/base64"
+	"strings"
+
+	alipay "github.com/try-labs/xpay"
+)
+
+/**
+ * @author: Sam
+ * @since: 1.0.0
+ * @date: 2026/10/19 18:40
+ * @desc: 使用测试密钥对录制的响应重新签名
+ */
+
+// Resigner 对响应重新签名，签名内容与客户端验签时截取的内容一致
+type Resigner struct {
+	privateKey   *rsa.PrivateKey
+	alipayCertSn string
+}
+
+// NewResigner privateKey 为测试用的支付宝私钥，对应客户端配置的支付宝公钥
+func NewResigner(privateKey *rsa.PrivateKey) *Resigner {
+	return &Resigner{privateKey: privateKey}
+}
+
+// WithAlipayCertSn 证书模式下客户端按alipay_cert_sn查找支付宝公钥，需要设置为测试支付宝公钥证书的SN
+func (r *Resigner) WithAlipayCertSn(sn string) *Resigner {
+	r.alipayCertSn = sn
+	return r
+}
+
+// Resign 替换响应中的alipay_cert_sn和sign，不是网关响应格式的内容原样返回
+func (r *Resigner) Resign(body []byte) ([]byte, error) {
+	str := string(body)
+	indexStart := strings.Index(str, `_response":`)
+	if indexStart < 0 {
+		return body, nil
+	}
+	indexStart += len(`_response":`)
+	indexEnd := strings.Index(str, `,"alipay_cert_sn":`)
+	if indexEnd < indexStart {
+		indexEnd = strings.Index(str, `,"sign":`)
+	}
+	if indexEnd < indexStart {
+		return body, nil
+	}
+	sign, err := alipay.RSASignWithKey([]byte(str[indexStart:indexEnd]), r.privateKey, crypto.SHA256)
+	if err != nil {
+		return nil, err
+	}
+	var builder strings.Builder
+	builder.WriteString(str[:indexEnd])
+	if len(r.alipayCertSn) > 0 {
+		builder.WriteString(`,"alipay_cert_sn":"` + r.alipayCertSn + `"`)
+	}
+	builder.WriteString(`,"sign":"` + base64.StdEncoding.EncodeToString(sign) + `"}`)
+	return []byte(builder.String()), nil
+}
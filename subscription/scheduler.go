@@ -0,0 +1,267 @@
+package subscription
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	alipay "github.com/try-labs/xpay"
+)
+
+/**
+ * @author: Sam
+ * @since: 1.0.0
+ * @date: 2026/10/20 00:40
+ * @desc: 周期扣款调度
+ *
+ * 到期的订阅使用代扣协议调用 alipay.trade.pay 扣款，每次扣款使用新的商户订单号 订阅号_期数_次数。
+ * 余额不足等可重试的失败按 RetryIntervals 再次扣款，重试次数用尽后暂停订阅；结果未知时先通过 alipay.trade.query 查询确认，
+ * 交易不存在时沿用原商户订单号重新扣款，避免重复扣款。协议解约的通知或者协议失效的错误码会取消该协议下的订阅。
+ */
+
+// DefaultRetrySubCodes 可重试的扣款失败
+var DefaultRetrySubCodes = map[string]bool{
+	"ACQ.BUYER_BALANCE_NOT_ENOUGH":               true,
+	"ACQ.BUYER_BANKCARD_BALANCE_NOT_ENOUGH":      true,
+	"ACQ.PAYMENT_FAIL":                           true,
+	"ACQ.BUYER_PAYMENT_AMOUNT_DAY_LIMIT_ERROR":   true,
+	"ACQ.BUYER_PAYMENT_AMOUNT_MONTH_LIMIT_ERROR": true,
+}
+
+// agreementEndedSubCodes 协议已解约或者失效，取消订阅
+var agreementEndedSubCodes = map[string]bool{
+	"ACQ.AGREEMENT_NOT_EXIST":         true,
+	"ACQ.AGREEMENT_INVALID":           true,
+	"ACQ.AGREEMENT_STATUS_NOT_NORMAL": true,
+	"ACQ.AGREEMENT_ERROR":             true,
+}
+
+// Scheduler 周期扣款调度
+type Scheduler struct {
+	client *alipay.Client
+	store  Store
+
+	Now func() time.Time // 当前时间，默认 time.Now
+	// RetryIntervals 可重试的失败后再次扣款的间隔，第n次失败后等待第n个间隔，失败次数超过间隔个数时暂停订阅，默认1天、2天、3天
+	RetryIntervals []time.Duration
+	RetrySubCodes  map[string]bool // 可重试的错误码，默认 DefaultRetrySubCodes
+	QueryInterval  time.Duration   // 结果未知时查询确认的间隔，默认10分钟
+}
+
+func NewScheduler(client *alipay.Client, store Store) *Scheduler {
+	return &Scheduler{
+		client:         client,
+		store:          store,
+		Now:            time.Now,
+		RetryIntervals: []time.Duration{24 * time.Hour, 48 * time.Hour, 72 * time.Hour},
+		RetrySubCodes:  DefaultRetrySubCodes,
+		QueryInterval:  10 * time.Minute,
+	}
+}
+
+// Subscribe 保存新的订阅，从 StartAt 开始扣款
+func (r *Scheduler) Subscribe(ctx context.Context, sub *Subscription) error {
+	if sub.PeriodType != "DAY" && sub.PeriodType != "MONTH" || sub.Period < 1 || sub.StartAt.IsZero() {
+		return fmt.Errorf("xpay: invalid period rule of subscription %s", sub.Id)
+	}
+	req := r.payReq(sub, outTradeNo(sub))
+	if err := req.DoValidate(); err != nil {
+		return err
+	}
+	exists, err := r.store.Subscription(ctx, sub.Id)
+	if err != nil {
+		return err
+	}
+	if exists != nil {
+		return ErrSubscriptionExists
+	}
+	now := r.Now()
+	sub.Status, sub.Cycle, sub.Retries, sub.PendingOutTradeNo = StatusActive, 0, 0, ""
+	sub.DueAt = sub.StartAt
+	sub.CreatedAt, sub.UpdatedAt = now, now
+	return r.store.Save(ctx, sub)
+}
+
+// Cancel 商户取消订阅，不影响代扣协议
+func (r *Scheduler) Cancel(ctx context.Context, id string) error {
+	sub, err := r.store.Subscription(ctx, id)
+	if err != nil {
+		return err
+	}
+	if sub == nil {
+		return ErrSubscriptionNotExist
+	}
+	return r.cancel(ctx, sub)
+}
+
+// HandleAgreementNotify 处理 AgreementNotify 解码的签约、解约通知，解约后取消该协议下的全部订阅
+func (r *Scheduler) HandleAgreementNotify(ctx context.Context, notifyReq *alipay.UserAgreementNotifyReq) error {
+	if !notifyReq.Unsigned() {
+		return nil
+	}
+	subs, err := r.store.AgreementSubscriptions(ctx, notifyReq.AgreementNo)
+	if err != nil {
+		return err
+	}
+	for _, sub := range subs {
+		if err = r.cancel(ctx, sub); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cancel 取消订阅，结果未知的扣款保留在 PendingOutTradeNo 中，由商户核实
+func (r *Scheduler) cancel(ctx context.Context, sub *Subscription) error {
+	if !sub.Status.Billable() {
+		return nil
+	}
+	sub.Status, sub.UpdatedAt = StatusCanceled, r.Now()
+	return r.store.Save(ctx, sub)
+}
+
+// RunDue 处理全部到期的订阅并返回本次的扣款记录，存储失败或者ctx结束时返回error，已处理的记录同样返回
+func (r *Scheduler) RunDue(ctx context.Context) ([]*Attempt, error) {
+	now := r.Now()
+	subs, err := r.store.Due(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	attempts := make([]*Attempt, 0, len(subs))
+	for _, sub := range subs {
+		if err = ctx.Err(); err != nil {
+			return attempts, err
+		}
+		attempt, err := r.bill(ctx, sub, now)
+		if attempt != nil {
+			attempts = append(attempts, attempt)
+		}
+		if err != nil {
+			return attempts, err
+		}
+	}
+	return attempts, nil
+}
+
+// outTradeNo 本期下一次扣款的商户订单号
+func outTradeNo(sub *Subscription) string {
+	return fmt.Sprintf("%s_%d_%d", sub.Id, sub.Cycle+1, sub.Retries+1)
+}
+
+func (r *Scheduler) payReq(sub *Subscription, outTradeNo string) alipay.TradePayReq {
+	return alipay.TradePayReq{
+		OutTradeNo:      outTradeNo,
+		TotalAmount:     sub.Amount,
+		Subject:         sub.Subject,
+		ProductCode:     alipay.GeneralWithholding,
+		AgreementParams: &alipay.AgreementParams{AgreementNo: sub.AgreementNo},
+	}
+}
+
+// bill 扣款一次，结果未知的订阅先查询，扣款前订阅已停止扣款时返回nil
+func (r *Scheduler) bill(ctx context.Context, sub *Subscription, now time.Time) (*Attempt, error) {
+	attempt := &Attempt{SubscriptionId: sub.Id, Cycle: sub.Cycle + 1, Amount: sub.Amount, At: now}
+	if len(sub.PendingOutTradeNo) > 0 {
+		attempt.Action, attempt.OutTradeNo = ActionQuery, sub.PendingOutTradeNo
+		if r.query(ctx, attempt) {
+			return attempt, r.settle(ctx, sub, attempt, now)
+		}
+	} else {
+		attempt.OutTradeNo = outTradeNo(sub)
+	}
+	// Due 返回之后订阅可能已被解约通知或者 Cancel 取消，扣款前重新读取
+	current, err := r.store.Subscription(ctx, sub.Id)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil || !current.Status.Billable() {
+		return nil, nil
+	}
+	attempt.Action = ActionPay
+	res, err := r.client.TradePay(ctx, r.payReq(sub, attempt.OutTradeNo))
+	switch {
+	case err != nil:
+		attempt.Result, attempt.Error = ResultUnknown, err.Error()
+	case res.Success():
+		attempt.Result, attempt.TradeNo = ResultPaid, res.TradeNo
+	default:
+		attempt.SubCode, attempt.SubMsg = res.SubCode, res.SubMsg
+		attempt.Result = r.failResult(&res.CommonRes)
+	}
+	return attempt, r.settle(ctx, sub, attempt, now)
+}
+
+// query 查询结果未知的扣款，交易不存在时返回false，需要沿用原商户订单号重新扣款
+func (r *Scheduler) query(ctx context.Context, attempt *Attempt) bool {
+	res, err := r.client.TradeQuery(ctx, alipay.TradeQueryReq{OutTradeNo: attempt.OutTradeNo})
+	switch {
+	case err != nil:
+		attempt.Result, attempt.Error = ResultUnknown, err.Error()
+	case res.SubCode == alipay.SubCodeTradeNotExist:
+		return false
+	case !res.Success():
+		attempt.Result, attempt.SubCode, attempt.SubMsg = ResultUnknown, res.SubCode, res.SubMsg
+	case res.TradeStatus == alipay.TradeSuccess || res.TradeStatus == alipay.TradeFinished:
+		attempt.Result, attempt.TradeNo = ResultPaid, res.TradeNo
+	case res.TradeStatus == alipay.TradeClosed:
+		attempt.Result, attempt.TradeNo = ResultDeclined, res.TradeNo
+	default:
+		attempt.Result, attempt.TradeNo = ResultUnknown, res.TradeNo
+	}
+	return true
+}
+
+func (r *Scheduler) failResult(res *alipay.CommonRes) AttemptResult {
+	switch {
+	case res.Code == alipay.CodeWaitBuyerPay || res.Code == alipay.CodeUnknownError || res.SubCode == alipay.SubCodeSysError:
+		return ResultUnknown
+	case r.RetrySubCodes[res.SubCode]:
+		return ResultDeclined
+	default:
+		return ResultRejected
+	}
+}
+
+// settle 按扣款结果更新订阅并保存扣款记录，扣款期间被取消的订阅只记录扣款结果，不会恢复扣款
+func (r *Scheduler) settle(ctx context.Context, sub *Subscription, attempt *Attempt, now time.Time) error {
+	current, err := r.store.Subscription(ctx, sub.Id)
+	if err != nil {
+		return err
+	}
+	if current != nil {
+		sub = current
+	}
+	status := sub.Status
+	sub.PendingOutTradeNo = ""
+	switch attempt.Result {
+	case ResultPaid:
+		sub.Cycle, sub.Retries, sub.Status = sub.Cycle+1, 0, StatusActive
+		sub.DueAt = sub.periodStart(sub.Cycle)
+		if sub.TotalPayments > 0 && sub.Cycle >= sub.TotalPayments {
+			sub.Status = StatusCompleted
+		}
+	case ResultDeclined:
+		sub.Retries++
+		if sub.Retries > len(r.RetryIntervals) {
+			sub.Status = StatusSuspended
+		} else {
+			sub.Status, sub.DueAt = StatusPastDue, now.Add(r.RetryIntervals[sub.Retries-1])
+		}
+	case ResultRejected:
+		sub.Status = StatusSuspended
+		if agreementEndedSubCodes[attempt.SubCode] {
+			sub.Status = StatusCanceled
+		}
+	default:
+		sub.PendingOutTradeNo, sub.DueAt = attempt.OutTradeNo, now.Add(r.QueryInterval)
+	}
+	if !status.Billable() {
+		sub.Status = status
+	}
+	sub.UpdatedAt = now
+	attempt.Status, attempt.DueAt = sub.Status, sub.DueAt
+	if err := r.store.AddAttempt(ctx, attempt); err != nil {
+		return err
+	}
+	return r.store.Save(ctx, sub)
+}
@@ -0,0 +1,278 @@
+package subscription_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	alipay "github.com/try-labs/xpay"
+	"github.com/try-labs/xpay/alipaytest"
+	. "github.com/try-labs/xpay/subscription"
+)
+
+/**
+ * @author: Sam
+ * @since:
+ * @date: 2026/10/20 00:40
+ * @desc:
+ */
+
+var cst = time.FixedZone("CST", 8*3600)
+
+type clock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *clock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+type fixture struct {
+	gateway   *alipaytest.Gateway
+	client    *alipay.Client
+	store     *alipaytest.SubscriptionStore
+	scheduler *Scheduler
+	clock     *clock
+	merchant  *httptest.Server
+	handled   chan *alipay.UserAgreementNotifyReq
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	f := &fixture{clock: &clock{now: time.Date(2026, 10, 20, 10, 0, 0, 0, cst)}, store: alipaytest.NewSubscriptionStore(), handled: make(chan *alipay.UserAgreementNotifyReq, 1)}
+	f.merchant = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		notifyReq, err := f.client.AgreementNotify(r)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if err = f.scheduler.HandleAgreementNotify(r.Context(), notifyReq); err != nil {
+			t.Error(err)
+			return
+		}
+		f.handled <- notifyReq
+		io.WriteString(w, "success")
+	}))
+	f.gateway = alipaytest.NewGateway(alipaytest.WithNow(f.clock.Now), alipaytest.WithLocation(cst))
+	client, err := f.gateway.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.client = client
+	f.scheduler = NewScheduler(client, f.store)
+	f.scheduler.Now = f.clock.Now
+	t.Cleanup(func() {
+		f.gateway.Close()
+		f.merchant.Close()
+	})
+	return f
+}
+
+// sign 签约代扣协议并返回协议号
+func (f *fixture) sign(t *testing.T, externalAgreementNo string, rule *alipay.AgreementPeriodRuleParams) string {
+	t.Helper()
+	signURL, err := f.client.UserAgreementPageSign(alipay.UserAgreementPageSignReq{
+		PersonalProductCode: alipay.CyclePayAuthP,
+		ProductCode:         alipay.GeneralWithholding,
+		SignScene:           "INDUSTRY|DIGITAL_MEDIA",
+		ExternalAgreementNo: externalAgreementNo,
+		AccessParams:        &alipay.AgreementAccessParams{Channel: "ALIPAYAPP"},
+		PeriodRuleParams:    rule,
+		NotifyUrl:           f.merchant.URL,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, err := http.Get(signURL.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+	if err = f.gateway.SignAgreement(externalAgreementNo, ""); err != nil {
+		t.Fatal(err)
+	}
+	return (<-f.handled).AgreementNo
+}
+
+func (f *fixture) runDue(t *testing.T, now time.Time) []*Attempt {
+	t.Helper()
+	f.clock.Set(now)
+	attempts, err := f.scheduler.RunDue(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return attempts
+}
+
+func (f *fixture) subscription(t *testing.T, id string) *Subscription {
+	t.Helper()
+	sub, err := f.store.Subscription(context.Background(), id)
+	if err != nil || sub == nil {
+		t.Fatalf("subscription %s: %v", id, err)
+	}
+	return sub
+}
+
+func TestScheduler_RunDue(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	rule := alipay.AgreementPeriodRuleParams{PeriodType: "MONTH", Period: 1, ExecuteTime: "2026-10-31", SingleAmount: alipay.MustParseMoney("25.00")}
+	agreementNo := f.sign(t, "AG20261020101", &rule)
+	sub, err := NewSubscription("S20261020101", agreementNo, "会员月卡", rule, cst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = f.scheduler.Subscribe(ctx, sub); err != nil {
+		t.Fatal(err)
+	}
+	if err = f.scheduler.Subscribe(ctx, sub); err != ErrSubscriptionExists {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if attempts := f.runDue(t, time.Date(2026, 10, 30, 23, 0, 0, 0, cst)); len(attempts) != 0 {
+		t.Fatalf("subscription should not be due: %+v", attempts[0])
+	}
+
+	// 第1期扣款成功，下一期按月递推，11月没有31日取月末
+	attempts := f.runDue(t, time.Date(2026, 10, 31, 9, 0, 0, 0, cst))
+	if len(attempts) != 1 || attempts[0].Result != ResultPaid || attempts[0].OutTradeNo != "S20261020101_1_1" {
+		t.Fatalf("unexpected attempts: %+v", attempts)
+	}
+	if trade, ok := f.gateway.Trade("S20261020101_1_1"); !ok || trade.TotalAmount != rule.SingleAmount || trade.TradeNo != attempts[0].TradeNo {
+		t.Errorf("unexpected trade: %+v", trade)
+	}
+	if sub = f.subscription(t, sub.Id); sub.Cycle != 1 || !sub.DueAt.Equal(time.Date(2026, 11, 30, 0, 0, 0, 0, cst)) {
+		t.Errorf("unexpected subscription: %+v", sub)
+	}
+
+	// 第2期余额不足，1天后重试成功，再下一期仍为31日
+	f.gateway.InjectError("alipay.trade.pay", alipaytest.ErrBuyerBalanceNotEnough, 1)
+	attempts = f.runDue(t, time.Date(2026, 11, 30, 9, 0, 0, 0, cst))
+	if len(attempts) != 1 || attempts[0].Result != ResultDeclined || attempts[0].SubCode != alipaytest.ErrBuyerBalanceNotEnough.SubCode || attempts[0].Status != StatusPastDue {
+		t.Fatalf("unexpected attempts: %+v", attempts)
+	}
+	if attempts = f.runDue(t, time.Date(2026, 12, 1, 8, 0, 0, 0, cst)); len(attempts) != 0 {
+		t.Fatalf("retry should wait for the interval: %+v", attempts[0])
+	}
+	attempts = f.runDue(t, time.Date(2026, 12, 1, 9, 0, 0, 0, cst))
+	if len(attempts) != 1 || attempts[0].Result != ResultPaid || attempts[0].OutTradeNo != "S20261020101_2_2" || attempts[0].Status != StatusActive {
+		t.Fatalf("unexpected attempts: %+v", attempts)
+	}
+	if sub = f.subscription(t, sub.Id); sub.Cycle != 2 || sub.Retries != 0 || !sub.DueAt.Equal(time.Date(2026, 12, 31, 0, 0, 0, 0, cst)) {
+		t.Errorf("unexpected subscription: %+v", sub)
+	}
+
+	// 第3期结果未知，查询到交易不存在后沿用原商户订单号扣款
+	f.gateway.InjectError("alipay.trade.pay", alipaytest.ErrSystemError, 1)
+	attempts = f.runDue(t, time.Date(2026, 12, 31, 9, 0, 0, 0, cst))
+	if len(attempts) != 1 || attempts[0].Result != ResultUnknown {
+		t.Fatalf("unexpected attempts: %+v", attempts)
+	}
+	if sub = f.subscription(t, sub.Id); sub.PendingOutTradeNo != "S20261020101_3_1" {
+		t.Errorf("unexpected subscription: %+v", sub)
+	}
+	pays := f.gateway.Calls("alipay.trade.pay")
+	attempts = f.runDue(t, time.Date(2026, 12, 31, 9, 10, 0, 0, cst))
+	if len(attempts) != 1 || attempts[0].Action != ActionPay || attempts[0].Result != ResultPaid || attempts[0].OutTradeNo != "S20261020101_3_1" {
+		t.Fatalf("unexpected attempts: %+v", attempts)
+	}
+	if f.gateway.Calls("alipay.trade.query") != 1 || f.gateway.Calls("alipay.trade.pay") != pays+1 {
+		t.Errorf("unknown result should be queried before paying again")
+	}
+
+	// 用户解约后停止扣款
+	if _, err = f.client.UserAgreementUnsign(ctx, alipay.UserAgreementUnsignReq{AgreementNo: agreementNo}); err != nil {
+		t.Fatal(err)
+	}
+	<-f.handled
+	if attempts = f.runDue(t, time.Date(2027, 1, 31, 9, 0, 0, 0, cst)); len(attempts) != 0 {
+		t.Fatalf("canceled subscription should not be billed: %+v", attempts[0])
+	}
+	if sub = f.subscription(t, sub.Id); sub.Status != StatusCanceled || sub.Cycle != 3 {
+		t.Errorf("unexpected subscription: %+v", sub)
+	}
+	if log := f.store.Attempts(sub.Id); len(log) != 5 {
+		t.Errorf("unexpected attempt log: %+v", log)
+	}
+}
+
+func TestScheduler_Dunning(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	rule := alipay.AgreementPeriodRuleParams{PeriodType: "DAY", Period: 7, ExecuteTime: "2026-10-20", SingleAmount: alipay.MustParseMoney("5.00")}
+	agreementNo := f.sign(t, "AG20261020102", &rule)
+	f.scheduler.RetryIntervals = []time.Duration{time.Hour, 2 * time.Hour}
+
+	sub, _ := NewSubscription("S20261020102", agreementNo, "周卡", rule, cst)
+	if err := f.scheduler.Subscribe(ctx, sub); err != nil {
+		t.Fatal(err)
+	}
+	f.gateway.InjectError("alipay.trade.pay", alipaytest.ErrBuyerBalanceNotEnough, 3)
+	start := time.Date(2026, 10, 20, 0, 0, 0, 0, cst)
+	for i, offset := range []time.Duration{0, time.Hour, 3 * time.Hour} {
+		attempts := f.runDue(t, start.Add(offset))
+		if len(attempts) != 1 || attempts[0].Result != ResultDeclined {
+			t.Fatalf("attempt %d: %+v", i, attempts)
+		}
+	}
+	if sub = f.subscription(t, sub.Id); sub.Status != StatusSuspended || sub.Retries != 3 {
+		t.Errorf("subscription should be suspended: %+v", sub)
+	}
+
+	// 协议不存在时取消订阅
+	invalid, _ := NewSubscription("S20261020103", "20260000000000000000", "周卡", rule, cst)
+	if err := f.scheduler.Subscribe(ctx, invalid); err != nil {
+		t.Fatal(err)
+	}
+	attempts := f.runDue(t, start.Add(4*time.Hour))
+	if len(attempts) != 1 || attempts[0].Result != ResultRejected || attempts[0].Status != StatusCanceled {
+		t.Fatalf("unexpected attempts: %+v", attempts)
+	}
+	if err := f.scheduler.Cancel(ctx, "S20261020104"); err != ErrSubscriptionNotExist {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+// TestScheduler_UnsignDuringPay 扣款请求期间收到解约通知，扣款成功后订阅仍为已取消
+func TestScheduler_UnsignDuringPay(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	rule := alipay.AgreementPeriodRuleParams{PeriodType: "DAY", Period: 1, ExecuteTime: "2026-10-20", SingleAmount: alipay.MustParseMoney("1.00")}
+	agreementNo := f.sign(t, "AG20261020105", &rule)
+	sub, _ := NewSubscription("S20261020105", agreementNo, "日卡", rule, cst)
+	if err := f.scheduler.Subscribe(ctx, sub); err != nil {
+		t.Fatal(err)
+	}
+	f.gateway.Use(func(req *alipaytest.Request) error {
+		if req.Method == "alipay.trade.pay" {
+			unsign := &alipay.UserAgreementNotifyReq{NotifyType: alipay.NotifyTypeAgreementUnsign, AgreementNo: agreementNo}
+			if err := f.scheduler.HandleAgreementNotify(ctx, unsign); err != nil {
+				t.Error(err)
+			}
+		}
+		return nil
+	})
+	attempts := f.runDue(t, time.Date(2026, 10, 20, 9, 0, 0, 0, cst))
+	if len(attempts) != 1 || attempts[0].Result != ResultPaid || attempts[0].Status != StatusCanceled {
+		t.Fatalf("unexpected attempts: %+v", attempts)
+	}
+	if sub = f.subscription(t, sub.Id); sub.Status != StatusCanceled || sub.Cycle != 1 {
+		t.Errorf("unexpected subscription: %+v", sub)
+	}
+	pays := f.gateway.Calls("alipay.trade.pay")
+	if attempts = f.runDue(t, time.Date(2026, 10, 22, 9, 0, 0, 0, cst)); len(attempts) != 0 || f.gateway.Calls("alipay.trade.pay") != pays {
+		t.Fatalf("canceled subscription should not be billed: %+v", attempts)
+	}
+}
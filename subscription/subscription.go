@@ -0,0 +1,142 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	alipay "github.com/try-labs/xpay"
+)
+
+/**
+ * @author: Sam
+ * @since: 1.0.0
+ * @date: 2026/10/20 00:40
+ * @desc: 订阅及扣款记录的存储
+ */
+
+var (
+	ErrSubscriptionExists   = errors.New("xpay: subscription already exists")
+	ErrSubscriptionNotExist = errors.New("xpay: subscription not exist")
+)
+
+// Status 订阅状态
+type Status string
+
+const (
+	StatusActive    Status = "ACTIVE"    // 正常扣款
+	StatusPastDue   Status = "PAST_DUE"  // 本期扣款失败，等待重试
+	StatusSuspended Status = "SUSPENDED" // 重试次数用尽或者扣款被拒绝，停止扣款
+	StatusCanceled  Status = "CANCELED"  // 用户解约、协议失效或者商户取消
+	StatusCompleted Status = "COMPLETED" // 已完成约定的扣款次数
+)
+
+// Billable 是否需要继续扣款
+func (r Status) Billable() bool {
+	return r == StatusActive || r == StatusPastDue
+}
+
+// Subscription 一个订阅，按周期使用代扣协议扣款
+type Subscription struct {
+	// Id 商户订阅号，用于生成商户订单号，不超过40个字符
+	Id            string       `json:"id"`
+	AgreementNo   string       `json:"agreement_no"` // 支付宝代扣协议号
+	Subject       string       `json:"subject"`      // 订单标题
+	Amount        alipay.Money `json:"amount"`       // 每期扣款金额
+	PeriodType    string       `json:"period_type"`  // DAY、MONTH
+	Period        int          `json:"period"`       // 周期数
+	StartAt       time.Time    `json:"start_at"`     // 首次扣款时间，之后按周期递推，按月递推时超过月末的日期取月末
+	TotalPayments int          `json:"total_payments,omitempty"`
+	Status        Status       `json:"status"`
+	Cycle         int          `json:"cycle"`   // 已扣款成功的期数
+	Retries       int          `json:"retries"` // 本期扣款失败的次数
+	DueAt         time.Time    `json:"due_at"`  // 下次扣款或者查询的时间
+	// PendingOutTradeNo 扣款结果未知的商户订单号，下次先查询确认，交易不存在时沿用该订单号重新扣款
+	PendingOutTradeNo string    `json:"pending_out_trade_no,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// NewSubscription 按签约时的周期规则创建订阅，每期扣款金额为单次扣款最大金额，location为nil时按东八区解析首次扣款日期
+func NewSubscription(id, agreementNo, subject string, rule alipay.AgreementPeriodRuleParams, location *time.Location) (*Subscription, error) {
+	if location == nil {
+		location = time.FixedZone("CST", 8*3600)
+	}
+	startAt, err := time.ParseInLocation("2006-01-02", rule.ExecuteTime, location)
+	if err != nil {
+		return nil, err
+	}
+	return &Subscription{
+		Id:            id,
+		AgreementNo:   agreementNo,
+		Subject:       subject,
+		Amount:        rule.SingleAmount,
+		PeriodType:    rule.PeriodType,
+		Period:        rule.Period,
+		StartAt:       startAt,
+		TotalPayments: rule.TotalPayments,
+	}, nil
+}
+
+// periodStart 第cycle期(从0开始)的扣款时间
+func (r *Subscription) periodStart(cycle int) time.Time {
+	if r.PeriodType == "DAY" {
+		return r.StartAt.AddDate(0, 0, cycle*r.Period)
+	}
+	year, month, day := r.StartAt.Date()
+	hour, min, sec := r.StartAt.Clock()
+	first := time.Date(year, month+time.Month(cycle*r.Period), 1, hour, min, sec, r.StartAt.Nanosecond(), r.StartAt.Location())
+	if lastDay := first.AddDate(0, 1, -1).Day(); day > lastDay {
+		day = lastDay
+	}
+	return first.AddDate(0, 0, day-1)
+}
+
+// AttemptAction 扣款记录的操作
+type AttemptAction string
+
+const (
+	ActionPay   AttemptAction = "PAY"   // alipay.trade.pay 协议扣款
+	ActionQuery AttemptAction = "QUERY" // alipay.trade.query 确认结果未知的扣款
+)
+
+// AttemptResult 扣款结果
+type AttemptResult string
+
+const (
+	ResultPaid     AttemptResult = "PAID"     // 扣款成功
+	ResultDeclined AttemptResult = "DECLINED" // 扣款失败，例如余额不足，按重试间隔再次扣款
+	ResultRejected AttemptResult = "REJECTED" // 不可重试的失败，例如协议已解约
+	ResultUnknown  AttemptResult = "UNKNOWN"  // 结果未知，稍后查询确认
+)
+
+// Attempt 一次扣款或者查询
+type Attempt struct {
+	SubscriptionId string        `json:"subscription_id"`
+	Cycle          int           `json:"cycle"` // 扣款的期数，从1开始
+	Action         AttemptAction `json:"action"`
+	OutTradeNo     string        `json:"out_trade_no"`
+	TradeNo        string        `json:"trade_no,omitempty"`
+	Amount         alipay.Money  `json:"amount"`
+	Result         AttemptResult `json:"result"`
+	SubCode        string        `json:"sub_code,omitempty"`
+	SubMsg         string        `json:"sub_msg,omitempty"`
+	Error          string        `json:"error,omitempty"`  // 请求失败的原因
+	Status         Status        `json:"status"`           // 处理后的订阅状态
+	DueAt          time.Time     `json:"due_at,omitempty"` // 处理后的下次扣款时间
+	At             time.Time     `json:"at"`
+}
+
+// Store 订阅及扣款记录的存储
+type Store interface {
+	// Subscription 按商户订阅号查询，不存在时返回nil
+	Subscription(ctx context.Context, id string) (*Subscription, error)
+	// AgreementSubscriptions 使用该代扣协议的全部订阅
+	AgreementSubscriptions(ctx context.Context, agreementNo string) ([]*Subscription, error)
+	// Due 需要扣款并且 DueAt 不晚于now的订阅，按 DueAt 排序
+	Due(ctx context.Context, now time.Time) ([]*Subscription, error)
+	// Save 新增或者按 Id 更新订阅
+	Save(ctx context.Context, sub *Subscription) error
+	// AddAttempt 追加扣款记录
+	AddAttempt(ctx context.Context, attempt *Attempt) error
+}
@@ -0,0 +1,151 @@
+package testkeys
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	alipay "github.com/try-labs/xpay"
+)
+
+/**
+ * @author: Sam
+ * @since: 1.0.0
+ * @date: 2026/10/19 17:50
+ * @desc: 根证书 -> 中间证书 -> 应用/支付宝公钥证书 的证书链
+ *
+ * 与支付宝下发的文件保持一致：根证书文件包含一张RSA根证书和一张ECC根证书（计算alipay_root_cert_sn时会被忽略），
+ * 公钥证书文件包含公钥证书和签发它的中间证书。
+ */
+
+const (
+	certPEMType  = "CERTIFICATE"
+	certValidity = 10 * 365 * 24 * time.Hour
+)
+
+// Certificate 证书及其私钥
+type Certificate struct {
+	Cert    *x509.Certificate
+	KeyPair *KeyPair // ECC根证书的私钥不是RSA，此时为nil
+	signer  crypto.Signer
+}
+
+// PEM 证书的PEM编码
+func (c *Certificate) PEM() []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: certPEMType, Bytes: c.Cert.Raw})
+}
+
+// SN 证书序列号，与 alipay.GetCertSN 计算方式一致
+func (c *Certificate) SN() string {
+	return alipay.GetCertSN(c.Cert)
+}
+
+// CertChain 测试用的证书链
+type CertChain struct {
+	Root         *Certificate // RSA根证书
+	ECCRoot      *Certificate // ECC根证书
+	Intermediate *Certificate // 由Root签发的中间证书，用于签发公钥证书
+}
+
+// GenerateCertChain 生成根证书和中间证书
+func GenerateCertChain() (*CertChain, error) {
+	rootKey, err := GenerateKeyPair(DefaultBits)
+	if err != nil {
+		return nil, err
+	}
+	root, err := createCert(caTemplate("xpay test Root CA R1"), nil, rootKey.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	root.KeyPair = rootKey
+	eccKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	eccRoot, err := createCert(caTemplate("xpay test Root CA E1"), nil, eccKey)
+	if err != nil {
+		return nil, err
+	}
+	intermediateKey, err := GenerateKeyPair(DefaultBits)
+	if err != nil {
+		return nil, err
+	}
+	intermediate, err := createCert(caTemplate("xpay test Class 2 CA R1"), root, intermediateKey.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	intermediate.KeyPair = intermediateKey
+	return &CertChain{Root: root, ECCRoot: eccRoot, Intermediate: intermediate}, nil
+}
+
+// Issue 使用中间证书为keyPair签发公钥证书
+func (c *CertChain) Issue(commonName string, keyPair *KeyPair) (*Certificate, error) {
+	template := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: commonName, Organization: []string{"xpay"}, Country: []string{"CN"}},
+		NotBefore:   time.Now().Add(-time.Hour),
+		NotAfter:    time.Now().Add(certValidity),
+		KeyUsage:    x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	cert, err := createCert(template, c.Intermediate, keyPair.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	cert.KeyPair = keyPair
+	return cert, nil
+}
+
+// RootCertPEM 根证书文件的内容
+func (c *CertChain) RootCertPEM() []byte {
+	return bytes.Join([][]byte{c.Root.PEM(), c.ECCRoot.PEM()}, nil)
+}
+
+// RootCertSN 根证书文件对应的alipay_root_cert_sn，只包含RSA签名的证书
+func (c *CertChain) RootCertSN() string {
+	return c.Root.SN()
+}
+
+// ChainPEM 公钥证书文件的内容，包含公钥证书和中间证书
+func (c *CertChain) ChainPEM(cert *Certificate) []byte {
+	return bytes.Join([][]byte{cert.PEM(), c.Intermediate.PEM()}, nil)
+}
+
+func caTemplate(commonName string) *x509.Certificate {
+	return &x509.Certificate{
+		Subject:               pkix.Name{CommonName: commonName, Organization: []string{"xpay"}, Country: []string{"CN"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(2 * certValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+}
+
+// createCert parent为nil时生成自签名证书
+func createCert(template *x509.Certificate, parent *Certificate, owner crypto.Signer) (*Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+	template.SerialNumber = serial
+	parentCert, signer := template, owner
+	if parent != nil {
+		parentCert, signer = parent.Cert, parent.signer
+	}
+	raw, err := x509.CreateCertificate(rand.Reader, template, parentCert, owner.Public(), signer)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(raw)
+	if err != nil {
+		return nil, err
+	}
+	return &Certificate{Cert: cert, signer: owner}, nil
+}
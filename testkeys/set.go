@@ -0,0 +1,128 @@
+package testkeys
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+
+	alipay "github.com/try-labs/xpay"
+)
+
+/**
+ * @author: Sam
+ * @since: 1.0.0
+ * @date: 2026/10/19 18:00
+ * @desc: 商户与支付宝双方配套的密钥、证书和签名策略
+ *
+ * 支付宝侧的签名策略是商户侧的镜像：使用支付宝私钥签名，使用应用公钥（证书）验签，
+ * 可用于模拟网关对响应、异步通知签名，或者校验商户的请求签名。
+ */
+
+// 证书文件名，与开放平台下载的文件名保持一致
+const (
+	AppCertFileName    = "appPublicCert.crt"
+	RootCertFileName   = "alipayRootCert.crt"
+	AlipayCertFileName = "alipayPublicCert.crt"
+)
+
+// Set 商户与支付宝双方的密钥和证书
+type Set struct {
+	App        *KeyPair     // 应用密钥
+	Alipay     *KeyPair     // 支付宝密钥
+	Chain      *CertChain   // 证书链
+	AppCert    *Certificate // 应用公钥证书
+	AlipayCert *Certificate // 支付宝公钥证书
+}
+
+// CertFiles 证书模式需要的三个证书文件路径
+type CertFiles struct {
+	AppCert    string
+	RootCert   string
+	AlipayCert string
+}
+
+// New 生成一套新的密钥和证书
+func New() (*Set, error) {
+	set := new(Set)
+	var err error
+	if set.App, err = GenerateKeyPair(DefaultBits); err != nil {
+		return nil, err
+	}
+	if set.Alipay, err = GenerateKeyPair(DefaultBits); err != nil {
+		return nil, err
+	}
+	if set.Chain, err = GenerateCertChain(); err != nil {
+		return nil, err
+	}
+	if set.AppCert, err = set.Chain.Issue("xpay test app", set.App); err != nil {
+		return nil, err
+	}
+	if set.AlipayCert, err = set.Chain.Issue("支付宝(中国)网络技术有限公司-test", set.Alipay); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+var (
+	sharedOnce sync.Once
+	shared     *Set
+)
+
+// Shared 进程内共享的一套密钥，生成RSA密钥比较耗时，不关心具体密钥的测试可以共用
+func Shared() *Set {
+	sharedOnce.Do(func() {
+		var err error
+		if shared, err = New(); err != nil {
+			panic(err)
+		}
+	})
+	return shared
+}
+
+// NormalStrategy 商户侧的公钥模式签名策略
+func (s *Set) NormalStrategy(appId string) alipay.SignVerifier {
+	return alipay.NewNormalRSA2SignStrategy(appId, s.App.PKCS1Base64(), s.App.PublicKeyBase64(), s.Alipay.PublicKeyBase64())
+}
+
+// AlipayNormalStrategy 支付宝侧的公钥模式签名策略
+func (s *Set) AlipayNormalStrategy(appId string) alipay.SignVerifier {
+	return alipay.NewNormalRSA2SignStrategy(appId, s.Alipay.PKCS1Base64(), s.Alipay.PublicKeyBase64(), s.App.PublicKeyBase64())
+}
+
+// WriteCertFiles 将证书写入dir，返回文件路径
+func (s *Set) WriteCertFiles(dir string) (*CertFiles, error) {
+	files := &CertFiles{
+		AppCert:    filepath.Join(dir, AppCertFileName),
+		RootCert:   filepath.Join(dir, RootCertFileName),
+		AlipayCert: filepath.Join(dir, AlipayCertFileName),
+	}
+	contents := map[string][]byte{
+		files.AppCert:    s.Chain.ChainPEM(s.AppCert),
+		files.RootCert:   s.Chain.RootCertPEM(),
+		files.AlipayCert: s.Chain.ChainPEM(s.AlipayCert),
+	}
+	for filename, content := range contents {
+		if err := os.WriteFile(filename, content, 0600); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// CertStrategy 商户侧的证书模式签名策略，证书文件写入dir
+func (s *Set) CertStrategy(appId, dir string) (alipay.SignVerifier, error) {
+	files, err := s.WriteCertFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	return alipay.NewCertSignStrategy(appId, s.App.PKCS1Base64(), files.AppCert, files.RootCert, files.AlipayCert), nil
+}
+
+// AlipayCertStrategy 支付宝侧的证书模式签名策略，应用证书与支付宝证书的位置互换，证书文件写入dir
+func (s *Set) AlipayCertStrategy(appId, dir string) (alipay.SignVerifier, error) {
+	files, err := s.WriteCertFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	return alipay.NewCertSignStrategy(appId, s.Alipay.PKCS1Base64(), files.AlipayCert, files.RootCert, files.AppCert), nil
+}
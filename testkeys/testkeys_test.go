@@ -0,0 +1,114 @@
+package testkeys
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+
+	alipay "github.com/try-labs/xpay"
+)
+
+/**
+ * @author: Sam
+ * @since:
+ * @date: 2026/10/19 18:10
+ * @desc:
+ */
+
+func TestKeyPair_Formats(t *testing.T) {
+	keyPair := Shared().App
+	if key, err := alipay.ParsePKCS1PrivateKey(alipay.FormatPKCS1PrivateKey(keyPair.PKCS1Base64())); err != nil || !key.Equal(keyPair.PrivateKey) {
+		t.Errorf("pkcs1 key mismatch: %v", err)
+	}
+	if key, err := alipay.ParsePKCS8PrivateKey(alipay.FormatPKCS8PrivateKey(keyPair.PKCS8Base64())); err != nil || !key.Equal(keyPair.PrivateKey) {
+		t.Errorf("pkcs8 key mismatch: %v", err)
+	}
+	if key, err := alipay.ParsePKCS8PrivateKey(keyPair.PKCS8PEM()); err != nil || !key.Equal(keyPair.PrivateKey) {
+		t.Errorf("pkcs8 pem mismatch: %v", err)
+	}
+	if key, err := alipay.ParsePKCS1PrivateKey(keyPair.PKCS1PEM()); err != nil || !key.Equal(keyPair.PrivateKey) {
+		t.Errorf("pkcs1 pem mismatch: %v", err)
+	}
+	if key, err := alipay.ParsePublicKey(alipay.FormatPublicKey(keyPair.PublicKeyBase64())); err != nil || !key.Equal(keyPair.PublicKey()) {
+		t.Errorf("public key mismatch: %v", err)
+	}
+}
+
+func TestCertChain(t *testing.T) {
+	set := Shared()
+	if err := set.AppCert.Cert.CheckSignatureFrom(set.Chain.Intermediate.Cert); err != nil {
+		t.Error(err)
+	}
+	if err := set.Chain.Intermediate.Cert.CheckSignatureFrom(set.Chain.Root.Cert); err != nil {
+		t.Error(err)
+	}
+	if set.AppCert.SN() == set.AlipayCert.SN() {
+		t.Error("cert sn should be different")
+	}
+}
+
+// verifyEncoded 使用对端的签名策略校验编码后的请求参数
+func verifyEncoded(t *testing.T, verifier alipay.SignVerifier, encode string) {
+	t.Helper()
+	values, err := url.ParseQuery(encode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	list := make([]string, 0, len(values))
+	for key := range values {
+		if key != alipay.ExcludeKeySign {
+			list = append(list, key+"="+values.Get(key))
+		}
+	}
+	sort.Strings(list)
+	if err = verifier.VerifySign(alipay.AsyncVerificationScene, values.Get("sign"), []byte(strings.Join(list, "&"))); err != nil {
+		t.Error(err)
+	}
+}
+
+func newParam() *alipay.CommonReqParam {
+	return &alipay.CommonReqParam{
+		Method:     "alipay.trade.query",
+		Format:     alipay.FormatJson,
+		Charset:    alipay.CharsetUTF8,
+		SignType:   alipay.SignTypeRSA2,
+		Timestamp:  "2026-10-19 18:10:00",
+		Version:    alipay.ApiVersion,
+		BizContent: `{"out_trade_no":"20261019000001"}`,
+	}
+}
+
+func TestSet_NormalStrategy(t *testing.T) {
+	set := Shared()
+	merchant := set.NormalStrategy("2021000000000001")
+	merchant.SetSignContent(newParam())
+	encode, err := merchant.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifyEncoded(t, set.AlipayNormalStrategy("2021000000000001"), encode)
+}
+
+func TestSet_CertStrategy(t *testing.T) {
+	set := Shared()
+	dir := t.TempDir()
+	merchant, err := set.CertStrategy("2021000000000001", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	param := newParam()
+	merchant.SetSignContent(param)
+	if param.AppCertSn != set.AppCert.SN() || param.AlipayRootCertSn != set.Chain.RootCertSN() {
+		t.Errorf("unexpected cert sn: %s %s", param.AppCertSn, param.AlipayRootCertSn)
+	}
+	encode, err := merchant.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	alipaySide, err := set.AlipayCertStrategy("2021000000000001", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifyEncoded(t, alipaySide, encode)
+}